@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/evan-idocoding/zkit/httpx"
+	"github.com/evan-idocoding/zkit/ops"
 )
 
 // New assembles and returns the admin subtree handler.
@@ -60,9 +61,18 @@ type Builder struct {
 	realIP RealIPSpec
 
 	paths map[string]http.Handler // path -> handler (one capability per path)
+	caps  []capabilityInfo        // capability metadata in enable order (for the index)
 
-	// Late-assembled endpoint.
+	// Late-assembled endpoints.
 	report *ReportSpec
+	bundle *BundleSpec
+	index  *IndexSpec
+
+	// Custom section names (EnableCustom), for duplicate detection.
+	customNames map[string]struct{}
+
+	// events receives guard denials and admin-side changes (see EnableEvents).
+	events *ops.EventHub
 
 	// Data sources for /report (captured at assembly time when endpoints are enabled).
 	reportState reportState
@@ -70,13 +80,17 @@ type Builder struct {
 
 func newBuilder() *Builder {
 	return &Builder{
-		paths: make(map[string]http.Handler),
+		paths:       make(map[string]http.Handler),
+		customNames: make(map[string]struct{}),
 	}
 }
 
 func (b *Builder) build() http.Handler {
 	// Late-assembled endpoints depend on what was enabled.
+	// The index goes last so it can list everything (including /report).
 	b.assembleReport()
+	b.assembleBundle()
+	b.assembleIndex()
 
 	mux := http.NewServeMux()
 
@@ -1,14 +1,36 @@
 package admin
 
 import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"path"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/evan-idocoding/zkit/httpx"
+	"github.com/evan-idocoding/zkit/ops"
 	"github.com/evan-idocoding/zkit/rt/task"
 	"github.com/evan-idocoding/zkit/rt/tuning"
+	"github.com/evan-idocoding/zkit/slogx"
 )
 
 func TestNilGuardPanics(t *testing.T) {
@@ -84,6 +106,31 @@ func TestTuningWrite_EmptyAccessDeniesAll(t *testing.T) {
 	}
 }
 
+func TestEnableTuningApply_AllowlistCoversEveryKey(t *testing.T) {
+	tu := tuning.New()
+	a, _ := tu.Bool("feature.a", false)
+	_, _ = tu.Bool("other.b", false)
+
+	h := New(
+		EnableTuningApply(TuningApplySpec{
+			Guard:  AllowAll(),
+			T:      tu,
+			Access: TuningAccessSpec{AllowPrefixes: []string{"feature."}},
+		}),
+	)
+	post := func(body string) int {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "http://admin.test/tuning/apply", strings.NewReader(body)))
+		return rr.Code
+	}
+	if code := post(`[{"key":"feature.a","value":"true"},{"key":"other.b","value":"true"}]`); code != http.StatusForbidden || a.Get() {
+		t.Fatalf("mixed batch: code=%d a=%v", code, a.Get())
+	}
+	if code := post(`[{"key":"feature.a","value":"true"}]`); code != http.StatusOK || !a.Get() {
+		t.Fatalf("allowed batch: code=%d a=%v", code, a.Get())
+	}
+}
+
 func TestTokensOrIPAllowList_AllowsTokenOrIP(t *testing.T) {
 	g := TokensOrIPAllowList([]string{"p1"}, []string{"10.0.0.0/8"})
 	h := New(
@@ -155,6 +202,67 @@ func TestTokensAndIPAllowList_RequiresBoth(t *testing.T) {
 	}
 }
 
+func TestTokensOrClientCerts_AllowsTokenOrCert(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ops-cli"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+
+	var identity string
+	g := TokensOrClientCerts([]string{"p1"}, []string{"cn:ops-cli"})
+	h := g.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := httpx.ClientCertIdentityFromRequest(r); ok {
+			identity = id.CommonName
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Neither token nor cert: denied.
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://admin.test/whoami", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	// Verified client cert: allowed, identity visible downstream.
+	rr2 := httptest.NewRecorder()
+	req2 := httptest.NewRequest(http.MethodGet, "https://admin.test/whoami", nil)
+	req2.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+	h.ServeHTTP(rr2, req2)
+	if rr2.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr2.Code)
+	}
+	if identity != "ops-cli" {
+		t.Fatalf("identity=%q, want %q", identity, "ops-cli")
+	}
+
+	// Token: allowed.
+	rr3 := httptest.NewRecorder()
+	req3 := httptest.NewRequest(http.MethodGet, "http://admin.test/whoami", nil)
+	req3.Header.Set(DefaultTokenHeader, "p1")
+	h.ServeHTTP(rr3, req3)
+	if rr3.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr3.Code)
+	}
+}
+
 func TestTokens_WithTokenHeader(t *testing.T) {
 	g := Tokens([]string{"p1"}, WithTokenHeader("X-Admin-Token"))
 	h := New(
@@ -195,6 +303,38 @@ func TestHealthz_Head(t *testing.T) {
 	}
 }
 
+func TestReadyz_MonitorAndNonCritical(t *testing.T) {
+	m := ops.NewReadyzMonitor([]ops.ReadyCheck{
+		{Name: "cache", NonCritical: true, Func: func(context.Context) error { return errors.New("cold") }},
+	})
+	m.RunNow(context.Background())
+	h := New(EnableReadyz(ReadyzSpec{Guard: AllowAll(), Monitor: m}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://admin.test/readyz", nil))
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Body.String(), "degraded\n") {
+		t.Fatalf("code=%d body=%q", rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("Age") == "" {
+		t.Fatalf("expected Age header")
+	}
+
+	h2 := New(EnableReadyz(ReadyzSpec{Guard: AllowAll(), Checks: []ReadyCheck{
+		{Name: "cache", NonCritical: true, Func: func(context.Context) error { return errors.New("cold") }},
+	}}))
+	rr = httptest.NewRecorder()
+	h2.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://admin.test/readyz", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "fail cache (non-critical): cold") {
+		t.Fatalf("code=%d body=%q", rr.Code, rr.Body.String())
+	}
+
+	assertPanics(t, func() {
+		_ = New(EnableReadyz(ReadyzSpec{Guard: AllowAll(), Monitor: m, Checks: []ReadyCheck{
+			{Name: "x", Func: func(context.Context) error { return nil }},
+		}}))
+	})
+}
+
 func TestTaskWrite_EmptyAccessDeniesAll(t *testing.T) {
 	mgr := task.NewManager()
 
@@ -271,6 +411,27 @@ func TestReport_IncludesOnlyEnabledSections(t *testing.T) {
 	}
 }
 
+func TestReport_IncludesTuningMetadata(t *testing.T) {
+	tu := tuning.New()
+	if _, err := tu.Bool("feature.a", false, tuning.WithMetaBool(tuning.Meta{Description: "Enables feature A.", Owner: "team-a"})); err != nil {
+		t.Fatalf("register tuning: %v", err)
+	}
+	h := New(
+		EnableTuningSnapshot(TuningSnapshotSpec{Guard: AllowAll(), T: tu}),
+		EnableReport(ReportSpec{Guard: AllowAll()}),
+	)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://admin.test/report", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "tuning\tfeature.a\tdescription\tEnables feature A.\n") || !strings.Contains(body, "tuning\tfeature.a\towner\tteam-a\n") {
+		t.Fatalf("expected tuning metadata in report, got:\n%s", body)
+	}
+}
+
 func TestReport_ProvidedTruncation(t *testing.T) {
 	// Make provided output large enough to trigger report truncation.
 	huge := strings.Repeat("a", reportProvidedMaxBytes+1024)
@@ -293,6 +454,30 @@ func TestReport_ProvidedTruncation(t *testing.T) {
 	}
 }
 
+func TestEnableProvidedSnapshot_ProvidersAndRedaction(t *testing.T) {
+	h := New(
+		EnableProvidedSnapshot(ProvidedSnapshotSpec{
+			Guard: AllowAll(),
+			Items: map[string]any{
+				"pool": ops.ProvidedFunc(func(ctx context.Context) (any, error) {
+					return map[string]any{"size": 4, "dsn": "postgres://u:p@h"}, nil
+				}),
+				"secret_sauce": "ketchup",
+			},
+			RedactKeys: []string{"dsn"},
+		}),
+		EnableReport(ReportSpec{Guard: AllowAll()}),
+	)
+	for _, p := range []string{"/provided?format=json", "/report?sections=provided&format=json"} {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://admin.test"+p, nil))
+		body := rr.Body.String()
+		if rr.Code != http.StatusOK || !strings.Contains(body, `"dsn":"[REDACTED]"`) || !strings.Contains(body, "ketchup") {
+			t.Fatalf("%s: code=%d body=%s", p, rr.Code, body)
+		}
+	}
+}
+
 func assertPanics(t *testing.T, fn func()) {
 	t.Helper()
 	defer func() {
@@ -302,3 +487,554 @@ func assertPanics(t *testing.T, fn func()) {
 	}()
 	fn()
 }
+
+func TestWithLockout_LocksOutAndClears(t *testing.T) {
+	l := httpx.NewLockout(httpx.WithLockoutThreshold(2))
+	read := Tokens([]string{"r"}, WithLockout(l))
+	write := Tokens([]string{"w"}, WithLockout(l))
+	h := New(
+		EnableHealthz(HealthzSpec{Guard: read}),
+		EnableLockoutSnapshot(LockoutSnapshotSpec{Guard: read, Lockout: l}),
+		EnableLockoutClear(LockoutClearSpec{Guard: write, Lockout: l}),
+	)
+	do := func(method, target, token, ip string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "http://admin.test"+target, nil)
+		req.RemoteAddr = ip + ":1234"
+		if token != "" {
+			req.Header.Set(DefaultTokenHeader, token)
+		}
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	do(http.MethodGet, "/healthz", "bad", "203.0.113.1")
+	do(http.MethodGet, "/healthz", "bad", "203.0.113.1")
+	if rr := do(http.MethodGet, "/healthz", "r", "203.0.113.1"); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d, got %d", http.StatusTooManyRequests, rr.Code)
+	} else if rr.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header")
+	}
+
+	rr := do(http.MethodGet, "/guard/lockouts", "r", "10.0.0.1")
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "locked\t203.0.113.1\t") {
+		t.Fatalf("unexpected lockouts response: %d %q", rr.Code, rr.Body.String())
+	}
+
+	if rr := do(http.MethodPost, "/guard/lockouts/clear?ip=203.0.113.1", "w", "10.0.0.1"); rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr := do(http.MethodGet, "/healthz", "r", "203.0.113.1"); rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+}
+
+func TestEnableCustom_GuardsMethodsIndexAndReport(t *testing.T) {
+	var flushed int
+	h := New(
+		EnableCustom(CustomSpec{
+			Guard:       AllowAll(),
+			Path:        "/app/queue",
+			Name:        "app.queue",
+			Description: "queue depth",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("depth\t3\n"))
+			}),
+			Reportable: true,
+		}),
+		EnableCustom(CustomSpec{
+			Guard: Tokens([]string{"w"}),
+			Path:  "/app/cache/flush",
+			Name:  "app.cache.flush",
+			Kind:  CustomWrite,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				flushed++
+			}),
+		}),
+		EnableReport(ReportSpec{Guard: AllowAll()}),
+		EnableIndex(IndexSpec{Guard: AllowAll()}),
+	)
+	serve := func(method, target, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "http://admin.test"+target, nil)
+		if token != "" {
+			req.Header.Set(httpx.DefaultAccessGuardTokenHeader, token)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Write: guard first, then method enforcement.
+	if rr := serve(http.MethodPost, "/app/cache/flush", ""); rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	rr := serve(http.MethodGet, "/app/cache/flush", "w")
+	if rr.Code != http.StatusMethodNotAllowed || rr.Header().Get("Allow") != "POST" {
+		t.Fatalf("expected 405 with Allow POST, got %d %q", rr.Code, rr.Header().Get("Allow"))
+	}
+	if rr := serve(http.MethodPost, "/app/cache/flush", "w"); rr.Code != http.StatusOK || flushed != 1 {
+		t.Fatalf("expected flush, got code=%d flushed=%d", rr.Code, flushed)
+	}
+
+	// Read: POST is rejected.
+	if rr := serve(http.MethodPost, "/app/queue", ""); rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected %d, got %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+
+	// Index lists custom endpoints, the report and itself; unknown paths are still 404.
+	body := serve(http.MethodGet, "/", "").Body.String()
+	for _, want := range []string{
+		"capability\tcustom.app.queue\tread\t/app/queue\tGET, HEAD\tqueue depth\n",
+		"capability\tcustom.app.cache.flush\twrite\t/app/cache/flush\tPOST\n",
+		"capability\treport\tread\t/report\tGET, HEAD\n",
+		"capability\tindex\tread\t/\tGET, HEAD\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected index line %q, got:\n%s", want, body)
+		}
+	}
+	if rr := serve(http.MethodGet, "/nope", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
+	}
+	if rr := serve(http.MethodGet, "/?format=json", ""); !strings.Contains(rr.Body.String(), `"name":"custom.app.queue"`) {
+		t.Fatalf("unexpected json index: %s", rr.Body.String())
+	}
+
+	// Reportable read endpoints become report sections.
+	body = serve(http.MethodGet, "/report", "").Body.String()
+	if !strings.Contains(body, "=== app.queue ===") || !strings.Contains(body, "| depth\t3\n") {
+		t.Fatalf("expected custom report section, got:\n%s", body)
+	}
+	if strings.Contains(body, "app.cache.flush") {
+		t.Fatalf("did not expect write endpoint in report, got:\n%s", body)
+	}
+}
+
+func TestEnableCustom_InvalidSpecPanics(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	cases := map[string][]Option{
+		"nil guard":              {EnableCustom(CustomSpec{Path: "/a", Name: "a", Handler: ok})},
+		"nil handler":            {EnableCustom(CustomSpec{Guard: AllowAll(), Path: "/a", Name: "a"})},
+		"empty path":             {EnableCustom(CustomSpec{Guard: AllowAll(), Name: "a", Handler: ok})},
+		"bad name":               {EnableCustom(CustomSpec{Guard: AllowAll(), Path: "/a", Name: "a b", Handler: ok})},
+		"builtin name":           {EnableCustom(CustomSpec{Guard: AllowAll(), Path: "/a", Name: "runtime", Handler: ok})},
+		"builtin report section": {EnableCustom(CustomSpec{Guard: AllowAll(), Path: "/a", Name: "log.levels", Handler: ok})},
+		"reportable write": {EnableCustom(CustomSpec{
+			Guard: AllowAll(), Path: "/a", Name: "a", Kind: CustomWrite, Handler: ok, Reportable: true,
+		})},
+		"duplicate name": {
+			EnableCustom(CustomSpec{Guard: AllowAll(), Path: "/a", Name: "a", Handler: ok}),
+			EnableCustom(CustomSpec{Guard: AllowAll(), Path: "/b", Name: "a", Handler: ok}),
+		},
+		"path collision": {
+			EnableRuntime(RuntimeSpec{Guard: AllowAll()}),
+			EnableCustom(CustomSpec{Guard: AllowAll(), Path: "/runtime", Name: "a", Handler: ok}),
+		},
+	}
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			assertPanics(t, func() { _ = New(opts...) })
+		})
+	}
+}
+
+func TestReport_JSONAndSectionSelection(t *testing.T) {
+	h := New(
+		EnableRuntime(RuntimeSpec{Guard: AllowAll()}),
+		EnableProvidedSnapshot(ProvidedSnapshotSpec{Guard: AllowAll(), Items: map[string]any{"x": "y"}}),
+		EnableCustom(CustomSpec{
+			Guard: AllowAll(), Path: "/app/plain", Name: "app.plain", Reportable: true,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("not json\n"))
+			}),
+		}),
+		EnableReport(ReportSpec{Guard: AllowAll()}),
+	)
+	get := func(target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://admin.test"+target, nil))
+		return rr
+	}
+
+	rr := get("/report?format=json&exclude=provided")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	var rep reportResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("decode: %v\n%s", err, rr.Body.String())
+	}
+	if !rep.OK || len(rep.Sections) != 2 {
+		t.Fatalf("unexpected report: %s", rr.Body.String())
+	}
+	rt := rep.Sections[0]
+	if rt.Name != "runtime" || rt.Status != http.StatusOK || len(rt.Data) == 0 || rt.Text != "" {
+		t.Fatalf("unexpected runtime section: %+v", rt)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(rt.Data, &payload); err != nil || payload["ok"] != true {
+		t.Fatalf("expected parsed runtime payload, got %s (%v)", rt.Data, err)
+	}
+	if p := rep.Sections[1]; p.Name != "app.plain" || p.Data != nil || p.Text != "not json\n" {
+		t.Fatalf("unexpected custom section: %+v", p)
+	}
+
+	body := get("/report?sections=runtime").Body.String()
+	if !strings.Contains(body, "selected sections: runtime\n") || strings.Contains(body, "=== provided ===") {
+		t.Fatalf("unexpected filtered report:\n%s", body)
+	}
+
+	if rr := get("/report?sections=nope"); rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestReport_SectionTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	h := New(
+		EnableRuntime(RuntimeSpec{Guard: AllowAll()}),
+		EnableCustom(CustomSpec{
+			Guard: AllowAll(), Path: "/app/slow", Name: "app.slow", Reportable: true,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release // ignores ctx on purpose
+			}),
+		}),
+		EnableReport(ReportSpec{Guard: AllowAll(), SectionTimeout: 20 * time.Millisecond}),
+	)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://admin.test/report?format=json", nil))
+	var rep reportResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("decode: %v\n%s", err, rr.Body.String())
+	}
+	if rep.OK || len(rep.Sections) != 2 {
+		t.Fatalf("unexpected report: %s", rr.Body.String())
+	}
+	if !rep.Sections[0].OK {
+		t.Fatalf("expected runtime section ok: %+v", rep.Sections[0])
+	}
+	if s := rep.Sections[1]; !s.TimedOut || s.OK || s.Status != http.StatusGatewayTimeout {
+		t.Fatalf("expected timed out section, got %+v", s)
+	}
+}
+
+func TestEnableGoroutines(t *testing.T) {
+	h := New(EnableGoroutines(GoroutinesSpec{Guard: AllowAll()}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://admin.test/goroutines?pkg=testing", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "group\t1\tcount\t") {
+		t.Fatalf("expected grouped dump, got:\n%s", rr.Body.String())
+	}
+}
+
+func TestEnableRuntimeWrites_PostOnly(t *testing.T) {
+	h := New(
+		EnableRuntimeGC(RuntimeGCSpec{Guard: AllowAll()}),
+		EnableRuntimeFreeOSMemory(RuntimeFreeOSMemorySpec{Guard: DenyAll()}),
+	)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "http://admin.test/runtime/gc", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "http://admin.test/runtime/free-os-memory", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestEnableEvents_GuardDenyAndLogLevel(t *testing.T) {
+	hub := ops.NewEventHub()
+	var lv slog.LevelVar
+	h := New(
+		EnableEvents(EventsSpec{Guard: AllowAll(), Hub: hub}),
+		EnableRuntime(RuntimeSpec{Guard: Tokens([]string{"t"})}),
+		EnableLogLevelSet(LogLevelSetSpec{Guard: AllowAll(), Var: &lv}),
+	)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events?types=guard,log")
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status=%d, want=%d", resp.StatusCode, http.StatusOK)
+	}
+	for i := 0; hub.Subscribers() != 1; i++ {
+		if i > 200 {
+			t.Fatalf("client did not subscribe")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	r1, err := http.Get(srv.URL + "/runtime")
+	if err != nil {
+		t.Fatalf("runtime: %v", err)
+	}
+	r1.Body.Close()
+	if r1.StatusCode != http.StatusForbidden {
+		t.Fatalf("status=%d, want=%d", r1.StatusCode, http.StatusForbidden)
+	}
+	r2, err := http.Post(srv.URL+"/log/level/set?level=debug", "", nil)
+	if err != nil {
+		t.Fatalf("log level set: %v", err)
+	}
+	r2.Body.Close()
+
+	var events []ops.Event
+	sc := bufio.NewScanner(resp.Body)
+	for len(events) < 2 && sc.Scan() {
+		if line := sc.Text(); strings.HasPrefix(line, "data: ") {
+			var e ops.Event
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			events = append(events, e)
+		}
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != ops.EventGuardDeny || events[0].Key != "/runtime" {
+		t.Fatalf("unexpected deny event: %+v", events[0])
+	}
+	if d := events[0].Data.(map[string]any); d["capability"] != "runtime" || d["status"] != float64(http.StatusForbidden) {
+		t.Fatalf("unexpected deny data: %v", d)
+	}
+	if events[1].Type != ops.EventLogLevel {
+		t.Fatalf("unexpected log level event: %+v", events[1])
+	}
+}
+
+func TestEnableEvents_InvalidSpecPanics(t *testing.T) {
+	assertPanics(t, func() { _ = New(EnableEvents(EventsSpec{Guard: AllowAll()})) })
+	hub := ops.NewEventHub()
+	assertPanics(t, func() {
+		_ = New(
+			EnableEvents(EventsSpec{Guard: AllowAll(), Hub: hub}),
+			EnableEvents(EventsSpec{Guard: AllowAll(), Hub: hub, Path: "/events2"}),
+		)
+	})
+}
+
+func TestEnableBundle(t *testing.T) {
+	entered, block := make(chan struct{}, 2), make(chan struct{})
+	var blocking atomic.Bool
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if blocking.Load() {
+			entered <- struct{}{}
+			<-block
+		}
+		_, _ = w.Write([]byte("slow ok\n"))
+	})
+	h := New(
+		EnableRuntime(RuntimeSpec{Guard: AllowAll()}),
+		EnableCustom(CustomSpec{Guard: AllowAll(), Path: "/app/slow", Name: "app.slow", Handler: slow, Reportable: true}),
+		EnableCustom(CustomSpec{
+			Guard: AllowAll(), Path: "/app/broken", Name: "app.broken", Reportable: true,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) }),
+		}),
+		EnableBundle(BundleSpec{Guard: AllowAll(), MaxCPUProfile: time.Second}),
+	)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/debug/bundle", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/gzip" {
+		t.Fatalf("status=%d content-type=%q body=%s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+	files := readTarGz(t, w.Body.Bytes())
+	for _, name := range []string{"report.txt", "report.json", "goroutines.txt", "heap.pb.gz", "allocs.pb.gz", "buildinfo.json", "manifest.json"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("missing %s in bundle (have %v)", name, keysOf(files))
+		}
+	}
+	if _, ok := files["cpu.pb.gz"]; ok {
+		t.Fatalf("unexpected cpu profile without ?cpu=")
+	}
+	if !strings.Contains(files["report.txt"], "slow ok") || !strings.Contains(files["goroutines.txt"], "goroutine ") {
+		t.Fatalf("unexpected bundle contents:\n%s", files["report.txt"])
+	}
+	var rep struct {
+		OK          bool      `json:"ok"`
+		Error       string    `json:"error"`
+		GeneratedAt time.Time `json:"generated_at"`
+	}
+	if err := json.Unmarshal([]byte(files["report.json"]), &rep); err != nil || rep.OK || rep.Error == "" {
+		t.Fatalf("report.json must reflect the failed section (err=%v):\n%s", err, files["report.json"])
+	}
+	if !strings.Contains(files["report.txt"], "generated_at: "+rep.GeneratedAt.Format(time.RFC3339Nano)+"\n") {
+		t.Fatalf("report.txt and report.json come from different runs:\n%s", files["report.txt"])
+	}
+	var m struct {
+		Files []struct {
+			Name  string `json:"name"`
+			Bytes int    `json:"bytes"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(files["manifest.json"]), &m); err != nil || len(m.Files) != 6 {
+		t.Fatalf("unexpected manifest (err=%v):\n%s", err, files["manifest.json"])
+	}
+
+	for _, tc := range []struct {
+		method, query string
+		want          int
+	}{
+		{http.MethodGet, "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "?cpu=soon", http.StatusBadRequest},
+		{http.MethodPost, "?cpu=2s", http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tc.method, "http://example/debug/bundle"+tc.query, nil))
+		if w.Code != tc.want {
+			t.Fatalf("%s %s: status=%d, want=%d", tc.method, tc.query, w.Code, tc.want)
+		}
+	}
+
+	// Only one capture at a time.
+	blocking.Store(true)
+	done := make(chan int)
+	go func() {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/debug/bundle", nil))
+		done <- w.Code
+	}()
+	<-entered
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/debug/bundle", nil))
+	blocking.Store(false)
+	close(block)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusConflict)
+	}
+	if c := <-done; c != http.StatusOK {
+		t.Fatalf("status=%d, want=%d", c, http.StatusOK)
+	}
+}
+
+func TestEnableBundle_MaxBytes(t *testing.T) {
+	h := New(EnableBundle(BundleSpec{Guard: AllowAll(), MaxBytes: 1}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/debug/bundle", nil))
+	files := readTarGz(t, w.Body.Bytes())
+	if len(files) != 1 || !strings.Contains(files["manifest.json"], `"skipped": true`) {
+		t.Fatalf("expected only a manifest with skipped files, got %v:\n%s", keysOf(files), files["manifest.json"])
+	}
+}
+
+func readTarGz(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	out := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatalf("tar: %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		out[path.Base(hdr.Name)] = string(data)
+	}
+}
+
+func keysOf(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestEnableLogLevels(t *testing.T) {
+	levels := slogx.NewLevels()
+	if _, err := levels.Register("db", slog.LevelInfo); err != nil {
+		t.Fatal(err)
+	}
+	h := New(
+		EnableReport(ReportSpec{Guard: AllowAll()}),
+		EnableLogLevels(LogLevelsSpec{Guard: AllowAll(), Levels: levels}),
+		EnableLogLevelsSet(LogLevelsSetSpec{Guard: AllowAll(), Levels: levels}),
+		EnableLogLevelsReset(LogLevelsResetSpec{Guard: AllowAll(), Levels: levels}),
+	)
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "http://admin.test"+target, nil))
+		return w
+	}
+
+	if w := serve(http.MethodPost, "/log/levels/set?name=db&level=debug&ttl=1h"); w.Code != http.StatusOK {
+		t.Fatalf("set: %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodGet, "/log/levels"); !strings.Contains(w.Body.String(), "log_level\tdb\tlevel\tdebug\n") {
+		t.Fatalf("list: %q", w.Body.String())
+	}
+	if w := serve(http.MethodGet, "/report?sections=log.levels"); !strings.Contains(w.Body.String(), "log_level\tdb\trevert_to\tinfo\n") {
+		t.Fatalf("report: %q", w.Body.String())
+	}
+	if w := serve(http.MethodPost, "/log/levels/reset?name=db"); w.Code != http.StatusOK {
+		t.Fatalf("reset: %d %s", w.Code, w.Body.String())
+	}
+	if lv, _ := levels.Var("db"); lv.Level() != slog.LevelInfo {
+		t.Fatalf("level=%v", lv.Level())
+	}
+	if w := serve(http.MethodGet, "/log/levels/set?name=db&level=debug"); w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET set: %d", w.Code)
+	}
+
+	assertPanics(t, func() { _ = New(EnableLogLevels(LogLevelsSpec{Guard: AllowAll()})) })
+	assertPanics(t, func() { _ = New(EnableLogLevelsSet(LogLevelsSetSpec{Levels: levels})) })
+}
+
+func TestEnableLogTail(t *testing.T) {
+	ring := slogx.NewRing()
+	logger := slog.New(ring.Handler(nil))
+	logger.Info("hello", "k", "v")
+	h := New(EnableLogTail(LogTailSpec{Guard: AllowAll(), Ring: ring}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://admin.test/log/tail?q=hello", nil))
+	if w.Code != http.StatusOK || !strings.HasSuffix(w.Body.String(), "\tINFO\thello\tk=v\n") {
+		t.Fatalf("code=%d body=%q", w.Code, w.Body.String())
+	}
+	assertPanics(t, func() { _ = New(EnableLogTail(LogTailSpec{Guard: AllowAll()})) })
+}
+
+func TestEnableDebugTargets(t *testing.T) {
+	targets := httpx.NewDebugTargets()
+	h := New(
+		EnableDebugTargets(DebugTargetsSpec{Guard: AllowAll(), Targets: targets}),
+		EnableDebugTargetsAdd(DebugTargetsAddSpec{Guard: AllowAll(), Targets: targets}),
+		EnableDebugTargetsRemove(DebugTargetsRemoveSpec{Guard: AllowAll(), Targets: targets}),
+	)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://admin.test/log/debug-targets/add?request_id=r-9", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("add: code=%d body=%q", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://admin.test/log/debug-targets", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "debug_target\trequest_id\tr-9\thits\t0\n") {
+		t.Fatalf("list: code=%d body=%q", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://admin.test/log/debug-targets/remove?request_id=r-9", nil))
+	if w.Code != http.StatusOK || len(targets.Snapshot()) != 0 {
+		t.Fatalf("remove: code=%d body=%q", w.Code, w.Body.String())
+	}
+	assertPanics(t, func() { _ = New(EnableDebugTargetsAdd(DebugTargetsAddSpec{Guard: AllowAll()})) })
+}
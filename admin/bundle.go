@@ -0,0 +1,299 @@
+package admin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime/pprof"
+	"sync/atomic"
+	"time"
+
+	"github.com/evan-idocoding/zkit/ops"
+)
+
+// BundleSpec configures the diagnostic bundle endpoint.
+type BundleSpec struct {
+	Guard Guard
+	Path  string // default "/debug/bundle"
+
+	// MaxBytes caps the uncompressed size of the bundle. Files that do not fit are skipped
+	// and listed as such in the manifest. <= 0 means default (64 MiB).
+	MaxBytes int64
+
+	// MaxCPUProfile caps the ?cpu= duration. 0 means default (30s); < 0 disables CPU profiles.
+	MaxCPUProfile time.Duration
+}
+
+const (
+	bundleDefaultMaxBytes      = 64 << 20
+	bundleDefaultMaxCPUProfile = 30 * time.Second
+)
+
+// EnableBundle mounts a write endpoint that captures an incident bundle and streams it
+// as a tar.gz download.
+//
+// The bundle contains:
+//   - report.txt / report.json: the rendered /report sections (everything /report would include,
+//     whether or not /report itself is enabled), from a single run: report.json carries the same
+//     section output as text
+//   - goroutines.txt: a full goroutine dump
+//   - heap.pb.gz / allocs.pb.gz: pprof profiles
+//   - cpu.pb.gz: a CPU profile, only when ?cpu=<duration> is given (e.g. ?cpu=10s)
+//   - buildinfo.json: build info including deps and settings
+//   - manifest.json: capture time, duration and size of every file
+//
+// Capturing a bundle briefly stops the world and may take seconds (CPU profile), so it is a
+// write capability (POST only). Only one capture runs at a time; concurrent requests get 409.
+func EnableBundle(spec BundleSpec) Option {
+	return func(b *Builder) {
+		requireBuilder(b)
+		requireGuard(spec.Guard, "bundle")
+		if b.bundle != nil {
+			panic("admin: bundle: EnableBundle called more than once")
+		}
+		s := spec
+		b.bundle = &s
+	}
+}
+
+func (b *Builder) assembleBundle() {
+	if b == nil || b.bundle == nil {
+		return
+	}
+	spec := *b.bundle
+	path := resolvePath(spec.Path, "/debug/bundle")
+	maxBytes := spec.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = bundleDefaultMaxBytes
+	}
+	maxCPU := spec.MaxCPUProfile
+	if maxCPU == 0 {
+		maxCPU = bundleDefaultMaxCPUProfile
+	}
+	timeout := b.reportTimeout()
+	sections := b.reportSections()
+
+	var busy atomic.Bool
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("admin: nil request")
+		}
+		asJSON := wantJSON(r)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeBundleError(w, asJSON, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		var cpu time.Duration
+		if v := r.URL.Query().Get("cpu"); v != "" {
+			d, err := time.ParseDuration(v)
+			switch {
+			case err != nil || d < 0:
+				writeBundleError(w, asJSON, http.StatusBadRequest, "invalid cpu (want a duration, e.g. 10s)")
+				return
+			case d > 0 && maxCPU < 0:
+				writeBundleError(w, asJSON, http.StatusBadRequest, "cpu profiles are disabled")
+				return
+			case d > maxCPU:
+				writeBundleError(w, asJSON, http.StatusBadRequest, "cpu exceeds max ("+maxCPU.String()+")")
+				return
+			}
+			cpu = d
+		}
+
+		if !busy.CompareAndSwap(false, true) {
+			writeBundleError(w, asJSON, http.StatusConflict, "bundle capture already in progress")
+			return
+		}
+		defer busy.Store(false)
+
+		bc := newBundleCapture(maxBytes)
+		bc.capture(r.Context(), sections, timeout, cpu)
+
+		name := "zkit-bundle-" + bc.started.UTC().Format("20060102T150405Z")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.tar.gz"`)
+		w.WriteHeader(http.StatusOK)
+		_ = bc.writeTarGz(w, name)
+	})
+
+	mountWrite(b, "bundle", path, spec.Guard, h)
+}
+
+type bundleResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+func writeBundleError(w http.ResponseWriter, asJSON bool, code int, msg string) {
+	w.Header().Set("Cache-Control", "no-store")
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(bundleResponse{OK: false, Error: msg})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(msg + "\n"))
+}
+
+// bundleManifest is manifest.json.
+type bundleManifest struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	// Duration is encoded as an integer number of nanoseconds in JSON.
+	Duration time.Duration `json:"duration"`
+	MaxBytes int64         `json:"max_bytes"`
+	Files    []bundleFile  `json:"files"`
+}
+
+type bundleFile struct {
+	Name       string    `json:"name"`
+	CapturedAt time.Time `json:"captured_at"`
+	// Duration is encoded as an integer number of nanoseconds in JSON.
+	Duration time.Duration `json:"duration"`
+	Bytes    int           `json:"bytes"`
+	Skipped  bool          `json:"skipped,omitempty"` // did not fit in MaxBytes
+	Error    string        `json:"error,omitempty"`
+
+	data []byte
+}
+
+type bundleCapture struct {
+	started  time.Time
+	maxBytes int64
+	used     int64
+	files    []bundleFile
+}
+
+func newBundleCapture(maxBytes int64) *bundleCapture {
+	return &bundleCapture{started: time.Now(), maxBytes: maxBytes}
+}
+
+func (c *bundleCapture) capture(ctx context.Context, sections []reportSection, timeout time.Duration, cpu time.Duration) {
+	c.add("buildinfo.json", func() ([]byte, error) {
+		bi, ok := ops.BuildInfoFull()
+		if !ok {
+			return nil, errors.New("build info not available")
+		}
+		return json.MarshalIndent(bi, "", "  ")
+	})
+	// One run for both files, so they describe the same moment.
+	var rep reportResponse
+	c.add("report.txt", func() ([]byte, error) {
+		rep = runReport(ctx, sections, timeout, false)
+		return []byte(renderReportText(rep, sections)), nil
+	})
+	c.add("report.json", func() ([]byte, error) {
+		if rep.Sections == nil {
+			rep.Sections = []reportSectionResult{}
+		}
+		return json.MarshalIndent(rep, "", "  ")
+	})
+	c.add("goroutines.txt", profileBytes("goroutine", 2))
+	c.add("heap.pb.gz", profileBytes("heap", 0))
+	c.add("allocs.pb.gz", profileBytes("allocs", 0))
+	if cpu > 0 {
+		c.add("cpu.pb.gz", func() ([]byte, error) { return cpuProfile(ctx, cpu) })
+	}
+}
+
+// add captures one file. Files are kept in memory (tar needs sizes up front) and dropped
+// once the bundle would exceed maxBytes.
+func (c *bundleCapture) add(name string, fn func() ([]byte, error)) {
+	f := bundleFile{Name: name, CapturedAt: time.Now()}
+	data, err := fn()
+	f.Duration = time.Since(f.CapturedAt)
+	if err != nil {
+		f.Error = err.Error()
+	}
+	if c.used+int64(len(data)) > c.maxBytes {
+		f.Skipped = true
+		f.Error = "skipped: exceeds bundle max bytes (" + itoa(len(data)) + " bytes)"
+		data = nil
+	}
+	f.Bytes = len(data)
+	f.data = data
+	c.used += int64(len(data))
+	c.files = append(c.files, f)
+}
+
+func (c *bundleCapture) writeTarGz(w http.ResponseWriter, dir string) error {
+	m := bundleManifest{
+		GeneratedAt: c.started,
+		Duration:    time.Since(c.started),
+		MaxBytes:    c.maxBytes,
+		Files:       c.files,
+	}
+	manifest, _ := json.MarshalIndent(m, "", "  ")
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	write := func(name string, at time.Time, data []byte) error {
+		hdr := &tar.Header{
+			Name:    dir + "/" + name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: at,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	for _, f := range c.files {
+		if f.Skipped || f.Error != "" {
+			continue
+		}
+		if err := write(f.Name, f.CapturedAt, f.data); err != nil {
+			return err
+		}
+	}
+	if err := write("manifest.json", c.started, manifest); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func profileBytes(name string, debug int) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		p := pprof.Lookup(name)
+		if p == nil {
+			return nil, errors.New("unknown profile " + name)
+		}
+		var buf bytes.Buffer
+		if err := p.WriteTo(&buf, debug); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+func cpuProfile(ctx context.Context, d time.Duration) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		// Typically another CPU profile (e.g. net/http/pprof) is running.
+		return nil, err
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+	pprof.StopCPUProfile()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CustomKind declares whether a custom endpoint is a read or a write capability.
+type CustomKind int
+
+const (
+	// CustomRead is a read capability: GET/HEAD only (other methods return 405).
+	CustomRead CustomKind = iota
+	// CustomWrite is a write capability: POST only (other methods return 405).
+	CustomWrite
+)
+
+func (k CustomKind) String() string {
+	switch k {
+	case CustomRead:
+		return "read"
+	case CustomWrite:
+		return "write"
+	default:
+		return "unknown"
+	}
+}
+
+// CustomSpec mounts an application-provided handler into the admin subtree.
+type CustomSpec struct {
+	Guard Guard
+	Path  string // required (no default)
+
+	// Name identifies the capability in the index and, when Reportable, names the /report section.
+	// Required; must not contain whitespace.
+	Name string
+
+	// Kind is CustomRead (default) or CustomWrite. admin enforces the matching HTTP methods,
+	// so a write handler is never reachable through a read guard by accident.
+	Kind CustomKind
+
+	Handler http.Handler
+
+	// Description is an optional one-line description shown in the index.
+	Description string
+
+	// Reportable adds the endpoint as a /report section (read endpoints only).
+	// The report calls the handler with GET and no query, or with ?format=json when the report
+	// itself is requested as JSON; output is capped like /provided.
+	Reportable bool
+}
+
+// EnableCustom mounts an application endpoint (e.g. cache flush, queue pause, feature dump)
+// under the admin subtree, protected by spec.Guard.
+//
+// Use a read guard for CustomRead and a write guard for CustomWrite. The endpoint is listed
+// by the index (EnableIndex) and, when Reportable, rendered as a /report section.
+func EnableCustom(spec CustomSpec) Option {
+	return func(b *Builder) {
+		requireBuilder(b)
+		name := strings.TrimSpace(spec.Name)
+		if name == "" || strings.ContainsAny(name, " \t\r\n") {
+			panic("admin: custom: invalid Name: " + spec.Name)
+		}
+		capName := "custom." + name
+		requireGuard(spec.Guard, capName)
+		if spec.Handler == nil {
+			panic("admin: " + capName + ": nil Handler")
+		}
+		if strings.TrimSpace(spec.Path) == "" {
+			panic("admin: " + capName + ": empty Path")
+		}
+		if _, dup := b.customNames[name]; dup || isBuiltinReportSection(name) {
+			panic("admin: " + capName + ": duplicated Name")
+		}
+		b.customNames[name] = struct{}{}
+
+		path := normalizePathOrPanic(spec.Path)
+		switch spec.Kind {
+		case CustomRead:
+			raw := methodsOnly(spec.Handler, http.MethodGet, http.MethodHead)
+			mountRead(b, capName, path, spec.Guard, raw)
+			if spec.Reportable {
+				b.reportState.custom = append(b.reportState.custom, namedReportSource{
+					name: name,
+					src:  reportSource{path: path, h: raw},
+				})
+			}
+		case CustomWrite:
+			if spec.Reportable {
+				panic("admin: " + capName + ": write endpoints cannot be Reportable")
+			}
+			mountWrite(b, capName, path, spec.Guard, methodsOnly(spec.Handler, http.MethodPost))
+		default:
+			panic("admin: " + capName + ": invalid Kind")
+		}
+		b.caps[len(b.caps)-1].Description = strings.TrimSpace(spec.Description)
+		b.caps[len(b.caps)-1].Custom = true
+	}
+}
+
+func isBuiltinReportSection(name string) bool {
+	switch name {
+	case "buildinfo", "runtime", "log.level", "log.levels", "tuning.snapshot", "tuning.overrides", "tasks.snapshot", "provided":
+		return true
+	default:
+		return false
+	}
+}
+
+// methodsOnly wraps h so that only the given methods reach it; others get 405 with an Allow header.
+func methodsOnly(h http.Handler, methods ...string) http.Handler {
+	allow := strings.Join(methods, ", ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, m := range methods {
+			if r.Method == m {
+				h.ServeHTTP(w, r)
+				return
+			}
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Allow", allow)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+}
@@ -72,28 +72,50 @@
 //
 // Default paths (relative to the mounted admin subtree):
 //
-// Report (human-oriented, GET/HEAD, text-only):
-//   - EnableReport:            "/report"
+// Report and index (GET/HEAD):
+//   - EnableReport:            "/report"   (?sections=a,b | ?exclude=a,b)
+//   - EnableIndex:             "/"         (lists every enabled capability; exact match only)
 //
 // Basic read endpoints (GET/HEAD):
 //   - EnableHealthz:           "/healthz"
-//   - EnableReadyz:            "/readyz"
+//   - EnableReadyz:            "/readyz"   (per-request Checks, or cached results of ReadyzSpec.Monitor)
 //   - EnableBuildInfo:         "/buildinfo"
 //   - EnableRuntime:           "/runtime"
+//   - EnableGoroutines:        "/goroutines"   (?func=&pkg=&state=&min_wait=)
 //   - EnableLogLevelGet:       "/log/level"
+//   - EnableLogLevels:         "/log/levels"   (per-component levels, slogx.Levels)
+//   - EnableLogTail:           "/log/tail"   (?n=&level=&since=&q=&request_id=&follow=1; slogx.Ring)
+//   - EnableDebugTargets:      "/log/debug-targets"   (request ids / client IPs logged at debug; httpx.DebugTargets)
 //   - EnableTuningSnapshot:    "/tuning/snapshot"
 //   - EnableTuningOverrides:   "/tuning/overrides"
 //   - EnableTuningLookup:      "/tuning/lookup"   (?key=)
+//   - EnableTuningHistory:     "/tuning/history"   (?key=; recent writes with cause and actor)
+//   - EnableTuningFlags:       "/tuning/flags"   (feature flags: spec and evaluation counters)
+//   - EnableTuningLayers:      "/tuning/layers"   (layer sources, e.g. tuning.FileSource / tuningremote.Source: sync status)
 //   - EnableTasksSnapshot:     "/tasks/snapshot"
-//   - EnableProvidedSnapshot:  "/provided"
+//   - EnableProvidedSnapshot:  "/provided"   (static values or ops.ProvidedFunc providers; sensitive keys redacted)
+//   - EnableLockoutSnapshot:   "/guard/lockouts"
+//   - EnableEvents:            "/events"   (SSE stream; ?types=a,b&prefix=)
 //
 // Write endpoints (POST):
 //   - EnableLogLevelSet:         "/log/level/set"          (?level=)
-//   - EnableTuningSet:           "/tuning/set"             (?key=&value=)
+//   - EnableLogLevelsSet:        "/log/levels/set"         (?name=&level=[&ttl=])
+//   - EnableLogLevelsReset:      "/log/levels/reset"       (?name=)
+//   - EnableDebugTargetsAdd:     "/log/debug-targets/add"  (?request_id= | ?ip=, [&ttl=])
+//   - EnableDebugTargetsRemove:  "/log/debug-targets/remove" (?request_id= | ?ip=)
+//   - EnableTuningSet:           "/tuning/set"             (?key=&value=[&ttl=])
 //   - EnableTuningResetDefault:  "/tuning/reset-default"   (?key=)
-//   - EnableTuningResetLast:     "/tuning/reset-last"      (?key=)
+//   - EnableTuningResetLast:     "/tuning/reset-last"      (?key=[&steps=])
+//   - EnableTuningApply:         "/tuning/apply"           (JSON body of overrides, all or nothing; [?dry_run=1])
 //   - EnableTaskTrigger:         "/tasks/trigger"          (?name=)
 //   - EnableTaskTriggerAndWait:  "/tasks/trigger-and-wait" (?name=&timeout=)
+//   - EnableLockoutClear:        "/guard/lockouts/clear"   (?ip= | ?global=true | ?all=true)
+//   - EnableRuntimeGC:           "/runtime/gc"
+//   - EnableRuntimeFreeOSMemory: "/runtime/free-os-memory"
+//   - EnableBundle:              "/debug/bundle"           (?cpu=10s; tar.gz download)
+//
+// Custom application endpoints (no default path):
+//   - EnableCustom:            CustomRead (GET/HEAD) or CustomWrite (POST); Path and Name are required.
 //
 // Notes on task write endpoints:
 //   - Task control is name-based: the admin/ops layer looks up tasks via task.Manager.Lookup.
@@ -123,11 +145,17 @@
 //   - Tokens / HotTokens (token from a header)
 //   - IPAllowList (client IP allowlist; integrates with WithRealIP)
 //   - TokensOrIPAllowList / TokensAndIPAllowList (token + IP composite guards)
+//   - ClientCerts / HotClientCerts (mTLS client certificate: CN, DNS/URI SANs, SHA-256 pins)
+//   - ClientCertsOrIPAllowList / ClientCertsAndIPAllowList / TokensOrClientCerts / TokensAndClientCerts
 //   - Check(fn) (custom fast predicate)
 //
 // Notes:
-//   - Static token/IP lists are fail-closed: empty/invalid inputs deny all.
+//   - Static token/IP/certificate lists are fail-closed: empty/invalid inputs deny all.
+//   - Certificate guards need TLS client auth on the server; the accepted identity is
+//     available to handlers via httpx.ClientCertIdentityFromRequest.
 //   - Token header can be customized via WithTokenHeader (applies to all token-based guards).
+//   - WithLockout(httpx.NewLockout(...)) adds brute-force protection to token-based guards:
+//     repeated failures lock the client IP out (429 + Retry-After, exponential and capped).
 //
 // # Real IP (for IP-based guards)
 //
@@ -143,14 +171,67 @@
 //
 // # Report (/report)
 //
-// The report endpoint outputs a human-oriented overview of *already enabled* read capabilities
-// (observation endpoints) in a single plain-text page.
+// The report endpoint outputs an overview of *already enabled* read capabilities
+// (observation endpoints) in a single response: a human-oriented plain-text page by default,
+// or ?format=json with one entry per section (parsed JSON payload, status, truncation flag,
+// timeout flag and capture duration).
 //
 // Design notes:
-//   - /report is text-only (no ?format= negotiation).
+//   - ?sections=runtime,tasks.snapshot renders only the listed sections; ?exclude=provided drops
+//     sections. Unknown section names return 400.
+//   - Each section is bounded by ReportSpec.SectionTimeout (default 5s). A section that overruns
+//     is reported as timed out and the report continues with the next one.
+//   - In JSON mode, sections that do not produce valid JSON (custom endpoints, truncated output)
+//     are returned as text.
 //   - /report includes only what is enabled in the same admin instance.
 //   - /report is guarded by its own Guard and does not attempt per-capability re-authorization.
 //   - The "provided" section is truncated to a conservative max size (reportProvidedMaxBytes).
+//   - Custom read endpoints with Reportable=true are appended as sections named after CustomSpec.Name,
+//     with the same size cap as "provided".
+//
+// # Custom endpoints
+//
+// EnableCustom mounts an application handler (cache flush, queue pause, feature dump, ...)
+// inside the guarded admin subtree:
+//
+//	admin.EnableCustom(admin.CustomSpec{
+//		Guard:   writeGuard,
+//		Path:    "/app/cache/flush",
+//		Name:    "cache.flush",
+//		Kind:    admin.CustomWrite,
+//		Handler: flushHandler,
+//	})
+//
+// admin enforces the methods of the declared Kind (GET/HEAD for reads, POST for writes), so
+// a write handler mounted with CustomWrite cannot be triggered by a GET. Custom endpoints are
+// listed by the index with a "custom." name prefix.
+//
+// # Diagnostic bundle (/debug/bundle)
+//
+// EnableBundle captures what is usually gathered by hand when escalating an incident and
+// streams it as one tar.gz: the /report sections (text and JSON), a goroutine dump, heap and
+// allocs profiles, an optional CPU profile (?cpu=10s, capped by MaxCPUProfile), full build info
+// and a manifest.json with per-file capture times and sizes. Files that would push the bundle
+// past MaxBytes are skipped and marked in the manifest. Only one capture runs at a time.
+//
+// # Events (/events)
+//
+// EnableEvents streams an ops.EventHub as Server-Sent Events: tuning changes, task runs,
+// log level changes, guard denials and service lifecycle transitions. Clients filter with
+// ?types=tuning,task (dotted prefixes) and ?prefix= (key prefix). Idle streams get heartbeat
+// comments; a client that falls behind its bounded buffer is dropped instead of slowing
+// publishers down.
+//
+// Once enabled, admin itself publishes guard.deny (any admin guard rejecting a request) and
+// log.level (EnableLogLevelSet). Tuning, component level and task events are wired by the caller
+// (EventHub.ObserveLogLevels for slogx.Levels):
+//
+//	hub := ops.NewEventHub()
+//	hub.ObserveTuning(tu)
+//	mgr := task.NewManager(
+//		task.WithManagerOnRunStart(hub.TaskRunStart),
+//		task.WithManagerOnRunFinish(hub.TaskRunFinish),
+//	)
 //
 // # Example: minimal admin
 //
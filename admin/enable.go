@@ -3,12 +3,15 @@ package admin
 import (
 	"context"
 	"log/slog"
+	"net/http"
 	"strings"
 	"time"
 
+	"github.com/evan-idocoding/zkit/httpx"
 	"github.com/evan-idocoding/zkit/ops"
 	"github.com/evan-idocoding/zkit/rt/task"
 	"github.com/evan-idocoding/zkit/rt/tuning"
+	"github.com/evan-idocoding/zkit/slogx"
 )
 
 // --- report ---
@@ -16,6 +19,14 @@ import (
 type ReportSpec struct {
 	Guard Guard
 	Path  string // default "/report"
+
+	// SectionTimeout bounds how long /report waits for each section.
+	// A section that exceeds it is reported as timed out and the report moves on; its handler
+	// keeps running in an abandoned goroutine (its context is canceled) and its output is
+	// discarded. Sections run one after another, so a report can take up to
+	// sections × SectionTimeout.
+	// 0 => default (5s); < 0 => no timeout.
+	SectionTimeout time.Duration
 }
 
 func EnableReport(spec ReportSpec) Option {
@@ -52,18 +63,33 @@ type ReadyCheck struct {
 	Name    string
 	Func    func(context.Context) error
 	Timeout time.Duration // <=0 means no extra timeout
+
+	// NonCritical failures degrade readiness (still 200) instead of failing it.
+	NonCritical bool
 }
 
 type ReadyzSpec struct {
 	Guard  Guard
 	Path   string // default "/readyz"
 	Checks []ReadyCheck
+
+	// Monitor, if set, serves cached results from a background-refreshed monitor instead of
+	// running Checks per request. The caller owns its lifecycle (Start / Shutdown).
+	// Monitor and Checks are mutually exclusive.
+	Monitor *ops.ReadyzMonitor
 }
 
 func EnableReadyz(spec ReadyzSpec) Option {
 	return func(b *Builder) {
 		requireGuard(spec.Guard, "readyz")
 		path := resolvePath(spec.Path, "/readyz")
+		if spec.Monitor != nil {
+			if len(spec.Checks) > 0 {
+				panic("admin: readyz: Monitor and Checks are mutually exclusive")
+			}
+			mountRead(b, "readyz", path, spec.Guard, ops.ReadyzMonitorHandler(spec.Monitor))
+			return
+		}
 		checks := make([]ops.ReadyCheck, 0, len(spec.Checks))
 		for i, c := range spec.Checks {
 			if c.Name == "" {
@@ -73,9 +99,10 @@ func EnableReadyz(spec ReadyzSpec) Option {
 				panic("admin: ready check[" + itoa(i) + "] " + c.Name + " has nil Func")
 			}
 			checks = append(checks, ops.ReadyCheck{
-				Name:    c.Name,
-				Func:    c.Func,
-				Timeout: c.Timeout,
+				Name:        c.Name,
+				Func:        c.Func,
+				Timeout:     c.Timeout,
+				NonCritical: c.NonCritical,
 			})
 		}
 		mountRead(b, "readyz", path, spec.Guard, ops.ReadyzHandler(checks))
@@ -124,6 +151,97 @@ func EnableRuntime(spec RuntimeSpec) Option {
 	}
 }
 
+type RuntimeGCSpec struct {
+	Guard Guard
+	Path  string // default "/runtime/gc"
+}
+
+// EnableRuntimeGC mounts a write action that forces a garbage collection.
+func EnableRuntimeGC(spec RuntimeGCSpec) Option {
+	return func(b *Builder) {
+		path := resolvePath(spec.Path, "/runtime/gc")
+		mountWrite(b, "runtime.gc", path, spec.Guard, ops.RuntimeGCHandler())
+	}
+}
+
+type RuntimeFreeOSMemorySpec struct {
+	Guard Guard
+	Path  string // default "/runtime/free-os-memory"
+}
+
+// EnableRuntimeFreeOSMemory mounts a write action that returns memory to the OS (debug.FreeOSMemory).
+func EnableRuntimeFreeOSMemory(spec RuntimeFreeOSMemorySpec) Option {
+	return func(b *Builder) {
+		path := resolvePath(spec.Path, "/runtime/free-os-memory")
+		mountWrite(b, "runtime.free-os-memory", path, spec.Guard, ops.RuntimeFreeOSMemoryHandler())
+	}
+}
+
+type GoroutinesSpec struct {
+	Guard Guard
+	Path  string // default "/goroutines"
+
+	// MaxBytes caps the response size (<= 0 => ops default, 1 MiB).
+	MaxBytes int
+}
+
+// EnableGoroutines mounts a grouped goroutine dump (?func=&pkg=&state=&min_wait=).
+//
+// Capturing stacks briefly stops the world, so it is not included in /report.
+func EnableGoroutines(spec GoroutinesSpec) Option {
+	return func(b *Builder) {
+		path := resolvePath(spec.Path, "/goroutines")
+		var opts []ops.GoroutinesOption
+		if spec.MaxBytes > 0 {
+			opts = append(opts, ops.WithGoroutinesMaxBytes(spec.MaxBytes))
+		}
+		mountRead(b, "goroutines", path, spec.Guard, ops.GoroutinesHandler(opts...))
+	}
+}
+
+// --- events ---
+
+type EventsSpec struct {
+	Guard Guard
+	Path  string // default "/events"
+	Hub   *ops.EventHub
+
+	// Heartbeat is the interval of SSE heartbeat comments. 0 means default (15s); < 0 disables them.
+	Heartbeat time.Duration
+	// Buffer is the per-client buffer (in events); slow clients are dropped. <= 0 means default (256).
+	Buffer int
+}
+
+// EnableEvents mounts a Server-Sent Events stream of Hub (?types=&prefix=).
+//
+// Once enabled, the admin subtree also publishes to Hub:
+//   - guard.deny for requests rejected by any admin guard
+//   - log.level for successful EnableLogLevelSet writes
+//
+// Other sources are wired by the caller: EventHub.ObserveTuning, EventHub.ObserveLogLevels,
+// task.WithManagerOnRunStart / WithManagerOnRunFinish with EventHub.TaskRunStart /
+// TaskRunFinish, and EventHub.Lifecycle.
+//
+// Streams are long-lived, so it is not included in /report.
+func EnableEvents(spec EventsSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "events")
+		if spec.Hub == nil {
+			panic("admin: events: nil EventHub")
+		}
+		if b.events != nil {
+			panic("admin: events: EnableEvents called more than once")
+		}
+		b.events = spec.Hub
+		path := resolvePath(spec.Path, "/events")
+		opts := []ops.EventsOption{ops.WithEventsBuffer(spec.Buffer)}
+		if spec.Heartbeat != 0 {
+			opts = append(opts, ops.WithEventsHeartbeat(spec.Heartbeat))
+		}
+		mountRead(b, "events", path, spec.Guard, ops.EventsHandler(spec.Hub, opts...))
+	}
+}
+
 // --- log level ---
 
 type LogLevelGetSpec struct {
@@ -158,7 +276,147 @@ func EnableLogLevelSet(spec LogLevelSetSpec) Option {
 			panic("admin: log.level.set: nil slog.LevelVar")
 		}
 		path := resolvePath(spec.Path, "/log/level/set")
-		mountWrite(b, "log.level.set", path, spec.Guard, ops.LogLevelSetHandler(spec.Var))
+		raw := ops.LogLevelSetHandler(spec.Var)
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			before := spec.Var.Level()
+			raw.ServeHTTP(w, r)
+			if after := spec.Var.Level(); after != before {
+				b.events.LogLevelChanged(before, after)
+			}
+		})
+		mountWrite(b, "log.level.set", path, spec.Guard, h)
+	}
+}
+
+// --- component log levels ---
+
+type LogLevelsSpec struct {
+	Guard  Guard
+	Path   string // default "/log/levels"
+	Levels *slogx.Levels
+}
+
+// EnableLogLevels mounts a read endpoint listing the per-component levels of Levels.
+func EnableLogLevels(spec LogLevelsSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "log.levels")
+		requireLevels(spec.Levels, "log.levels")
+		path := resolvePath(spec.Path, "/log/levels")
+		raw := ops.LogLevelsHandler(spec.Levels)
+		mountRead(b, "log.levels", path, spec.Guard, raw)
+		b.reportState.logLevels = reportSource{path: path, h: raw}
+	}
+}
+
+type LogLevelsSetSpec struct {
+	Guard  Guard
+	Path   string // default "/log/levels/set"
+	Levels *slogx.Levels
+}
+
+// EnableLogLevelsSet mounts a write endpoint that sets one component level
+// (?name=&level=[&ttl=]; a ttl reverts the level automatically).
+func EnableLogLevelsSet(spec LogLevelsSetSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "log.levels.set")
+		requireLevels(spec.Levels, "log.levels.set")
+		path := resolvePath(spec.Path, "/log/levels/set")
+		mountWrite(b, "log.levels.set", path, spec.Guard, ops.LogLevelsSetHandler(spec.Levels))
+	}
+}
+
+type LogLevelsResetSpec struct {
+	Guard  Guard
+	Path   string // default "/log/levels/reset"
+	Levels *slogx.Levels
+}
+
+// EnableLogLevelsReset mounts a write endpoint that restores one component's registration
+// level and cancels a pending ttl (?name=).
+func EnableLogLevelsReset(spec LogLevelsResetSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "log.levels.reset")
+		requireLevels(spec.Levels, "log.levels.reset")
+		path := resolvePath(spec.Path, "/log/levels/reset")
+		mountWrite(b, "log.levels.reset", path, spec.Guard, ops.LogLevelsResetHandler(spec.Levels))
+	}
+}
+
+type LogTailSpec struct {
+	Guard Guard
+	Path  string // default "/log/tail"
+	Ring  *slogx.Ring
+
+	// MaxRecords caps ?n= (<= 0 => ops default, 1000).
+	MaxRecords int
+}
+
+// EnableLogTail mounts a read endpoint serving the most recent records of Ring
+// (?n=&level=&since=&q=&request_id=, ?follow=1 to stream).
+//
+// Follow streams are long-lived, so it is not included in /report.
+func EnableLogTail(spec LogTailSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "log.tail")
+		if spec.Ring == nil {
+			panic("admin: log.tail: nil slogx.Ring")
+		}
+		path := resolvePath(spec.Path, "/log/tail")
+		h := ops.LogTailHandler(spec.Ring, ops.WithLogTailMaxRecords(spec.MaxRecords))
+		mountRead(b, "log.tail", path, spec.Guard, h)
+	}
+}
+
+// --- per-request debug logging ---
+
+type DebugTargetsSpec struct {
+	Guard   Guard
+	Path    string // default "/log/debug-targets"
+	Targets *httpx.DebugTargets
+}
+
+// EnableDebugTargets mounts a read endpoint listing the request ids and client IPs currently
+// registered for per-request debug logging (see httpx.DebugLog).
+func EnableDebugTargets(spec DebugTargetsSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "log.debug_targets")
+		requireDebugTargets(spec.Targets, "log.debug_targets")
+		path := resolvePath(spec.Path, "/log/debug-targets")
+		mountRead(b, "log.debug_targets", path, spec.Guard, ops.DebugTargetsHandler(spec.Targets))
+	}
+}
+
+type DebugTargetsAddSpec struct {
+	Guard   Guard
+	Path    string // default "/log/debug-targets/add"
+	Targets *httpx.DebugTargets
+}
+
+// EnableDebugTargetsAdd mounts a write endpoint that registers a request id or client IP
+// for debug logging (?request_id= | ?ip=, [&ttl=]). Registrations expire automatically.
+func EnableDebugTargetsAdd(spec DebugTargetsAddSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "log.debug_targets.add")
+		requireDebugTargets(spec.Targets, "log.debug_targets.add")
+		path := resolvePath(spec.Path, "/log/debug-targets/add")
+		mountWrite(b, "log.debug_targets.add", path, spec.Guard, ops.DebugTargetsAddHandler(spec.Targets))
+	}
+}
+
+type DebugTargetsRemoveSpec struct {
+	Guard   Guard
+	Path    string // default "/log/debug-targets/remove"
+	Targets *httpx.DebugTargets
+}
+
+// EnableDebugTargetsRemove mounts a write endpoint that unregisters a request id or client IP
+// (?request_id= | ?ip=).
+func EnableDebugTargetsRemove(spec DebugTargetsRemoveSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "log.debug_targets.remove")
+		requireDebugTargets(spec.Targets, "log.debug_targets.remove")
+		path := resolvePath(spec.Path, "/log/debug-targets/remove")
+		mountWrite(b, "log.debug_targets.remove", path, spec.Guard, ops.DebugTargetsRemoveHandler(spec.Targets))
 	}
 }
 
@@ -225,6 +483,57 @@ func EnableTuningLookup(spec TuningLookupSpec) Option {
 	}
 }
 
+type TuningHistorySpec struct {
+	Guard  Guard
+	Path   string // default "/tuning/history"
+	T      *tuning.Tuning
+	Access TuningAccessSpec // optional filter for reads
+}
+
+func EnableTuningHistory(spec TuningHistorySpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "tuning.history")
+		requireTuning(spec.T, "tuning.history")
+		path := resolvePath(spec.Path, "/tuning/history")
+		opts := tuningReadOptionsOrPanic(spec.Access)
+		mountRead(b, "tuning.history", path, spec.Guard, ops.TuningHistoryHandler(spec.T, opts...))
+	}
+}
+
+type TuningFlagsSpec struct {
+	Guard  Guard
+	Path   string // default "/tuning/flags"
+	T      *tuning.Tuning
+	Access TuningAccessSpec // optional filter for reads
+}
+
+func EnableTuningFlags(spec TuningFlagsSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "tuning.flags")
+		requireTuning(spec.T, "tuning.flags")
+		path := resolvePath(spec.Path, "/tuning/flags")
+		opts := tuningReadOptionsOrPanic(spec.Access)
+		mountRead(b, "tuning.flags", path, spec.Guard, ops.TuningFlagsHandler(spec.T, opts...))
+	}
+}
+
+type TuningLayersSpec struct {
+	Guard  Guard
+	Path   string // default "/tuning/layers"
+	T      *tuning.Tuning
+	Access TuningAccessSpec // optional filter for reads
+}
+
+func EnableTuningLayers(spec TuningLayersSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "tuning.layers")
+		requireTuning(spec.T, "tuning.layers")
+		path := resolvePath(spec.Path, "/tuning/layers")
+		opts := tuningReadOptionsOrPanic(spec.Access)
+		mountRead(b, "tuning.layers", path, spec.Guard, ops.TuningLayersHandler(spec.T, opts...))
+	}
+}
+
 type TuningSetSpec struct {
 	Guard  Guard
 	Path   string // default "/tuning/set"
@@ -276,6 +585,25 @@ func EnableTuningResetLast(spec TuningResetLastSpec) Option {
 	}
 }
 
+type TuningApplySpec struct {
+	Guard  Guard
+	Path   string // default "/tuning/apply"
+	T      *tuning.Tuning
+	Access TuningAccessSpec // required allowlist for writes; empty => deny-all; every key in a batch must pass
+}
+
+// EnableTuningApply mounts the batch write endpoint: a JSON array of overrides applied all at once
+// (or not at all); ?dry_run=1 returns the diff without applying.
+func EnableTuningApply(spec TuningApplySpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "tuning.apply")
+		requireTuning(spec.T, "tuning.apply")
+		path := resolvePath(spec.Path, "/tuning/apply")
+		opts := tuningWriteOptionsOrPanic(spec.Access)
+		mountWrite(b, "tuning.apply", path, spec.Guard, ops.TuningApplyHandler(spec.T, opts...))
+	}
+}
+
 // --- tasks ---
 
 type TaskAccessSpec struct {
@@ -359,8 +687,15 @@ type ProvidedSnapshotSpec struct {
 	Guard Guard
 	Path  string // default "/provided"
 
+	// Items values may be ops.ProvidedFunc providers, evaluated per request.
 	Items    map[string]any
 	MaxBytes int // optional; default conservative (aligned with ops)
+
+	// Timeout bounds each provider (0 => ops default, 2s; < 0 => no timeout).
+	Timeout time.Duration
+	// RedactKeys replaces the redacted key patterns (nil => ops.DefaultProvidedRedactKeys;
+	// empty non-nil => key-based redaction disabled). `zkit:"redact"` tags always apply.
+	RedactKeys []string
 }
 
 func EnableProvidedSnapshot(spec ProvidedSnapshotSpec) Option {
@@ -374,15 +709,58 @@ func EnableProvidedSnapshot(spec ProvidedSnapshotSpec) Option {
 		if spec.MaxBytes > 0 {
 			opts = append(opts, ops.WithProvidedSnapshotMaxBytes(spec.MaxBytes))
 		}
+		opts = append(opts, ops.WithProvidedSnapshotTimeout(spec.Timeout))
+		if spec.RedactKeys != nil {
+			opts = append(opts, ops.WithProvidedSnapshotRedactKeys(spec.RedactKeys...))
+		}
 		raw := ops.ProvidedSnapshotHandler(spec.Items, opts...)
 		mountRead(b, "provided.snapshot", path, spec.Guard, raw)
 		// /report truncates provided output on its own; disable max-bytes in the report view
 		// so a user-provided MaxBytes doesn't turn /report into "response too large".
-		reportH := ops.ProvidedSnapshotHandler(spec.Items, ops.WithProvidedSnapshotMaxBytes(0))
+		reportH := ops.ProvidedSnapshotHandler(spec.Items, append(opts, ops.WithProvidedSnapshotMaxBytes(0))...)
 		b.reportState.providedSnapshot = reportSource{path: path, h: reportH}
 	}
 }
 
+// --- guard lockouts ---
+
+type LockoutSnapshotSpec struct {
+	Guard   Guard
+	Path    string // default "/guard/lockouts"
+	Lockout *httpx.Lockout
+}
+
+// EnableLockoutSnapshot mounts a read endpoint listing locked-out client IPs and deny counts
+// by reason for a Lockout wired into guards via WithLockout.
+func EnableLockoutSnapshot(spec LockoutSnapshotSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "guard.lockouts")
+		if spec.Lockout == nil {
+			panic("admin: guard.lockouts: nil httpx.Lockout")
+		}
+		path := resolvePath(spec.Path, "/guard/lockouts")
+		mountRead(b, "guard.lockouts", path, spec.Guard, ops.LockoutSnapshotHandler(spec.Lockout))
+	}
+}
+
+type LockoutClearSpec struct {
+	Guard   Guard
+	Path    string // default "/guard/lockouts/clear"
+	Lockout *httpx.Lockout
+}
+
+// EnableLockoutClear mounts a write endpoint that clears lockouts (?ip=, ?global=true or ?all=true).
+func EnableLockoutClear(spec LockoutClearSpec) Option {
+	return func(b *Builder) {
+		requireGuard(spec.Guard, "guard.lockouts.clear")
+		if spec.Lockout == nil {
+			panic("admin: guard.lockouts.clear: nil httpx.Lockout")
+		}
+		path := resolvePath(spec.Path, "/guard/lockouts/clear")
+		mountWrite(b, "guard.lockouts.clear", path, spec.Guard, ops.LockoutClearHandler(spec.Lockout))
+	}
+}
+
 // --- helpers ---
 
 func requireBuilder(b *Builder) {
@@ -403,6 +781,18 @@ func requireTuning(t *tuning.Tuning, capName string) {
 	}
 }
 
+func requireLevels(l *slogx.Levels, capName string) {
+	if l == nil {
+		panic("admin: " + capName + ": nil slogx.Levels")
+	}
+}
+
+func requireDebugTargets(t *httpx.DebugTargets, capName string) {
+	if t == nil {
+		panic("admin: " + capName + ": nil httpx.DebugTargets")
+	}
+}
+
 // --- access (tuning/tasks allowlists) ---
 
 func tuningReadOptionsOrPanic(a TuningAccessSpec) []ops.TuningOption {
@@ -13,7 +13,7 @@ import (
 type Guard interface {
 	// Middleware returns a net/http middleware that enforces this guard.
 	//
-	// Denied requests must respond with HTTP 403.
+	// Denied requests must respond with HTTP 403 (429 with Retry-After when locked out, see WithLockout).
 	Middleware() func(http.Handler) http.Handler
 }
 
@@ -63,7 +63,8 @@ type TokenSetLike interface {
 type TokenOption func(*tokenConfig)
 
 type tokenConfig struct {
-	header string
+	header  string
+	lockout *httpx.Lockout
 }
 
 // WithTokenHeader overrides the token header name for token-based guards.
@@ -81,6 +82,31 @@ func WithTokenHeader(name string) TokenOption {
 	}
 }
 
+// WithLockout enables brute-force protection for token-based guards.
+//
+// Failed requests are tracked per client IP (and optionally globally) by l; offenders are
+// temporarily locked out and receive 429 with Retry-After. Share one Lockout between guards
+// (e.g. read and write) to track failures across the whole admin subtree, and expose it with
+// EnableLockoutSnapshot / EnableLockoutClear. A nil l is ignored.
+func WithLockout(l *httpx.Lockout) TokenOption {
+	return func(c *tokenConfig) {
+		if c == nil {
+			return
+		}
+		if l != nil {
+			c.lockout = l
+		}
+	}
+}
+
+func (c tokenConfig) guardOptions() []httpx.AccessGuardOption {
+	opts := []httpx.AccessGuardOption{httpx.WithTokenHeader(c.header)}
+	if c.lockout != nil {
+		opts = append(opts, httpx.WithLockout(c.lockout))
+	}
+	return opts
+}
+
 func applyTokenOptions(opts []TokenOption) tokenConfig {
 	cfg := tokenConfig{header: DefaultTokenHeader}
 	for _, opt := range opts {
@@ -101,10 +127,9 @@ func applyTokenOptions(opts []TokenOption) tokenConfig {
 //   - blank tokens are ignored; if none remain => deny-all
 func Tokens(tokens []string, opts ...TokenOption) Guard {
 	cfg := applyTokenOptions(opts)
-	return guardFunc{mw: httpx.AccessGuard(
-		httpx.WithTokenHeader(cfg.header),
+	return guardFunc{mw: httpx.AccessGuard(append(cfg.guardOptions(),
 		httpx.WithTokens(tokens),
-	)}
+	)...)}
 }
 
 // HotTokens returns a guard that validates requests using a hot-update token set.
@@ -115,10 +140,9 @@ func HotTokens(set TokenSetLike, opts ...TokenOption) Guard {
 		panic("admin: HotTokens: nil token set")
 	}
 	cfg := applyTokenOptions(opts)
-	return guardFunc{mw: httpx.AccessGuard(
-		httpx.WithTokenHeader(cfg.header),
+	return guardFunc{mw: httpx.AccessGuard(append(cfg.guardOptions(),
 		httpx.WithTokenSet(set),
-	)}
+	)...)}
 }
 
 // IPAllowList returns a guard backed by a static IP allowlist.
@@ -137,12 +161,11 @@ func IPAllowList(cidrsOrIPs ...string) Guard {
 // This is a thin wrapper around httpx.AccessGuard with WithOr().
 func TokensOrIPAllowList(tokens []string, cidrsOrIPs []string, opts ...TokenOption) Guard {
 	cfg := applyTokenOptions(opts)
-	return guardFunc{mw: httpx.AccessGuard(
-		httpx.WithTokenHeader(cfg.header),
+	return guardFunc{mw: httpx.AccessGuard(append(cfg.guardOptions(),
 		httpx.WithTokens(tokens),
 		httpx.WithIPAllowList(cidrsOrIPs),
 		httpx.WithOr(),
-	)}
+	)...)}
 }
 
 // HotTokensOrIPAllowList is like TokensOrIPAllowList, but token validation uses a hot-update set.
@@ -151,12 +174,11 @@ func HotTokensOrIPAllowList(set TokenSetLike, cidrsOrIPs []string, opts ...Token
 		panic("admin: HotTokensOrIPAllowList: nil token set")
 	}
 	cfg := applyTokenOptions(opts)
-	return guardFunc{mw: httpx.AccessGuard(
-		httpx.WithTokenHeader(cfg.header),
+	return guardFunc{mw: httpx.AccessGuard(append(cfg.guardOptions(),
 		httpx.WithTokenSet(set),
 		httpx.WithIPAllowList(cidrsOrIPs),
 		httpx.WithOr(),
-	)}
+	)...)}
 }
 
 // TokensAndIPAllowList returns a guard that allows a request when:
@@ -166,11 +188,10 @@ func HotTokensOrIPAllowList(set TokenSetLike, cidrsOrIPs []string, opts ...Token
 // This is a thin wrapper around httpx.AccessGuard (default AND semantics).
 func TokensAndIPAllowList(tokens []string, cidrsOrIPs []string, opts ...TokenOption) Guard {
 	cfg := applyTokenOptions(opts)
-	return guardFunc{mw: httpx.AccessGuard(
-		httpx.WithTokenHeader(cfg.header),
+	return guardFunc{mw: httpx.AccessGuard(append(cfg.guardOptions(),
 		httpx.WithTokens(tokens),
 		httpx.WithIPAllowList(cidrsOrIPs),
-	)}
+	)...)}
 }
 
 // HotTokensAndIPAllowList is like TokensAndIPAllowList, but token validation uses a hot-update set.
@@ -179,11 +200,10 @@ func HotTokensAndIPAllowList(set TokenSetLike, cidrsOrIPs []string, opts ...Toke
 		panic("admin: HotTokensAndIPAllowList: nil token set")
 	}
 	cfg := applyTokenOptions(opts)
-	return guardFunc{mw: httpx.AccessGuard(
-		httpx.WithTokenHeader(cfg.header),
+	return guardFunc{mw: httpx.AccessGuard(append(cfg.guardOptions(),
 		httpx.WithTokenSet(set),
 		httpx.WithIPAllowList(cidrsOrIPs),
-	)}
+	)...)}
 }
 
 // Check returns a guard backed by a custom fast predicate.
@@ -198,3 +218,81 @@ func Check(fn func(r *http.Request) bool) Guard {
 		httpx.WithCheck(fn),
 	)}
 }
+
+// ClientCertSetLike is a client certificate allow set used by certificate-based guards.
+//
+// See httpx.ClientCertSetLike; httpx.AtomicClientCertAllowList is the stock implementation.
+// Implementations must be safe for concurrent use.
+// The request path must be fast and must not block.
+type ClientCertSetLike = httpx.ClientCertSetLike
+
+// ClientCerts returns a guard backed by a static client certificate allowlist (mTLS).
+//
+// Entries use the httpx.AtomicClientCertAllowList syntax:
+// "cn:<name>", "dns:<name>", "uri:<uri>" (trailing "*" = prefix, e.g. SPIFFE IDs), "sha256:<hex>".
+// Empty/invalid inputs deny all (fail-closed).
+//
+// The server must request client certificates (tls.Config.ClientAuth); name-based
+// entries only match verified chains. The accepted identity is available to handlers
+// via httpx.ClientCertIdentityFromRequest.
+func ClientCerts(entries ...string) Guard {
+	return guardFunc{mw: httpx.AccessGuard(
+		httpx.WithClientCertAllowList(entries),
+	)}
+}
+
+// HotClientCerts returns a guard backed by a hot-update client certificate set.
+//
+// set must be non-nil (nil is an assembly error and will panic).
+func HotClientCerts(set ClientCertSetLike) Guard {
+	if set == nil {
+		panic("admin: HotClientCerts: nil client cert set")
+	}
+	return guardFunc{mw: httpx.AccessGuard(
+		httpx.WithClientCertSet(set),
+	)}
+}
+
+// ClientCertsOrIPAllowList returns a guard that allows a request when:
+//   - client certificate is allowed, OR
+//   - client IP is allowlisted.
+func ClientCertsOrIPAllowList(entries []string, cidrsOrIPs []string) Guard {
+	return guardFunc{mw: httpx.AccessGuard(
+		httpx.WithClientCertAllowList(entries),
+		httpx.WithIPAllowList(cidrsOrIPs),
+		httpx.WithOr(),
+	)}
+}
+
+// ClientCertsAndIPAllowList returns a guard that allows a request when:
+//   - client certificate is allowed, AND
+//   - client IP is allowlisted.
+func ClientCertsAndIPAllowList(entries []string, cidrsOrIPs []string) Guard {
+	return guardFunc{mw: httpx.AccessGuard(
+		httpx.WithClientCertAllowList(entries),
+		httpx.WithIPAllowList(cidrsOrIPs),
+	)}
+}
+
+// TokensOrClientCerts returns a guard that allows a request when:
+//   - token is allowed, OR
+//   - client certificate is allowed.
+func TokensOrClientCerts(tokens []string, entries []string, opts ...TokenOption) Guard {
+	cfg := applyTokenOptions(opts)
+	return guardFunc{mw: httpx.AccessGuard(append(cfg.guardOptions(),
+		httpx.WithTokens(tokens),
+		httpx.WithClientCertAllowList(entries),
+		httpx.WithOr(),
+	)...)}
+}
+
+// TokensAndClientCerts returns a guard that allows a request when:
+//   - token is allowed, AND
+//   - client certificate is allowed.
+func TokensAndClientCerts(tokens []string, entries []string, opts ...TokenOption) Guard {
+	cfg := applyTokenOptions(opts)
+	return guardFunc{mw: httpx.AccessGuard(append(cfg.guardOptions(),
+		httpx.WithTokens(tokens),
+		httpx.WithClientCertAllowList(entries),
+	)...)}
+}
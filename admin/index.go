@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// capabilityInfo is the index entry for one mounted capability.
+type capabilityInfo struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Kind        string `json:"kind"`    // "read" | "write"
+	Methods     string `json:"methods"` // e.g. "GET, HEAD"
+	Description string `json:"description,omitempty"`
+	Custom      bool   `json:"custom,omitempty"`
+}
+
+type IndexSpec struct {
+	Guard Guard
+	Path  string // default "/"
+}
+
+// EnableIndex mounts a capability index that lists every enabled capability
+// (name, kind, path, methods), including custom endpoints and /report.
+//
+// The default path "/" only matches the subtree root exactly; unknown paths still return 404.
+func EnableIndex(spec IndexSpec) Option {
+	return func(b *Builder) {
+		requireBuilder(b)
+		requireGuard(spec.Guard, "index")
+		if b.index != nil {
+			panic("admin: EnableIndex called more than once")
+		}
+		if spec.Path == "" {
+			spec.Path = "/"
+		}
+		spec.Path = normalizePathOrPanic(spec.Path)
+		b.index = &spec
+	}
+}
+
+type indexResponse struct {
+	OK           bool             `json:"ok"`
+	Capabilities []capabilityInfo `json:"capabilities"`
+}
+
+func (b *Builder) assembleIndex() {
+	if b == nil || b.index == nil {
+		return
+	}
+	spec := *b.index
+	path := spec.Path
+
+	// Register the index itself first so it is listed too.
+	b.describe(capabilityInfo{Name: "index", Path: path, Kind: "read", Methods: "GET, HEAD"})
+	caps := append([]capabilityInfo(nil), b.caps...)
+
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("admin: nil request")
+		}
+		// "/" is a ServeMux catch-all; keep unknown paths as 404.
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if wantJSON(r) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			if r.Method == http.MethodHead {
+				return
+			}
+			_ = json.NewEncoder(w).Encode(indexResponse{OK: true, Capabilities: caps})
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write([]byte(renderIndexText(caps)))
+	})
+
+	h = b.guard("index", spec.Guard, h)
+	b.register(path, h)
+}
+
+func renderIndexText(caps []capabilityInfo) string {
+	// Stable and greppable:
+	//   capability\t<name>\t<kind>\t<path>\t<methods>[\t<description>]\n
+	var out strings.Builder
+	out.Grow(64 * len(caps))
+	for _, c := range caps {
+		out.WriteString("capability\t")
+		out.WriteString(c.Name)
+		out.WriteByte('\t')
+		out.WriteString(c.Kind)
+		out.WriteByte('\t')
+		out.WriteString(c.Path)
+		out.WriteByte('\t')
+		out.WriteString(c.Methods)
+		if c.Description != "" {
+			out.WriteByte('\t')
+			out.WriteString(strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(c.Description))
+		}
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"strings"
@@ -10,6 +11,11 @@ import (
 
 func itoa(i int) string { return strconv.Itoa(i) }
 
+// wantJSON reports whether the request asks for JSON output (?format=json), aligned with ops.
+func wantJSON(r *http.Request) bool {
+	return r != nil && r.URL != nil && r.URL.Query().Get("format") == "json"
+}
+
 func resolvePath(specPath, def string) string {
 	if strings.TrimSpace(specPath) == "" {
 		return def
@@ -62,8 +68,9 @@ func mountRead(b *Builder, name, path string, g Guard, h http.Handler) http.Hand
 	if h == nil {
 		panic("admin: " + name + ": nil handler")
 	}
-	h = g.Middleware()(h)
+	h = b.guard(name, g, h)
 	b.register(path, h)
+	b.describe(capabilityInfo{Name: name, Path: path, Kind: "read", Methods: "GET, HEAD"})
 	return h
 }
 
@@ -77,7 +84,60 @@ func mountWrite(b *Builder, name, path string, g Guard, h http.Handler) http.Han
 	if h == nil {
 		panic("admin: " + name + ": nil handler")
 	}
-	h = g.Middleware()(h)
+	h = b.guard(name, g, h)
 	b.register(path, h)
+	b.describe(capabilityInfo{Name: name, Path: path, Kind: "write", Methods: "POST"})
 	return h
 }
+
+// describe records capability metadata for the index endpoint.
+func (b *Builder) describe(c capabilityInfo) {
+	c.Path = normalizePathOrPanic(c.Path)
+	b.caps = append(b.caps, c)
+}
+
+// guard wraps h with g. When an event hub is attached (EnableEvents), requests
+// rejected by g are published as guard.deny events.
+//
+// The hub is looked up per request, so EnableEvents may come after other options.
+func (b *Builder) guard(name string, g Guard, h http.Handler) http.Handler {
+	passed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if st, ok := r.Context().Value(guardStateKey{}).(*guardState); ok {
+			st.passed = true
+			w = st.w // the original writer: keep http.Flusher etc. for streaming handlers
+		}
+		h.ServeHTTP(w, r)
+	})
+	guarded := g.Middleware()(passed)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub := b.events
+		if hub == nil || r == nil {
+			guarded.ServeHTTP(w, r)
+			return
+		}
+		st := &guardState{w: w, status: http.StatusOK}
+		guarded.ServeHTTP(&guardRecorder{ResponseWriter: w, st: st}, r.WithContext(context.WithValue(r.Context(), guardStateKey{}, st)))
+		if !st.passed && st.status >= 400 {
+			hub.GuardDenied(r, name, st.status)
+		}
+	})
+}
+
+type guardStateKey struct{}
+
+type guardState struct {
+	w      http.ResponseWriter
+	passed bool
+	status int
+}
+
+// guardRecorder captures the status written by a guard that rejects a request.
+type guardRecorder struct {
+	http.ResponseWriter
+	st *guardState
+}
+
+func (g *guardRecorder) WriteHeader(code int) {
+	g.st.status = code
+	g.ResponseWriter.WriteHeader(code)
+}
@@ -3,8 +3,11 @@ package admin
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -24,16 +27,26 @@ type reportState struct {
 	buildInfo        reportSource
 	runtime          reportSource
 	logLevelGet      reportSource
+	logLevels        reportSource
 	tuningSnapshot   reportSource
 	tuningOverrides  reportSource
 	tasksSnapshot    reportSource
 	providedSnapshot reportSource
+
+	// Reportable custom endpoints (EnableCustom), in enable order.
+	custom []namedReportSource
+}
+
+type namedReportSource struct {
+	name string
+	src  reportSource
 }
 
 type reportSection struct {
 	name  string
 	src   reportSource
-	limit int // 0 => no limit
+	limit int    // 0 => no limit
+	note  string // optional note rendered under the section header
 }
 
 func (s reportSource) enabled() bool { return s.h != nil }
@@ -49,127 +62,363 @@ func (b *Builder) assembleReport() {
 		path = "/report"
 	}
 	path = normalizePathOrPanic(path)
-
-	sections := make([]reportSection, 0, 8)
-	add := func(name string, src reportSource, limit int) {
-		if !src.enabled() {
-			return
-		}
-		sections = append(sections, reportSection{name: name, src: src, limit: limit})
-	}
-
-	// Stable order. Keep it human-oriented.
-	add("buildinfo", b.reportState.buildInfo, 0)
-	add("runtime", b.reportState.runtime, 0)
-	add("log.level", b.reportState.logLevelGet, 0)
-	add("tuning.snapshot", b.reportState.tuningSnapshot, 0)
-	add("tuning.overrides", b.reportState.tuningOverrides, 0)
-	add("tasks.snapshot", b.reportState.tasksSnapshot, 0)
-	add("provided", b.reportState.providedSnapshot, reportProvidedMaxBytes)
+	timeout := b.reportTimeout()
+	sections := b.reportSections()
 
 	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r == nil {
 			panic("admin: nil request")
 		}
-		// Align with ops: avoid caching operational responses.
-		w.Header().Set("Cache-Control", "no-store")
+		asJSON := wantJSON(r)
 		if r.Method != http.MethodGet && r.Method != http.MethodHead {
 			w.Header().Set("Allow", "GET, HEAD")
-			w.WriteHeader(http.StatusMethodNotAllowed)
+			writeReport(w, r, asJSON, http.StatusMethodNotAllowed, reportResponse{Error: "method not allowed"}, sections)
 			return
 		}
 
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		selected, err := selectReportSections(sections, r.URL.Query())
+		if err != "" {
+			writeReport(w, r, asJSON, http.StatusBadRequest, reportResponse{Error: err}, sections)
+			return
+		}
 		// For HEAD, avoid doing any work.
 		if r.Method == http.MethodHead {
-			w.WriteHeader(http.StatusOK)
+			writeReport(w, r, asJSON, http.StatusOK, reportResponse{OK: true}, sections)
 			return
 		}
 
-		_, body := renderReport(r.Context(), sections)
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(body))
+		rep := runReport(r.Context(), selected, timeout, asJSON)
+		rep.filtered = len(selected) != len(sections)
+		writeReport(w, r, asJSON, http.StatusOK, rep, sections)
 	})
 
-	h = spec.Guard.Middleware()(h)
+	h = b.guard("report", spec.Guard, h)
 	b.register(path, h)
+	b.describe(capabilityInfo{Name: "report", Path: path, Kind: "read", Methods: "GET, HEAD"})
 }
 
-func renderReport(ctx context.Context, sections []reportSection) (ok bool, body string) {
-	now := time.Now()
+// reportTimeout returns the per-section timeout (ReportSpec.SectionTimeout, or the default).
+func (b *Builder) reportTimeout() time.Duration {
+	if b.report != nil && b.report.SectionTimeout != 0 {
+		return b.report.SectionTimeout
+	}
+	return reportDefaultSectionTimeout
+}
 
-	enabledNames := make([]string, 0, len(sections))
-	for _, s := range sections {
-		enabledNames = append(enabledNames, s.name)
+// reportSections returns the enabled report sections in their stable order.
+func (b *Builder) reportSections() []reportSection {
+	sections := make([]reportSection, 0, 8)
+	add := func(name string, src reportSource, limit int, note string) {
+		if !src.enabled() {
+			return
+		}
+		sections = append(sections, reportSection{name: name, src: src, limit: limit, note: note})
 	}
 
-	// Render sections first so we can decide the top-level status line.
-	ok = true
-	var out strings.Builder
-	out.Grow(4096)
+	// Stable order. Keep it human-oriented.
+	add("buildinfo", b.reportState.buildInfo, 0, "")
+	add("runtime", b.reportState.runtime, 0, "")
+	add("log.level", b.reportState.logLevelGet, 0, "")
+	add("log.levels", b.reportState.logLevels, 0, "")
+	add("tuning.snapshot", b.reportState.tuningSnapshot, 0, "")
+	add("tuning.overrides", b.reportState.tuningOverrides, 0, "")
+	add("tasks.snapshot", b.reportState.tasksSnapshot, 0, "")
+	add("provided", b.reportState.providedSnapshot, reportProvidedMaxBytes,
+		"below are user-provided snapshots (not built-in report sections)")
+	for _, c := range b.reportState.custom {
+		add(c.name, c.src, reportProvidedMaxBytes, "application endpoint "+c.src.path+" (not a built-in report section)")
+	}
+	return sections
+}
+
+// reportDefaultSectionTimeout is the per-section timeout used when ReportSpec.SectionTimeout is 0.
+const reportDefaultSectionTimeout = 5 * time.Second
+
+type reportResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+	// Duration is encoded as an integer number of nanoseconds in JSON.
+	Duration time.Duration         `json:"duration"`
+	Sections []reportSectionResult `json:"sections"`
+
+	filtered bool // a ?sections= / ?exclude= filter was applied (text header only)
+}
+
+// reportSectionResult is the captured output of one /report section.
+type reportSectionResult struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Status int    `json:"status"`
+	Note   string `json:"note,omitempty"`
+	// Duration is encoded as an integer number of nanoseconds in JSON.
+	Duration  time.Duration `json:"duration"`
+	Truncated bool          `json:"truncated,omitempty"`
+	TimedOut  bool          `json:"timed_out,omitempty"`
+	Error     string        `json:"error,omitempty"`
+
+	// Data is the section's JSON payload. Sections that do not produce valid JSON
+	// (custom endpoints, truncated output) are returned as Text instead.
+	Data json.RawMessage `json:"data,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+// selectReportSections applies ?sections= (include) and ?exclude= (comma-separated names).
+// Unknown names are rejected so typos do not silently produce an empty report.
+func selectReportSections(all []reportSection, q url.Values) (out []reportSection, errMsg string) {
+	known := make(map[string]bool, len(all))
+	for _, s := range all {
+		known[s.name] = true
+	}
+	parse := func(key string) (map[string]bool, string) {
+		raw, ok := q[key]
+		if !ok {
+			return nil, ""
+		}
+		set := make(map[string]bool)
+		for _, v := range raw {
+			for _, name := range strings.Split(v, ",") {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					continue
+				}
+				if !known[name] {
+					return nil, "unknown section: " + name
+				}
+				set[name] = true
+			}
+		}
+		return set, ""
+	}
+	include, errMsg := parse("sections")
+	if errMsg != "" {
+		return nil, errMsg
+	}
+	exclude, errMsg := parse("exclude")
+	if errMsg != "" {
+		return nil, errMsg
+	}
+	out = make([]reportSection, 0, len(all))
+	for _, s := range all {
+		if include != nil && !include[s.name] {
+			continue
+		}
+		if exclude[s.name] {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out, ""
+}
+
+func runReport(ctx context.Context, sections []reportSection, timeout time.Duration, asJSON bool) reportResponse {
+	start := time.Now()
+	rep := reportResponse{
+		OK:          true,
+		GeneratedAt: start,
+		Sections:    make([]reportSectionResult, 0, len(sections)),
+	}
+	for _, sec := range sections {
+		res := captureSection(ctx, sec, timeout, asJSON)
+		if !res.OK {
+			rep.OK = false
+		}
+		rep.Sections = append(rep.Sections, res)
+	}
+	rep.Duration = time.Since(start)
+	if !rep.OK {
+		rep.Error = "one or more sections failed"
+	}
+	return rep
+}
+
+// captureSection runs one section handler. It never waits longer than timeout (< 0 => no timeout):
+// a section that overruns is abandoned and its output discarded.
+func captureSection(parent context.Context, sec reportSection, timeout time.Duration, asJSON bool) (res reportSectionResult) {
+	res.Name = sec.name
+	res.Note = sec.note
+
+	start := time.Now()
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, timeout)
+	}
+	defer cancel()
+
+	path := sec.src.path
+	if path == "" {
+		path = "/"
+	}
+	target := "http://admin.report.invalid" + path
+	if asJSON {
+		target += "?format=json"
+	}
+	req := httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+
+	type captured struct {
+		rw    *textCapture
+		panic any
+	}
+	done := make(chan captured, 1)
+	go func() {
+		rw := newTextCapture(sec.limit)
+		defer func() {
+			done <- captured{rw: rw, panic: recover()}
+		}()
+		sec.src.h.ServeHTTP(rw, req)
+	}()
+
+	var c captured
+	select {
+	case c = <-done:
+	case <-ctx.Done():
+		res.Duration = time.Since(start)
+		res.Status = http.StatusGatewayTimeout
+		if ctx.Err() == context.DeadlineExceeded {
+			res.TimedOut = true
+			res.Error = "timed out after " + timeout.String()
+		} else {
+			res.Error = ctx.Err().Error()
+		}
+		return res
+	}
+	res.Duration = time.Since(start)
+
+	if c.panic != nil {
+		res.Status = http.StatusInternalServerError
+		res.Error = fmt.Sprintf("panic: %v", c.panic)
+		return res
+	}
+
+	res.Status = c.rw.status
+	if res.Status == 0 {
+		res.Status = http.StatusOK
+	}
+	res.Truncated = c.rw.truncated
+	res.OK = res.Status >= 200 && res.Status < 300
+	if !res.OK {
+		res.Error = "status " + itoa(res.Status)
+	}
+
+	// Ignore any Content-Type produced by the sub-handler; decide by content.
+	body := c.rw.buf.Bytes()
+	if asJSON && !res.Truncated && json.Valid(body) {
+		res.Data = json.RawMessage(append([]byte(nil), bytes.TrimSpace(body)...))
+	} else {
+		res.Text = string(body)
+	}
+	return res
+}
+
+func writeReport(w http.ResponseWriter, r *http.Request, asJSON bool, code int, rep reportResponse, enabled []reportSection) {
+	// Align with ops: avoid caching operational responses.
+	w.Header().Set("Cache-Control", "no-store")
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if rep.Sections == nil {
+			rep.Sections = []reportSectionResult{}
+		}
+		_ = json.NewEncoder(w).Encode(rep)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if code != http.StatusOK {
+		_, _ = w.Write([]byte(rep.Error + "\n"))
+		return
+	}
+	_, _ = w.Write([]byte(renderReportText(rep, enabled)))
+}
 
+func renderReportText(rep reportResponse, enabled []reportSection) string {
 	const (
 		sectionHeaderPrefix = "=== "
 		sectionHeaderSuffix = " ===\n"
 		indentPrefix        = "| "
 	)
 
-	for _, sec := range sections {
+	var out strings.Builder
+	out.Grow(4096)
+	if rep.OK {
+		out.WriteString("ok\n")
+	} else {
+		out.WriteString("error: one or more sections failed\n")
+	}
+	out.WriteString("generated_at: ")
+	out.WriteString(rep.GeneratedAt.Format(time.RFC3339Nano))
+	out.WriteByte('\n')
+
+	enabledNames := make([]string, 0, len(enabled))
+	for _, s := range enabled {
+		enabledNames = append(enabledNames, s.name)
+	}
+	if len(enabledNames) == 0 {
+		out.WriteString("enabled sections: (none)\n")
+	} else {
+		out.WriteString("enabled sections: ")
+		out.WriteString(strings.Join(enabledNames, ", "))
+		out.WriteByte('\n')
+	}
+	if rep.filtered {
+		names := make([]string, 0, len(rep.Sections))
+		for _, s := range rep.Sections {
+			names = append(names, s.Name)
+		}
+		if len(names) == 0 {
+			out.WriteString("selected sections: (none)\n")
+		} else {
+			out.WriteString("selected sections: ")
+			out.WriteString(strings.Join(names, ", "))
+			out.WriteByte('\n')
+		}
+	}
+
+	for _, sec := range rep.Sections {
+		// Keep a blank line between the header and each section.
 		out.WriteByte('\n')
 		out.WriteString(sectionHeaderPrefix)
-		out.WriteString(sec.name)
+		out.WriteString(sec.Name)
 		out.WriteString(sectionHeaderSuffix)
 
-		if sec.name == "provided" {
+		if sec.Note != "" {
 			out.WriteString(indentPrefix)
-			out.WriteString("note: below are user-provided snapshots (not built-in report sections)\n")
+			out.WriteString("note: ")
+			out.WriteString(sec.Note)
+			out.WriteByte('\n')
 		}
-
-		code, text, truncated := callHandlerTextCaptured(ctx, sec.src, sec.limit)
-		if code < 200 || code >= 300 {
-			ok = false
+		if sec.Error != "" {
 			out.WriteString(indentPrefix)
-			out.WriteString("error: status ")
-			out.WriteString(itoa(code))
+			out.WriteString("error: ")
+			out.WriteString(sec.Error)
 			out.WriteByte('\n')
 		}
-		if text == "" {
+		if sec.TimedOut {
+			continue
+		}
+		if sec.Text == "" {
 			out.WriteString(indentPrefix)
 			out.WriteString("(empty)\n")
 		} else {
-			appendIndented(&out, text, indentPrefix)
-			if text[len(text)-1] != '\n' {
+			appendIndented(&out, sec.Text, indentPrefix)
+			if sec.Text[len(sec.Text)-1] != '\n' {
 				out.WriteByte('\n')
 			}
 		}
-		if truncated {
+		if sec.Truncated {
 			out.WriteString(indentPrefix)
 			out.WriteString("(truncated)\n")
 		}
 	}
-
-	var header strings.Builder
-	header.Grow(256)
-	if ok {
-		header.WriteString("ok\n")
-	} else {
-		header.WriteString("error: one or more sections failed\n")
-	}
-	header.WriteString("generated_at: ")
-	header.WriteString(now.Format(time.RFC3339Nano))
-	header.WriteByte('\n')
-	if len(enabledNames) == 0 {
-		header.WriteString("enabled sections: (none)\n")
-	} else {
-		header.WriteString("enabled sections: ")
-		header.WriteString(strings.Join(enabledNames, ", "))
-		header.WriteByte('\n')
-	}
-
-	// Ensure a blank line between header and first section.
-	header.WriteByte('\n')
-	header.WriteString(out.String())
-	return ok, header.String()
+	return out.String()
 }
 
 func appendIndented(b *strings.Builder, s string, prefix string) {
@@ -197,33 +446,6 @@ func appendIndented(b *strings.Builder, s string, prefix string) {
 	}
 }
 
-func callHandlerTextCaptured(ctx context.Context, src reportSource, limit int) (status int, text string, truncated bool) {
-	if src.h == nil {
-		return http.StatusInternalServerError, "nil handler\n", false
-	}
-
-	path := src.path
-	if path == "" {
-		path = "/"
-	}
-
-	req := httptest.NewRequest(http.MethodGet, "http://admin.report.invalid"+path, nil).WithContext(ctx)
-
-	rw := newTextCapture(limit)
-	src.h.ServeHTTP(rw, req)
-	status = rw.status
-	if status == 0 {
-		status = http.StatusOK
-	}
-
-	// Ignore any Content-Type produced by the sub-handler; /report is text-only.
-	b := rw.buf.Bytes()
-	if len(b) == 0 {
-		return status, "", rw.truncated
-	}
-	return status, string(b), rw.truncated
-}
-
 type textCapture struct {
 	hdr       http.Header
 	status    int
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/tabwriter"
+
+	"github.com/evan-idocoding/zkit/httpx/client"
+)
+
+// maxBodyBytes caps how much of a response zkitctl reads.
+const maxBodyBytes = 16 << 20 // 16 MiB
+
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+)
+
+// api issues admin requests for one resolved target.
+type api struct {
+	t      target
+	hc     *http.Client
+	format outputFormat
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func newAPI(t target, format outputFormat, stdout, stderr io.Writer) *api {
+	mws := []client.Middleware{client.SetHeader("User-Agent", "zkitctl")}
+	if t.token != "" {
+		mws = append(mws, client.SetHeader(t.tokenHeader, t.token))
+	}
+	return &api{
+		t:      t,
+		hc:     client.New(client.WithTimeout(t.timeout), client.WithMiddlewares(mws...)),
+		format: format,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// response is a fully read admin response.
+type response struct {
+	method string
+	path   string
+	status int
+	body   []byte
+}
+
+func (r response) httpOK() bool { return r.status >= 200 && r.status < 300 }
+
+// ok reports whether the response is successful: 2xx and, for JSON bodies, not {"ok": false}.
+func (r response) ok() bool {
+	if !r.httpOK() {
+		return false
+	}
+	var v struct {
+		OK *bool `json:"ok"`
+	}
+	if json.Unmarshal(r.body, &v) == nil && v.OK != nil {
+		return *v.OK
+	}
+	return true
+}
+
+// do sends one request without a body. The output format is passed to the server via ?format=.
+func (a *api) do(ctx context.Context, method, path string, q url.Values) (response, error) {
+	return a.doJSON(ctx, method, path, q, nil)
+}
+
+// doJSON is like do, but sends body (if non-nil) as application/json.
+func (a *api) doJSON(ctx context.Context, method, path string, q url.Values, body []byte) (response, error) {
+	if q == nil {
+		q = url.Values{}
+	}
+	if a.format == outputJSON {
+		q.Set("format", "json")
+	} else {
+		q.Set("format", "text")
+	}
+	u := a.t.base + path + "?" + q.Encode()
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return response{}, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := a.hc.Do(req)
+	if err != nil {
+		return response{}, err
+	}
+	respBody, err := client.ReadAllAndCloseLimit(resp.Body, maxBodyBytes)
+	if err != nil {
+		return response{}, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return response{method: method, path: path, status: resp.StatusCode, body: respBody}, nil
+}
+
+// print writes the response body in the selected format.
+//
+// Text bodies from ops handlers are tab-separated; in table mode they are aligned.
+// raw disables alignment (for free-form text such as /report). Text bodies of non-2xx
+// responses are error messages and go to stderr; JSON always goes to stdout.
+func (a *api) print(r response, raw bool) {
+	out := a.stdout
+	if !r.httpOK() && a.format != outputJSON {
+		out = a.stderr
+	}
+	switch {
+	case len(r.body) == 0:
+	case a.format == outputJSON:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, r.body, "", "  "); err != nil {
+			_, _ = out.Write(r.body)
+			return
+		}
+		buf.WriteByte('\n')
+		_, _ = out.Write(buf.Bytes())
+	case raw || !bytes.Contains(r.body, []byte{'\t'}):
+		_, _ = out.Write(r.body)
+	default:
+		tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		_, _ = tw.Write(r.body)
+		_ = tw.Flush()
+	}
+}
+
+// finish prints r and converts it to an exit code (0 ok, 1 non-OK).
+//
+// Non-OK responses are also summarized on stderr so scripts see why they failed.
+func (a *api) finish(r response, raw bool) int {
+	a.print(r, raw)
+	if r.ok() {
+		return exitOK
+	}
+	fmt.Fprintf(a.stderr, "zkitctl: %s %s: %d %s\n", r.method, r.path, r.status, http.StatusText(r.status))
+	return exitNotOK
+}
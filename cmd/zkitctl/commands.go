@@ -0,0 +1,475 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+// cli dispatches subcommands against one admin target.
+type cli struct {
+	api   *api
+	stdin io.Reader
+}
+
+func (c *cli) dispatch(ctx context.Context, args []string) int {
+	cmd, rest := args[0], args[1:]
+	switch cmd {
+	case "report":
+		return c.report(ctx, rest)
+	case "runtime", "buildinfo", "readyz":
+		if len(rest) != 0 {
+			return c.usage(cmd + " takes no arguments")
+		}
+		return c.get(ctx, "/"+cmd, nil)
+	case "goroutines":
+		return c.goroutines(ctx, rest)
+	case "tuning":
+		return c.tuning(ctx, rest)
+	case "tasks":
+		return c.tasks(ctx, rest)
+	case "log":
+		return c.log(ctx, rest)
+	default:
+		return c.usage("unknown command " + cmd)
+	}
+}
+
+func (c *cli) usage(msg string) int {
+	fmt.Fprintf(c.api.stderr, "zkitctl: %s (see zkitctl -h)\n", msg)
+	return exitUsage
+}
+
+func (c *cli) get(ctx context.Context, path string, q url.Values) int {
+	return c.call(ctx, http.MethodGet, path, q)
+}
+
+func (c *cli) post(ctx context.Context, path string, q url.Values) int {
+	return c.call(ctx, http.MethodPost, path, q)
+}
+
+func (c *cli) call(ctx context.Context, method, path string, q url.Values) int {
+	r, err := c.api.do(ctx, method, path, q)
+	if err != nil {
+		fmt.Fprintf(c.api.stderr, "zkitctl: %v\n", err)
+		return exitNotOK
+	}
+	return c.api.finish(r, false)
+}
+
+// --- report ---
+
+func (c *cli) report(ctx context.Context, args []string) int {
+	fs := c.flags("report")
+	sections := fs.String("sections", "", "only these sections (comma-separated)")
+	exclude := fs.String("exclude", "", "skip these sections (comma-separated)")
+	if fs.Parse(args) != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 0 {
+		return c.usage("report takes no arguments")
+	}
+	q := url.Values{}
+	if *sections != "" {
+		q.Set("sections", *sections)
+	}
+	if *exclude != "" {
+		q.Set("exclude", *exclude)
+	}
+	r, err := c.api.do(ctx, http.MethodGet, "/report", q)
+	if err != nil {
+		fmt.Fprintf(c.api.stderr, "zkitctl: %v\n", err)
+		return exitNotOK
+	}
+	code := c.api.finish(r, true)
+	// The text report is always 200; its first line carries the status.
+	if code == exitOK && c.api.format == outputTable && !bytes.HasPrefix(r.body, []byte("ok\n")) {
+		fmt.Fprintln(c.api.stderr, "zkitctl: report: one or more sections failed")
+		return exitNotOK
+	}
+	return code
+}
+
+// --- goroutines ---
+
+func (c *cli) goroutines(ctx context.Context, args []string) int {
+	fs := c.flags("goroutines")
+	fn := fs.String("func", "", "only goroutines with a frame whose function contains this")
+	pkg := fs.String("pkg", "", "only goroutines with a frame whose package path contains this")
+	state := fs.String("state", "", "only goroutines whose state contains this")
+	minWait := fs.Duration("min-wait", 0, "only goroutines blocked at least this long")
+	if fs.Parse(args) != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 0 {
+		return c.usage("goroutines takes no arguments")
+	}
+	q := url.Values{}
+	for k, v := range map[string]string{"func": *fn, "pkg": *pkg, "state": *state} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	if *minWait > 0 {
+		q.Set("min_wait", minWait.String())
+	}
+	return c.get(ctx, "/goroutines", q)
+}
+
+// --- tuning ---
+
+func (c *cli) tuning(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		return c.usage("tuning: missing subcommand")
+	}
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "list":
+		if len(rest) != 0 {
+			return c.usage("tuning list takes no arguments")
+		}
+		return c.get(ctx, "/tuning/snapshot", nil)
+	case "get":
+		if len(rest) != 1 {
+			return c.usage("usage: tuning get KEY")
+		}
+		return c.get(ctx, "/tuning/lookup", url.Values{"key": {rest[0]}})
+	case "flags":
+		if len(rest) != 0 {
+			return c.usage("tuning flags takes no arguments")
+		}
+		return c.get(ctx, "/tuning/flags", nil)
+	case "layers":
+		if len(rest) != 0 {
+			return c.usage("tuning layers takes no arguments")
+		}
+		return c.get(ctx, "/tuning/layers", nil)
+	case "history":
+		if len(rest) != 1 {
+			return c.usage("usage: tuning history KEY")
+		}
+		return c.get(ctx, "/tuning/history", url.Values{"key": {rest[0]}})
+	case "set":
+		fs := c.flags("tuning set")
+		ttl := fs.Duration("ttl", 0, "revert automatically after this long (0 = permanent)")
+		if fs.Parse(rest) != nil {
+			return exitUsage
+		}
+		if fs.NArg() != 2 {
+			return c.usage("usage: tuning set [-ttl D] KEY VALUE")
+		}
+		q := url.Values{"key": {fs.Arg(0)}, "value": {fs.Arg(1)}}
+		if *ttl > 0 {
+			q.Set("ttl", ttl.String())
+		}
+		return c.post(ctx, "/tuning/set", q)
+	case "reset":
+		fs := c.flags("tuning reset")
+		last := fs.Bool("last", false, "reset to the last value instead of the default")
+		steps := fs.Int("steps", 0, "roll back this many writes (see tuning history)")
+		if fs.Parse(rest) != nil {
+			return exitUsage
+		}
+		if fs.NArg() != 1 || *steps < 0 {
+			return c.usage("usage: tuning reset [-last | -steps N] KEY")
+		}
+		q := url.Values{"key": {fs.Arg(0)}}
+		path := "/tuning/reset-default"
+		switch {
+		case *steps > 0:
+			path = "/tuning/reset-last"
+			q.Set("steps", strconv.Itoa(*steps))
+		case *last:
+			path = "/tuning/reset-last"
+		}
+		return c.post(ctx, path, q)
+	case "export":
+		if len(rest) != 0 {
+			return c.usage("tuning export takes no arguments")
+		}
+		return c.tuningExport(ctx)
+	case "apply":
+		fs := c.flags("tuning apply")
+		dryRun := fs.Bool("dry-run", false, "validate and print the diff without applying")
+		if fs.Parse(rest) != nil {
+			return exitUsage
+		}
+		if fs.NArg() != 1 {
+			return c.usage("usage: tuning apply [-dry-run] FILE")
+		}
+		return c.tuningApply(ctx, fs.Arg(0), *dryRun)
+	default:
+		return c.usage("tuning: unknown subcommand " + sub)
+	}
+}
+
+// tuningExport prints current overrides as a JSON array of tuning.OverrideItem
+// (the same shape as tuning.ExportOverridesJSON), regardless of -o.
+func (c *cli) tuningExport(ctx context.Context) int {
+	j := *c.api
+	j.format = outputJSON
+	r, err := j.do(ctx, http.MethodGet, "/tuning/overrides", nil)
+	if err != nil {
+		fmt.Fprintf(c.api.stderr, "zkitctl: %v\n", err)
+		return exitNotOK
+	}
+	if !r.ok() {
+		return j.finish(r, false)
+	}
+	var resp struct {
+		Overrides []tuning.OverrideItem `json:"overrides"`
+	}
+	if err := json.Unmarshal(r.body, &resp); err != nil {
+		fmt.Fprintf(c.api.stderr, "zkitctl: tuning export: %v\n", err)
+		return exitNotOK
+	}
+	if resp.Overrides == nil {
+		resp.Overrides = []tuning.OverrideItem{}
+	}
+	b, _ := json.MarshalIndent(resp.Overrides, "", "  ")
+	_, _ = c.api.stdout.Write(append(b, '\n'))
+	return exitOK
+}
+
+// tuningApply sets every override in file (the output of tuning export) as one batch via
+// /tuning/apply: either all of them are applied or none. Redacted values cannot be applied and
+// are skipped.
+func (c *cli) tuningApply(ctx context.Context, file string, dryRun bool) int {
+	var (
+		b   []byte
+		err error
+	)
+	if file == "-" {
+		b, err = io.ReadAll(c.stdin)
+	} else {
+		b, err = os.ReadFile(file)
+	}
+	if err != nil {
+		fmt.Fprintf(c.api.stderr, "zkitctl: tuning apply: %v\n", err)
+		return exitUsage
+	}
+	items, err := parseOverrides(b)
+	if err != nil {
+		fmt.Fprintf(c.api.stderr, "zkitctl: tuning apply: %v\n", err)
+		return exitUsage
+	}
+	batch := make([]tuning.OverrideItem, 0, len(items))
+	for _, it := range items {
+		if it.Value == "<redacted>" {
+			fmt.Fprintf(c.api.stderr, "zkitctl: tuning apply: skipping redacted key %s\n", it.Key)
+			continue
+		}
+		batch = append(batch, it)
+	}
+	if len(batch) == 0 {
+		return exitOK
+	}
+	body, _ := json.Marshal(batch)
+	q := url.Values{}
+	if dryRun {
+		q.Set("dry_run", "1")
+	}
+	r, err := c.api.doJSON(ctx, http.MethodPost, "/tuning/apply", q, body)
+	if err != nil {
+		fmt.Fprintf(c.api.stderr, "zkitctl: %v\n", err)
+		return exitNotOK
+	}
+	return c.api.finish(r, false)
+}
+
+// parseOverrides accepts a JSON array of overrides, or a /tuning/overrides JSON response.
+func parseOverrides(b []byte) ([]tuning.OverrideItem, error) {
+	b = bytes.TrimSpace(b)
+	var items []tuning.OverrideItem
+	if len(b) > 0 && b[0] == '{' {
+		var resp struct {
+			Overrides []tuning.OverrideItem `json:"overrides"`
+		}
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return nil, err
+		}
+		items = resp.Overrides
+	} else if err := json.Unmarshal(b, &items); err != nil {
+		return nil, err
+	}
+	for i, it := range items {
+		if strings.TrimSpace(it.Key) == "" {
+			return nil, fmt.Errorf("item %d: empty key", i)
+		}
+	}
+	return items, nil
+}
+
+// --- tasks ---
+
+func (c *cli) tasks(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		return c.usage("tasks: missing subcommand")
+	}
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "list":
+		if len(rest) != 0 {
+			return c.usage("tasks list takes no arguments")
+		}
+		return c.get(ctx, "/tasks/snapshot", nil)
+	case "trigger":
+		if len(rest) != 1 {
+			return c.usage("usage: tasks trigger NAME")
+		}
+		return c.post(ctx, "/tasks/trigger", url.Values{"name": {rest[0]}})
+	case "wait":
+		fs := c.flags("tasks wait")
+		timeout := fs.Duration("timeout", 0, "server-side wait timeout (0 = server default)")
+		if fs.Parse(rest) != nil {
+			return exitUsage
+		}
+		if fs.NArg() != 1 {
+			return c.usage("usage: tasks wait [-timeout D] NAME")
+		}
+		q := url.Values{"name": {fs.Arg(0)}}
+		if *timeout > 0 {
+			q.Set("timeout", timeout.String())
+			// Give the server a chance to answer before the client gives up.
+			if c.api.hc.Timeout < *timeout+5*time.Second {
+				hc := *c.api.hc
+				hc.Timeout = *timeout + 5*time.Second
+				c.api.hc = &hc
+			}
+		}
+		return c.post(ctx, "/tasks/trigger-and-wait", q)
+	default:
+		return c.usage("tasks: unknown subcommand " + sub)
+	}
+}
+
+// --- log ---
+
+func (c *cli) log(ctx context.Context, args []string) int {
+	const usage = "usage: log level [get] | log level set LEVEL | log levels [list] | log levels set [-ttl D] NAME LEVEL | log levels reset NAME | log tail [flags] | log debug [list] | log debug add [-ttl D] KIND VALUE | log debug remove KIND VALUE"
+	if len(args) == 0 {
+		return c.usage(usage)
+	}
+	rest := args[1:]
+	switch args[0] {
+	case "level":
+		switch {
+		case len(rest) == 0 || (len(rest) == 1 && rest[0] == "get"):
+			return c.get(ctx, "/log/level", nil)
+		case len(rest) == 2 && rest[0] == "set":
+			return c.post(ctx, "/log/level/set", url.Values{"level": {rest[1]}})
+		}
+	case "levels":
+		switch {
+		case len(rest) == 0 || (len(rest) == 1 && rest[0] == "list"):
+			return c.get(ctx, "/log/levels", nil)
+		case rest[0] == "set":
+			fs := c.flags("log levels set")
+			ttl := fs.Duration("ttl", 0, "revert automatically after this long (0 = permanent)")
+			if fs.Parse(rest[1:]) != nil {
+				return exitUsage
+			}
+			if fs.NArg() != 2 {
+				return c.usage("usage: log levels set [-ttl D] NAME LEVEL")
+			}
+			q := url.Values{"name": {fs.Arg(0)}, "level": {fs.Arg(1)}}
+			if *ttl > 0 {
+				q.Set("ttl", ttl.String())
+			}
+			return c.post(ctx, "/log/levels/set", q)
+		case len(rest) == 2 && rest[0] == "reset":
+			return c.post(ctx, "/log/levels/reset", url.Values{"name": {rest[1]}})
+		}
+	case "tail":
+		return c.logTail(ctx, rest)
+	case "debug":
+		return c.logDebug(ctx, rest)
+	}
+	return c.usage(usage)
+}
+
+// logDebug manages the request ids / client IPs marked for per-request debug logging.
+// KIND is request_id or ip.
+func (c *cli) logDebug(ctx context.Context, args []string) int {
+	const usage = "usage: log debug [list] | log debug add [-ttl D] KIND VALUE | log debug remove KIND VALUE (KIND: request_id, ip)"
+	if len(args) == 0 || (len(args) == 1 && args[0] == "list") {
+		return c.get(ctx, "/log/debug-targets", nil)
+	}
+	switch args[0] {
+	case "add":
+		fs := c.flags("log debug add")
+		ttl := fs.Duration("ttl", 0, "expire after this long (0 = server default)")
+		if fs.Parse(args[1:]) != nil {
+			return exitUsage
+		}
+		if fs.NArg() != 2 || !validDebugKind(fs.Arg(0)) {
+			return c.usage(usage)
+		}
+		q := url.Values{fs.Arg(0): {fs.Arg(1)}}
+		if *ttl > 0 {
+			q.Set("ttl", ttl.String())
+		}
+		return c.post(ctx, "/log/debug-targets/add", q)
+	case "remove":
+		if len(args) != 3 || !validDebugKind(args[1]) {
+			return c.usage(usage)
+		}
+		return c.post(ctx, "/log/debug-targets/remove", url.Values{args[1]: {args[2]}})
+	}
+	return c.usage(usage)
+}
+
+func validDebugKind(kind string) bool {
+	return kind == "request_id" || kind == "ip"
+}
+
+func (c *cli) logTail(ctx context.Context, args []string) int {
+	fs := c.flags("log tail")
+	n := fs.Int("n", 0, "number of records (0 = server default)")
+	level := fs.String("level", "", "minimum level (debug, info, warn, error)")
+	since := fs.Duration("since", 0, "only records from the last D")
+	contains := fs.String("q", "", "only records whose message contains this")
+	requestID := fs.String("request-id", "", "only records of this request ID")
+	if fs.Parse(args) != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 0 {
+		return c.usage("log tail takes no arguments")
+	}
+	q := url.Values{}
+	if *n > 0 {
+		q.Set("n", fmt.Sprint(*n))
+	}
+	if *since > 0 {
+		q.Set("since", since.String())
+	}
+	for k, v := range map[string]string{"level": *level, "q": *contains, "request_id": *requestID} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	r, err := c.api.do(ctx, http.MethodGet, "/log/tail", q)
+	if err != nil {
+		fmt.Fprintf(c.api.stderr, "zkitctl: %v\n", err)
+		return exitNotOK
+	}
+	// Records have a variable number of fields: print them as they are.
+	return c.api.finish(r, true)
+}
+
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.api.stderr)
+	return fs
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+// fileConfig is the on-disk profile file (JSON).
+//
+//	{
+//	  "default": "prod",
+//	  "profiles": {
+//	    "prod": {"base_url": "https://svc.internal", "prefix": "/-/", "token_env": "PROD_ADMIN_TOKEN"}
+//	  }
+//	}
+type fileConfig struct {
+	Default  string             `json:"default,omitempty"`
+	Profiles map[string]profile `json:"profiles"`
+}
+
+// profile describes how to reach one admin subtree.
+type profile struct {
+	BaseURL string `json:"base_url"`
+	// Prefix is the admin mount prefix (default "/-/"). Use "/" for a standalone admin server.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Token sources, highest priority first: TokenFile, TokenEnv, Token.
+	TokenFile   string `json:"token_file,omitempty"`
+	TokenEnv    string `json:"token_env,omitempty"`
+	Token       string `json:"token,omitempty"`
+	TokenHeader string `json:"token_header,omitempty"` // default "X-Access-Token"
+
+	Timeout string `json:"timeout,omitempty"` // Go duration; default "10s"
+}
+
+const (
+	defaultPrefix  = "/-/"
+	defaultTimeout = 10 * time.Second
+	// tokenEnvFallback is consulted when neither flags nor the profile name a token source.
+	tokenEnvFallback = "ZKITCTL_TOKEN"
+)
+
+// defaultConfigPath returns $ZKITCTL_CONFIG or <user config dir>/zkitctl/config.json.
+func defaultConfigPath(getenv func(string) string) string {
+	if p := getenv("ZKITCTL_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "zkitctl", "config.json")
+}
+
+// loadConfig reads the profile file. A missing file is not an error unless required.
+func loadConfig(path string, required bool) (fileConfig, error) {
+	var cfg fileConfig
+	if path == "" {
+		if required {
+			return cfg, errors.New("no config file (set -config or ZKITCTL_CONFIG)")
+		}
+		return cfg, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !required {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// pick returns the named profile, or the default profile when name is empty.
+func (c fileConfig) pick(name string) (profile, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		return profile{}, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+	return p, nil
+}
+
+// overlay applies non-empty fields of o on top of p.
+func (p profile) overlay(o profile) profile {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&p.BaseURL, o.BaseURL)
+	set(&p.Prefix, o.Prefix)
+	set(&p.TokenHeader, o.TokenHeader)
+	set(&p.Timeout, o.Timeout)
+	// A token source given on the command line replaces the profile's sources entirely.
+	if o.TokenFile != "" || o.TokenEnv != "" || o.Token != "" {
+		p.TokenFile, p.TokenEnv, p.Token = o.TokenFile, o.TokenEnv, o.Token
+	}
+	return p
+}
+
+// target is a resolved profile, ready to use.
+type target struct {
+	base        string // scheme://host[:port] + normalized prefix, without trailing slash
+	token       string
+	tokenHeader string
+	timeout     time.Duration
+}
+
+func (p profile) resolve(getenv func(string) string) (target, error) {
+	var t target
+	base := strings.TrimRight(strings.TrimSpace(p.BaseURL), "/")
+	if base == "" {
+		return t, errors.New("no base URL (set -url or a profile base_url)")
+	}
+	if !strings.HasPrefix(base, "http://") && !strings.HasPrefix(base, "https://") {
+		return t, fmt.Errorf("invalid base URL %q (want http:// or https://)", p.BaseURL)
+	}
+	prefix := p.Prefix
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix != "" {
+		base += "/" + prefix
+	}
+	t.base = base
+
+	t.tokenHeader = p.TokenHeader
+	if t.tokenHeader == "" {
+		t.tokenHeader = httpx.DefaultAccessGuardTokenHeader
+	}
+
+	switch {
+	case p.TokenFile != "":
+		b, err := os.ReadFile(p.TokenFile)
+		if err != nil {
+			return t, fmt.Errorf("token file: %w", err)
+		}
+		t.token = strings.TrimSpace(string(b))
+	case p.TokenEnv != "":
+		t.token = strings.TrimSpace(getenv(p.TokenEnv))
+		if t.token == "" {
+			return t, fmt.Errorf("token env %s is empty", p.TokenEnv)
+		}
+	case p.Token != "":
+		t.token = p.Token
+	default:
+		t.token = strings.TrimSpace(getenv(tokenEnvFallback))
+	}
+
+	t.timeout = defaultTimeout
+	if p.Timeout != "" {
+		d, err := time.ParseDuration(p.Timeout)
+		if err != nil || d <= 0 {
+			return t, fmt.Errorf("invalid timeout %q", p.Timeout)
+		}
+		t.timeout = d
+	}
+	return t, nil
+}
@@ -0,0 +1,159 @@
+// Command zkitctl is a command-line client for the zkit admin subtree.
+//
+// It wraps the default admin endpoints (see package admin) so operators do not need
+// to hand-craft curl calls with token headers and query parameters.
+//
+// Usage:
+//
+//	zkitctl [flags] <command> [args]
+//
+// Commands:
+//
+//	report [-sections a,b] [-exclude a,b]
+//	runtime | buildinfo | readyz
+//	goroutines [-func S] [-pkg S] [-state S] [-min-wait D]
+//	tuning list | get KEY | history KEY | flags | layers | set [-ttl D] KEY VALUE
+//	tuning reset [-last | -steps N] KEY | export | apply [-dry-run] FILE
+//	tasks list | trigger NAME | wait [-timeout D] NAME
+//	log level [get] | log level set LEVEL
+//	log levels [list] | log levels set [-ttl D] NAME LEVEL | log levels reset NAME
+//	log tail [-n N] [-level L] [-since D] [-q S] [-request-id ID]
+//	log debug [list] | log debug add [-ttl D] KIND VALUE | log debug remove KIND VALUE
+//
+// Targets come from a JSON profile file ($ZKITCTL_CONFIG, or <user config dir>/zkitctl/config.json)
+// and/or flags:
+//
+//	{
+//	  "default": "prod",
+//	  "profiles": {
+//	    "prod":  {"base_url": "https://svc.internal", "token_env": "PROD_ADMIN_TOKEN"},
+//	    "local": {"base_url": "http://127.0.0.1:8081", "prefix": "/", "token_file": "/run/admin-token"}
+//	  }
+//	}
+//
+// Tokens are read from (highest priority first) -token-file, -token-env, the profile's
+// token_file/token_env/token, and finally $ZKITCTL_TOKEN. There is intentionally no -token
+// flag: command lines are visible to other local users.
+//
+// Output is a table (aligned ops text output) by default, or JSON with -o json.
+//
+// Exit codes:
+//   - 0: the response was OK
+//   - 1: the request failed or the response was not OK (non-2xx or {"ok": false})
+//   - 2: usage or configuration error
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	exitOK    = 0
+	exitNotOK = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+const usageText = `usage: zkitctl [flags] <command> [args]
+
+commands:
+  report [-sections a,b] [-exclude a,b]   combined report
+  runtime                                 runtime stats
+  buildinfo                               build information
+  readyz                                  readiness checks
+  goroutines [-func S] [-pkg S] [-state S] [-min-wait D]
+                                          grouped goroutine dump
+  tuning list                             all tuning variables
+  tuning get KEY                          one tuning variable
+  tuning history KEY                      recent writes of a tuning variable
+  tuning flags                            feature flags with evaluation counters
+  tuning layers                           layer sources (files, remote): sync status
+  tuning set [-ttl D] KEY VALUE           set a tuning variable (reverting after D)
+  tuning reset [-last | -steps N] KEY     reset to default (or to the last value, or N writes back)
+  tuning export                           current overrides as JSON (input for apply)
+  tuning apply [-dry-run] FILE            set every override in FILE at once ("-" = stdin)
+  tasks list                              task snapshot
+  tasks trigger NAME                      trigger a task
+  tasks wait [-timeout D] NAME            trigger a task and wait for it
+  log level [get]                         current log level
+  log level set LEVEL                     set the log level
+  log levels [list]                       per-component log levels
+  log levels set [-ttl D] NAME LEVEL      set a component level (reverting after D)
+  log levels reset NAME                   restore a component's initial level
+  log tail [-n N] [-level L] [-since D]   recent log records (also -q S, -request-id ID)
+  log debug [list]                        request ids / client IPs logged at debug
+  log debug add [-ttl D] KIND VALUE       log a request_id or ip (IP/CIDR) at debug for D
+  log debug remove KIND VALUE             stop debug logging for a request_id or ip
+
+flags:
+`
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("zkitctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usageText)
+		fs.PrintDefaults()
+	}
+
+	var (
+		configPath  = fs.String("config", "", "profile file (default $ZKITCTL_CONFIG or <user config dir>/zkitctl/config.json)")
+		profileName = fs.String("profile", "", "profile name (default: the file's \"default\")")
+		output      = fs.String("o", string(outputTable), "output format: table|json")
+		flags       profile
+	)
+	fs.StringVar(&flags.BaseURL, "url", "", "admin base URL, e.g. https://svc.internal (overrides profile)")
+	fs.StringVar(&flags.Prefix, "prefix", "", "admin mount prefix (default \"/-/\"; \"/\" for a standalone admin server)")
+	fs.StringVar(&flags.TokenFile, "token-file", "", "read the token from this file")
+	fs.StringVar(&flags.TokenEnv, "token-env", "", "read the token from this environment variable")
+	fs.StringVar(&flags.TokenHeader, "token-header", "", "token header (default \"X-Access-Token\")")
+	fs.StringVar(&flags.Timeout, "timeout", "", "request timeout (default 10s)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	format := outputFormat(*output)
+	if format != outputTable && format != outputJSON {
+		fmt.Fprintf(stderr, "zkitctl: invalid -o %q (want table or json)\n", *output)
+		return exitUsage
+	}
+
+	path := *configPath
+	if path == "" {
+		path = defaultConfigPath(getenv)
+	}
+	cfg, err := loadConfig(path, *configPath != "" || *profileName != "")
+	if err != nil {
+		fmt.Fprintf(stderr, "zkitctl: config: %v\n", err)
+		return exitUsage
+	}
+	p, err := cfg.pick(*profileName)
+	if err != nil {
+		fmt.Fprintf(stderr, "zkitctl: %v\n", err)
+		return exitUsage
+	}
+	t, err := p.overlay(flags).resolve(getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "zkitctl: %v\n", err)
+		return exitUsage
+	}
+
+	c := &cli{api: newAPI(t, format, stdout, stderr), stdin: stdin}
+	return c.dispatch(ctx, fs.Args())
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/admin"
+	"github.com/evan-idocoding/zkit/httpx"
+	"github.com/evan-idocoding/zkit/rt/tuning"
+	"github.com/evan-idocoding/zkit/slogx"
+)
+
+func newTestAdmin(t *testing.T) (*httptest.Server, *tuning.Tuning) {
+	t.Helper()
+	tu := tuning.New()
+	if _, err := tu.Int64("pool.size", 4); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if _, err := tu.Bool("feature.x", false); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	read := admin.Tokens([]string{"r", "w"})
+	write := admin.Tokens([]string{"w"})
+	h := admin.New(
+		admin.EnableRuntime(admin.RuntimeSpec{Guard: read}),
+		admin.EnableReport(admin.ReportSpec{Guard: read}),
+		admin.EnableTuningSnapshot(admin.TuningSnapshotSpec{Guard: read, T: tu}),
+		admin.EnableTuningOverrides(admin.TuningOverridesSpec{Guard: read, T: tu}),
+		admin.EnableTuningLookup(admin.TuningLookupSpec{Guard: read, T: tu}),
+		admin.EnableTuningHistory(admin.TuningHistorySpec{Guard: read, T: tu}),
+		admin.EnableTuningResetLast(admin.TuningResetLastSpec{Guard: write, T: tu, Access: admin.TuningAccessSpec{AllowFunc: func(string) bool { return true }}}),
+		admin.EnableTuningSet(admin.TuningSetSpec{Guard: write, T: tu, Access: admin.TuningAccessSpec{AllowFunc: func(string) bool { return true }}}),
+		admin.EnableTuningApply(admin.TuningApplySpec{Guard: write, T: tu, Access: admin.TuningAccessSpec{AllowFunc: func(string) bool { return true }}}),
+	)
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv, tu
+}
+
+type runResult struct {
+	code           int
+	stdout, stderr string
+}
+
+func runCtl(t *testing.T, env map[string]string, stdin string, args ...string) runResult {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	getenv := func(k string) string { return env[k] }
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr, getenv)
+	return runResult{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func TestRun_ProfilesTokensAndExitCodes(t *testing.T) {
+	srv, tu := newTestAdmin(t)
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := `{"default": "test", "profiles": {"test": {"base_url": "` + srv.URL + `", "prefix": "/", "token_env": "T"}}}`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	env := map[string]string{"ZKITCTL_CONFIG": cfgPath, "T": "r"}
+
+	res := runCtl(t, env, "", "tuning", "get", "pool.size")
+	if res.code != exitOK || !strings.Contains(res.stdout, "pool.size") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	// Read token cannot write: 403 => exit 1.
+	res = runCtl(t, env, "", "tuning", "set", "pool.size", "8")
+	if res.code != exitNotOK || !strings.Contains(res.stderr, "403") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	// Flag token source overrides the profile.
+	env["W"] = "w"
+	res = runCtl(t, env, "", "-token-env", "W", "-o", "json", "tuning", "set", "pool.size", "8")
+	if res.code != exitOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	var resp struct {
+		OK  bool   `json:"ok"`
+		Key string `json:"key"`
+	}
+	if err := json.Unmarshal([]byte(res.stdout), &resp); err != nil || !resp.OK || resp.Key != "pool.size" {
+		t.Fatalf("unexpected json output %q (%v)", res.stdout, err)
+	}
+
+	res = runCtl(t, env, "", "-token-env", "W", "tuning", "set", "-ttl", "1h", "pool.size", "9")
+	if res.code != exitOK || !strings.Contains(res.stdout, "new.expires_at") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	res = runCtl(t, env, "", "tuning", "history", "pool.size")
+	if res.code != exitOK || strings.Count(res.stdout, "tuning_history") != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	res = runCtl(t, env, "", "-token-env", "W", "tuning", "reset", "-steps", "2", "pool.size")
+	if res.code != exitOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if it, _ := tu.Lookup("pool.size"); it.Value != int64(4) {
+		t.Fatalf("pool.size=%v, want 4", it.Value)
+	}
+
+	// {"ok": false} / non-2xx on a missing key.
+	res = runCtl(t, env, "", "-o", "json", "tuning", "get", "nope")
+	if res.code != exitNotOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	if res := runCtl(t, env, "", "nope"); res.code != exitUsage {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res := runCtl(t, env, "", "-profile", "missing", "runtime"); res.code != exitUsage {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestRun_TuningExportApply(t *testing.T) {
+	srv, tu := newTestAdmin(t)
+	env := map[string]string{"ZKITCTL_TOKEN": "w"}
+	if err := tu.SetFromString("pool.size", "16"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	res := runCtl(t, env, "", "-url", srv.URL, "-prefix", "/", "tuning", "export")
+	if res.code != exitOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	var items []tuning.OverrideItem
+	if err := json.Unmarshal([]byte(res.stdout), &items); err != nil || len(items) != 1 || items[0].Value != "16" {
+		t.Fatalf("unexpected export %q (%v)", res.stdout, err)
+	}
+
+	in := `[{"key":"pool.size","type":"int64","value":"32"},{"key":"feature.x","type":"bool","value":"true"}]`
+	res = runCtl(t, env, in, "-url", srv.URL, "-prefix", "/", "tuning", "apply", "-")
+	if res.code != exitOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	got := map[string]string{}
+	for _, ov := range tu.ExportOverrides() {
+		got[ov.Key] = ov.Value
+	}
+	if got["pool.size"] != "32" || got["feature.x"] != "true" {
+		t.Fatalf("unexpected overrides after apply: %v", got)
+	}
+
+	// Dry run prints the diff and changes nothing.
+	in = `[{"key":"pool.size","value":"64"}]`
+	res = runCtl(t, env, in, "-url", srv.URL, "-prefix", "/", "tuning", "apply", "-dry-run", "-")
+	if res.code != exitOK || !strings.Contains(res.stdout, "new.value") || !strings.Contains(res.stdout, "64") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	// Apply is all-or-nothing.
+	in = `[{"key":"pool.size","value":"1"},{"key":"nope","value":"1"}]`
+	res = runCtl(t, env, in, "-url", srv.URL, "-prefix", "/", "tuning", "apply", "-")
+	if res.code != exitNotOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if v, _ := tu.Lookup("pool.size"); v.Value != int64(32) {
+		t.Fatalf("expected pool.size unchanged, got %v", v.Value)
+	}
+}
+
+func TestRun_Report(t *testing.T) {
+	srv, _ := newTestAdmin(t)
+	env := map[string]string{"ZKITCTL_TOKEN": "r"}
+	res := runCtl(t, env, "", "-url", srv.URL, "-prefix", "/", "report", "-sections", "runtime")
+	if res.code != exitOK || !strings.HasPrefix(res.stdout, "ok\n") || !strings.Contains(res.stdout, "=== runtime ===") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if strings.Contains(res.stdout, "=== tuning.snapshot ===") {
+		t.Fatalf("expected filtered report, got:\n%s", res.stdout)
+	}
+}
+
+func TestRun_LogLevels(t *testing.T) {
+	levels := slogx.NewLevels()
+	if _, err := levels.Register("db", slog.LevelInfo); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	srv := httptest.NewServer(admin.New(
+		admin.EnableLogLevels(admin.LogLevelsSpec{Guard: admin.AllowAll(), Levels: levels}),
+		admin.EnableLogLevelsSet(admin.LogLevelsSetSpec{Guard: admin.AllowAll(), Levels: levels}),
+		admin.EnableLogLevelsReset(admin.LogLevelsResetSpec{Guard: admin.AllowAll(), Levels: levels}),
+	))
+	defer srv.Close()
+	ctl := func(args ...string) runResult {
+		return runCtl(t, nil, "", append([]string{"-url", srv.URL, "-prefix", "/"}, args...)...)
+	}
+
+	if res := ctl("log", "levels", "set", "-ttl", "1h", "db", "debug"); res.code != exitOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res := ctl("-o", "json", "log", "levels"); res.code != exitOK || !strings.Contains(res.stdout, `"revert_to": {`) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res := ctl("log", "levels", "reset", "db"); res.code != exitOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if lv, _ := levels.Var("db"); lv.Level() != slog.LevelInfo {
+		t.Fatalf("level=%v", lv.Level())
+	}
+	if res := ctl("log", "levels", "set", "db"); res.code != exitUsage {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestRun_LogTail(t *testing.T) {
+	ring := slogx.NewRing()
+	logger := slog.New(ring.Handler(nil))
+	logger.Info("first")
+	logger.Warn("second", "k", "v")
+	srv := httptest.NewServer(admin.New(admin.EnableLogTail(admin.LogTailSpec{Guard: admin.AllowAll(), Ring: ring})))
+	defer srv.Close()
+
+	res := runCtl(t, nil, "", "-url", srv.URL, "-prefix", "/", "log", "tail", "-level", "warn", "-n", "5")
+	if res.code != exitOK || !strings.HasSuffix(res.stdout, "\tWARN\tsecond\tk=v\n") || strings.Contains(res.stdout, "first") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestRun_LogDebug(t *testing.T) {
+	targets := httpx.NewDebugTargets()
+	srv := httptest.NewServer(admin.New(
+		admin.EnableDebugTargets(admin.DebugTargetsSpec{Guard: admin.AllowAll(), Targets: targets}),
+		admin.EnableDebugTargetsAdd(admin.DebugTargetsAddSpec{Guard: admin.AllowAll(), Targets: targets}),
+		admin.EnableDebugTargetsRemove(admin.DebugTargetsRemoveSpec{Guard: admin.AllowAll(), Targets: targets}),
+	))
+	defer srv.Close()
+	ctl := func(args ...string) runResult {
+		return runCtl(t, nil, "", append([]string{"-url", srv.URL, "-prefix", "/"}, args...)...)
+	}
+
+	if res := ctl("log", "debug", "add", "-ttl", "5m", "ip", "10.0.0.1"); res.code != exitOK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res := ctl("-o", "json", "log", "debug"); res.code != exitOK || !strings.Contains(res.stdout, `"value": "10.0.0.1"`) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res := ctl("log", "debug", "remove", "ip", "10.0.0.1"); res.code != exitOK || len(targets.Snapshot()) != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res := ctl("log", "debug", "add", "user", "bob"); res.code != exitUsage {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
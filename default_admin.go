@@ -8,16 +8,37 @@ import (
 	"time"
 
 	"github.com/evan-idocoding/zkit/admin"
+	"github.com/evan-idocoding/zkit/httpx"
+	"github.com/evan-idocoding/zkit/ops"
 	"github.com/evan-idocoding/zkit/rt/task"
 	"github.com/evan-idocoding/zkit/rt/tuning"
+	"github.com/evan-idocoding/zkit/slogx"
 )
 
 // ReadyCheck is a single readiness check for /readyz.
 // Name is required (used in the report); Timeout is optional (zero = no extra timeout).
+// NonCritical failures report "degraded" (still 200) instead of failing readiness.
 type ReadyCheck struct {
-	Name    string
-	Func    func(context.Context) error
-	Timeout time.Duration
+	Name        string
+	Func        func(context.Context) error
+	Timeout     time.Duration
+	NonCritical bool
+}
+
+// CustomEndpoint is an application endpoint mounted into the admin subtree (see admin.EnableCustom).
+//
+// Read endpoints (Write=false) are protected by ReadGuard and accept GET/HEAD.
+// Write endpoints (Write=true) are protected by WriteGuard and accept POST; they require WriteGuard != nil.
+type CustomEndpoint struct {
+	Name    string // required; unique; no whitespace
+	Path    string // required, e.g. "/app/cache/flush"
+	Write   bool
+	Handler http.Handler
+
+	// Description is an optional one-line description shown in the index.
+	Description string
+	// Reportable includes a read endpoint as a /report section.
+	Reportable bool
 }
 
 // AdminSpec configures NewDefaultAdmin. All fields are optional except ReadGuard.
@@ -31,10 +52,29 @@ type ReadyCheck struct {
 //   - TrustedProxies: CIDRs or IPs of trusted proxies; empty = do not trust proxy headers, use RemoteAddr.
 //   - TrustedHeaders: header names used to extract client IP (e.g. X-Forwarded-For, X-Real-IP). Empty = default order.
 //   - ReadyChecks: /readyz checks; empty slice = endpoint still enabled, no checks.
+//   - ReadyzMonitor: /readyz serves cached results of this background monitor instead of running checks per
+//     request; mutually exclusive with ReadyChecks. NewDefaultService starts/shuts it down; otherwise the caller does.
 //   - LogLevelVar: enables /log/level (read) when non-nil.
+//   - LogLevels: enables /log/levels (per-component levels, read) when non-nil. To keep /log/level in sync with the
+//     "default" component, create it with slogx.WithLevelsDefault(LogLevelVar).
+//   - LogRing: enables /log/tail (recent records captured by LogRing.Handler) when non-nil.
+//   - DebugTargets: enables /log/debug-targets (request ids / client IPs marked for debug logging by
+//     httpx.DebugLog) when non-nil. Wire the same registry into the business handler chain with
+//     httpx.DebugLog(httpx.WithDebugLogTargets(...)).
 //   - Tuning + TuningReadAllow*: tuning read endpoints; Tuning must be non-nil. Read allowlist: zero = no filter.
 //   - TaskManager + TaskReadAllow*: /tasks/snapshot; TaskManager must be non-nil. Read allowlist: zero = no filter.
 //   - ProvidedItems: when non-nil, enables /provided with this map; nil = disabled. ProvidedMaxBytes optional (<=0 = default).
+//     Values may be ops.ProvidedFunc providers (evaluated per request). Sensitive fields are redacted: `zkit:"redact"`
+//     struct tags, plus keys matching ProvidedRedactKeys (nil = ops.DefaultProvidedRedactKeys, e.g. *password*).
+//   - Lockout: enables /guard/lockouts (read) when non-nil. Wire the same Lockout into ReadGuard/WriteGuard
+//     with WithLockout for it to record anything.
+//   - EnableGoroutines: enables /goroutines (grouped goroutine dump); off by default since it stops the world briefly.
+//   - Events: enables /events (SSE stream) when non-nil. Admin publishes guard denials and /log/level changes to it.
+//     Tuning and LogLevels changes need Events.ObserveTuning/ObserveLogLevels (call the returned stop functions
+//     when the handler is discarded); task runs need task.WithManagerOnRunStart/OnRunFinish(Events.TaskRunStart/
+//     TaskRunFinish). NewDefaultService does all of this for the service's lifetime, and publishes lifecycle
+//     transitions.
+//   - Custom: application endpoints; read entries use ReadGuard (write entries: see Writes).
 //
 // Note on overlap with ServiceSpec:
 //   - AdminSpec.{LogLevelVar,Tuning,TaskManager} are admin handler data sources. When NewDefaultService is used and
@@ -45,7 +85,15 @@ type ReadyCheck struct {
 // # Writes (WriteGuard nil = all write endpoints disabled)
 //   - WriteGuard: when non-nil, write endpoints may be enabled; this guard protects them. Required for any write.
 //   - EnableLogLevelSet: requires WriteGuard != nil and LogLevelVar != nil (coexistence).
-//   - Tuning write group (/tuning/set, reset-default, reset-last): set TuningWritesEnabled true to enable; requires Tuning != nil. Allowlist (empty = deny-all) applies.
+//   - EnableLogLevelsSet: enables /log/levels/set (?name=&level=[&ttl=]) and /log/levels/reset; requires WriteGuard != nil
+//     and LogLevels != nil.
+//   - EnableDebugTargetsWrite: enables /log/debug-targets/add (?request_id= | ?ip=, [&ttl=]) and
+//     /log/debug-targets/remove; requires WriteGuard != nil and DebugTargets != nil.
+//   - EnableLockoutClear: enables /guard/lockouts/clear; requires WriteGuard != nil and Lockout != nil.
+//   - EnableRuntimeWrites: enables /runtime/gc and /runtime/free-os-memory; requires WriteGuard != nil.
+//   - EnableBundle: enables /debug/bundle (tar.gz diagnostic bundle); requires WriteGuard != nil.
+//   - Custom entries with Write=true: require WriteGuard != nil (admin will panic otherwise).
+//   - Tuning write group (/tuning/set, reset-default, reset-last, apply): set TuningWritesEnabled true to enable; requires Tuning != nil. Allowlist (empty = deny-all) applies.
 //   - Task write group (/tasks/trigger, trigger-and-wait): set TaskWritesEnabled true to enable; requires TaskManager != nil. Allowlist (empty = deny-all) applies.
 //
 // # Access allowlist rules (Tuning* and Task* Allow* fields)
@@ -62,9 +110,13 @@ type AdminSpec struct {
 
 	// Ready checks for /readyz. Empty = no checks (endpoint still responds OK).
 	ReadyChecks []ReadyCheck
+	// ReadyzMonitor serves cached /readyz results (mutually exclusive with ReadyChecks).
+	ReadyzMonitor *ops.ReadyzMonitor
 
 	// Optional read sources.
 	LogLevelVar *slog.LevelVar
+	LogLevels   *slogx.Levels
+	LogRing     *slogx.Ring
 	Tuning      *tuning.Tuning
 	TaskManager *task.Manager
 
@@ -78,9 +130,26 @@ type AdminSpec struct {
 	TaskReadAllowNames    []string
 	TaskReadAllowFunc     func(name string) bool
 
+	// Enable /goroutines (grouped goroutine dump).
+	EnableGoroutines bool
+
+	// Events: non-nil = enable /events (SSE) streaming this hub.
+	Events *ops.EventHub
+
 	// Provided (sensitive). Non-nil = enable /provided with this map; nil = disabled.
 	ProvidedItems    map[string]any
 	ProvidedMaxBytes int // <= 0 uses ops default
+	// ProvidedRedactKeys: key patterns redacted in /provided. nil = ops defaults; empty non-nil = none.
+	ProvidedRedactKeys []string
+
+	// DebugTargets: non-nil = enable /log/debug-targets. Share it with httpx.DebugLog.
+	DebugTargets *httpx.DebugTargets
+
+	// Lockout: non-nil = enable /guard/lockouts. Share it with guards via WithLockout.
+	Lockout *httpx.Lockout
+
+	// Custom application endpoints (read: ReadGuard; write: WriteGuard). Listed in the index.
+	Custom []CustomEndpoint
 
 	// Writes: nil = no write endpoints. Non-nil = guard for all write endpoints; individual groups gated by their Enable flag and allowlists.
 	WriteGuard Guard
@@ -88,6 +157,21 @@ type AdminSpec struct {
 	// Enable /log/level/set. Requires WriteGuard != nil and LogLevelVar != nil.
 	EnableLogLevelSet bool
 
+	// Enable /log/levels/set and /log/levels/reset. Requires WriteGuard != nil and LogLevels != nil.
+	EnableLogLevelsSet bool
+
+	// Enable /log/debug-targets/add and /log/debug-targets/remove. Requires WriteGuard != nil and DebugTargets != nil.
+	EnableDebugTargetsWrite bool
+
+	// Enable /guard/lockouts/clear. Requires WriteGuard != nil and Lockout != nil.
+	EnableLockoutClear bool
+
+	// Enable /runtime/gc and /runtime/free-os-memory. Requires WriteGuard != nil.
+	EnableRuntimeWrites bool
+
+	// Enable /debug/bundle (diagnostic bundle download). Requires WriteGuard != nil.
+	EnableBundle bool
+
 	// Tuning writes: TuningWritesEnabled true = enable group (requires Tuning != nil). Allowlist applies; empty = deny-all. AllowFunc mutually exclusive with slices.
 	TuningWritesEnabled      bool
 	TuningWriteAllowPrefixes []string
@@ -116,21 +200,51 @@ func NewDefaultAdmin(spec AdminSpec) http.Handler {
 		TrustedHeaders: spec.TrustedHeaders,
 	}))
 
+	if spec.ReadyzMonitor != nil && len(spec.ReadyChecks) > 0 {
+		panic("zkit: NewDefaultAdmin: ReadyzMonitor and ReadyChecks are mutually exclusive")
+	}
 	readyChecks := readyChecksToAdmin(spec.ReadyChecks)
 	opts = append(opts,
+		admin.EnableIndex(admin.IndexSpec{Guard: spec.ReadGuard}),
 		admin.EnableReport(admin.ReportSpec{Guard: spec.ReadGuard}),
 		admin.EnableHealthz(admin.HealthzSpec{Guard: spec.ReadGuard}),
-		admin.EnableReadyz(admin.ReadyzSpec{Guard: spec.ReadGuard, Checks: readyChecks}),
+		admin.EnableReadyz(admin.ReadyzSpec{Guard: spec.ReadGuard, Checks: readyChecks, Monitor: spec.ReadyzMonitor}),
 		admin.EnableBuildInfo(admin.BuildInfoSpec{Guard: spec.ReadGuard}),
 		admin.EnableRuntime(admin.RuntimeSpec{Guard: spec.ReadGuard}),
 	)
 
+	if spec.EnableGoroutines {
+		opts = append(opts, admin.EnableGoroutines(admin.GoroutinesSpec{Guard: spec.ReadGuard}))
+	}
+
+	if spec.Events != nil {
+		opts = append(opts, admin.EnableEvents(admin.EventsSpec{Guard: spec.ReadGuard, Hub: spec.Events}))
+	}
+
 	if spec.LogLevelVar != nil {
 		opts = append(opts, admin.EnableLogLevelGet(admin.LogLevelGetSpec{
 			Guard: spec.ReadGuard,
 			Var:   spec.LogLevelVar,
 		}))
 	}
+	if spec.LogRing != nil {
+		opts = append(opts, admin.EnableLogTail(admin.LogTailSpec{
+			Guard: spec.ReadGuard,
+			Ring:  spec.LogRing,
+		}))
+	}
+	if spec.LogLevels != nil {
+		opts = append(opts, admin.EnableLogLevels(admin.LogLevelsSpec{
+			Guard:  spec.ReadGuard,
+			Levels: spec.LogLevels,
+		}))
+	}
+	if spec.DebugTargets != nil {
+		opts = append(opts, admin.EnableDebugTargets(admin.DebugTargetsSpec{
+			Guard:   spec.ReadGuard,
+			Targets: spec.DebugTargets,
+		}))
+	}
 
 	tuningReadAccess := tuningAccessSpec(spec.TuningReadAllowPrefixes, spec.TuningReadAllowKeys, spec.TuningReadAllowFunc)
 	if spec.Tuning != nil {
@@ -150,6 +264,21 @@ func NewDefaultAdmin(spec AdminSpec) http.Handler {
 				T:      spec.Tuning,
 				Access: tuningReadAccess,
 			}),
+			admin.EnableTuningHistory(admin.TuningHistorySpec{
+				Guard:  spec.ReadGuard,
+				T:      spec.Tuning,
+				Access: tuningReadAccess,
+			}),
+			admin.EnableTuningFlags(admin.TuningFlagsSpec{
+				Guard:  spec.ReadGuard,
+				T:      spec.Tuning,
+				Access: tuningReadAccess,
+			}),
+			admin.EnableTuningLayers(admin.TuningLayersSpec{
+				Guard:  spec.ReadGuard,
+				T:      spec.Tuning,
+				Access: tuningReadAccess,
+			}),
 		)
 	}
 
@@ -164,13 +293,61 @@ func NewDefaultAdmin(spec AdminSpec) http.Handler {
 
 	if spec.ProvidedItems != nil {
 		opts = append(opts, admin.EnableProvidedSnapshot(admin.ProvidedSnapshotSpec{
-			Guard:    spec.ReadGuard,
-			Items:    spec.ProvidedItems,
-			MaxBytes: spec.ProvidedMaxBytes,
+			Guard:      spec.ReadGuard,
+			Items:      spec.ProvidedItems,
+			MaxBytes:   spec.ProvidedMaxBytes,
+			RedactKeys: spec.ProvidedRedactKeys,
+		}))
+	}
+
+	if spec.Lockout != nil {
+		opts = append(opts, admin.EnableLockoutSnapshot(admin.LockoutSnapshotSpec{
+			Guard:   spec.ReadGuard,
+			Lockout: spec.Lockout,
+		}))
+	}
+
+	for i, c := range spec.Custom {
+		g, kind := spec.ReadGuard, admin.CustomRead
+		if c.Write {
+			if spec.WriteGuard == nil {
+				panic("zkit: NewDefaultAdmin: Custom[" + strconv.Itoa(i) + "] " + c.Name + " is a write endpoint but WriteGuard is nil")
+			}
+			g, kind = spec.WriteGuard, admin.CustomWrite
+		}
+		opts = append(opts, admin.EnableCustom(admin.CustomSpec{
+			Guard:       g,
+			Path:        c.Path,
+			Name:        c.Name,
+			Kind:        kind,
+			Handler:     c.Handler,
+			Description: c.Description,
+			Reportable:  c.Reportable,
 		}))
 	}
 
 	if spec.WriteGuard != nil {
+		if spec.EnableLockoutClear {
+			if spec.Lockout == nil {
+				panic("zkit: NewDefaultAdmin: EnableLockoutClear requires Lockout")
+			}
+			opts = append(opts, admin.EnableLockoutClear(admin.LockoutClearSpec{
+				Guard:   spec.WriteGuard,
+				Lockout: spec.Lockout,
+			}))
+		}
+
+		if spec.EnableRuntimeWrites {
+			opts = append(opts,
+				admin.EnableRuntimeGC(admin.RuntimeGCSpec{Guard: spec.WriteGuard}),
+				admin.EnableRuntimeFreeOSMemory(admin.RuntimeFreeOSMemorySpec{Guard: spec.WriteGuard}),
+			)
+		}
+
+		if spec.EnableBundle {
+			opts = append(opts, admin.EnableBundle(admin.BundleSpec{Guard: spec.WriteGuard}))
+		}
+
 		if spec.EnableLogLevelSet {
 			if spec.LogLevelVar == nil {
 				panic("zkit: NewDefaultAdmin: EnableLogLevelSet requires LogLevelVar")
@@ -181,6 +358,26 @@ func NewDefaultAdmin(spec AdminSpec) http.Handler {
 			}))
 		}
 
+		if spec.EnableLogLevelsSet {
+			if spec.LogLevels == nil {
+				panic("zkit: NewDefaultAdmin: EnableLogLevelsSet requires LogLevels")
+			}
+			opts = append(opts,
+				admin.EnableLogLevelsSet(admin.LogLevelsSetSpec{Guard: spec.WriteGuard, Levels: spec.LogLevels}),
+				admin.EnableLogLevelsReset(admin.LogLevelsResetSpec{Guard: spec.WriteGuard, Levels: spec.LogLevels}),
+			)
+		}
+
+		if spec.EnableDebugTargetsWrite {
+			if spec.DebugTargets == nil {
+				panic("zkit: NewDefaultAdmin: EnableDebugTargetsWrite requires DebugTargets")
+			}
+			opts = append(opts,
+				admin.EnableDebugTargetsAdd(admin.DebugTargetsAddSpec{Guard: spec.WriteGuard, Targets: spec.DebugTargets}),
+				admin.EnableDebugTargetsRemove(admin.DebugTargetsRemoveSpec{Guard: spec.WriteGuard, Targets: spec.DebugTargets}),
+			)
+		}
+
 		if tuningWritesEnabled(spec) {
 			if spec.Tuning == nil {
 				panic("zkit: NewDefaultAdmin: tuning writes enabled but Tuning is nil")
@@ -202,6 +399,11 @@ func NewDefaultAdmin(spec AdminSpec) http.Handler {
 					T:      spec.Tuning,
 					Access: access,
 				}),
+				admin.EnableTuningApply(admin.TuningApplySpec{
+					Guard:  spec.WriteGuard,
+					T:      spec.Tuning,
+					Access: access,
+				}),
 			)
 		}
 
@@ -240,7 +442,7 @@ func readyChecksToAdmin(checks []ReadyCheck) []admin.ReadyCheck {
 		if c.Func == nil {
 			panic("zkit: ReadyChecks[" + strconv.Itoa(i) + "] " + c.Name + " has nil Func")
 		}
-		out[i] = admin.ReadyCheck{Name: c.Name, Func: c.Func, Timeout: c.Timeout}
+		out[i] = admin.ReadyCheck{Name: c.Name, Func: c.Func, Timeout: c.Timeout, NonCritical: c.NonCritical}
 	}
 	return out
 }
@@ -4,7 +4,11 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/evan-idocoding/zkit/httpx"
+	"github.com/evan-idocoding/zkit/slogx"
 )
 
 func TestNewDefaultAdmin_Provided_DefaultDisabled(t *testing.T) {
@@ -52,3 +56,139 @@ func TestNewDefaultAdmin_LogLevelGet_Works(t *testing.T) {
 		t.Fatalf("status=%d, want %d, body=%s", rw.Code, http.StatusOK, rw.Body.String())
 	}
 }
+
+func TestNewDefaultAdmin_Custom(t *testing.T) {
+	noop := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := NewDefaultAdmin(AdminSpec{
+		ReadGuard:  AllowAll(),
+		WriteGuard: DenyAll(),
+		Custom: []CustomEndpoint{
+			{Name: "app.info", Path: "/app/info", Handler: noop},
+			{Name: "app.flush", Path: "/app/flush", Write: true, Handler: noop},
+		},
+	})
+	serve := func(method, target string) int {
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, httptest.NewRequest(method, "http://admin.test"+target, nil))
+		return rw.Code
+	}
+	if code := serve(http.MethodGet, "/app/info"); code != http.StatusOK {
+		t.Fatalf("read status=%d, want %d", code, http.StatusOK)
+	}
+	// Write entries use WriteGuard.
+	if code := serve(http.MethodPost, "/app/flush"); code != http.StatusForbidden {
+		t.Fatalf("write status=%d, want %d", code, http.StatusForbidden)
+	}
+}
+
+func TestNewDefaultAdmin_CustomWrite_RequiresWriteGuard(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	_ = NewDefaultAdmin(AdminSpec{
+		ReadGuard: AllowAll(),
+		Custom: []CustomEndpoint{{
+			Name: "app.flush", Path: "/app/flush", Write: true,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		}},
+	})
+}
+
+func TestNewDefaultAdmin_Bundle_UsesWriteGuard(t *testing.T) {
+	h := NewDefaultAdmin(AdminSpec{
+		ReadGuard:    AllowAll(),
+		WriteGuard:   DenyAll(),
+		EnableBundle: true,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "http://admin.test/debug/bundle", nil)
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+	if rw.Code != http.StatusForbidden {
+		t.Fatalf("status=%d, want %d", rw.Code, http.StatusForbidden)
+	}
+}
+
+func TestNewDefaultAdmin_LogLevels(t *testing.T) {
+	levels := slogx.NewLevels()
+	if _, err := levels.Register("db", slog.LevelInfo); err != nil {
+		t.Fatal(err)
+	}
+	h := NewDefaultAdmin(AdminSpec{
+		ReadGuard:          AllowAll(),
+		WriteGuard:         AllowAll(),
+		LogLevels:          levels,
+		EnableLogLevelsSet: true,
+	})
+	serve := func(method, target string) int {
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, httptest.NewRequest(method, "http://admin.test"+target, nil))
+		return rw.Code
+	}
+	if code := serve(http.MethodGet, "/log/levels"); code != http.StatusOK {
+		t.Fatalf("list status=%d", code)
+	}
+	if code := serve(http.MethodPost, "/log/levels/set?name=db&level=debug&ttl=1m"); code != http.StatusOK {
+		t.Fatalf("set status=%d", code)
+	}
+	if code := serve(http.MethodPost, "/log/levels/reset?name=db"); code != http.StatusOK {
+		t.Fatalf("reset status=%d", code)
+	}
+}
+
+func TestNewDefaultAdmin_LogLevelsSet_RequiresLevels(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	_ = NewDefaultAdmin(AdminSpec{
+		ReadGuard:          AllowAll(),
+		WriteGuard:         AllowAll(),
+		EnableLogLevelsSet: true,
+	})
+}
+
+func TestNewDefaultAdmin_LogTail(t *testing.T) {
+	ring := slogx.NewRing()
+	slog.New(ring.Handler(nil)).Info("hello")
+	h := NewDefaultAdmin(AdminSpec{ReadGuard: AllowAll(), LogRing: ring})
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://admin.test/log/tail", nil))
+	if rw.Code != http.StatusOK || !strings.Contains(rw.Body.String(), "\tINFO\thello\n") {
+		t.Fatalf("status=%d body=%q", rw.Code, rw.Body.String())
+	}
+}
+
+func TestNewDefaultAdmin_DebugTargets(t *testing.T) {
+	targets := httpx.NewDebugTargets()
+	h := NewDefaultAdmin(AdminSpec{
+		ReadGuard:               AllowAll(),
+		WriteGuard:              AllowAll(),
+		DebugTargets:            targets,
+		EnableDebugTargetsWrite: true,
+	})
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "http://admin.test/log/debug-targets/add?ip=10.0.0.0/8&ttl=1m", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("add: status=%d body=%q", rw.Code, rw.Body.String())
+	}
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://admin.test/log/debug-targets", nil))
+	if rw.Code != http.StatusOK || !strings.Contains(rw.Body.String(), "debug_target\tip\t10.0.0.0/8\t") {
+		t.Fatalf("list: status=%d body=%q", rw.Code, rw.Body.String())
+	}
+}
+
+func TestNewDefaultAdmin_DebugTargetsWrite_RequiresTargets(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	_ = NewDefaultAdmin(AdminSpec{ReadGuard: AllowAll(), WriteGuard: AllowAll(), EnableDebugTargetsWrite: true})
+}
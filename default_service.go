@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/evan-idocoding/zkit/ops"
 	"github.com/evan-idocoding/zkit/rt/task"
 	"github.com/evan-idocoding/zkit/rt/tuning"
 )
@@ -47,6 +48,11 @@ type Service struct {
 
 	tasksEnabled bool
 
+	events       *ops.EventHub // lifecycle transitions (Admin.Events)
+	stopObserves []func()      // Tuning / LogLevels observers feeding events; stopped after shutdown
+
+	readyz *ops.ReadyzMonitor // Admin.ReadyzMonitor; started/shut down with tasks
+
 	servers       []managedServer // primary + extra + (admin standalone if present)
 	adminOnlySrv  *http.Server
 	adminOnlyName string
@@ -159,7 +165,11 @@ func NewDefaultService(spec ServiceSpec) *Service {
 		spec.TasksExposeToAdmin ||
 		(adminWrites && taskWritesEnabled(*spec.Admin)))
 	if adminNeedsTasks && mgr == nil {
-		mgr = task.NewManager()
+		if hub := spec.Admin.Events; hub != nil {
+			mgr = task.NewManager(task.WithManagerOnRunStart(hub.TaskRunStart), task.WithManagerOnRunFinish(hub.TaskRunFinish))
+		} else {
+			mgr = task.NewManager()
+		}
 		if adminWrites && taskWritesEnabled(*spec.Admin) {
 			tasksEnabled = true
 		}
@@ -187,6 +197,8 @@ func NewDefaultService(spec ServiceSpec) *Service {
 		if adminSpec.ReadGuard == nil {
 			panic("zkit: ServiceSpec.Admin: nil ReadGuard")
 		}
+		s.events = adminSpec.Events
+		s.readyz = adminSpec.ReadyzMonitor
 		if adminSpec.LogLevelVar == nil && spec.LogExposeToAdmin {
 			adminSpec.LogLevelVar = lv
 		}
@@ -208,6 +220,12 @@ func NewDefaultService(spec ServiceSpec) *Service {
 
 		adminHandler = NewDefaultAdmin(adminSpec)
 		s.AdminHandler = adminHandler
+		if hub := adminSpec.Events; hub != nil {
+			s.stopObserves = append(s.stopObserves,
+				hub.ObserveTuning(adminSpec.Tuning),
+				hub.ObserveLogLevels(adminSpec.LogLevels),
+			)
+		}
 
 		if spec.AdminStandaloneServer == nil {
 			adminMountPrefix = strings.TrimSpace(spec.AdminMountPrefix)
@@ -304,6 +322,7 @@ func (s *Service) Start(ctx context.Context) error {
 	s.started = true
 	s.startCtx, s.startStop = context.WithCancel(ctx)
 	s.mu.Unlock()
+	s.events.Lifecycle("starting")
 
 	// 1) OnStart hooks.
 	for i, h := range s.onStart {
@@ -325,6 +344,13 @@ func (s *Service) Start(ctx context.Context) error {
 			return err
 		}
 	}
+	if s.readyz != nil {
+		if err := s.readyz.Start(s.startCtx); err != nil {
+			s.recordPrimary(err)
+			s.initiateShutdown()
+			return err
+		}
+	}
 
 	// 3) servers (primary + extra + standalone admin)
 	for i := range s.servers {
@@ -338,6 +364,7 @@ func (s *Service) Start(ctx context.Context) error {
 			return err
 		}
 	}
+	s.events.Lifecycle("started")
 	return nil
 }
 
@@ -476,6 +503,7 @@ func (s *Service) doShutdown() {
 	if stop != nil {
 		stop()
 	}
+	s.events.Lifecycle("stopping")
 
 	ctx := context.Background()
 	cancel := func() {}
@@ -530,6 +558,11 @@ func (s *Service) doShutdown() {
 			errs = append(errs, fmt.Errorf("tasks shutdown: %w", err))
 		}
 	}
+	if s.readyz != nil {
+		if err := s.readyz.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("readyz monitor shutdown: %w", err))
+		}
+	}
 
 	// 3) OnShutdown hooks (sequential; best-effort run all)
 	for i, h := range s.onShutdown {
@@ -567,6 +600,10 @@ func (s *Service) doShutdown() {
 	s.waitErr = errors.Join(primary, shutdownErr)
 	s.mu.Unlock()
 
+	s.events.Lifecycle("stopped")
+	for _, stop := range s.stopObserves {
+		stop()
+	}
 	close(s.shutdownCh)
 	close(s.doneCh)
 }
@@ -606,6 +643,7 @@ func (s *Service) runSignalWatcher() (<-chan os.Signal, func()) {
 //   - Service lifecycle ownership is controlled by ServiceSpec fields:
 //   - TasksManager: when non-nil, Service starts/shuts down the task manager; when nil, Service does not manage it
 //     (even if Admin.TaskManager is set to expose tasks endpoints).
+//   - Admin.ReadyzMonitor: Service starts it (after tasks, before servers) and shuts it down (after tasks).
 //   - Tuning/LogLevelVar: not started/stopped by Service; they are data sources only.
 type ServiceSpec struct {
 	// Signals: SignalsDisable true = Run() does not listen for OS signals. Signals nil/empty = default set (SIGINT+SIGTERM on Unix).
@@ -1,10 +1,18 @@
 package zkit
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/evan-idocoding/zkit/ops"
+	"github.com/evan-idocoding/zkit/rt/tuning"
 )
 
 func TestService_WaitBeforeStart_ErrNotStarted(t *testing.T) {
@@ -107,3 +115,160 @@ func TestService_StartRollback_DoesNotShutdownUnstartedServers(t *testing.T) {
 		t.Fatalf("Wait err=%v, want %v", werr, err)
 	}
 }
+
+func TestService_EventsLifecycle(t *testing.T) {
+	hub := ops.NewEventHub()
+	s := NewDefaultService(ServiceSpec{
+		Primary: &HTTPServerSpec{
+			Addr:    "127.0.0.1:0",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		},
+		Admin: &AdminSpec{ReadGuard: AllowAll(), Events: hub},
+	})
+	admin := httptest.NewServer(s.AdminHandler)
+	defer admin.Close()
+
+	resp, err := http.Get(admin.URL + "/events?types=lifecycle")
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	defer resp.Body.Close()
+	for i := 0; hub.Subscribers() != 1; i++ {
+		if i > 200 {
+			t.Fatalf("client did not subscribe")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start err=%v", err)
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown err=%v", err)
+	}
+
+	var states []string
+	sc := bufio.NewScanner(resp.Body)
+	for len(states) < 4 && sc.Scan() {
+		if line := sc.Text(); strings.HasPrefix(line, "data: ") {
+			var e ops.Event
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			states = append(states, e.Key)
+		}
+	}
+	if got := strings.Join(states, ","); got != "starting,started,stopping,stopped" {
+		t.Fatalf("unexpected lifecycle: %s", got)
+	}
+}
+
+func TestService_ReadyzMonitorLifecycle(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	m := ops.NewReadyzMonitor([]ops.ReadyCheck{{
+		Name: "db",
+		Func: func(context.Context) error {
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	}})
+	s := NewDefaultService(ServiceSpec{
+		Primary: &HTTPServerSpec{
+			Addr:    "127.0.0.1:0",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		},
+		Admin: &AdminSpec{ReadGuard: AllowAll(), ReadyzMonitor: m},
+	})
+	admin := httptest.NewServer(s.AdminHandler)
+	defer admin.Close()
+
+	// Not started: the check is pending.
+	resp, err := http.Get(admin.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("readyz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status=%d, want 503 before Start", resp.StatusCode)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start err=%v", err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("monitor did not run the check")
+	}
+	for i := 0; ; i++ {
+		resp, err = http.Get(admin.URL + "/readyz")
+		if err != nil {
+			t.Fatalf("readyz: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			break
+		}
+		if i > 200 {
+			t.Fatalf("status=%d, want 200 after Start", resp.StatusCode)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown err=%v", err)
+	}
+	if err := m.Start(context.Background()); err != ops.ErrReadyzMonitorStarted {
+		t.Fatalf("monitor Start after Service err=%v, want ErrReadyzMonitorStarted", err)
+	}
+}
+
+func TestService_EventsTuningObservedOnce(t *testing.T) {
+	hub := ops.NewEventHub()
+	tu := tuning.New()
+	v, _ := tu.Int64("app.limit", 1)
+	spec := AdminSpec{ReadGuard: AllowAll(), Events: hub, Tuning: tu}
+	s := NewDefaultService(ServiceSpec{
+		Primary: &HTTPServerSpec{
+			Addr:    "127.0.0.1:0",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		},
+		Admin: &spec,
+	})
+	// Rebuilt admin handlers must not register more observers.
+	_ = NewDefaultAdmin(spec)
+	_ = NewDefaultAdmin(spec)
+
+	admin := httptest.NewServer(s.AdminHandler)
+	defer admin.Close()
+	resp, err := http.Get(admin.URL + "/events?types=tuning")
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	defer resp.Body.Close()
+	for i := 0; hub.Subscribers() != 1; i++ {
+		if i > 200 {
+			t.Fatalf("client did not subscribe")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	_ = v.Set(2)
+	_ = v.Set(3)
+	var news []string
+	sc := bufio.NewScanner(resp.Body)
+	for len(news) < 2 && sc.Scan() {
+		if line := sc.Text(); strings.HasPrefix(line, "data: ") {
+			var e ops.Event
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			news = append(news, fmt.Sprint(e.Data.(map[string]any)["new"]))
+		}
+	}
+	if got := strings.Join(news, ","); got != "2,3" {
+		t.Fatalf("tuning events: %s", got)
+	}
+}
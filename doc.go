@@ -34,6 +34,7 @@
 //	_ = svc.Run(context.Background())
 //
 // With the default admin kit, the always-on read endpoints include:
+//   - / (capability index)
 //   - /report
 //   - /healthz
 //   - /readyz
@@ -55,8 +56,12 @@
 //   - AdminSpec.WriteGuard == nil disables all write endpoints.
 //
 //   - If WriteGuard is non-nil, enable write groups explicitly: EnableLogLevelSet for /log/level/set;
+//     EnableLockoutClear for /guard/lockouts/clear;
 //     TuningWritesEnabled / TaskWritesEnabled for tuning and task writes. Allowlist (empty = deny-all) applies for tuning/task writes.
 //
+//   - Application endpoints can join the admin subtree via AdminSpec.Custom: read entries use ReadGuard,
+//     write entries use WriteGuard (and panic if it is nil).
+//
 //   - /provided (custom diagnostic snapshot) is disabled by default because it is typically more sensitive;
 //     enable it explicitly by setting AdminSpec.ProvidedItems to a non-nil map.
 //
@@ -117,7 +122,7 @@
 //
 // ServiceSpec (NewDefaultService): SignalsDisable, Signals, ShutdownTimeout, Primary, Extra, Admin (*AdminSpec), AdminMountPrefix, AdminStandaloneServer, TasksManager, TasksExposeToAdmin, Tuning, TuningExposeToAdmin, LogLevelVar, LogExposeToAdmin, OnStart, OnShutdown, OnServeError.
 //
-// AdminSpec (Admin field / NewDefaultAdmin): ReadGuard (required), TrustedProxies, TrustedHeaders, ReadyChecks, LogLevelVar, Tuning, TaskManager, TuningReadAllowPrefixes/Keys/Func, TaskReadAllowPrefixes/Names/Func, ProvidedItems, ProvidedMaxBytes, WriteGuard, EnableLogLevelSet, TuningWritesEnabled, TuningWriteAllowPrefixes/Keys/Func, TaskWritesEnabled, TaskWriteAllowPrefixes/Names/Func.
+// AdminSpec (Admin field / NewDefaultAdmin): ReadGuard (required), TrustedProxies, TrustedHeaders, ReadyChecks, LogLevelVar, Tuning, TaskManager, TuningReadAllowPrefixes/Keys/Func, TaskReadAllowPrefixes/Names/Func, ProvidedItems, ProvidedMaxBytes, Lockout, WriteGuard, EnableLogLevelSet, EnableLockoutClear, TuningWritesEnabled, TuningWriteAllowPrefixes/Keys/Func, TaskWritesEnabled, TaskWriteAllowPrefixes/Names/Func.
 //
 // HTTPServerSpec (Primary, Extra, AdminStandaloneServer): Name, Critical, Server (or Addr+Handler).
 //
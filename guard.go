@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/evan-idocoding/zkit/admin"
+	"github.com/evan-idocoding/zkit/httpx"
 )
 
 // Guard enforces request admission for admin endpoints.
@@ -15,6 +16,9 @@ type Guard = admin.Guard
 // TokenSetLike is a token set for hot-update guards (e.g. HotTokens).
 type TokenSetLike = admin.TokenSetLike
 
+// ClientCertSetLike is a client certificate set for hot-update guards (e.g. HotClientCerts).
+type ClientCertSetLike = admin.ClientCertSetLike
+
 // TokenOption configures token-based guards (e.g. header name).
 type TokenOption = admin.TokenOption
 
@@ -30,6 +34,10 @@ func DenyAll() Guard { return admin.DenyAll() }
 // WithTokenHeader overrides the token header name for token-based guards.
 func WithTokenHeader(name string) TokenOption { return admin.WithTokenHeader(name) }
 
+// WithLockout enables brute-force protection (per-IP lockouts, 429 + Retry-After) for token-based guards.
+// Create l with httpx.NewLockout and expose it via AdminSpec.Lockout.
+func WithLockout(l *httpx.Lockout) TokenOption { return admin.WithLockout(l) }
+
 // Tokens returns a guard that validates requests using a static token list.
 func Tokens(tokens []string, opts ...TokenOption) Guard {
 	return admin.Tokens(tokens, opts...)
@@ -65,6 +73,37 @@ func HotTokensAndIPAllowList(set TokenSetLike, cidrsOrIPs []string, opts ...Toke
 	return admin.HotTokensAndIPAllowList(set, cidrsOrIPs, opts...)
 }
 
+// ClientCerts returns a guard backed by a static client certificate allowlist
+// ("cn:", "dns:", "uri:", "sha256:" entries; requires TLS client auth on the server).
+func ClientCerts(entries ...string) Guard {
+	return admin.ClientCerts(entries...)
+}
+
+// HotClientCerts returns a guard backed by a hot-update client certificate set.
+func HotClientCerts(set ClientCertSetLike) Guard {
+	return admin.HotClientCerts(set)
+}
+
+// ClientCertsOrIPAllowList returns a guard that allows when the client certificate is allowed OR IP is allowlisted.
+func ClientCertsOrIPAllowList(entries []string, cidrsOrIPs []string) Guard {
+	return admin.ClientCertsOrIPAllowList(entries, cidrsOrIPs)
+}
+
+// ClientCertsAndIPAllowList returns a guard that allows when the client certificate is allowed AND IP is allowlisted.
+func ClientCertsAndIPAllowList(entries []string, cidrsOrIPs []string) Guard {
+	return admin.ClientCertsAndIPAllowList(entries, cidrsOrIPs)
+}
+
+// TokensOrClientCerts returns a guard that allows when token is allowed OR the client certificate is allowed.
+func TokensOrClientCerts(tokens []string, entries []string, opts ...TokenOption) Guard {
+	return admin.TokensOrClientCerts(tokens, entries, opts...)
+}
+
+// TokensAndClientCerts returns a guard that allows when token is allowed AND the client certificate is allowed.
+func TokensAndClientCerts(tokens []string, entries []string, opts ...TokenOption) Guard {
+	return admin.TokensAndClientCerts(tokens, entries, opts...)
+}
+
 // Check returns a guard backed by a custom fast predicate (must not block, no I/O).
 func Check(fn func(r *http.Request) bool) Guard {
 	return admin.Check(fn)
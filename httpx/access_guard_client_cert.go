@@ -0,0 +1,295 @@
+package httpx
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// ClientCertSetLike is a client certificate allow set used by AccessGuard.
+//
+// leaf is the client's leaf certificate (r.TLS.PeerCertificates[0]); verified reports
+// whether the TLS stack verified the chain (len(r.TLS.VerifiedChains) > 0).
+// On success, rule is a short description of the matching entry (e.g. "cn:ops-cli");
+// it is exposed to downstream handlers via ClientCertIdentity.MatchedRule.
+//
+// Implementations must be safe for concurrent use.
+// The request path must be fast and must not block.
+type ClientCertSetLike interface {
+	Match(leaf *x509.Certificate, verified bool) (rule string, ok bool)
+}
+
+// AtomicClientCertAllowList is an updateable client certificate allowlist intended for hot changes.
+//
+// Entries are "<kind>:<value>":
+//   - "cn:<common name>": subject CommonName (exact, case-sensitive)
+//   - "dns:<name>": DNS SAN (exact, case-insensitive)
+//   - "uri:<uri>": URI SAN (exact); a trailing "*" matches by prefix
+//     (e.g. "uri:spiffe://prod.example/ns/ops/*")
+//   - "sha256:<hex>": SHA-256 fingerprint of the DER certificate (case-insensitive; ':' separators allowed)
+//
+// Name-based entries (cn/dns/uri) and AllowAll only match certificates whose chain was verified by the
+// TLS stack (tls.Config.ClientAuth = VerifyClientCertIfGiven or RequireAndVerifyClientCert).
+// Fingerprint entries are pins and match regardless of chain verification.
+//
+// Read path (Match) is lock-free and non-blocking.
+// Write path (Update/AllowAll) is atomic and may allocate.
+type AtomicClientCertAllowList struct {
+	snap atomic.Pointer[clientCertSnapshot]
+}
+
+type clientCertSnapshot struct {
+	allowAll     bool
+	cns          map[string]struct{}
+	dnsNames     map[string]struct{}
+	uris         map[string]struct{}
+	uriPrefixes  []string
+	fingerprints map[string]struct{}
+}
+
+func (s *clientCertSnapshot) size() int {
+	return len(s.cns) + len(s.dnsNames) + len(s.uris) + len(s.uriPrefixes) + len(s.fingerprints)
+}
+
+// NewAtomicClientCertAllowList creates a new allowlist in the deny-all state.
+func NewAtomicClientCertAllowList() *AtomicClientCertAllowList {
+	a := &AtomicClientCertAllowList{}
+	a.snap.Store(&clientCertSnapshot{})
+	return a
+}
+
+// Update parses and replaces the current allowlist snapshot.
+//
+// Semantics:
+//   - entries == nil: sets to empty (deny-all)
+//   - invalid/blank entries (unknown kind, empty value, malformed fingerprint) are ignored
+//   - if no valid entries remain, it becomes empty (deny-all)
+func (a *AtomicClientCertAllowList) Update(entries []string) {
+	if a == nil {
+		return
+	}
+	a.snap.Store(parseClientCertEntries(entries))
+}
+
+// AllowAll sets this allowlist to allow any client certificate whose chain was verified by the
+// TLS stack (tls.Config.ClientAuth = VerifyClientCertIfGiven or RequireAndVerifyClientCert), i.e.
+// any certificate issued by the configured client CAs.
+//
+// Unverified certificates (RequestClientCert, RequireAnyClientCert) and requests without a
+// client certificate are still denied: anyone can present a self-signed certificate.
+func (a *AtomicClientCertAllowList) AllowAll() {
+	if a == nil {
+		return
+	}
+	a.snap.Store(&clientCertSnapshot{allowAll: true})
+}
+
+// Match reports whether leaf is allowed.
+//
+// It is safe for concurrent use.
+func (a *AtomicClientCertAllowList) Match(leaf *x509.Certificate, verified bool) (rule string, ok bool) {
+	if a == nil || leaf == nil {
+		return "", false
+	}
+	snap := a.snap.Load()
+	if snap == nil {
+		return "", false
+	}
+	if snap.allowAll {
+		if !verified {
+			return "", false
+		}
+		return "*", true
+	}
+	if len(snap.fingerprints) > 0 {
+		fp := clientCertFingerprint(leaf)
+		if _, ok := snap.fingerprints[fp]; ok {
+			return "sha256:" + fp, true
+		}
+	}
+	if !verified {
+		return "", false
+	}
+	if cn := leaf.Subject.CommonName; cn != "" {
+		if _, ok := snap.cns[cn]; ok {
+			return "cn:" + cn, true
+		}
+	}
+	for _, name := range leaf.DNSNames {
+		n := strings.ToLower(name)
+		if _, ok := snap.dnsNames[n]; ok {
+			return "dns:" + n, true
+		}
+	}
+	for _, u := range leaf.URIs {
+		if u == nil {
+			continue
+		}
+		s := u.String()
+		if _, ok := snap.uris[s]; ok {
+			return "uri:" + s, true
+		}
+		for _, p := range snap.uriPrefixes {
+			if strings.HasPrefix(s, p) {
+				return "uri:" + p + "*", true
+			}
+		}
+	}
+	return "", false
+}
+
+func (a *AtomicClientCertAllowList) empty() bool {
+	if a == nil {
+		return true
+	}
+	snap := a.snap.Load()
+	if snap == nil {
+		return true
+	}
+	return !snap.allowAll && snap.size() == 0
+}
+
+func parseClientCertEntries(entries []string) *clientCertSnapshot {
+	s := &clientCertSnapshot{}
+	add := func(m *map[string]struct{}, v string) {
+		if *m == nil {
+			*m = make(map[string]struct{})
+		}
+		(*m)[v] = struct{}{}
+	}
+	for _, raw := range entries {
+		kind, value, ok := strings.Cut(strings.TrimSpace(raw), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "cn":
+			add(&s.cns, value)
+		case "dns":
+			add(&s.dnsNames, strings.ToLower(value))
+		case "uri":
+			if p, ok := strings.CutSuffix(value, "*"); ok {
+				if p != "" {
+					s.uriPrefixes = append(s.uriPrefixes, p)
+				}
+				continue
+			}
+			add(&s.uris, value)
+		case "sha256":
+			fp, ok := normalizeFingerprint(value)
+			if !ok {
+				continue
+			}
+			add(&s.fingerprints, fp)
+		}
+	}
+	return s
+}
+
+func normalizeFingerprint(s string) (string, bool) {
+	s = strings.ToLower(strings.ReplaceAll(s, ":", ""))
+	if len(s) != sha256.Size*2 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(s); err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+func clientCertFingerprint(c *x509.Certificate) string {
+	sum := sha256.Sum256(c.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+type clientCertSetValidator struct{ set ClientCertSetLike }
+
+type clientCertSetEmptyAware interface {
+	ClientCertSetLike
+	empty() bool
+}
+
+func (v clientCertSetValidator) Validate(r *http.Request) (id *ClientCertIdentity, ok bool, reason DenyReason) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0] == nil {
+		return nil, false, DenyReasonCertMissing
+	}
+	if v.set == nil {
+		return nil, false, DenyReasonCertAllowListEmpty
+	}
+	if ea, ok := v.set.(clientCertSetEmptyAware); ok && ea.empty() {
+		return nil, false, DenyReasonCertAllowListEmpty
+	}
+	leaf := r.TLS.PeerCertificates[0]
+	verified := len(r.TLS.VerifiedChains) > 0
+	rule, ok := v.set.Match(leaf, verified)
+	if !ok {
+		return nil, false, DenyReasonCertNotAllowed
+	}
+	ident := newClientCertIdentity(leaf, verified)
+	ident.MatchedRule = rule
+	return &ident, true, ""
+}
+
+// ClientCertIdentity describes the client certificate accepted by AccessGuard.
+//
+// It is attached to the request context when the client certificate branch passes,
+// and can be read by downstream handlers via ClientCertIdentityFromRequest.
+type ClientCertIdentity struct {
+	Subject     string
+	CommonName  string
+	DNSNames    []string
+	URIs        []string
+	SHA256      string // lowercase hex of the DER certificate digest
+	Verified    bool   // chain verified by the TLS stack
+	MatchedRule string // allowlist entry that matched (e.g. "cn:ops-cli")
+}
+
+func newClientCertIdentity(leaf *x509.Certificate, verified bool) ClientCertIdentity {
+	id := ClientCertIdentity{
+		Subject:    leaf.Subject.String(),
+		CommonName: leaf.Subject.CommonName,
+		SHA256:     clientCertFingerprint(leaf),
+		Verified:   verified,
+	}
+	if len(leaf.DNSNames) > 0 {
+		id.DNSNames = append([]string(nil), leaf.DNSNames...)
+	}
+	for _, u := range leaf.URIs {
+		if u != nil {
+			id.URIs = append(id.URIs, u.String())
+		}
+	}
+	return id
+}
+
+type clientCertIdentityKey struct{}
+
+// ClientCertIdentityFromContext extracts the client certificate identity from ctx.
+//
+// Returns false if the request did not pass an AccessGuard client certificate branch.
+func ClientCertIdentityFromContext(ctx context.Context) (ClientCertIdentity, bool) {
+	if ctx == nil {
+		return ClientCertIdentity{}, false
+	}
+	id, ok := ctx.Value(clientCertIdentityKey{}).(*ClientCertIdentity)
+	if !ok || id == nil {
+		return ClientCertIdentity{}, false
+	}
+	return *id, true
+}
+
+// ClientCertIdentityFromRequest extracts the client certificate identity from r.Context().
+func ClientCertIdentityFromRequest(r *http.Request) (ClientCertIdentity, bool) {
+	if r == nil {
+		return ClientCertIdentity{}, false
+	}
+	return ClientCertIdentityFromContext(r.Context())
+}
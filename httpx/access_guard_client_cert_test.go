@@ -0,0 +1,267 @@
+package httpx
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClientCert(t *testing.T, cn string, dnsNames []string, uris []string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     dnsNames,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, raw := range uris {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", raw, err)
+		}
+		tmpl.URIs = append(tmpl.URIs, u)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	return c
+}
+
+func withClientCert(req *http.Request, c *x509.Certificate, verified bool) *http.Request {
+	st := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{c}}
+	if verified {
+		st.VerifiedChains = [][]*x509.Certificate{{c}}
+	}
+	req.TLS = st
+	return req
+}
+
+func TestAtomicClientCertAllowList_Match(t *testing.T) {
+	c := newTestClientCert(t, "ops-cli", []string{"Ops.Example.Test"}, []string{"spiffe://prod.example/ns/ops/sa/cli"})
+	fp := clientCertFingerprint(c)
+
+	cases := []struct {
+		name     string
+		entries  []string
+		verified bool
+		wantOK   bool
+		wantRule string
+	}{
+		{"nil denies", nil, true, false, ""},
+		{"cn", []string{"cn:ops-cli"}, true, true, "cn:ops-cli"},
+		{"cn requires verified", []string{"cn:ops-cli"}, false, false, ""},
+		{"dns case-insensitive", []string{"dns:ops.example.test"}, true, true, "dns:ops.example.test"},
+		{"uri exact", []string{"uri:spiffe://prod.example/ns/ops/sa/cli"}, true, true, "uri:spiffe://prod.example/ns/ops/sa/cli"},
+		{"uri prefix", []string{"uri:spiffe://prod.example/ns/ops/*"}, true, true, "uri:spiffe://prod.example/ns/ops/*"},
+		{"uri prefix mismatch", []string{"uri:spiffe://prod.example/ns/web/*"}, true, false, ""},
+		{"fingerprint unverified", []string{"sha256:" + strings.ToUpper(fp)}, false, true, "sha256:" + fp},
+		{"invalid entries ignored", []string{"", "bogus", "cn:", "sha256:zz", "cn:other"}, true, false, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := NewAtomicClientCertAllowList()
+			a.Update(tc.entries)
+			rule, ok := a.Match(c, tc.verified)
+			if ok != tc.wantOK || rule != tc.wantRule {
+				t.Fatalf("Match=(%q,%v), want (%q,%v)", rule, ok, tc.wantRule, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestAtomicClientCertAllowList_EmptyAndAllowAll(t *testing.T) {
+	a := NewAtomicClientCertAllowList()
+	if !a.empty() {
+		t.Fatalf("expected new allowlist to be empty")
+	}
+	a.Update([]string{"bogus:x"})
+	if !a.empty() {
+		t.Fatalf("expected allowlist with only invalid entries to be empty")
+	}
+	a.AllowAll()
+	if a.empty() {
+		t.Fatalf("expected allow-all allowlist to be non-empty")
+	}
+	c := newTestClientCert(t, "x", nil, nil)
+	if _, ok := a.Match(c, true); !ok {
+		t.Fatalf("expected allow-all to match a verified cert")
+	}
+	if _, ok := a.Match(c, false); ok {
+		t.Fatalf("expected allow-all to deny an unverified cert")
+	}
+	if _, ok := a.Match(nil, true); ok {
+		t.Fatalf("expected nil cert to be denied")
+	}
+}
+
+func TestAccessGuard_ClientCert(t *testing.T) {
+	c := newTestClientCert(t, "ops-cli", nil, []string{"spiffe://prod.example/ops-cli"})
+	set := NewAtomicClientCertAllowList()
+	set.Update([]string{"uri:spiffe://prod.example/ops-cli"})
+
+	var reasons []DenyReason
+	var got ClientCertIdentity
+	h := Chain(AccessGuard(
+		WithClientCertSet(set),
+		WithOnDeny(func(r *http.Request, reason DenyReason) { reasons = append(reasons, reason) }),
+	)).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := ClientCertIdentityFromRequest(r)
+		if !ok {
+			t.Errorf("expected identity in context")
+		}
+		got = id
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, withClientCert(httptest.NewRequest(http.MethodGet, "https://example.test/", nil), c, true))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	if got.CommonName != "ops-cli" || got.MatchedRule != "uri:spiffe://prod.example/ops-cli" || !got.Verified {
+		t.Fatalf("unexpected identity: %+v", got)
+	}
+	if len(got.URIs) != 1 || got.SHA256 != clientCertFingerprint(c) {
+		t.Fatalf("unexpected identity: %+v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://example.test/", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	// Hot update: remove the entry.
+	set.Update([]string{"uri:spiffe://prod.example/other"})
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, withClientCert(httptest.NewRequest(http.MethodGet, "https://example.test/", nil), c, true))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	set.Update(nil)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, withClientCert(httptest.NewRequest(http.MethodGet, "https://example.test/", nil), c, true))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	want := []DenyReason{DenyReasonCertMissing, DenyReasonCertNotAllowed, DenyReasonCertAllowListEmpty}
+	if len(reasons) != len(want) {
+		t.Fatalf("reasons=%v, want %v", reasons, want)
+	}
+	for i := range want {
+		if reasons[i] != want[i] {
+			t.Fatalf("reasons=%v, want %v", reasons, want)
+		}
+	}
+}
+
+func TestAccessGuard_TokenOrClientCert(t *testing.T) {
+	c := newTestClientCert(t, "ops-cli", nil, nil)
+	var sawIdentity bool
+	h := Chain(AccessGuard(
+		WithTokens([]string{"t1"}),
+		WithClientCertAllowList([]string{"cn:ops-cli"}),
+		WithOr(),
+	)).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, sawIdentity = ClientCertIdentityFromRequest(r)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("token only", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+		req.Header.Set(DefaultAccessGuardTokenHeader, "t1")
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+		}
+		if sawIdentity {
+			t.Fatalf("expected no identity without client cert")
+		}
+	})
+	t.Run("cert only", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, withClientCert(httptest.NewRequest(http.MethodGet, "https://example.test/", nil), c, true))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+		}
+		if !sawIdentity {
+			t.Fatalf("expected identity")
+		}
+	})
+	t.Run("neither", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, withClientCert(httptest.NewRequest(http.MethodGet, "https://example.test/", nil), c, false))
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+}
+
+func TestAccessGuard_ClientCertAndIP(t *testing.T) {
+	c := newTestClientCert(t, "ops-cli", nil, nil)
+	h := Chain(AccessGuard(
+		WithClientCertAllowList([]string{"cn:ops-cli"}),
+		WithIPAllowList([]string{"10.0.0.0/8"}),
+	)).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	req := withClientCert(httptest.NewRequest(http.MethodGet, "https://example.test/", nil), c, true)
+	req.RemoteAddr = "10.1.2.3:1234"
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	req = withClientCert(httptest.NewRequest(http.MethodGet, "https://example.test/", nil), c, true)
+	req.RemoteAddr = "192.168.1.1:1234"
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestAccessGuard_ClientCertOptionConflicts(t *testing.T) {
+	assertPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("%s: expected panic", name)
+			}
+		}()
+		fn()
+	}
+	assertPanic("nil set", func() { _ = AccessGuard(WithClientCertSet(nil)) })
+	assertPanic("duplicate", func() {
+		_ = AccessGuard(WithClientCertAllowList(nil), WithClientCertSet(NewAtomicClientCertAllowList()))
+	})
+	assertPanic("check", func() {
+		_ = AccessGuard(WithClientCertAllowList(nil), WithCheck(func(*http.Request) bool { return true }))
+	})
+}
@@ -0,0 +1,541 @@
+package httpx
+
+import (
+	"container/heap"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DenyReasonLockedOut is reported (to WithOnDeny hooks and Lockout deny counts) when a request
+// is rejected with 429 because its client IP, or the guard as a whole, is locked out.
+const DenyReasonLockedOut DenyReason = "locked-out"
+
+// Lockout tracks AccessGuard failures per client IP and globally, and temporarily locks out
+// offenders (brute-force protection).
+//
+// Policy:
+//   - A client IP that fails Threshold times within Window is locked out for BaseDuration.
+//     Each further lockout of the same IP doubles the duration (exponential), capped at MaxDuration.
+//     Strikes are forgotten after MaxDuration without failures, or after a successful request
+//     through the guard that recorded them (see WithLockout).
+//   - Optionally (GlobalThreshold > 0), when all clients together fail GlobalThreshold times within
+//     Window, every client is locked out (same exponential policy).
+//   - Locked-out requests are rejected with 429 and a Retry-After header, before any guard check.
+//
+// Wire it into a guard with WithLockout (recommended; this also resets a client's failures on
+// success). Alternatively, Lockout.OnDeny can be passed to WithOnDeny to only record failures,
+// and Lockout.Middleware placed in front of the guard to enforce lockouts.
+//
+// The number of tracked IPs is bounded (WithLockoutMaxTracked). When the bound is reached, the
+// entries that expired first are evicted; if none has expired, new IPs are not tracked
+// individually (the global limit still applies).
+//
+// A Lockout may be shared by several guards (e.g. read and write guards of the same admin subtree).
+// It is safe for concurrent use.
+type Lockout struct {
+	cfg lockoutConfig
+
+	guards atomic.Uint64 // last guard ID handed out by WithLockout
+
+	mu         sync.Mutex
+	ips        map[string]*lockoutEntry
+	expiry     lockoutHeap // tracked entries, ordered by pruneAt
+	global     lockoutEntry
+	denyCounts map[DenyReason]uint64
+}
+
+type lockoutEntry struct {
+	windowStart time.Time
+	failures    int
+	strikes     int
+	lastFailure time.Time
+	until       time.Time
+
+	// failedBy is the guard whose denials the failures and strikes come from: 0 = none yet,
+	// lockoutMixed = several guards, or denials recorded by OnDeny.
+	failedBy uint64
+
+	// Per-IP entries only.
+	ip      string
+	pruneAt time.Time // the entry can be dropped once now is after pruneAt
+	index   int       // position in Lockout.expiry
+}
+
+// lockoutHeap is a min-heap of tracked entries by pruneAt, so eviction is O(log n).
+type lockoutHeap []*lockoutEntry
+
+func (h lockoutHeap) Len() int           { return len(h) }
+func (h lockoutHeap) Less(i, j int) bool { return h[i].pruneAt.Before(h[j].pruneAt) }
+func (h lockoutHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *lockoutHeap) Push(x any) {
+	e := x.(*lockoutEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+func (h *lockoutHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+// LockoutOption configures a Lockout.
+type LockoutOption func(*lockoutConfig)
+
+type lockoutConfig struct {
+	threshold       int
+	globalThreshold int
+	window          time.Duration
+	base            time.Duration
+	max             time.Duration
+	maxTracked      int
+	ipResolver      func(r *http.Request) (net.IP, bool)
+	now             func() time.Time
+}
+
+// Lockout defaults.
+const (
+	DefaultLockoutThreshold    = 5
+	DefaultLockoutWindow       = time.Minute
+	DefaultLockoutBaseDuration = time.Minute
+	DefaultLockoutMaxDuration  = time.Hour
+	DefaultLockoutMaxTracked   = 10000
+)
+
+// WithLockoutThreshold sets the number of failures (per IP, within the window) that triggers a lockout.
+//
+// Default is DefaultLockoutThreshold. n <= 0 is ignored.
+func WithLockoutThreshold(n int) LockoutOption {
+	return func(c *lockoutConfig) {
+		if n > 0 {
+			c.threshold = n
+		}
+	}
+}
+
+// WithLockoutGlobalThreshold enables the global lockout: when all clients together fail n times
+// within the window, every client is locked out.
+//
+// Default is 0 (disabled). n <= 0 disables it.
+func WithLockoutGlobalThreshold(n int) LockoutOption {
+	return func(c *lockoutConfig) {
+		if n < 0 {
+			n = 0
+		}
+		c.globalThreshold = n
+	}
+}
+
+// WithLockoutWindow sets the window in which failures are counted.
+//
+// Default is DefaultLockoutWindow. d <= 0 is ignored.
+func WithLockoutWindow(d time.Duration) LockoutOption {
+	return func(c *lockoutConfig) {
+		if d > 0 {
+			c.window = d
+		}
+	}
+}
+
+// WithLockoutDuration sets the first lockout duration and the cap for exponential growth.
+//
+// Defaults are DefaultLockoutBaseDuration and DefaultLockoutMaxDuration.
+// Non-positive values are ignored; max is raised to base if smaller.
+func WithLockoutDuration(base, max time.Duration) LockoutOption {
+	return func(c *lockoutConfig) {
+		if base > 0 {
+			c.base = base
+		}
+		if max > 0 {
+			c.max = max
+		}
+	}
+}
+
+// WithLockoutMaxTracked bounds the number of client IPs tracked individually.
+//
+// Default is DefaultLockoutMaxTracked. n <= 0 is ignored.
+func WithLockoutMaxTracked(n int) LockoutOption {
+	return func(c *lockoutConfig) {
+		if n > 0 {
+			c.maxTracked = n
+		}
+	}
+}
+
+// WithLockoutIPResolver sets the client IP resolver used by Lockout.OnDeny and Lockout.Middleware.
+//
+// Guards wired with WithLockout use the guard's own resolver (see WithIPResolver).
+// Default is RealIPFromRequest when present, otherwise RemoteAddr. If fn is nil, the option is ignored.
+func WithLockoutIPResolver(fn func(r *http.Request) (net.IP, bool)) LockoutOption {
+	return func(c *lockoutConfig) {
+		if fn != nil {
+			c.ipResolver = fn
+		}
+	}
+}
+
+// WithLockoutNow sets a custom clock (tests). If fn is nil, the option is ignored.
+func WithLockoutNow(fn func() time.Time) LockoutOption {
+	return func(c *lockoutConfig) {
+		if fn != nil {
+			c.now = fn
+		}
+	}
+}
+
+// NewLockout creates a Lockout with the given options.
+func NewLockout(opts ...LockoutOption) *Lockout {
+	cfg := lockoutConfig{
+		threshold:  DefaultLockoutThreshold,
+		window:     DefaultLockoutWindow,
+		base:       DefaultLockoutBaseDuration,
+		max:        DefaultLockoutMaxDuration,
+		maxTracked: DefaultLockoutMaxTracked,
+		ipResolver: defaultAccessGuardIPResolver,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.max < cfg.base {
+		cfg.max = cfg.base
+	}
+	return &Lockout{
+		cfg:        cfg,
+		ips:        make(map[string]*lockoutEntry),
+		denyCounts: make(map[DenyReason]uint64),
+	}
+}
+
+// WithLockout wires l into AccessGuard:
+//   - requests from a locked-out client IP (or while globally locked out) are rejected with 429
+//     and Retry-After, before any other check; WithOnDeny observes them as DenyReasonLockedOut
+//   - every denial is recorded as a failure for the client IP (and globally)
+//   - an allowed request clears its client IP's failures and strikes, if they were all recorded
+//     by this guard: when l is shared (e.g. by read and write guards), a success on one guard
+//     does not clear failures recorded by another
+//
+// The client IP comes from the guard's IP resolver (see WithIPResolver).
+// If l is nil, the option is ignored.
+func WithLockout(l *Lockout) AccessGuardOption {
+	return func(c *accessGuardConfig) {
+		if l != nil {
+			c.lockout = l
+		}
+	}
+}
+
+// OnDeny records a denial. Its signature matches WithOnDeny, so it can be used as a hook
+// on guards that do not use WithLockout.
+//
+// DenyReasonLockedOut is counted but not recorded as a failure.
+func (l *Lockout) OnDeny(r *http.Request, reason DenyReason) {
+	if l == nil || r == nil {
+		return
+	}
+	l.recordDeny(l.clientKey(r, l.cfg.ipResolver), lockoutMixed, reason)
+}
+
+// Middleware returns a middleware that rejects locked-out clients with 429 and Retry-After.
+//
+// Use it in front of a guard that reports denials via OnDeny. Guards wired with WithLockout
+// already enforce lockouts and do not need it.
+func (l *Lockout) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		if next == nil {
+			panic("httpx: nil next handler")
+		}
+		if l == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r == nil {
+				panic("httpx: nil request")
+			}
+			key := l.clientKey(r, l.cfg.ipResolver)
+			if retry, locked := l.check(key); locked {
+				l.recordDeny(key, lockoutMixed, DenyReasonLockedOut)
+				writeLockedOut(w, retry)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Clear removes the lockout and failure history of ip. It reports whether ip was tracked.
+func (l *Lockout) Clear(ip string) bool {
+	if l == nil {
+		return false
+	}
+	key := normalizeLockoutIP(ip)
+	if key == "" {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.ips[key]
+	if ok {
+		l.untrackLocked(e)
+	}
+	return ok
+}
+
+// ClearGlobal removes the global lockout and failure history.
+func (l *Lockout) ClearGlobal() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.global = lockoutEntry{}
+	l.mu.Unlock()
+}
+
+// ClearAll removes every per-IP and global lockout. Deny counts are kept.
+func (l *Lockout) ClearAll() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.ips = make(map[string]*lockoutEntry)
+	l.expiry = nil
+	l.global = lockoutEntry{}
+	l.mu.Unlock()
+}
+
+// LockoutEntry describes a locked-out client IP.
+type LockoutEntry struct {
+	IP          string        `json:"ip"`
+	Strikes     int           `json:"strikes"`
+	LockedUntil time.Time     `json:"locked_until"`
+	RetryAfter  time.Duration `json:"retry_after"`
+}
+
+// LockoutSnapshot is a point-in-time view of a Lockout.
+type LockoutSnapshot struct {
+	Now time.Time `json:"now"`
+
+	// GlobalLockedUntil is non-zero while the global lockout is active.
+	GlobalLockedUntil time.Time `json:"global_locked_until,omitempty"`
+
+	// Locked lists currently locked-out client IPs, sorted by IP.
+	Locked []LockoutEntry `json:"locked"`
+
+	// Tracked is the number of client IPs with recorded failures (locked or not).
+	Tracked int `json:"tracked"`
+
+	// DenyCounts counts denials by reason since creation (including DenyReasonLockedOut).
+	DenyCounts map[DenyReason]uint64 `json:"deny_counts"`
+}
+
+// Snapshot returns the current lockouts and deny counts.
+func (l *Lockout) Snapshot() LockoutSnapshot {
+	if l == nil {
+		return LockoutSnapshot{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.cfg.now()
+	s := LockoutSnapshot{
+		Now:        now,
+		Tracked:    len(l.ips),
+		Locked:     []LockoutEntry{},
+		DenyCounts: make(map[DenyReason]uint64, len(l.denyCounts)),
+	}
+	if l.global.until.After(now) {
+		s.GlobalLockedUntil = l.global.until
+	}
+	for ip, e := range l.ips {
+		if !e.until.After(now) {
+			continue
+		}
+		s.Locked = append(s.Locked, LockoutEntry{
+			IP:          ip,
+			Strikes:     e.strikes,
+			LockedUntil: e.until,
+			RetryAfter:  e.until.Sub(now),
+		})
+	}
+	sort.Slice(s.Locked, func(i, j int) bool { return s.Locked[i].IP < s.Locked[j].IP })
+	for k, v := range l.denyCounts {
+		s.DenyCounts[k] = v
+	}
+	return s
+}
+
+func (l *Lockout) clientKey(r *http.Request, resolver func(*http.Request) (net.IP, bool)) string {
+	if resolver == nil {
+		resolver = defaultAccessGuardIPResolver
+	}
+	ip, ok := resolver(r)
+	if !ok || ip == nil {
+		return ""
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return ip.String()
+}
+
+func normalizeLockoutIP(s string) string {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return ""
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return ip.String()
+}
+
+// check reports whether key (or the guard as a whole) is currently locked out.
+func (l *Lockout) check(key string) (retryAfter time.Duration, locked bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.cfg.now()
+	if l.global.until.After(now) {
+		retryAfter = l.global.until.Sub(now)
+		locked = true
+	}
+	if e := l.ips[key]; key != "" && e != nil && e.until.After(now) {
+		if d := e.until.Sub(now); d > retryAfter {
+			retryAfter = d
+		}
+		locked = true
+	}
+	return retryAfter, locked
+}
+
+// lockoutMixed is the failedBy value of failures that no single guard's success may clear.
+const lockoutMixed = ^uint64(0)
+
+// newGuardID returns an ID identifying one guard wired with WithLockout.
+func (l *Lockout) newGuardID() uint64 { return l.guards.Add(1) }
+
+// recordDeny records a denial by guard (a newGuardID ID, or lockoutMixed).
+func (l *Lockout) recordDeny(key string, guard uint64, reason DenyReason) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.denyCounts[reason]++
+	if reason == DenyReasonLockedOut {
+		return
+	}
+	now := l.cfg.now()
+	if l.cfg.globalThreshold > 0 {
+		l.fail(&l.global, now, l.cfg.globalThreshold, lockoutMixed)
+	}
+	if key == "" {
+		return
+	}
+	e := l.ips[key]
+	if e == nil {
+		if len(l.ips) >= l.cfg.maxTracked {
+			l.pruneLocked(now)
+		}
+		if len(l.ips) >= l.cfg.maxTracked {
+			return
+		}
+		e = &lockoutEntry{ip: key}
+		l.ips[key] = e
+		heap.Push(&l.expiry, e)
+	}
+	l.fail(e, now, l.cfg.threshold, guard)
+	e.pruneAt = l.pruneAt(e)
+	heap.Fix(&l.expiry, e.index)
+}
+
+// recordSuccess records an allowed request by guard: it clears key's entry unless another guard
+// (or OnDeny) contributed to it.
+func (l *Lockout) recordSuccess(key string, guard uint64) {
+	if key == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e := l.ips[key]; e != nil && e.failedBy == guard && !e.until.After(l.cfg.now()) {
+		l.untrackLocked(e)
+	}
+}
+
+func (l *Lockout) fail(e *lockoutEntry, now time.Time, threshold int, guard uint64) {
+	if e.until.After(now) {
+		return
+	}
+	if !e.lastFailure.IsZero() && now.Sub(e.lastFailure) > l.cfg.max {
+		e.strikes = 0
+	}
+	if now.Sub(e.windowStart) > l.cfg.window {
+		e.windowStart = now
+		e.failures = 0
+	}
+	if e.strikes == 0 && e.failures == 0 { // nothing left from earlier denials
+		e.failedBy = guard
+	} else if e.failedBy != guard {
+		e.failedBy = lockoutMixed
+	}
+	e.failures++
+	e.lastFailure = now
+	if e.failures < threshold {
+		return
+	}
+	e.strikes++
+	d := l.cfg.base
+	for i := 1; i < e.strikes && d < l.cfg.max; i++ {
+		d *= 2
+	}
+	if d > l.cfg.max {
+		d = l.cfg.max
+	}
+	e.until = now.Add(d)
+	e.failures = 0
+	e.windowStart = now
+}
+
+// pruneAt returns the time after which e is neither locked out nor carrying recent failures or
+// strikes.
+func (l *Lockout) pruneAt(e *lockoutEntry) time.Time {
+	t := e.lastFailure.Add(l.cfg.window)
+	if e.until.After(t) {
+		t = e.until
+	}
+	if e.strikes > 0 {
+		if s := e.lastFailure.Add(l.cfg.max); s.After(t) {
+			t = s
+		}
+	}
+	return t
+}
+
+// pruneLocked evicts the entries that can be dropped, in expiry order.
+func (l *Lockout) pruneLocked(now time.Time) {
+	for len(l.expiry) > 0 && now.After(l.expiry[0].pruneAt) {
+		l.untrackLocked(l.expiry[0])
+	}
+}
+
+func (l *Lockout) untrackLocked(e *lockoutEntry) {
+	heap.Remove(&l.expiry, e.index)
+	delete(l.ips, e.ip)
+}
+
+func writeLockedOut(w http.ResponseWriter, retryAfter time.Duration) {
+	secs := int64((retryAfter + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+	w.WriteHeader(http.StatusTooManyRequests)
+}
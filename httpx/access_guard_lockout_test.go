@@ -0,0 +1,245 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func lockoutTestRequest(ip, token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+	req.RemoteAddr = ip + ":1234"
+	if token != "" {
+		req.Header.Set(DefaultAccessGuardTokenHeader, token)
+	}
+	return req
+}
+
+func TestAccessGuard_WithLockout(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLockout(
+		WithLockoutThreshold(3),
+		WithLockoutWindow(time.Minute),
+		WithLockoutDuration(10*time.Second, 30*time.Second),
+		WithLockoutNow(clk.Now),
+	)
+	var reasons []DenyReason
+	h := Chain(AccessGuard(
+		WithTokens([]string{"good"}),
+		WithLockout(l),
+		WithOnDeny(func(r *http.Request, reason DenyReason) { reasons = append(reasons, reason) }),
+	)).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(ip, token string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, lockoutTestRequest(ip, token))
+		return rr
+	}
+
+	for i := 0; i < 3; i++ {
+		if rr := serve("10.0.0.1", "bad"); rr.Code != http.StatusForbidden {
+			t.Fatalf("attempt %d: expected %d, got %d", i, http.StatusForbidden, rr.Code)
+		}
+	}
+
+	// Locked out: even the right token is rejected with 429.
+	rr := serve("10.0.0.1", "good")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d, got %d", http.StatusTooManyRequests, rr.Code)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "10" {
+		t.Fatalf("Retry-After=%q, want %q", got, "10")
+	}
+	if reasons[len(reasons)-1] != DenyReasonLockedOut {
+		t.Fatalf("last reason=%q, want %q", reasons[len(reasons)-1], DenyReasonLockedOut)
+	}
+
+	// Other IPs are unaffected.
+	if rr := serve("10.0.0.2", "good"); rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	snap := l.Snapshot()
+	if len(snap.Locked) != 1 || snap.Locked[0].IP != "10.0.0.1" || snap.Locked[0].Strikes != 1 {
+		t.Fatalf("unexpected snapshot: %+v", snap)
+	}
+	if snap.DenyCounts[DenyReasonTokenNotAllowed] != 3 || snap.DenyCounts[DenyReasonLockedOut] != 1 {
+		t.Fatalf("unexpected deny counts: %+v", snap.DenyCounts)
+	}
+
+	// Second lockout doubles the duration.
+	clk.Advance(11 * time.Second)
+	for i := 0; i < 3; i++ {
+		serve("10.0.0.1", "bad")
+	}
+	rr = serve("10.0.0.1", "good")
+	if got := rr.Header().Get("Retry-After"); rr.Code != http.StatusTooManyRequests || got != "20" {
+		t.Fatalf("code=%d Retry-After=%q, want 429/20", rr.Code, got)
+	}
+
+	// Third lockout is capped at the max duration.
+	clk.Advance(21 * time.Second)
+	for i := 0; i < 3; i++ {
+		serve("10.0.0.1", "bad")
+	}
+	rr = serve("10.0.0.1", "good")
+	if got := rr.Header().Get("Retry-After"); rr.Code != http.StatusTooManyRequests || got != "30" {
+		t.Fatalf("code=%d Retry-After=%q, want 429/30", rr.Code, got)
+	}
+
+	// Clear lifts the lockout immediately.
+	if !l.Clear("10.0.0.1") {
+		t.Fatalf("expected Clear to report a tracked IP")
+	}
+	if rr := serve("10.0.0.1", "good"); rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	if l.Clear("10.0.0.1") {
+		t.Fatalf("expected Clear to report untracked IP")
+	}
+}
+
+func TestAccessGuard_WithLockout_SuccessResetsFailures(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLockout(WithLockoutThreshold(2), WithLockoutNow(clk.Now))
+	h := Chain(AccessGuard(WithTokens([]string{"good"}), WithLockout(l))).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for i := 0; i < 5; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), lockoutTestRequest("10.0.0.1", "bad"))
+		clk.Advance(time.Second)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, lockoutTestRequest("10.0.0.1", "good"))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("round %d: expected %d, got %d", i, http.StatusOK, rr.Code)
+		}
+	}
+}
+
+func TestLockout_Global(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLockout(
+		WithLockoutThreshold(100),
+		WithLockoutGlobalThreshold(3),
+		WithLockoutDuration(time.Minute, time.Hour),
+		WithLockoutNow(clk.Now),
+	)
+	h := Chain(l.Middleware(), AccessGuard(WithTokens([]string{"good"}), WithOnDeny(l.OnDeny))).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		h.ServeHTTP(httptest.NewRecorder(), lockoutTestRequest(ip, "bad"))
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, lockoutTestRequest("10.0.0.9", "good"))
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d, got %d", http.StatusTooManyRequests, rr.Code)
+	}
+	if snap := l.Snapshot(); snap.GlobalLockedUntil.IsZero() || len(snap.Locked) != 0 {
+		t.Fatalf("unexpected snapshot: %+v", snap)
+	}
+
+	l.ClearGlobal()
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, lockoutTestRequest("10.0.0.9", "good"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+}
+
+func TestLockout_MaxTracked(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLockout(WithLockoutThreshold(1), WithLockoutMaxTracked(2), WithLockoutNow(clk.Now))
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		l.OnDeny(lockoutTestRequest(ip, ""), DenyReasonTokenMissing)
+	}
+	snap := l.Snapshot()
+	if snap.Tracked != 2 || len(snap.Locked) != 2 {
+		t.Fatalf("unexpected snapshot: %+v", snap)
+	}
+	if snap.DenyCounts[DenyReasonTokenMissing] != 3 {
+		t.Fatalf("unexpected deny counts: %+v", snap.DenyCounts)
+	}
+	l.ClearAll()
+	if snap := l.Snapshot(); snap.Tracked != 0 || len(snap.Locked) != 0 {
+		t.Fatalf("unexpected snapshot after ClearAll: %+v", snap)
+	}
+}
+
+func TestLockout_MaxTracked_EvictsExpiredFirst(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLockout(WithLockoutThreshold(5), WithLockoutWindow(time.Minute), WithLockoutMaxTracked(2), WithLockoutNow(clk.Now))
+	l.OnDeny(lockoutTestRequest("10.0.0.1", ""), DenyReasonTokenMissing)
+	clk.Advance(30 * time.Second)
+	l.OnDeny(lockoutTestRequest("10.0.0.2", ""), DenyReasonTokenMissing)
+	l.OnDeny(lockoutTestRequest("10.0.0.3", ""), DenyReasonTokenMissing) // table full, nothing expired
+	if _, ok := l.ips["10.0.0.3"]; ok {
+		t.Fatal("10.0.0.3 should not be tracked")
+	}
+
+	clk.Advance(31 * time.Second) // 10.0.0.1's failure is outside the window
+	l.OnDeny(lockoutTestRequest("10.0.0.3", ""), DenyReasonTokenMissing)
+	if _, ok := l.ips["10.0.0.1"]; ok {
+		t.Fatal("10.0.0.1 should have been evicted")
+	}
+	for _, ip := range []string{"10.0.0.2", "10.0.0.3"} {
+		if _, ok := l.ips[ip]; !ok {
+			t.Fatalf("%s should be tracked", ip)
+		}
+	}
+	if len(l.expiry) != len(l.ips) {
+		t.Fatalf("heap size %d, tracked %d", len(l.expiry), len(l.ips))
+	}
+	if !l.Clear("10.0.0.2") || len(l.expiry) != 1 {
+		t.Fatalf("clear: heap size %d", len(l.expiry))
+	}
+}
+
+func TestLockout_SharedGuards_SuccessOnOtherGuardDoesNotClear(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLockout(WithLockoutThreshold(3), WithLockoutNow(clk.Now))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	read := Chain(AccessGuard(WithTokens([]string{"read"}), WithLockout(l))).Handler(ok)
+	write := Chain(AccessGuard(WithTokens([]string{"write"}), WithLockout(l))).Handler(ok)
+	serve := func(h http.Handler, token string) int {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, lockoutTestRequest("10.0.0.1", token))
+		return rr.Code
+	}
+
+	// A read-token holder guessing the write token, with a good read between guesses.
+	for i := 0; i < 3; i++ {
+		if code := serve(write, "guess"); code != http.StatusForbidden {
+			t.Fatalf("guess %d: status=%d", i, code)
+		}
+		if i < 2 {
+			if code := serve(read, "read"); code != http.StatusOK {
+				t.Fatalf("read %d: status=%d", i, code)
+			}
+		}
+	}
+	if code := serve(write, "write"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected lockout, got %d", code)
+	}
+
+	// Failures recorded by a single guard are still cleared by its own success.
+	clk.Advance(2 * time.Hour)
+	l.ClearAll()
+	_ = serve(write, "guess")
+	_ = serve(write, "guess")
+	if code := serve(write, "write"); code != http.StatusOK {
+		t.Fatalf("status=%d", code)
+	}
+	_ = serve(write, "guess")
+	_ = serve(write, "guess")
+	if code := serve(write, "write"); code != http.StatusOK {
+		t.Fatalf("success did not clear failures: status=%d", code)
+	}
+}
@@ -67,10 +67,11 @@
 //   - Recover: recover panics and report (stderr by default).
 //   - RequestID: propagate/generate X-Request-ID and store it in context.
 //   - RealIP: extract client IP from trusted proxy headers (default-safe).
-//   - AccessGuard: allow/deny requests by token/IP/custom check (fail-closed defaults).
+//   - AccessGuard: allow/deny requests by token/IP/client cert/custom check (fail-closed defaults).
 //   - Timeout: derive request context with deadline (does not write response).
 //   - BodyLimit: enforce request body size (early reject on Content-Length + MaxBytesReader).
 //   - CORS: write CORS headers and short-circuit preflight (debug-friendly defaults).
+//   - DebugLog: mark selected requests for per-request debug logging (signed header or DebugTargets).
 //
 // # Middleware options quick reference (admin-oriented)
 //
@@ -115,15 +116,36 @@
 //   - WithIPResolver(func(*http.Request) (net.IP, bool)): override IP extraction
 //     (default: RealIPFromRequest when present, else RemoteAddr).
 //
+// Client certificate branch (optional; mTLS):
+//   - WithClientCertAllowList([]string): static allowlist of "cn:", "dns:", "uri:" (trailing * = prefix),
+//     "sha256:" entries (empty => deny-all, fail-closed; name entries require a verified chain).
+//   - WithClientCertSet(ClientCertSetLike): hot-update allow set.
+//   - ClientCertIdentityFromRequest / ClientCertIdentityFromContext: accepted identity for downstream handlers.
+//
 // Composition / hooks:
-//   - WithOr(): combine token+IP with OR instead of default AND.
+//   - WithOr(): combine token/IP/cert branches with OR instead of default AND.
 //   - WithCheck(func(*http.Request) bool): exclusive custom validator (cannot combine with token/IP options).
 //   - WithDenyStatus(int): override deny HTTP status (default: 403).
 //   - WithOnDeny(func(*http.Request, DenyReason)): observability hook on deny (must not write response).
+//   - WithLockout(*Lockout): brute-force protection (per-IP/global failure tracking, exponential
+//     lockouts, 429 + Retry-After; see NewLockout and its WithLockout* options).
 //
 // Helper types (for hot updates):
 //   - AtomicTokenSet (implements TokenSetLike)
 //   - AtomicIPAllowList (implements IPAllowSetLike)
+//   - AtomicClientCertAllowList (implements ClientCertSetLike)
+//   - Lockout (Snapshot / Clear / ClearGlobal / ClearAll; OnDeny usable with WithOnDeny)
+//
+// DebugLog (DebugLogOption):
+//   - WithDebugLogHeader(string): header carrying the debug token (default: "X-Debug-Log").
+//   - WithDebugLogSecret([]byte): accept expiring HMAC tokens made by SignDebugToken.
+//   - WithDebugLogHeaderCheck(func(*http.Request, string) bool): custom header check.
+//   - WithDebugLogTargets(*DebugTargets): match registered request ids / client IPs (entries expire).
+//
+// Helpers:
+//   - DebugFromRequest / DebugFromContext
+//   - WithDebug
+//   - DebugTargets (Add / Remove / Snapshot / Match)
 //
 // Timeout (TimeoutOption):
 //   - Timeout(timeout time.Duration, ...): base timeout parameter; <= 0 means "skip".
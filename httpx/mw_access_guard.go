@@ -3,6 +3,7 @@
 // AccessGuard denies requests unless they pass configured checks. It supports:
 //   - token validation (from a header, default: X-Access-Token)
 //   - client IP allowlist (RealIP middleware when present; otherwise RemoteAddr)
+//   - client certificate allowlist (mTLS; CN, DNS/URI SANs, SHA-256 fingerprints)
 //   - a fully custom WithCheck predicate (exclusive)
 //
 // Security defaults are fail-closed: enabling token/IP validation with an empty set denies all.
@@ -14,9 +15,14 @@
 // Minimal usage (IP allowlist):
 //
 //	h := httpx.Wrap(finalHandler, httpx.AccessGuard(httpx.WithIPAllowList([]string{"10.0.0.0/8"})))
+//
+// Minimal usage (client certificate allowlist; requires TLS client auth on the server):
+//
+//	h := httpx.Wrap(finalHandler, httpx.AccessGuard(httpx.WithClientCertAllowList([]string{"uri:spiffe://prod.example/ops-cli"})))
 package httpx
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"strings"
@@ -38,12 +44,17 @@ type accessGuardConfig struct {
 	ipResolver func(r *http.Request) (net.IP, bool)
 	ipV        ipValidator
 
+	certV certValidator
+
 	check  func(r *http.Request) bool
 	onDeny func(r *http.Request, reason DenyReason)
 
+	lockout *Lockout
+
 	// Assembly tracking (used to detect conflicting options).
 	haveTokenV bool
 	haveIPV    bool
+	haveCertV  bool
 	haveCheck  bool
 }
 
@@ -61,15 +72,18 @@ const (
 type DenyReason string
 
 const (
-	DenyReasonTokenMissing      DenyReason = "token-missing"
-	DenyReasonTokenAmbiguous    DenyReason = "token-ambiguous"
-	DenyReasonTokenEmpty        DenyReason = "token-empty"
-	DenyReasonTokenSetEmpty     DenyReason = "token-set-empty"
-	DenyReasonTokenNotAllowed   DenyReason = "token-not-allowed"
-	DenyReasonIPParseFailed     DenyReason = "ip-parse-failed"
-	DenyReasonIPAllowListEmpty  DenyReason = "ip-allowlist-empty"
-	DenyReasonIPNotAllowed      DenyReason = "ip-not-allowed"
-	DenyReasonCustomCheckDenied DenyReason = "check-denied"
+	DenyReasonTokenMissing       DenyReason = "token-missing"
+	DenyReasonTokenAmbiguous     DenyReason = "token-ambiguous"
+	DenyReasonTokenEmpty         DenyReason = "token-empty"
+	DenyReasonTokenSetEmpty      DenyReason = "token-set-empty"
+	DenyReasonTokenNotAllowed    DenyReason = "token-not-allowed"
+	DenyReasonIPParseFailed      DenyReason = "ip-parse-failed"
+	DenyReasonIPAllowListEmpty   DenyReason = "ip-allowlist-empty"
+	DenyReasonIPNotAllowed       DenyReason = "ip-not-allowed"
+	DenyReasonCertMissing        DenyReason = "cert-missing"
+	DenyReasonCertAllowListEmpty DenyReason = "cert-allowlist-empty"
+	DenyReasonCertNotAllowed     DenyReason = "cert-not-allowed"
+	DenyReasonCustomCheckDenied  DenyReason = "check-denied"
 )
 
 type tokenValidator interface {
@@ -80,6 +94,10 @@ type ipValidator interface {
 	Validate(ip net.IP) (ok bool, reason DenyReason)
 }
 
+type certValidator interface {
+	Validate(r *http.Request) (id *ClientCertIdentity, ok bool, reason DenyReason)
+}
+
 // WithTokenHeader sets the header name used for token validation.
 //
 // Default is DefaultAccessGuardTokenHeader. Empty/blank names are ignored.
@@ -179,6 +197,47 @@ func WithIPAllowSet(set IPAllowSetLike) AccessGuardOption {
 	}
 }
 
+// WithClientCertAllowList enables client certificate validation with a static allowlist.
+//
+// Entries use the AtomicClientCertAllowList syntax ("cn:", "dns:", "uri:", "sha256:").
+// The guard only inspects the certificate the TLS stack accepted: configure
+// tls.Config.ClientAuth (and ClientCAs) on the server; name-based entries
+// require a verified chain.
+//
+// Semantics:
+//   - entries == nil: enabled, but deny-all (fail-closed)
+//   - len(entries) == 0: enabled, but deny-all (fail-closed)
+//   - invalid entries are ignored; if no valid entries remain, deny-all (fail-closed)
+//   - requests without a client certificate are denied by this branch
+//
+// On success, the accepted identity is available via ClientCertIdentityFromRequest.
+// To disable certificate validation, do NOT configure any certificate-related option.
+func WithClientCertAllowList(entries []string) AccessGuardOption {
+	return func(c *accessGuardConfig) {
+		ensureNoCheck(c, "WithClientCertAllowList")
+		ensureNoCertV(c, "WithClientCertAllowList")
+		allow := NewAtomicClientCertAllowList()
+		allow.Update(entries)
+		c.certV = clientCertSetValidator{set: allow}
+		c.haveCertV = true
+	}
+}
+
+// WithClientCertSet enables client certificate validation with a user-provided allow set.
+//
+// set must be non-nil. To disable certificate validation, do NOT configure any certificate-related option.
+func WithClientCertSet(set ClientCertSetLike) AccessGuardOption {
+	return func(c *accessGuardConfig) {
+		ensureNoCheck(c, "WithClientCertSet")
+		ensureNoCertV(c, "WithClientCertSet")
+		if set == nil {
+			panic("httpx: AccessGuard WithClientCertSet: nil client cert set")
+		}
+		c.certV = clientCertSetValidator{set: set}
+		c.haveCertV = true
+	}
+}
+
 // WithIPResolver sets a custom IP resolver.
 //
 // Default is:
@@ -207,9 +266,9 @@ func WithDenyStatus(code int) AccessGuardOption {
 
 // WithOr switches the combination logic from the default AND to OR.
 //
-// When both token and IP are enabled:
-//   - default (AND): ok = tokenOK && ipOK
-//   - WithOr (OR):  ok = tokenOK || ipOK
+// When several branches (token, IP, client certificate) are enabled:
+//   - default (AND): every enabled branch must pass (e.g. tokenOK && ipOK)
+//   - WithOr (OR):  at least one enabled branch must pass (e.g. tokenOK || certOK)
 func WithOr() AccessGuardOption {
 	return func(c *accessGuardConfig) {
 		ensureNoCheck(c, "WithOr")
@@ -231,8 +290,8 @@ func WithCheck(fn func(r *http.Request) bool) AccessGuardOption {
 		if c.logic == guardLogicAny {
 			panic("httpx: AccessGuard WithCheck conflicts with WithOr")
 		}
-		if c.haveTokenV || c.haveIPV {
-			panic("httpx: AccessGuard WithCheck conflicts with token/ip options")
+		if c.haveTokenV || c.haveIPV || c.haveCertV {
+			panic("httpx: AccessGuard WithCheck conflicts with token/ip/cert options")
 		}
 		c.check = fn
 		c.haveCheck = true
@@ -256,18 +315,23 @@ func WithOnDeny(fn func(r *http.Request, reason DenyReason)) AccessGuardOption {
 // AccessGuard returns a middleware that enforces an access guard based on:
 //   - optional token validation (token from a header + validator)
 //   - optional client IP allowlist (real IP from RealIP middleware when present)
+//   - optional client certificate allowlist (mTLS)
 //
 // Rules:
 //   - If IP allowlist is enabled, the client IP must match one of the allowlisted CIDRs/IPs.
 //     Client IP is taken from RealIP middleware when present; otherwise it falls back to RemoteAddr.
 //   - If token validation is enabled, the request must provide exactly one non-empty token header value
 //     that matches the configured token validator.
-//   - If both are enabled, both checks must pass (AND).
-//   - If neither is enabled, it panics (configuration/assembly error).
+//   - If client certificate validation is enabled, the TLS connection must carry a client
+//     certificate accepted by the configured set; the identity is then attached to the request
+//     context (see ClientCertIdentityFromRequest).
+//   - If several are enabled, all checks must pass (AND), unless WithOr() is set.
+//   - If none is enabled, it panics (configuration/assembly error).
 //
 // Security defaults:
 //   - When token validation is enabled but the token set is empty, it denies all (fail-closed).
 //   - When IP validation is enabled but the allowlist is empty, it denies all (fail-closed).
+//   - When certificate validation is enabled but the allowlist is empty, it denies all (fail-closed).
 //   - OR must be explicitly enabled via WithOr().
 func AccessGuard(opts ...AccessGuardOption) Middleware {
 	cfg := accessGuardConfig{
@@ -289,18 +353,24 @@ func AccessGuard(opts ...AccessGuardOption) Middleware {
 	}
 
 	if cfg.haveCheck {
-		if cfg.haveTokenV || cfg.haveIPV {
-			panic("httpx: AccessGuard WithCheck conflicts with token/ip options")
+		if cfg.haveTokenV || cfg.haveIPV || cfg.haveCertV {
+			panic("httpx: AccessGuard WithCheck conflicts with token/ip/cert options")
 		}
 	}
 
 	tokenEnabled := cfg.tokenV != nil
 	ipEnabled := cfg.ipV != nil
+	certEnabled := cfg.certV != nil
 	checkEnabled := cfg.check != nil
-	if !tokenEnabled && !ipEnabled && !checkEnabled {
+	if !tokenEnabled && !ipEnabled && !certEnabled && !checkEnabled {
 		panic("httpx: access_guard has no checks configured")
 	}
 
+	var lockGuard uint64
+	if cfg.lockout != nil {
+		lockGuard = cfg.lockout.newGuardID()
+	}
+
 	return func(next http.Handler) http.Handler {
 		if next == nil {
 			panic("httpx: nil next handler")
@@ -310,11 +380,31 @@ func AccessGuard(opts ...AccessGuardOption) Middleware {
 				panic("httpx: nil request")
 			}
 
+			var lockKey string
+			if cfg.lockout != nil {
+				lockKey = cfg.lockout.clientKey(r, cfg.ipResolver)
+				if retry, locked := cfg.lockout.check(lockKey); locked {
+					cfg.lockout.recordDeny(lockKey, lockGuard, DenyReasonLockedOut)
+					accessGuardNotifyDeny(r, cfg.onDeny, DenyReasonLockedOut)
+					writeLockedOut(w, retry)
+					return
+				}
+			}
+			deny := func(reason DenyReason) {
+				if cfg.lockout != nil {
+					cfg.lockout.recordDeny(lockKey, lockGuard, reason)
+				}
+				accessGuardDeny(w, r, cfg.denyStatus, cfg.onDeny, reason)
+			}
+
 			if checkEnabled {
 				if !cfg.check(r) {
-					accessGuardDeny(w, r, cfg.denyStatus, cfg.onDeny, DenyReasonCustomCheckDenied)
+					deny(DenyReasonCustomCheckDenied)
 					return
 				}
+				if cfg.lockout != nil {
+					cfg.lockout.recordSuccess(lockKey, lockGuard)
+				}
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -322,8 +412,11 @@ func AccessGuard(opts ...AccessGuardOption) Middleware {
 			var (
 				tokenOK  = true
 				ipOK     = true
+				certOK   = true
 				tokenWhy DenyReason
 				ipWhy    DenyReason
+				certWhy  DenyReason
+				certID   *ClientCertIdentity
 			)
 			if tokenEnabled {
 				tokenOK, tokenWhy = accessGuardTokenOK(r, cfg.tokenHeader, cfg.tokenV)
@@ -331,7 +424,14 @@ func AccessGuard(opts ...AccessGuardOption) Middleware {
 			if ipEnabled {
 				ipOK, ipWhy = accessGuardIPOK(r, cfg.ipResolver, cfg.ipV)
 			}
-			ok := accessGuardCombine(cfg.logic, tokenEnabled, tokenOK, ipEnabled, ipOK)
+			if certEnabled {
+				certID, certOK, certWhy = cfg.certV.Validate(r)
+			}
+			ok := accessGuardCombine(cfg.logic,
+				guardBranch{enabled: tokenEnabled, ok: tokenOK},
+				guardBranch{enabled: ipEnabled, ok: ipOK},
+				guardBranch{enabled: certEnabled, ok: certOK},
+			)
 			if !ok {
 				// Prefer reporting the first enabled branch's reason, for stability.
 				reason := DenyReasonCustomCheckDenied
@@ -339,36 +439,51 @@ func AccessGuard(opts ...AccessGuardOption) Middleware {
 					reason = tokenWhy
 				} else if ipEnabled && !ipOK {
 					reason = ipWhy
+				} else if certEnabled && !certOK {
+					reason = certWhy
 				}
-				accessGuardDeny(w, r, cfg.denyStatus, cfg.onDeny, reason)
+				deny(reason)
 				return
 			}
 
+			if cfg.lockout != nil {
+				cfg.lockout.recordSuccess(lockKey, lockGuard)
+			}
+			if certID != nil {
+				r = r.WithContext(context.WithValue(r.Context(), clientCertIdentityKey{}, certID))
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-func accessGuardCombine(logic guardLogic, tokenEnabled bool, tokenOK bool, ipEnabled bool, ipOK bool) bool {
-	switch logic {
-	case guardLogicAny:
-		// OR mode: if both enabled => tokenOK || ipOK; if only one enabled => that one.
-		if tokenEnabled && ipEnabled {
-			return tokenOK || ipOK
+type guardBranch struct {
+	enabled bool
+	ok      bool
+}
+
+func accessGuardCombine(logic guardLogic, branches ...guardBranch) bool {
+	enabled := 0
+	passed := 0
+	for _, b := range branches {
+		if !b.enabled {
+			continue
 		}
-		if tokenEnabled {
-			return tokenOK
+		enabled++
+		if b.ok {
+			passed++
 		}
-		return ipOK
+	}
+	if enabled == 0 {
+		return false
+	}
+	switch logic {
+	case guardLogicAny:
+		// OR mode: at least one enabled branch must pass.
+		return passed > 0
 	default:
-		// AND mode (default): if both enabled => tokenOK && ipOK; if only one enabled => that one.
-		if tokenEnabled && ipEnabled {
-			return tokenOK && ipOK
-		}
-		if tokenEnabled {
-			return tokenOK
-		}
-		return ipOK
+		// AND mode (default): every enabled branch must pass.
+		return passed == enabled
 	}
 }
 
@@ -421,12 +536,16 @@ func singleHeaderValueWithReason(h http.Header, name string) (value string, reas
 }
 
 func accessGuardDeny(w http.ResponseWriter, r *http.Request, status int, onDeny func(*http.Request, DenyReason), reason DenyReason) {
+	accessGuardNotifyDeny(r, onDeny, reason)
+	w.WriteHeader(status)
+}
+
+func accessGuardNotifyDeny(r *http.Request, onDeny func(*http.Request, DenyReason), reason DenyReason) {
 	if onDeny != nil {
 		if p := callOnDenyNoPanic(onDeny, r, reason); p != nil {
 			reportAccessGuardHookPanicToStderr(r, p)
 		}
 	}
-	w.WriteHeader(status)
 }
 
 func callOnDenyNoPanic(fn func(*http.Request, DenyReason), r *http.Request, reason DenyReason) (panicked any) {
@@ -451,6 +570,12 @@ func ensureNoIPV(c *accessGuardConfig, opt string) {
 	}
 }
 
+func ensureNoCertV(c *accessGuardConfig, opt string) {
+	if c.haveCertV {
+		panic("httpx: AccessGuard " + opt + " conflicts with existing client cert option")
+	}
+}
+
 func ensureNoCheck(c *accessGuardConfig, opt string) {
 	if c.haveCheck {
 		panic("httpx: AccessGuard " + opt + " conflicts with WithCheck")
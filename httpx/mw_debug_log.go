@@ -0,0 +1,521 @@
+// DebugLog middleware.
+//
+// DebugLog marks selected requests for debug logging. Loggers honoring the mark (see
+// slogx.DebugHandler) lower the effective level to debug for that request only, so one bad
+// request can be investigated without raising the process-wide level.
+//
+// A request is marked when either:
+//   - it carries the debug header (default X-Debug-Log) and the header value is accepted by the
+//     configured verifier: a signed, expiring token (WithDebugLogSecret, see SignDebugToken) or a
+//     custom check (WithDebugLogHeaderCheck). Without a verifier the header is ignored.
+//   - its request id or client IP matches a DebugTargets entry (WithDebugLogTargets). Entries are
+//     registered at runtime (e.g. through the admin /log/debug-targets/add endpoint) and expire.
+//
+// Place DebugLog after RequestID and RealIP so that targets can match on their values:
+//
+//	targets := httpx.NewDebugTargets()
+//	h := httpx.Wrap(finalHandler,
+//		httpx.RequestID(),
+//		httpx.RealIP(...),
+//		httpx.DebugLog(httpx.WithDebugLogSecret(secret), httpx.WithDebugLogTargets(targets)),
+//	)
+//
+// Extracting:
+//
+//	if httpx.DebugFromRequest(r) { ... }
+package httpx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDebugLogHeader is the default header carrying a debug token.
+const DefaultDebugLogHeader = "X-Debug-Log"
+
+// DebugLogOption configures the DebugLog middleware.
+type DebugLogOption func(*debugLogConfig)
+
+type debugLogConfig struct {
+	header  string
+	secret  []byte
+	check   func(r *http.Request, value string) bool
+	targets *DebugTargets
+	now     func() time.Time
+}
+
+// WithDebugLogHeader sets the header carrying the debug token.
+//
+// Default is DefaultDebugLogHeader. Blank names are ignored.
+func WithDebugLogHeader(name string) DebugLogOption {
+	return func(c *debugLogConfig) {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			c.header = name
+		}
+	}
+}
+
+// WithDebugLogSecret accepts header values produced by SignDebugToken with the same secret
+// that have not expired yet.
+//
+// An empty secret is ignored.
+func WithDebugLogSecret(secret []byte) DebugLogOption {
+	return func(c *debugLogConfig) {
+		if len(secret) > 0 {
+			c.secret = append([]byte(nil), secret...)
+		}
+	}
+}
+
+// WithDebugLogHeaderCheck accepts header values for which fn returns true (e.g. a token set
+// lookup). It is consulted when the signed-token check (if any) fails.
+//
+// fn must be fast and must not block. If fn is nil, the option is ignored.
+func WithDebugLogHeaderCheck(fn func(r *http.Request, value string) bool) DebugLogOption {
+	return func(c *debugLogConfig) {
+		if fn != nil {
+			c.check = fn
+		}
+	}
+}
+
+// WithDebugLogTargets marks requests whose request id or client IP is registered in t.
+// If t is nil, the option is ignored.
+func WithDebugLogTargets(t *DebugTargets) DebugLogOption {
+	return func(c *debugLogConfig) {
+		if t != nil {
+			c.targets = t
+		}
+	}
+}
+
+// WithDebugLogNow sets a custom clock for token expiry checks (tests). If fn is nil, the option is ignored.
+func WithDebugLogNow(fn func() time.Time) DebugLogOption {
+	return func(c *debugLogConfig) {
+		if fn != nil {
+			c.now = fn
+		}
+	}
+}
+
+// DebugLog returns a middleware that marks selected requests for debug logging.
+// See the file-level documentation for the matching rules.
+func DebugLog(opts ...DebugLogOption) Middleware {
+	cfg := debugLogConfig{
+		header: DefaultDebugLogHeader,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	verify := len(cfg.secret) > 0 || cfg.check != nil
+
+	return func(next http.Handler) http.Handler {
+		if next == nil {
+			panic("httpx: nil next handler")
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r == nil {
+				panic("httpx: nil request")
+			}
+			if DebugFromContext(r.Context()) || !cfg.match(r, verify) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithDebug(r.Context())))
+		})
+	}
+}
+
+func (c *debugLogConfig) match(r *http.Request, verify bool) bool {
+	if verify {
+		if v := strings.TrimSpace(r.Header.Get(c.header)); v != "" {
+			if len(c.secret) > 0 && verifyDebugToken(c.secret, v, c.now()) {
+				return true
+			}
+			if c.check != nil && c.check(r, v) {
+				return true
+			}
+		}
+	}
+	return c.targets != nil && c.targets.Match(r)
+}
+
+// SignDebugToken returns a debug header value accepted by WithDebugLogSecret(secret) until expiresAt.
+//
+// The token is "<unix expiry>.<hex HMAC-SHA256 of the expiry>"; it is not bound to a request,
+// so keep expiries short.
+func SignDebugToken(secret []byte, expiresAt time.Time) string {
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return exp + "." + debugTokenMAC(secret, exp)
+}
+
+func debugTokenMAC(secret []byte, exp string) string {
+	m := hmac.New(sha256.New, secret)
+	_, _ = m.Write([]byte(exp))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+func verifyDebugToken(secret []byte, token string, now time.Time) bool {
+	exp, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || !now.Before(time.Unix(unix, 0)) {
+		return false
+	}
+	return hmac.Equal([]byte(mac), []byte(debugTokenMAC(secret, exp)))
+}
+
+// debugKey is the context key for the debug logging mark.
+type debugKey struct{}
+
+// WithDebug returns a derived context marked for debug logging.
+func WithDebug(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, debugKey{}, true)
+}
+
+// DebugFromContext reports whether ctx is marked for debug logging.
+func DebugFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(debugKey{}).(bool)
+	return v
+}
+
+// DebugFromRequest reports whether r.Context() is marked for debug logging.
+func DebugFromRequest(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	return DebugFromContext(r.Context())
+}
+
+// DebugTargetKind is the kind of value a DebugTarget matches.
+type DebugTargetKind string
+
+// Debug target kinds.
+const (
+	// DebugTargetRequestID matches the request id stored by the RequestID middleware.
+	DebugTargetRequestID DebugTargetKind = "request_id"
+	// DebugTargetIP matches the client IP (an exact IP or a CIDR).
+	DebugTargetIP DebugTargetKind = "ip"
+)
+
+// DebugTargets defaults.
+const (
+	DefaultDebugTargetTTL         = 10 * time.Minute
+	DefaultDebugTargetsMaxTTL     = time.Hour
+	DefaultDebugTargetsMaxTracked = 100
+)
+
+// DebugTargets errors.
+var (
+	// ErrInvalidDebugTarget is returned for an unknown kind or an invalid value.
+	ErrInvalidDebugTarget = errors.New("httpx: invalid debug target")
+	// ErrDebugTargetTTL is returned when the ttl exceeds the configured maximum.
+	ErrDebugTargetTTL = errors.New("httpx: debug target ttl exceeds maximum")
+	// ErrTooManyDebugTargets is returned when the registry is full.
+	ErrTooManyDebugTargets = errors.New("httpx: too many debug targets")
+)
+
+// DebugTarget is a registered request id or client IP.
+type DebugTarget struct {
+	Kind      DebugTargetKind `json:"kind"`
+	Value     string          `json:"value"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	// Hits counts requests marked because of this target.
+	Hits uint64 `json:"hits"`
+}
+
+// DebugTargets is a registry of request ids and client IPs whose requests DebugLog marks for
+// debug logging. Every entry expires; expired entries are dropped lazily.
+//
+// It is safe for concurrent use. Match is cheap while the registry is empty.
+type DebugTargets struct {
+	cfg debugTargetsConfig
+
+	// live is a copy-on-write view of entries for Match, which never takes mu unless the view
+	// holds an expired entry. nil when there are no entries.
+	live atomic.Pointer[debugTargetsView]
+
+	mu      sync.Mutex
+	entries map[debugTargetKey]*debugTargetEntry
+}
+
+type debugTargetsView struct {
+	next time.Time // earliest expiry: from then on the view must be pruned
+	ids  map[string]debugTargetEntry
+	ips  []debugTargetEntry
+}
+
+type debugTargetKey struct {
+	kind  DebugTargetKind
+	value string
+}
+
+type debugTargetEntry struct {
+	expires time.Time
+	ipNet   *net.IPNet // DebugTargetIP only
+	hits    *atomic.Uint64
+}
+
+// DebugTargetsOption configures DebugTargets.
+type DebugTargetsOption func(*debugTargetsConfig)
+
+type debugTargetsConfig struct {
+	maxTTL     time.Duration
+	maxTracked int
+	ipResolver func(r *http.Request) (net.IP, bool)
+	now        func() time.Time
+}
+
+// WithDebugTargetsMaxTTL sets the longest ttl Add accepts.
+//
+// Default is DefaultDebugTargetsMaxTTL. d <= 0 is ignored.
+func WithDebugTargetsMaxTTL(d time.Duration) DebugTargetsOption {
+	return func(c *debugTargetsConfig) {
+		if d > 0 {
+			c.maxTTL = d
+		}
+	}
+}
+
+// WithDebugTargetsMaxTracked bounds the number of registered targets.
+//
+// Default is DefaultDebugTargetsMaxTracked. n <= 0 is ignored.
+func WithDebugTargetsMaxTracked(n int) DebugTargetsOption {
+	return func(c *debugTargetsConfig) {
+		if n > 0 {
+			c.maxTracked = n
+		}
+	}
+}
+
+// WithDebugTargetsIPResolver sets the client IP resolver used by Match.
+//
+// Default is RealIPFromRequest when present, otherwise RemoteAddr. If fn is nil, the option is ignored.
+func WithDebugTargetsIPResolver(fn func(r *http.Request) (net.IP, bool)) DebugTargetsOption {
+	return func(c *debugTargetsConfig) {
+		if fn != nil {
+			c.ipResolver = fn
+		}
+	}
+}
+
+// WithDebugTargetsNow sets a custom clock (tests). If fn is nil, the option is ignored.
+func WithDebugTargetsNow(fn func() time.Time) DebugTargetsOption {
+	return func(c *debugTargetsConfig) {
+		if fn != nil {
+			c.now = fn
+		}
+	}
+}
+
+// NewDebugTargets creates an empty registry.
+func NewDebugTargets(opts ...DebugTargetsOption) *DebugTargets {
+	cfg := debugTargetsConfig{
+		maxTTL:     DefaultDebugTargetsMaxTTL,
+		maxTracked: DefaultDebugTargetsMaxTracked,
+		ipResolver: defaultAccessGuardIPResolver,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return &DebugTargets{
+		cfg:     cfg,
+		entries: make(map[debugTargetKey]*debugTargetEntry),
+	}
+}
+
+// Add registers a target for ttl. ttl <= 0 means DefaultDebugTargetTTL.
+//
+// IP values may be an IP or a CIDR; they are normalized (the returned Value is the canonical
+// form). Adding an existing target replaces its expiry.
+func (t *DebugTargets) Add(kind DebugTargetKind, value string, ttl time.Duration) (DebugTarget, error) {
+	key, ipNet, err := normalizeDebugTarget(kind, value)
+	if err != nil {
+		return DebugTarget{}, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultDebugTargetTTL
+	}
+	if ttl > t.cfg.maxTTL {
+		return DebugTarget{}, ErrDebugTargetTTL
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.cfg.now()
+	t.pruneLocked(now)
+	e := t.entries[key]
+	if e == nil {
+		if len(t.entries) >= t.cfg.maxTracked {
+			return DebugTarget{}, ErrTooManyDebugTargets
+		}
+		e = &debugTargetEntry{ipNet: ipNet, hits: new(atomic.Uint64)}
+		t.entries[key] = e
+	}
+	e.expires = now.Add(ttl)
+	t.publishLocked()
+	return DebugTarget{Kind: key.kind, Value: key.value, ExpiresAt: e.expires, Hits: e.hits.Load()}, nil
+}
+
+// Remove unregisters a target. It reports whether the target was registered (and not expired).
+func (t *DebugTargets) Remove(kind DebugTargetKind, value string) bool {
+	key, _, err := normalizeDebugTarget(kind, value)
+	if err != nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[key]
+	if !ok {
+		return false
+	}
+	delete(t.entries, key)
+	t.publishLocked()
+	return e.expires.After(t.cfg.now())
+}
+
+// Snapshot returns the live targets, sorted by kind and value.
+func (t *DebugTargets) Snapshot() []DebugTarget {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pruneLocked(t.cfg.now())
+	out := make([]DebugTarget, 0, len(t.entries))
+	for k, e := range t.entries {
+		out = append(out, DebugTarget{Kind: k.kind, Value: k.value, ExpiresAt: e.expires, Hits: e.hits.Load()})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// Match reports whether r's request id or client IP is a live target, counting a hit if so.
+//
+// It reads a copy-on-write view of the targets and only locks to prune expired ones.
+func (t *DebugTargets) Match(r *http.Request) bool {
+	if t == nil || r == nil {
+		return false
+	}
+	v := t.live.Load()
+	if v == nil {
+		return false
+	}
+	now := t.cfg.now()
+	if !now.Before(v.next) {
+		t.mu.Lock()
+		t.pruneLocked(now)
+		t.mu.Unlock()
+		if v = t.live.Load(); v == nil {
+			return false
+		}
+	}
+
+	if id, _ := RequestIDFromRequest(r); id != "" {
+		if e, ok := v.ids[id]; ok && e.expires.After(now) {
+			e.hits.Add(1)
+			return true
+		}
+	}
+	if len(v.ips) == 0 {
+		return false
+	}
+	ip, _ := t.cfg.ipResolver(r)
+	if ip == nil {
+		return false
+	}
+	for _, e := range v.ips {
+		if e.expires.After(now) && e.ipNet.Contains(ip) {
+			e.hits.Add(1)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *DebugTargets) pruneLocked(now time.Time) {
+	n := len(t.entries)
+	for k, e := range t.entries {
+		if !e.expires.After(now) {
+			delete(t.entries, k)
+		}
+	}
+	if len(t.entries) != n {
+		t.publishLocked()
+	}
+}
+
+// publishLocked rebuilds the view read by Match.
+func (t *DebugTargets) publishLocked() {
+	if len(t.entries) == 0 {
+		t.live.Store(nil)
+		return
+	}
+	v := &debugTargetsView{ids: make(map[string]debugTargetEntry)}
+	for k, e := range t.entries {
+		if v.next.IsZero() || e.expires.Before(v.next) {
+			v.next = e.expires
+		}
+		if k.kind == DebugTargetIP {
+			v.ips = append(v.ips, *e)
+		} else {
+			v.ids[k.value] = *e
+		}
+	}
+	t.live.Store(v)
+}
+
+func normalizeDebugTarget(kind DebugTargetKind, value string) (debugTargetKey, *net.IPNet, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return debugTargetKey{}, nil, ErrInvalidDebugTarget
+	}
+	switch kind {
+	case DebugTargetRequestID:
+		if !defaultValidateRequestID(value, 128) {
+			return debugTargetKey{}, nil, ErrInvalidDebugTarget
+		}
+		return debugTargetKey{kind: kind, value: value}, nil, nil
+	case DebugTargetIP:
+		nets := parseCIDRsOrIPs([]string{value})
+		if len(nets) != 1 {
+			return debugTargetKey{}, nil, ErrInvalidDebugTarget
+		}
+		n := nets[0]
+		s := n.String()
+		if ones, bits := n.Mask.Size(); ones == bits {
+			s = n.IP.String()
+		}
+		return debugTargetKey{kind: kind, value: s}, n, nil
+	default:
+		return debugTargetKey{}, nil, ErrInvalidDebugTarget
+	}
+}
@@ -0,0 +1,191 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func debugLogProbe(h Middleware) func(req *http.Request) bool {
+	var marked bool
+	wrapped := h(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		marked = DebugFromRequest(r)
+	}))
+	return func(req *http.Request) bool {
+		marked = false
+		wrapped.ServeHTTP(httptest.NewRecorder(), req)
+		return marked
+	}
+}
+
+func TestDebugLog_SignedHeader(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	secret := []byte("k")
+	serve := debugLogProbe(DebugLog(WithDebugLogSecret(secret), WithDebugLogNow(clk.Now)))
+	req := func(v string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+		if v != "" {
+			r.Header.Set(DefaultDebugLogHeader, v)
+		}
+		return r
+	}
+
+	tok := SignDebugToken(secret, clk.t.Add(time.Minute))
+	if !serve(req(tok)) {
+		t.Fatalf("valid token not accepted")
+	}
+	if serve(req("")) {
+		t.Fatalf("request without header marked")
+	}
+	if serve(req(SignDebugToken([]byte("other"), clk.t.Add(time.Minute)))) {
+		t.Fatalf("token signed with another secret accepted")
+	}
+	if serve(req(tok + "0")) {
+		t.Fatalf("tampered token accepted")
+	}
+	clk.Advance(time.Minute)
+	if serve(req(tok)) {
+		t.Fatalf("expired token accepted")
+	}
+}
+
+func TestDebugLog_HeaderIgnoredWithoutVerifier(t *testing.T) {
+	serve := debugLogProbe(DebugLog())
+	r := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+	r.Header.Set(DefaultDebugLogHeader, "anything")
+	if serve(r) {
+		t.Fatalf("unverified header marked the request")
+	}
+}
+
+func TestDebugLog_HeaderCheck(t *testing.T) {
+	serve := debugLogProbe(DebugLog(
+		WithDebugLogHeader("X-Dbg"),
+		WithDebugLogHeaderCheck(func(r *http.Request, v string) bool { return v == "let-me-in" }),
+	))
+	r := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+	r.Header.Set("X-Dbg", "let-me-in")
+	if !serve(r) {
+		t.Fatalf("checked header not accepted")
+	}
+	r.Header.Set("X-Dbg", "nope")
+	if serve(r) {
+		t.Fatalf("rejected header marked the request")
+	}
+}
+
+func TestDebugLog_Targets(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	targets := NewDebugTargets(WithDebugTargetsNow(clk.Now), WithDebugTargetsMaxTTL(time.Hour))
+	mw := DebugLog(WithDebugLogTargets(targets))
+	serve := debugLogProbe(func(next http.Handler) http.Handler {
+		return RequestID()(mw(next))
+	})
+	req := func(ip, id string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+		r.RemoteAddr = ip + ":1234"
+		if id != "" {
+			r.Header.Set(DefaultRequestIDHeader, id)
+		}
+		return r
+	}
+
+	if serve(req("10.0.0.1", "")) {
+		t.Fatalf("marked with empty registry")
+	}
+	if _, err := targets.Add(DebugTargetIP, "10.1.0.0/16", time.Minute); err != nil {
+		t.Fatalf("Add ip: %v", err)
+	}
+	if _, err := targets.Add(DebugTargetRequestID, "req-42", 2*time.Minute); err != nil {
+		t.Fatalf("Add request_id: %v", err)
+	}
+	if !serve(req("10.1.2.3", "")) || serve(req("10.2.0.1", "")) {
+		t.Fatalf("ip target mismatch")
+	}
+	if !serve(req("10.2.0.1", "req-42")) {
+		t.Fatalf("request_id target not matched")
+	}
+
+	snap := targets.Snapshot()
+	if len(snap) != 2 || snap[0].Kind != DebugTargetIP || snap[0].Value != "10.1.0.0/16" || snap[0].Hits != 1 ||
+		snap[1].Kind != DebugTargetRequestID || snap[1].Hits != 1 {
+		t.Fatalf("snapshot=%+v", snap)
+	}
+
+	// Entries expire on their own.
+	clk.Advance(time.Minute)
+	if serve(req("10.1.2.3", "")) {
+		t.Fatalf("expired ip target still matched")
+	}
+	if snap := targets.Snapshot(); len(snap) != 1 || snap[0].Kind != DebugTargetRequestID {
+		t.Fatalf("snapshot after expiry=%+v", snap)
+	}
+	if !targets.Remove(DebugTargetRequestID, "req-42") || targets.Remove(DebugTargetRequestID, "req-42") {
+		t.Fatalf("Remove mismatch")
+	}
+}
+
+func TestDebugTargets_AddValidation(t *testing.T) {
+	targets := NewDebugTargets(WithDebugTargetsMaxTTL(time.Hour), WithDebugTargetsMaxTracked(1))
+
+	for _, tc := range []struct {
+		kind  DebugTargetKind
+		value string
+		ttl   time.Duration
+		want  error
+	}{
+		{DebugTargetIP, "not-an-ip", 0, ErrInvalidDebugTarget},
+		{DebugTargetRequestID, "bad id", 0, ErrInvalidDebugTarget},
+		{"user", "x", 0, ErrInvalidDebugTarget},
+		{DebugTargetIP, "10.0.0.1", 2 * time.Hour, ErrDebugTargetTTL},
+	} {
+		if _, err := targets.Add(tc.kind, tc.value, tc.ttl); !errors.Is(err, tc.want) {
+			t.Fatalf("Add(%q, %q, %v) err=%v, want %v", tc.kind, tc.value, tc.ttl, err, tc.want)
+		}
+	}
+
+	got, err := targets.Add(DebugTargetIP, " ::ffff:10.0.0.1 ", 0)
+	if err != nil || got.Value != "10.0.0.1" {
+		t.Fatalf("Add normalized=%+v err=%v", got, err)
+	}
+	if !got.ExpiresAt.After(time.Now().Add(DefaultDebugTargetTTL - time.Minute)) {
+		t.Fatalf("default ttl not applied: %v", got.ExpiresAt)
+	}
+	if _, err := targets.Add(DebugTargetIP, "10.0.0.1", time.Minute); err != nil {
+		t.Fatalf("re-adding an existing target: %v", err)
+	}
+	if _, err := targets.Add(DebugTargetIP, "10.0.0.2", 0); !errors.Is(err, ErrTooManyDebugTargets) {
+		t.Fatalf("err=%v, want ErrTooManyDebugTargets", err)
+	}
+}
+
+func TestDebugTargets_MatchPrunesExpired(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1700000000, 0)}
+	targets := NewDebugTargets(WithDebugTargetsNow(clk.Now))
+	_, _ = targets.Add(DebugTargetIP, "10.0.0.0/8", time.Minute)
+	_, _ = targets.Add(DebugTargetIP, "192.168.1.1", 2*time.Minute)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+	req.RemoteAddr = "10.1.2.3:1234"
+	if !targets.Match(req) {
+		t.Fatal("expected a match")
+	}
+
+	clk.Advance(90 * time.Second) // the first target expired: Match prunes it
+	if targets.Match(req) {
+		t.Fatal("expired target matched")
+	}
+	if v := targets.live.Load(); v == nil || len(v.ips) != 1 {
+		t.Fatalf("view=%+v", v)
+	}
+
+	clk.Advance(time.Minute) // all expired: Match is back to the empty fast path
+	if targets.Match(req) || targets.live.Load() != nil {
+		t.Fatal("expected no live targets")
+	}
+	if snap := targets.Snapshot(); len(snap) != 0 {
+		t.Fatalf("snapshot=%+v", snap)
+	}
+}
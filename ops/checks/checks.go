@@ -0,0 +1,298 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/httpx/client"
+	"github.com/evan-idocoding/zkit/ops"
+	"github.com/evan-idocoding/zkit/rt/task"
+)
+
+// --- network ---
+
+// TCPDial checks that a TCP connection to addr ("host:port") can be established.
+// The connection is closed immediately.
+func TCPDial(addr string) ops.ReadyCheckFunc {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		panic("checks: TCPDial: empty addr")
+	}
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("tcp dial %s: %w", addr, err)
+		}
+		_ = conn.Close()
+		return nil
+	}
+}
+
+// DNSResolve checks that host resolves to at least one address.
+func DNSResolve(host string) ops.ReadyCheckFunc {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		panic("checks: DNSResolve: empty host")
+	}
+	return func(ctx context.Context) error {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return fmt.Errorf("dns %s: %w", host, err)
+		}
+		if len(addrs) == 0 {
+			return fmt.Errorf("dns %s: no addresses", host)
+		}
+		return nil
+	}
+}
+
+type httpConfig struct {
+	client *http.Client
+	status []int
+	header http.Header
+}
+
+// HTTPOption configures HTTPGet.
+type HTTPOption func(*httpConfig)
+
+// WithHTTPClient sets the client used by HTTPGet. nil means default (client.New()).
+func WithHTTPClient(c *http.Client) HTTPOption {
+	return func(cfg *httpConfig) { cfg.client = c }
+}
+
+// WithHTTPStatus sets the accepted status codes. Empty means default (any 2xx).
+func WithHTTPStatus(codes ...int) HTTPOption {
+	return func(cfg *httpConfig) { cfg.status = append([]int(nil), codes...) }
+}
+
+// WithHTTPHeader adds a request header (e.g. Authorization).
+func WithHTTPHeader(key, value string) HTTPOption {
+	return func(cfg *httpConfig) {
+		if cfg.header == nil {
+			cfg.header = make(http.Header)
+		}
+		cfg.header.Add(key, value)
+	}
+}
+
+// httpDrainLimit bounds how much of the response body is read to allow connection reuse.
+const httpDrainLimit = 4 << 10
+
+// HTTPGet checks that a GET of rawURL answers with an accepted status (default: any 2xx).
+//
+// The request uses the check context, so its deadline is ReadyCheck.Timeout.
+// The response body is drained (up to 4 KiB) and discarded.
+func HTTPGet(rawURL string, opts ...HTTPOption) ops.ReadyCheckFunc {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		panic("checks: HTTPGet: empty url")
+	}
+	var cfg httpConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.client == nil {
+		cfg.client = client.New()
+	}
+	if _, err := http.NewRequest(http.MethodGet, rawURL, nil); err != nil {
+		panic("checks: HTTPGet: invalid url: " + err.Error())
+	}
+	want := "2xx"
+	if len(cfg.status) > 0 {
+		s := make([]string, len(cfg.status))
+		for i, c := range cfg.status {
+			s[i] = fmt.Sprint(c)
+		}
+		want = strings.Join(s, ", ")
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return fmt.Errorf("http GET %s: %w", rawURL, err)
+		}
+		for k, vs := range cfg.header {
+			req.Header[k] = append([]string(nil), vs...)
+		}
+		resp, err := cfg.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("http GET %s: %w", rawURL, unwrapURLError(err))
+		}
+		_ = client.DrainAndClose(resp.Body, httpDrainLimit)
+		if !statusAccepted(resp.StatusCode, cfg.status) {
+			return fmt.Errorf("http GET %s: status %s (want %s)", rawURL, resp.Status, want)
+		}
+		return nil
+	}
+}
+
+func statusAccepted(code int, accepted []int) bool {
+	if len(accepted) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, c := range accepted {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// unwrapURLError drops *url.Error's "Get \"<url>\":" prefix; the check error already names the URL.
+func unwrapURLError(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return ue.Err
+	}
+	return err
+}
+
+// --- files ---
+
+// FileExists checks that path exists and is a regular file (symlinks are followed).
+func FileExists(path string) ops.ReadyCheckFunc {
+	path = requirePath(path, "FileExists")
+	return func(context.Context) error {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("file %s: %w", path, unwrapPathError(err))
+		}
+		if !fi.Mode().IsRegular() {
+			return fmt.Errorf("file %s: not a regular file (%s)", path, fi.Mode().Type())
+		}
+		return nil
+	}
+}
+
+// DirExists checks that path exists and is a directory (symlinks are followed).
+func DirExists(path string) ops.ReadyCheckFunc {
+	path = requirePath(path, "DirExists")
+	return func(context.Context) error {
+		return statDir(path)
+	}
+}
+
+// DirWritable checks that path is a directory in which a file can be created.
+//
+// It creates and removes a temporary file named ".zkit-check-*".
+func DirWritable(path string) ops.ReadyCheckFunc {
+	path = requirePath(path, "DirWritable")
+	return func(context.Context) error {
+		if err := statDir(path); err != nil {
+			return err
+		}
+		f, err := os.CreateTemp(path, ".zkit-check-*")
+		if err != nil {
+			return fmt.Errorf("dir %s: not writable: %w", path, unwrapPathError(err))
+		}
+		name := f.Name()
+		werr := f.Close()
+		rerr := os.Remove(name)
+		if werr != nil {
+			return fmt.Errorf("dir %s: not writable: %w", path, werr)
+		}
+		if rerr != nil {
+			return fmt.Errorf("dir %s: remove probe file %s: %w", path, filepath.Base(name), unwrapPathError(rerr))
+		}
+		return nil
+	}
+}
+
+func statDir(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("dir %s: %w", path, unwrapPathError(err))
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("dir %s: not a directory", path)
+	}
+	return nil
+}
+
+func requirePath(path, fn string) string {
+	if strings.TrimSpace(path) == "" {
+		panic("checks: " + fn + ": empty path")
+	}
+	return path
+}
+
+// unwrapPathError drops *fs.PathError's "op path:" prefix; the check error already names the path.
+func unwrapPathError(err error) error {
+	var pe *os.PathError
+	if errors.As(err, &pe) {
+		return pe.Err
+	}
+	return err
+}
+
+// --- database/sql ---
+
+// Pinger is implemented by *sql.DB and *sql.Conn.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SQLPing checks a database with PingContext (e.g. a *sql.DB).
+func SQLPing(db Pinger) ops.ReadyCheckFunc {
+	if db == nil {
+		panic("checks: SQLPing: nil db")
+	}
+	return func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("sql ping: %w", err)
+		}
+		return nil
+	}
+}
+
+// --- tasks ---
+
+// TaskSucceededWithin checks that the task named name (in mgr) succeeded within the last
+// window, based on task.Status.LastSuccess.
+//
+// The task is looked up on every run, so it may be registered after the check is created.
+// A task that has not succeeded yet fails the check until its first success.
+func TaskSucceededWithin(mgr *task.Manager, name string, window time.Duration) ops.ReadyCheckFunc {
+	if mgr == nil {
+		panic("checks: TaskSucceededWithin: nil Manager")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		panic("checks: TaskSucceededWithin: empty name")
+	}
+	if window <= 0 {
+		panic("checks: TaskSucceededWithin: window must be > 0")
+	}
+	return func(context.Context) error {
+		h, ok := mgr.Lookup(name)
+		if !ok {
+			return fmt.Errorf("task %q: not found", name)
+		}
+		st := h.Status()
+		if st.LastSuccess.IsZero() {
+			return fmt.Errorf("task %q: never succeeded%s", name, lastErrorSuffix(st))
+		}
+		if age := time.Since(st.LastSuccess); age > window {
+			return fmt.Errorf("task %q: last success %s ago, want within %s%s",
+				name, age.Truncate(time.Second), window, lastErrorSuffix(st))
+		}
+		return nil
+	}
+}
+
+func lastErrorSuffix(st task.Status) string {
+	if st.LastError == "" || !st.LastFinished.After(st.LastSuccess) {
+		return ""
+	}
+	return " (last error: " + st.LastError + ")"
+}
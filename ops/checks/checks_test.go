@@ -0,0 +1,239 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evan-idocoding/zkit/rt/task"
+)
+
+func assertPanics(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	fn()
+}
+
+func wantErrContains(t *testing.T, err error, substr string) {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), substr) {
+		t.Fatalf("err=%v, want containing %q", err, substr)
+	}
+}
+
+func TestTCPDial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	if err := TCPDial(addr)(context.Background()); err != nil {
+		t.Fatalf("dial open port: %v", err)
+	}
+	_ = ln.Close()
+	wantErrContains(t, TCPDial(addr)(context.Background()), "tcp dial "+addr+": ")
+
+	assertPanics(t, func() { TCPDial(" ") })
+}
+
+func TestDNSResolve(t *testing.T) {
+	if err := DNSResolve("localhost")(context.Background()); err != nil {
+		t.Fatalf("localhost: %v", err)
+	}
+	wantErrContains(t, DNSResolve("does-not-exist.invalid")(context.Background()), "dns does-not-exist.invalid: ")
+	assertPanics(t, func() { DNSResolve("") })
+}
+
+func TestHTTPGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t" && r.URL.Path == "/auth" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/moved":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(strings.Repeat("x", 1<<16)))
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if err := HTTPGet(srv.URL + "/ok")(ctx); err != nil {
+		t.Fatalf("ok: %v", err)
+	}
+	wantErrContains(t, HTTPGet(srv.URL+"/down")(ctx), "http GET "+srv.URL+"/down: status 503 Service Unavailable (want 2xx)")
+	wantErrContains(t, HTTPGet(srv.URL+"/moved", WithHTTPStatus(200))(ctx), "status 204 No Content (want 200)")
+	if err := HTTPGet(srv.URL+"/down", WithHTTPStatus(503))(ctx); err != nil {
+		t.Fatalf("accepted 503: %v", err)
+	}
+	wantErrContains(t, HTTPGet(srv.URL+"/auth")(ctx), "status 401")
+	if err := HTTPGet(srv.URL+"/auth", WithHTTPHeader("Authorization", "Bearer t"))(ctx); err != nil {
+		t.Fatalf("auth: %v", err)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err := HTTPGet(srv.URL, WithHTTPClient(srv.Client()))(cctx)
+	if !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), `Get "`) {
+		t.Fatalf("canceled err=%v", err)
+	}
+
+	assertPanics(t, func() { HTTPGet("") })
+	assertPanics(t, func() { HTTPGet("http://[::1") })
+}
+
+func TestFileChecks(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cfg.yaml")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := FileExists(file)(ctx); err != nil {
+		t.Fatalf("FileExists: %v", err)
+	}
+	wantErrContains(t, FileExists(dir)(ctx), "file "+dir+": not a regular file")
+	err := FileExists(filepath.Join(dir, "missing"))(ctx)
+	wantErrContains(t, err, "missing: ")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("err=%v, want ErrNotExist", err)
+	}
+
+	if err := DirExists(dir)(ctx); err != nil {
+		t.Fatalf("DirExists: %v", err)
+	}
+	wantErrContains(t, DirExists(file)(ctx), "dir "+file+": not a directory")
+
+	if err := DirWritable(dir)(ctx); err != nil {
+		t.Fatalf("DirWritable: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("probe file left behind: %v", entries)
+	}
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		ro := filepath.Join(dir, "ro")
+		if err := os.Mkdir(ro, 0o555); err != nil {
+			t.Fatal(err)
+		}
+		wantErrContains(t, DirWritable(ro)(ctx), "dir "+ro+": not writable: ")
+	}
+
+	assertPanics(t, func() { FileExists("") })
+	assertPanics(t, func() { DirWritable(" ") })
+}
+
+func TestDiskFree(t *testing.T) {
+	switch runtime.GOOS {
+	case "linux", "darwin", "freebsd":
+	default:
+		t.Skip("disk usage not supported on " + runtime.GOOS)
+	}
+	dir := t.TempDir()
+	ctx := context.Background()
+	if err := DiskFree(dir, 0)(ctx); err != nil {
+		t.Fatalf("DiskFree(0): %v", err)
+	}
+	wantErrContains(t, DiskFree(dir, math.MaxUint64)(ctx), "free, want >= 16.0 EiB")
+	if err := DiskFreeRatio(dir, 0)(ctx); err != nil {
+		t.Fatalf("DiskFreeRatio(0): %v", err)
+	}
+	err := DiskFreeRatio(dir, 1)(ctx)
+	if err != nil {
+		wantErrContains(t, err, "want >= 100.0%")
+	}
+	wantErrContains(t, DiskFree(filepath.Join(dir, "missing"), 0)(ctx), "disk "+filepath.Join(dir, "missing")+": ")
+
+	assertPanics(t, func() { DiskFreeRatio(dir, 1.5) })
+	assertPanics(t, func() { DiskFreeRatio(dir, math.NaN()) })
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[uint64]string{
+		0:          "0 B",
+		1023:       "1023 B",
+		1536:       "1.5 KiB",
+		812 << 20:  "812.0 MiB",
+		1 << 30:    "1.0 GiB",
+		3 << 40:    "3.0 TiB",
+		1<<64 - 1:  "16.0 EiB",
+		1<<60 + 10: "1.0 EiB",
+	} {
+		if got := formatBytes(n); got != want {
+			t.Fatalf("formatBytes(%d)=%q, want %q", n, got, want)
+		}
+	}
+}
+
+type pingerFunc func(context.Context) error
+
+func (f pingerFunc) PingContext(ctx context.Context) error { return f(ctx) }
+
+func TestSQLPing(t *testing.T) {
+	ctx := context.Background()
+	if err := SQLPing(pingerFunc(func(context.Context) error { return nil }))(ctx); err != nil {
+		t.Fatalf("ok: %v", err)
+	}
+	boom := errors.New("driver: bad connection")
+	err := SQLPing(pingerFunc(func(context.Context) error { return boom }))(ctx)
+	if !errors.Is(err, boom) || err.Error() != "sql ping: driver: bad connection" {
+		t.Fatalf("err=%v", err)
+	}
+	assertPanics(t, func() { SQLPing(nil) })
+}
+
+func TestTaskSucceededWithin(t *testing.T) {
+	m := task.NewManager()
+	fail := true
+	h := m.MustAdd(task.Trigger(func(context.Context) error {
+		if fail {
+			return errors.New("upstream timeout")
+		}
+		return nil
+	}), task.WithName("sync"))
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(context.Background())
+	ctx := context.Background()
+
+	check := TaskSucceededWithin(m, "sync", time.Minute)
+	if err := check(ctx); err == nil || err.Error() != `task "sync": never succeeded` {
+		t.Fatalf("err=%v", err)
+	}
+	_ = h.TriggerAndWait(ctx)
+	if err := check(ctx); err == nil || err.Error() != `task "sync": never succeeded (last error: upstream timeout)` {
+		t.Fatalf("err=%v", err)
+	}
+
+	fail = false
+	_ = h.TriggerAndWait(ctx)
+	if err := check(ctx); err != nil {
+		t.Fatalf("after success: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	wantErrContains(t, TaskSucceededWithin(m, "sync", 10*time.Millisecond)(ctx), `task "sync": last success 0s ago, want within 10ms`)
+	wantErrContains(t, TaskSucceededWithin(m, "missing", time.Minute)(ctx), `task "missing": not found`)
+
+	assertPanics(t, func() { TaskSucceededWithin(nil, "sync", time.Minute) })
+	assertPanics(t, func() { TaskSucceededWithin(m, "", time.Minute) })
+	assertPanics(t, func() { TaskSucceededWithin(m, "sync", 0) })
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/evan-idocoding/zkit/ops"
+)
+
+// diskSpace is the space of the filesystem containing a path.
+type diskSpace struct {
+	Total uint64 // bytes
+	Free  uint64 // bytes available to unprivileged users
+}
+
+// DiskFree checks that the filesystem containing path has at least minFree bytes available
+// to unprivileged users.
+//
+// It is supported on Linux, macOS and FreeBSD; elsewhere the check always fails.
+func DiskFree(path string, minFree uint64) ops.ReadyCheckFunc {
+	path = requirePath(path, "DiskFree")
+	return func(context.Context) error {
+		u, err := diskUsage(path)
+		if err != nil {
+			return fmt.Errorf("disk %s: %w", path, unwrapPathError(err))
+		}
+		if u.Free < minFree {
+			return fmt.Errorf("disk %s: %s free, want >= %s", path, formatBytes(u.Free), formatBytes(minFree))
+		}
+		return nil
+	}
+}
+
+// DiskFreeRatio checks that the filesystem containing path has at least minRatio (0..1) of
+// its space available to unprivileged users. Invalid ratios panic.
+//
+// It is supported on Linux, macOS and FreeBSD; elsewhere the check always fails.
+func DiskFreeRatio(path string, minRatio float64) ops.ReadyCheckFunc {
+	path = requirePath(path, "DiskFreeRatio")
+	if !(minRatio >= 0 && minRatio <= 1) {
+		panic("checks: DiskFreeRatio: minRatio must be within [0, 1]")
+	}
+	return func(context.Context) error {
+		u, err := diskUsage(path)
+		if err != nil {
+			return fmt.Errorf("disk %s: %w", path, unwrapPathError(err))
+		}
+		if u.Total == 0 {
+			return fmt.Errorf("disk %s: filesystem reports zero size", path)
+		}
+		ratio := float64(u.Free) / float64(u.Total)
+		if ratio < minRatio {
+			return fmt.Errorf("disk %s: %s%% free (%s of %s), want >= %s%%", path,
+				formatPercent(ratio), formatBytes(u.Free), formatBytes(u.Total), formatPercent(minRatio))
+		}
+		return nil
+	}
+}
+
+func formatPercent(r float64) string {
+	return strconv.FormatFloat(r*100, 'f', 1, 64)
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatUint(n, 10) + " B"
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit && exp < 5; m /= unit {
+		div *= unit
+		exp++
+	}
+	return strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64) + " " + "KMGTPE"[exp:exp+1] + "iB"
+}
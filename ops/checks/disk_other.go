@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd)
+
+package checks
+
+import (
+	"errors"
+	"runtime"
+)
+
+func diskUsage(string) (diskSpace, error) {
+	return diskSpace{}, errors.New("disk usage not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd
+
+package checks
+
+import "syscall"
+
+func diskUsage(path string) (diskSpace, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return diskSpace{}, err
+	}
+	bsize := uint64(st.Bsize)
+	return diskSpace{
+		Total: uint64(st.Blocks) * bsize,
+		Free:  uint64(st.Bavail) * bsize,
+	}, nil
+}
@@ -0,0 +1,23 @@
+// Package checks provides ready-made check functions for ops.ReadyCheck (and admin /readyz).
+//
+// Every constructor returns an ops.ReadyCheckFunc. Arguments are validated eagerly: invalid
+// arguments (empty address, nil DB, ...) are assembly errors and will panic.
+//
+// Checks honor the context passed by the readiness handler, so pair them with
+// ReadyCheck.Timeout (or the monitor interval) instead of building timeouts into each check.
+// Errors name the target and, where useful, the observed value and the threshold, so the
+// /readyz report is actionable on its own:
+//
+//	tcp dial db:5432: dial tcp 10.0.0.7:5432: connect: connection refused
+//	http GET http://search:9200/_cluster/health: status 503 Service Unavailable (want 200)
+//	disk /var/lib/app: 812.0 MiB free, want >= 1.0 GiB
+//	task "sync-catalog": last success 23m ago, want within 10m (last error: upstream timeout)
+//
+// # Example
+//
+//	checks := []ops.ReadyCheck{
+//		{Name: "db", Func: checks.SQLPing(db), Timeout: time.Second},
+//		{Name: "search", Func: checks.HTTPGet("http://search:9200/_cluster/health"), NonCritical: true},
+//		{Name: "disk", Func: checks.DiskFree("/var/lib/app", 1<<30)},
+//	}
+package checks
@@ -0,0 +1,28 @@
+package checks_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evan-idocoding/zkit/ops"
+	"github.com/evan-idocoding/zkit/ops/checks"
+)
+
+func ExampleFileExists() {
+	dir, _ := os.MkdirTemp("", "checks-example")
+	defer os.RemoveAll(dir)
+
+	rc := ops.ReadyCheck{
+		Name:    "config",
+		Func:    checks.FileExists(filepath.Join(dir, "app.yaml")),
+		Timeout: time.Second,
+	}
+	err := rc.Func(context.Background())
+	fmt.Println(err != nil)
+
+	// Output:
+	// true
+}
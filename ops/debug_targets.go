@@ -0,0 +1,267 @@
+package ops
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+type debugTargetsConfig struct {
+	format Format
+}
+
+// DebugTargetsOption configures DebugTargetsHandler / DebugTargetsAddHandler / DebugTargetsRemoveHandler.
+type DebugTargetsOption func(*debugTargetsConfig)
+
+// WithDebugTargetsDefaultFormat sets the default response format for debug target handlers.
+//
+// This default can be overridden per request by URL query:
+//   - ?format=json
+//   - ?format=text
+//
+// Default is FormatText.
+func WithDebugTargetsDefaultFormat(f Format) DebugTargetsOption {
+	return func(c *debugTargetsConfig) { c.format = f }
+}
+
+func applyDebugTargetsOptions(opts []DebugTargetsOption) debugTargetsConfig {
+	cfg := debugTargetsConfig{
+		format: FormatText,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.format != FormatText && cfg.format != FormatJSON {
+		cfg.format = FormatText
+	}
+	return cfg
+}
+
+type debugTargetsResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	Targets []httpx.DebugTarget `json:"targets,omitempty"`
+}
+
+type debugTargetsWriteResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	Target *httpx.DebugTarget `json:"target,omitempty"`
+	// Removed reports whether a live target was removed (remove only).
+	Removed *bool `json:"removed,omitempty"`
+}
+
+// DebugTargetsHandler returns a handler that lists the live request ids and client IPs
+// registered for per-request debug logging (see httpx.DebugLog).
+//
+// Behavior:
+//   - GET/HEAD only; other methods return 405.
+//   - Text or JSON (controlled by option or ?format=).
+func DebugTargetsHandler(t *httpx.DebugTargets, opts ...DebugTargetsOption) http.Handler {
+	if t == nil {
+		panic("ops: nil httpx.DebugTargets")
+	}
+	cfg := applyDebugTargetsOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeDebugTargets(w, r, format, http.StatusMethodNotAllowed, debugTargetsResponse{Error: "method not allowed"})
+			return
+		}
+		writeDebugTargets(w, r, format, http.StatusOK, debugTargetsResponse{OK: true, Targets: t.Snapshot()})
+	})
+}
+
+// DebugTargetsAddHandler returns a handler that registers a request id or client IP for
+// per-request debug logging. Registrations expire automatically.
+//
+// Input:
+//   - POST only
+//   - URL query: exactly one of ?request_id=<id> or ?ip=<ip or cidr>, plus optional
+//     &ttl=<duration> (default httpx.DefaultDebugTargetTTL; capped by the registry's max ttl)
+//
+// A full registry returns 409.
+func DebugTargetsAddHandler(t *httpx.DebugTargets, opts ...DebugTargetsOption) http.Handler {
+	if t == nil {
+		panic("ops: nil httpx.DebugTargets")
+	}
+	cfg := applyDebugTargetsOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeDebugTargetsWrite(w, format, http.StatusMethodNotAllowed, debugTargetsWriteResponse{Error: "method not allowed"})
+			return
+		}
+		kind, value, ok := debugTargetFromQuery(r)
+		if !ok {
+			writeDebugTargetsWrite(w, format, http.StatusBadRequest, debugTargetsWriteResponse{
+				Error: "want exactly one of: request_id, ip",
+			})
+			return
+		}
+		var ttl time.Duration
+		if v := strings.TrimSpace(r.URL.Query().Get("ttl")); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				writeDebugTargetsWrite(w, format, http.StatusBadRequest, debugTargetsWriteResponse{
+					Error: "invalid ttl (want a duration, e.g. 15m)",
+				})
+				return
+			}
+			ttl = d
+		}
+		target, err := t.Add(kind, value, ttl)
+		if err != nil {
+			code := http.StatusBadRequest
+			if errors.Is(err, httpx.ErrTooManyDebugTargets) {
+				code = http.StatusConflict
+			}
+			writeDebugTargetsWrite(w, format, code, debugTargetsWriteResponse{Error: err.Error()})
+			return
+		}
+		writeDebugTargetsWrite(w, format, http.StatusOK, debugTargetsWriteResponse{OK: true, Target: &target})
+	})
+}
+
+// DebugTargetsRemoveHandler returns a handler that unregisters a request id or client IP.
+//
+// Input:
+//   - POST only
+//   - URL query: exactly one of ?request_id=<id> or ?ip=<ip or cidr>
+func DebugTargetsRemoveHandler(t *httpx.DebugTargets, opts ...DebugTargetsOption) http.Handler {
+	if t == nil {
+		panic("ops: nil httpx.DebugTargets")
+	}
+	cfg := applyDebugTargetsOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeDebugTargetsWrite(w, format, http.StatusMethodNotAllowed, debugTargetsWriteResponse{Error: "method not allowed"})
+			return
+		}
+		kind, value, ok := debugTargetFromQuery(r)
+		if !ok {
+			writeDebugTargetsWrite(w, format, http.StatusBadRequest, debugTargetsWriteResponse{
+				Error: "want exactly one of: request_id, ip",
+			})
+			return
+		}
+		removed := t.Remove(kind, value)
+		writeDebugTargetsWrite(w, format, http.StatusOK, debugTargetsWriteResponse{
+			OK:      true,
+			Target:  &httpx.DebugTarget{Kind: kind, Value: strings.TrimSpace(value)},
+			Removed: &removed,
+		})
+	})
+}
+
+func debugTargetFromQuery(r *http.Request) (httpx.DebugTargetKind, string, bool) {
+	id, haveID := getQueryRequired(r, "request_id")
+	ip, haveIP := getQueryRequired(r, "ip")
+	switch {
+	case haveID && !haveIP:
+		return httpx.DebugTargetRequestID, id, true
+	case haveIP && !haveID:
+		return httpx.DebugTargetIP, ip, true
+	default:
+		return "", "", false
+	}
+}
+
+func writeDebugTargets(w http.ResponseWriter, r *http.Request, f Format, code int, resp debugTargetsResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if resp.OK && resp.Targets == nil {
+			resp.Targets = []httpx.DebugTarget{}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if !resp.OK {
+			writeTextError(w, resp.Error)
+			return
+		}
+		var b strings.Builder
+		b.Grow(96 * len(resp.Targets))
+		for _, t := range resp.Targets {
+			appendDebugTargetLines(&b, t)
+		}
+		_, _ = w.Write([]byte(b.String()))
+	}
+}
+
+func writeDebugTargetsWrite(w http.ResponseWriter, f Format, code int, resp debugTargetsWriteResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if !resp.OK {
+			writeTextError(w, resp.Error)
+			return
+		}
+		var b strings.Builder
+		b.Grow(128)
+		if resp.Target != nil {
+			if resp.Removed != nil {
+				writeDebugTargetField(&b, *resp.Target, "removed", strconv.FormatBool(*resp.Removed))
+			} else {
+				appendDebugTargetLines(&b, *resp.Target)
+			}
+		}
+		_, _ = w.Write([]byte(b.String()))
+	}
+}
+
+func appendDebugTargetLines(b *strings.Builder, t httpx.DebugTarget) {
+	// Stable and greppable: debug_target\t<kind>\t<value>\t<field>\t<value>\n
+	writeDebugTargetField(b, t, "expires_at", t.ExpiresAt.UTC().Format(time.RFC3339))
+	writeDebugTargetField(b, t, "hits", strconv.FormatUint(t.Hits, 10))
+}
+
+func writeDebugTargetField(b *strings.Builder, t httpx.DebugTarget, field, value string) {
+	b.WriteString("debug_target\t")
+	b.WriteString(string(t.Kind))
+	b.WriteByte('\t')
+	b.WriteString(escapeTextField(t.Value))
+	b.WriteByte('\t')
+	b.WriteString(field)
+	b.WriteByte('\t')
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
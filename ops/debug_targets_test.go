@@ -0,0 +1,79 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+func TestDebugTargetsHandlers(t *testing.T) {
+	targets := httpx.NewDebugTargets(httpx.WithDebugTargetsMaxTTL(time.Hour), httpx.WithDebugTargetsMaxTracked(2))
+	add := DebugTargetsAddHandler(targets)
+	remove := DebugTargetsRemoveHandler(targets)
+	list := DebugTargetsHandler(targets)
+	post := func(h http.Handler, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/?"+query, nil))
+		return w
+	}
+
+	w := post(add, "ip=10.0.0.1&ttl=5m")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "debug_target\tip\t10.0.0.1\texpires_at\t") {
+		t.Fatalf("add ip: status=%d body=%q", w.Code, w.Body.String())
+	}
+	if w := post(add, "request_id=r-1&format=json"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"kind":"request_id"`) {
+		t.Fatalf("add request_id: status=%d body=%q", w.Code, w.Body.String())
+	}
+
+	for _, tc := range []struct {
+		query string
+		code  int
+	}{
+		{"", http.StatusBadRequest},
+		{"ip=10.0.0.1&request_id=r-1", http.StatusBadRequest},
+		{"ip=nope", http.StatusBadRequest},
+		{"ip=10.0.0.2&ttl=2h", http.StatusBadRequest},
+		{"ip=10.0.0.2&ttl=x", http.StatusBadRequest},
+		{"ip=10.0.0.2", http.StatusConflict},
+	} {
+		if w := post(add, tc.query); w.Code != tc.code {
+			t.Fatalf("add ?%s: status=%d, want %d (%q)", tc.query, w.Code, tc.code, w.Body.String())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	list.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/?format=json", nil))
+	var got struct {
+		OK      bool                `json:"ok"`
+		Targets []httpx.DebugTarget `json:"targets"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !got.OK || len(got.Targets) != 2 || got.Targets[0].Kind != httpx.DebugTargetIP {
+		t.Fatalf("list=%+v", got)
+	}
+
+	if w := post(remove, "ip=10.0.0.1"); w.Body.String() != "debug_target\tip\t10.0.0.1\tremoved\ttrue\n" {
+		t.Fatalf("remove body=%q", w.Body.String())
+	}
+	if w := post(remove, "ip=10.0.0.1"); !strings.HasSuffix(w.Body.String(), "removed\tfalse\n") {
+		t.Fatalf("remove again body=%q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	list.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD" {
+		t.Fatalf("list POST: status=%d allow=%q", w.Code, w.Header().Get("Allow"))
+	}
+	w = httptest.NewRecorder()
+	add.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/?ip=10.0.0.1", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "POST" {
+		t.Fatalf("add GET: status=%d allow=%q", w.Code, w.Header().Get("Allow"))
+	}
+}
@@ -19,12 +19,21 @@
 // # What ops provides
 //
 // This package includes handlers for:
-//   - health: HealthzHandler (liveness), ReadyzHandler (readiness checks)
-//   - runtime/build: RuntimeHandler, BuildInfoHandler
+//   - health: HealthzHandler (liveness), ReadyzHandler (readiness checks),
+//     ReadyzMonitor + ReadyzMonitorHandler (background-refreshed, cached readiness);
+//     package ops/checks provides ready-made ReadyCheckFunc constructors
+//   - runtime/build: RuntimeHandler, GoroutinesHandler, BuildInfoHandler
+//   - runtime actions: RuntimeGCHandler, RuntimeFreeOSMemoryHandler
 //   - tasks: TasksSnapshotHandler, TaskTriggerHandler, TaskTriggerAndWaitHandler (rt/task integration)
-//   - tuning: TuningSnapshotHandler, TuningOverridesHandler, TuningLookupHandler, TuningSetHandler, Reset* (rt/tuning integration)
-//   - logging: LogLevelGetHandler, LogLevelSetHandler (slog.LevelVar)
-//   - injected snapshots: ProvidedSnapshotHandler (render provided data as JSON/text)
+//   - tuning: TuningSnapshotHandler, TuningOverridesHandler, TuningLookupHandler, TuningSetHandler, Reset*, TuningApplyHandler (rt/tuning integration)
+//   - logging: LogLevelGetHandler, LogLevelSetHandler (slog.LevelVar),
+//     LogLevelsHandler, LogLevelsSetHandler, LogLevelsResetHandler (per-component slogx.Levels),
+//     LogTailHandler (recent records of a slogx.Ring, with ?follow=1 streaming),
+//     DebugTargetsHandler, DebugTargetsAddHandler, DebugTargetsRemoveHandler (httpx.DebugTargets)
+//   - guard lockouts: LockoutSnapshotHandler, LockoutClearHandler (httpx.Lockout)
+//   - injected snapshots: ProvidedSnapshotHandler (render provided data or ProvidedFunc results
+//     as JSON/text, with `zkit:"redact"` tags and key patterns redacted)
+//   - events: EventsHandler (Server-Sent Events stream of an EventHub)
 //
 // # Security notes
 //
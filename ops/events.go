@@ -0,0 +1,434 @@
+package ops
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evan-idocoding/zkit/rt/task"
+	"github.com/evan-idocoding/zkit/rt/tuning"
+	"github.com/evan-idocoding/zkit/slogx"
+)
+
+// Event types published by the EventHub adapters.
+//
+// Custom types may be published with EventHub.Publish; dotted names group naturally
+// with the ?types= filter (see EventsHandler).
+const (
+	EventTuningChange = "tuning.change"
+	EventTaskStart    = "task.start"
+	EventTaskFinish   = "task.finish"
+	EventLogLevel     = "log.level"
+	EventGuardDeny    = "guard.deny"
+	EventLifecycle    = "lifecycle"
+)
+
+// Event is one entry of the admin event stream.
+type Event struct {
+	// Seq is assigned by the hub; it increases by one per published event.
+	Seq  uint64 `json:"seq"`
+	Type string `json:"type"`
+	// Key identifies the subject of the event (tuning key, task name, path, lifecycle state).
+	Key  string    `json:"key,omitempty"`
+	At   time.Time `json:"at"`
+	Data any       `json:"data,omitempty"`
+}
+
+// EventHub fans out events to EventsHandler clients.
+//
+// Publishing never blocks: each client has a bounded buffer, and a client whose
+// buffer is full is dropped (its stream ends with a "dropped" event). This keeps
+// publishers (tuning writes, task hooks, guards) unaffected by slow consumers.
+//
+// The zero value is not usable; use NewEventHub. All methods are safe for concurrent use
+// and are no-ops on a nil hub.
+type EventHub struct {
+	mu   sync.Mutex
+	seq  uint64
+	subs map[*eventSub]struct{}
+}
+
+type eventSub struct {
+	ch     chan Event // closed when the subscriber is removed
+	filter eventFilter
+}
+
+// NewEventHub creates an empty hub.
+func NewEventHub() *EventHub {
+	return &EventHub{subs: make(map[*eventSub]struct{})}
+}
+
+// Publish sends e to all matching clients. At defaults to now.
+func (h *EventHub) Publish(e Event) {
+	if h == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seq++
+	e.Seq = h.seq
+	for s := range h.subs {
+		if !s.filter.match(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			// Slow consumer: drop it rather than block the publisher.
+			delete(h.subs, s)
+			close(s.ch)
+		}
+	}
+}
+
+// Subscribers returns the number of connected clients.
+func (h *EventHub) Subscribers() int {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+func (h *EventHub) subscribe(f eventFilter, buffer int) *eventSub {
+	s := &eventSub{ch: make(chan Event, buffer), filter: f}
+	h.mu.Lock()
+	h.subs[s] = struct{}{}
+	h.mu.Unlock()
+	return s
+}
+
+func (h *EventHub) unsubscribe(s *eventSub) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		close(s.ch)
+	}
+}
+
+// ObserveTuning publishes a tuning.change event for every runtime write in t.
+// Values of redacted variables are "<redacted>". It returns a function that stops observing.
+func (h *EventHub) ObserveTuning(t *tuning.Tuning) (stop func()) {
+	if h == nil || t == nil {
+		return func() {}
+	}
+	return t.OnChange(func(c tuning.Change) {
+		h.Publish(Event{Type: EventTuningChange, Key: c.Key, At: c.At, Data: tuningChangeEvent{
+			Type:   string(c.Type),
+			Old:    c.Old,
+			New:    c.New,
+			Source: c.Source.String(),
+		}})
+	})
+}
+
+type tuningChangeEvent struct {
+	Type   string `json:"type"`
+	Old    any    `json:"old"`
+	New    any    `json:"new"`
+	Source string `json:"source"`
+}
+
+// TaskRunStart publishes a task.start event. It matches task.WithManagerOnRunStart:
+//
+//	task.NewManager(task.WithManagerOnRunStart(hub.TaskRunStart))
+func (h *EventHub) TaskRunStart(info task.RunStartInfo) {
+	if h == nil {
+		return
+	}
+	h.Publish(Event{Type: EventTaskStart, Key: info.Name, At: info.StartedAt, Data: taskRunEvent{
+		Kind:        info.Kind.String(),
+		ScheduledAt: info.ScheduledAt,
+		StartedAt:   info.StartedAt,
+	}})
+}
+
+// TaskRunFinish publishes a task.finish event. It matches task.WithManagerOnRunFinish.
+func (h *EventHub) TaskRunFinish(info task.RunFinishInfo) {
+	if h == nil {
+		return
+	}
+	h.Publish(Event{Type: EventTaskFinish, Key: info.Name, At: info.FinishedAt, Data: taskRunEvent{
+		Kind:        info.Kind.String(),
+		ScheduledAt: info.ScheduledAt,
+		StartedAt:   info.StartedAt,
+		FinishedAt:  info.FinishedAt,
+		Duration:    info.Duration,
+		Err:         info.Err,
+		Panicked:    info.Panicked,
+	}})
+}
+
+type taskRunEvent struct {
+	Kind        string    `json:"kind"`
+	ScheduledAt time.Time `json:"scheduled_at,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+	// Duration is encoded as an integer number of nanoseconds in JSON.
+	Duration time.Duration `json:"duration,omitempty"`
+	Err      string        `json:"err,omitempty"`
+	Panicked bool          `json:"panicked,omitempty"`
+}
+
+// LogLevelChanged publishes a log.level event.
+func (h *EventHub) LogLevelChanged(oldLevel, newLevel slog.Level) {
+	if h == nil {
+		return
+	}
+	h.Publish(Event{Type: EventLogLevel, Data: logLevelEvent{
+		Old: LogLevelSnapshot{Level: levelToEnum(oldLevel), LevelValue: int(oldLevel)},
+		New: LogLevelSnapshot{Level: levelToEnum(newLevel), LevelValue: int(newLevel)},
+	}})
+}
+
+type logLevelEvent struct {
+	Old   LogLevelSnapshot `json:"old"`
+	New   LogLevelSnapshot `json:"new"`
+	Cause string           `json:"cause,omitempty"` // slogx.LevelCause* (component levels only)
+}
+
+// ObserveLogLevels publishes a log.level event, keyed by component name, for every level
+// change made through levels (including TTL reverts). It returns a function that stops observing.
+func (h *EventHub) ObserveLogLevels(levels *slogx.Levels) (stop func()) {
+	if h == nil || levels == nil {
+		return func() {}
+	}
+	return levels.OnChange(func(c slogx.LevelChange) {
+		h.Publish(Event{Type: EventLogLevel, Key: c.Name, At: c.At, Data: logLevelEvent{
+			Old:   levelSnapshot(c.Old),
+			New:   levelSnapshot(c.New),
+			Cause: c.Cause,
+		}})
+	})
+}
+
+// GuardDenied publishes a guard.deny event for a request rejected with status
+// (403, or 429 when locked out). capability names the guarded endpoint (may be empty).
+func (h *EventHub) GuardDenied(r *http.Request, capability string, status int) {
+	if h == nil || r == nil {
+		return
+	}
+	e := guardDenyEvent{Capability: capability, Method: r.Method, Status: status, RemoteAddr: r.RemoteAddr}
+	path := ""
+	if r.URL != nil {
+		path = r.URL.Path
+	}
+	h.Publish(Event{Type: EventGuardDeny, Key: path, Data: e})
+}
+
+type guardDenyEvent struct {
+	Capability string `json:"capability,omitempty"`
+	Method     string `json:"method"`
+	Status     int    `json:"status"`
+	RemoteAddr string `json:"remote_addr,omitempty"`
+}
+
+// Lifecycle publishes a lifecycle event; state is the new state (e.g. "starting", "stopped").
+func (h *EventHub) Lifecycle(state string) {
+	if h == nil {
+		return
+	}
+	h.Publish(Event{Type: EventLifecycle, Key: state})
+}
+
+// --- SSE handler ---
+
+type eventsConfig struct {
+	heartbeat time.Duration
+	buffer    int
+}
+
+// EventsOption configures EventsHandler.
+type EventsOption func(*eventsConfig)
+
+// WithEventsHeartbeat sets the interval of heartbeat comments sent on idle streams.
+//
+// <= 0 disables heartbeats. Default is 15s.
+func WithEventsHeartbeat(d time.Duration) EventsOption {
+	return func(c *eventsConfig) { c.heartbeat = d }
+}
+
+// WithEventsBuffer sets the per-client buffer size (in events).
+//
+// A client whose buffer is full when an event is published is dropped.
+// <= 0 means default (256).
+func WithEventsBuffer(n int) EventsOption {
+	return func(c *eventsConfig) { c.buffer = n }
+}
+
+func applyEventsOptions(opts []EventsOption) eventsConfig {
+	cfg := eventsConfig{
+		heartbeat: 15 * time.Second,
+		buffer:    256,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.buffer <= 0 {
+		cfg.buffer = 256
+	}
+	return cfg
+}
+
+type eventsResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// EventsHandler returns a handler that streams hub events as Server-Sent Events.
+//
+// Stream format (one message per event):
+//
+//	id: <seq>
+//	event: <type>
+//	data: <Event as JSON>
+//
+// Idle streams receive ": heartbeat" comments. When the client falls behind (its buffer
+// fills up), the stream ends with an "event: dropped" message; clients may reconnect.
+//
+// Filters (URL query):
+//   - ?types=a,b: only these event types; "task" matches "task.start" and "task.finish".
+//   - ?prefix=p: only events whose Key starts with p.
+//
+// Behavior:
+//   - GET/HEAD only; other methods return 405. HEAD does not subscribe.
+//   - Errors before the stream starts honor ?format=json|text.
+func EventsHandler(hub *EventHub, opts ...EventsOption) http.Handler {
+	if hub == nil {
+		panic("ops: nil EventHub")
+	}
+	cfg := applyEventsOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, FormatText)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeEventsError(w, format, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		fl, ok := w.(http.Flusher)
+		if !ok {
+			writeEventsError(w, format, http.StatusInternalServerError, "streaming not supported")
+			return
+		}
+
+		q := r.URL.Query()
+		f := eventFilter{prefix: q.Get("prefix")}
+		for _, t := range strings.Split(q.Get("types"), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				f.types = append(f.types, t)
+			}
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		w.Header().Set("X-Accel-Buffering", "no")
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		sub := hub.subscribe(f, cfg.buffer)
+		defer hub.unsubscribe(sub)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(": ok\n\n"))
+		fl.Flush()
+
+		var tick <-chan time.Time
+		if cfg.heartbeat > 0 {
+			t := time.NewTicker(cfg.heartbeat)
+			defer t.Stop()
+			tick = t.C
+		}
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-sub.ch:
+				if !ok {
+					_, _ = w.Write([]byte("event: dropped\ndata: {\"reason\":\"slow consumer\"}\n\n"))
+					fl.Flush()
+					return
+				}
+				if _, err := w.Write(renderSSE(e)); err != nil {
+					return
+				}
+				fl.Flush()
+			case <-tick:
+				if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+					return
+				}
+				fl.Flush()
+			}
+		}
+	})
+}
+
+type eventFilter struct {
+	types  []string
+	prefix string
+}
+
+func (f eventFilter) match(e Event) bool {
+	if f.prefix != "" && !strings.HasPrefix(e.Key, f.prefix) {
+		return false
+	}
+	if len(f.types) == 0 {
+		return true
+	}
+	for _, t := range f.types {
+		if e.Type == t || strings.HasPrefix(e.Type, t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func renderSSE(e Event) []byte {
+	data, err := json.Marshal(e)
+	if err != nil {
+		data, _ = json.Marshal(Event{Seq: e.Seq, Type: e.Type, Key: e.Key, At: e.At})
+	}
+	b := make([]byte, 0, len(data)+64)
+	b = append(b, "id: "...)
+	b = strconv.AppendUint(b, e.Seq, 10)
+	b = append(b, "\nevent: "...)
+	b = append(b, sanitizeSSEField(e.Type)...)
+	b = append(b, "\ndata: "...)
+	b = append(b, data...)
+	b = append(b, "\n\n"...)
+	return b
+}
+
+// sanitizeSSEField keeps a field on a single line (SSE is line-delimited).
+func sanitizeSSEField(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+func writeEventsError(w http.ResponseWriter, f Format, code int, msg string) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(eventsResponse{OK: false, Error: msg})
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		writeTextError(w, msg)
+	}
+}
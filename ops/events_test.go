@@ -0,0 +1,140 @@
+package ops
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evan-idocoding/zkit/rt/task"
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+// readSSE reads SSE messages (events and comments) from body until n events were read.
+func readSSE(t *testing.T, sc *bufio.Scanner, n int) (events []Event, names []string, comments []string) {
+	t.Helper()
+	var name string
+	for len(events) < n && sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, ":"):
+			comments = append(comments, line)
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			var e Event
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+				t.Fatalf("decode %q: %v", line, err)
+			}
+			events = append(events, e)
+			names = append(names, name)
+		}
+	}
+	return events, names, comments
+}
+
+func waitSubscribers(t *testing.T, hub *EventHub, n int) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		if hub.Subscribers() == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("subscribers=%d, want=%d", hub.Subscribers(), n)
+}
+
+func TestEvents_StreamAndFilters(t *testing.T) {
+	hub := NewEventHub()
+	srv := httptest.NewServer(EventsHandler(hub))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?types=tuning,task.finish&prefix=app.", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	waitSubscribers(t, hub, 1)
+
+	tu := tuning.New()
+	v, _ := tu.Int64("app.limit", 1)
+	hub.ObserveTuning(tu)
+	hub.TaskRunStart(task.RunStartInfo{Name: "app.sync", StartedAt: time.Now()})
+	hub.TaskRunFinish(task.RunFinishInfo{Name: "other", FinishedAt: time.Now()})
+	hub.LogLevelChanged(slog.LevelInfo, slog.LevelDebug)
+	if err := v.Set(5); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	hub.TaskRunFinish(task.RunFinishInfo{Name: "app.sync", FinishedAt: time.Now(), Err: "boom"})
+
+	events, names, _ := readSSE(t, bufio.NewScanner(resp.Body), 2)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != EventTuningChange || names[0] != EventTuningChange || events[0].Key != "app.limit" {
+		t.Fatalf("unexpected first event: %+v", events[0])
+	}
+	data := events[0].Data.(map[string]any)
+	if data["old"] != float64(1) || data["new"] != float64(5) || data["source"] != "runtime-set" {
+		t.Fatalf("unexpected tuning data: %v", data)
+	}
+	if events[1].Type != EventTaskFinish || events[1].Key != "app.sync" || events[1].Seq <= events[0].Seq {
+		t.Fatalf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestEvents_HeartbeatAndSlowConsumer(t *testing.T) {
+	hub := NewEventHub()
+	srv := httptest.NewServer(EventsHandler(hub, WithEventsHeartbeat(10*time.Millisecond), WithEventsBuffer(1)))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	waitSubscribers(t, hub, 1)
+
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		if sc.Text() == ": heartbeat" {
+			break
+		}
+	}
+
+	// The handler is parked on the heartbeat; flood the buffer so the client is dropped.
+	for i := 0; i < 1000 && hub.Subscribers() > 0; i++ {
+		hub.Lifecycle("tick")
+	}
+	if hub.Subscribers() != 0 {
+		t.Fatalf("expected the slow client to be dropped")
+	}
+	var dropped bool
+	for sc.Scan() {
+		if sc.Text() == "event: dropped" {
+			dropped = true
+		}
+	}
+	if !dropped {
+		t.Fatalf("expected a dropped event")
+	}
+}
+
+func TestEvents_MethodNotAllowed(t *testing.T) {
+	w := httptest.NewRecorder()
+	EventsHandler(NewEventHub()).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/events?format=json", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD" {
+		t.Fatalf("status=%d allow=%q", w.Code, w.Header().Get("Allow"))
+	}
+}
@@ -0,0 +1,524 @@
+package ops
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type goroutinesConfig struct {
+	format   Format
+	maxBytes int
+}
+
+// GoroutinesOption configures GoroutinesHandler.
+type GoroutinesOption func(*goroutinesConfig)
+
+// WithGoroutinesDefaultFormat sets the default response format.
+//
+// This default can be overridden per request by URL query:
+//   - ?format=json
+//   - ?format=text
+//
+// Default is FormatText.
+func WithGoroutinesDefaultFormat(f Format) GoroutinesOption {
+	return func(c *goroutinesConfig) { c.format = f }
+}
+
+// WithGoroutinesMaxBytes sets an upper bound for the response body size.
+//
+// Unlike WithProvidedSnapshotMaxBytes, an oversized dump is not rejected: groups are
+// dropped from the end (least common stacks first) and the response is marked truncated; the
+// groups count covers only the groups shown.
+//
+// <= 0 means "no limit". Default is 1 MiB.
+func WithGoroutinesMaxBytes(n int) GoroutinesOption {
+	return func(c *goroutinesConfig) { c.maxBytes = n }
+}
+
+func applyGoroutinesOptions(opts []GoroutinesOption) goroutinesConfig {
+	cfg := goroutinesConfig{
+		format:   FormatText,
+		maxBytes: 1 << 20,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.format != FormatText && cfg.format != FormatJSON {
+		cfg.format = FormatText
+	}
+	return cfg
+}
+
+// GoroutineFilter selects goroutines for Goroutines. The zero value matches everything.
+type GoroutineFilter struct {
+	// Func keeps goroutines with a frame whose function name contains this substring.
+	Func string
+	// Package keeps goroutines with a frame whose package path contains this substring.
+	Package string
+	// State keeps goroutines whose state contains this substring (e.g. "chan receive", "IO wait").
+	State string
+	// MinWait keeps goroutines blocked for at least this long.
+	// The runtime reports waits in whole minutes and only from one minute on.
+	MinWait time.Duration
+}
+
+// GoroutineFrame is one stack frame.
+type GoroutineFrame struct {
+	Func string `json:"func"`
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// GoroutineGroup is a set of goroutines with the same state and identical stacks.
+type GoroutineGroup struct {
+	Count int    `json:"count"`
+	State string `json:"state"`
+	// WaitMin/WaitMax are encoded as integer numbers of nanoseconds in JSON (0 = not waiting / under a minute).
+	WaitMin        time.Duration `json:"wait_min,omitempty"`
+	WaitMax        time.Duration `json:"wait_max,omitempty"`
+	LockedToThread bool          `json:"locked_to_thread,omitempty"`
+	// IDs holds up to goroutineGroupMaxIDs goroutine IDs, for correlation with other dumps.
+	IDs       []uint64         `json:"ids"`
+	Stack     []GoroutineFrame `json:"stack"`
+	CreatedBy *GoroutineFrame  `json:"created_by,omitempty"`
+}
+
+// GoroutineDump is a grouped goroutine dump.
+type GoroutineDump struct {
+	Total   int              `json:"total"`   // goroutines in the dump
+	Matched int              `json:"matched"` // goroutines that passed the filter
+	Groups  []GoroutineGroup `json:"groups"`  // most common stacks first
+	// Truncated reports that the dump was cut short (stack buffer or response size cap).
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+const (
+	goroutineGroupMaxIDs     = 8
+	goroutineStackMaxBufSize = 64 << 20 // 64 MiB
+)
+
+// Goroutines captures runtime.Stack for all goroutines and returns it grouped by
+// state and identical stacks, most common first.
+//
+// Capturing all stacks briefly stops the world; do not call it in a tight loop.
+func Goroutines(f GoroutineFilter) GoroutineDump {
+	buf, truncated := captureAllStacks()
+	d := GroupGoroutineStacks(buf, f)
+	d.Truncated = d.Truncated || truncated
+	return d
+}
+
+func captureAllStacks() (buf []byte, truncated bool) {
+	size := 64 << 10
+	for {
+		buf = make([]byte, size)
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n], false
+		}
+		if size >= goroutineStackMaxBufSize {
+			return buf[:n], true
+		}
+		size *= 2
+	}
+}
+
+// GroupGoroutineStacks parses the output of runtime.Stack(buf, true) (or a goroutine
+// profile with debug=2) and groups it like Goroutines.
+func GroupGoroutineStacks(dump []byte, f GoroutineFilter) GoroutineDump {
+	var out GoroutineDump
+	index := make(map[string]int) // group key -> index in out.Groups
+	for _, block := range bytes.Split(dump, []byte("\n\n")) {
+		g, ok := parseGoroutine(string(block))
+		if !ok {
+			continue
+		}
+		out.Total++
+		if !f.match(g) {
+			continue
+		}
+		out.Matched++
+
+		key := g.key()
+		i, seen := index[key]
+		if !seen {
+			i = len(out.Groups)
+			index[key] = i
+			out.Groups = append(out.Groups, GoroutineGroup{
+				State:          g.state,
+				WaitMin:        g.wait,
+				WaitMax:        g.wait,
+				LockedToThread: g.locked,
+				IDs:            make([]uint64, 0, 1),
+				Stack:          g.stack,
+				CreatedBy:      g.createdBy,
+			})
+		}
+		gr := &out.Groups[i]
+		gr.Count++
+		if g.wait < gr.WaitMin {
+			gr.WaitMin = g.wait
+		}
+		if g.wait > gr.WaitMax {
+			gr.WaitMax = g.wait
+		}
+		if len(gr.IDs) < goroutineGroupMaxIDs {
+			gr.IDs = append(gr.IDs, g.id)
+		}
+	}
+	sort.SliceStable(out.Groups, func(i, j int) bool {
+		a, b := out.Groups[i], out.Groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.WaitMax > b.WaitMax
+	})
+	if out.Groups == nil {
+		out.Groups = []GoroutineGroup{}
+	}
+	return out
+}
+
+type parsedGoroutine struct {
+	id        uint64
+	state     string
+	wait      time.Duration
+	locked    bool
+	stack     []GoroutineFrame
+	createdBy *GoroutineFrame
+}
+
+func (g parsedGoroutine) key() string {
+	var b strings.Builder
+	b.WriteString(g.state)
+	if g.locked {
+		b.WriteString("|locked")
+	}
+	for _, fr := range g.stack {
+		b.WriteByte('|')
+		b.WriteString(fr.Func)
+		b.WriteByte('@')
+		b.WriteString(fr.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(fr.Line))
+	}
+	if g.createdBy != nil {
+		b.WriteString("|created_by|")
+		b.WriteString(g.createdBy.Func)
+		b.WriteByte('@')
+		b.WriteString(g.createdBy.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(g.createdBy.Line))
+	}
+	return b.String()
+}
+
+// parseGoroutine parses one block:
+//
+//	goroutine 7 [chan receive, 5 minutes, locked to thread]:
+//	main.worker(0xc000012345)
+//		/src/main.go:42 +0x1d
+//	created by main.main in goroutine 1
+//		/src/main.go:17 +0x25
+func parseGoroutine(block string) (g parsedGoroutine, ok bool) {
+	lines := strings.Split(strings.TrimSpace(block), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "goroutine ") {
+		return g, false
+	}
+	head := strings.TrimPrefix(lines[0], "goroutine ")
+	sp := strings.IndexByte(head, ' ')
+	if sp < 0 {
+		return g, false
+	}
+	id, err := strconv.ParseUint(head[:sp], 10, 64)
+	if err != nil {
+		return g, false
+	}
+	g.id = id
+	lb, rb := strings.IndexByte(head, '['), strings.LastIndexByte(head, ']')
+	if lb < 0 || rb < lb {
+		return g, false
+	}
+	for i, part := range strings.Split(head[lb+1:rb], ", ") {
+		switch {
+		case i == 0:
+			g.state = part
+		case strings.HasSuffix(part, " minutes"):
+			if n, err := strconv.Atoi(strings.TrimSuffix(part, " minutes")); err == nil {
+				g.wait = time.Duration(n) * time.Minute
+			}
+		case part == "locked to thread":
+			g.locked = true
+		}
+	}
+
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "\t") || line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "...") {
+			// "...additional frames elided..."
+			g.stack = append(g.stack, GoroutineFrame{Func: strings.TrimSpace(line)})
+			continue
+		}
+		fr := GoroutineFrame{}
+		if rest, isCreated := strings.CutPrefix(line, "created by "); isCreated {
+			if j := strings.Index(rest, " in goroutine "); j >= 0 {
+				rest = rest[:j]
+			}
+			fr.Func = rest
+		} else {
+			fr.Func = trimCallArgs(line)
+		}
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+			fr.File, fr.Line = parseFrameLocation(lines[i+1])
+			i++
+		}
+		if strings.HasPrefix(line, "created by ") {
+			c := fr
+			g.createdBy = &c
+			continue
+		}
+		g.stack = append(g.stack, fr)
+	}
+	return g, true
+}
+
+// trimCallArgs turns "pkg.(*T).m(0x1, 0x2)" into "pkg.(*T).m".
+func trimCallArgs(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, ")") {
+		if i := strings.LastIndexByte(s, '('); i > 0 {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// parseFrameLocation parses "\t/src/main.go:42 +0x1d".
+func parseFrameLocation(s string) (file string, line int) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, " +0x"); i >= 0 {
+		s = s[:i]
+	}
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return s, 0
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return s, 0
+	}
+	return s[:i], n
+}
+
+// funcPackage returns the package path of a fully-qualified function name:
+// "net/http.(*conn).serve" => "net/http".
+func funcPackage(fn string) string {
+	slash := strings.LastIndexByte(fn, '/')
+	if dot := strings.IndexByte(fn[slash+1:], '.'); dot >= 0 {
+		return fn[:slash+1+dot]
+	}
+	return fn
+}
+
+func (f GoroutineFilter) match(g parsedGoroutine) bool {
+	if f.State != "" && !strings.Contains(g.state, f.State) {
+		return false
+	}
+	if f.MinWait > 0 && g.wait < f.MinWait {
+		return false
+	}
+	if f.Func == "" && f.Package == "" {
+		return true
+	}
+	for _, fr := range g.stack {
+		if f.Func != "" && !strings.Contains(fr.Func, f.Func) {
+			continue
+		}
+		if f.Package != "" && !strings.Contains(funcPackage(fr.Func), f.Package) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+type goroutinesResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	Goroutines *GoroutineDump `json:"goroutines,omitempty"`
+}
+
+// GoroutinesHandler returns a handler that dumps all goroutines grouped by identical stacks.
+//
+// Input (all optional):
+//   - ?func=<substring>: keep goroutines with a frame whose function contains it
+//   - ?pkg=<substring>: keep goroutines with a frame whose package path contains it
+//   - ?state=<substring>: keep goroutines whose state contains it (e.g. "select")
+//   - ?min_wait=<go duration>: keep goroutines blocked at least this long (minute granularity)
+//
+// Behavior:
+//   - GET/HEAD only; other methods return 405.
+//   - By default, it renders text. You can change the default with options.
+//   - The response format can be overridden per request by URL query (?format=json|text).
+//   - Output is capped (WithGoroutinesMaxBytes); the least common groups are dropped first.
+func GoroutinesHandler(opts ...GoroutinesOption) http.Handler {
+	cfg := applyGoroutinesOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeGoroutines(w, r, format, 0, http.StatusMethodNotAllowed, goroutinesResponse{
+				OK:    false,
+				Error: "method not allowed",
+			})
+			return
+		}
+
+		var f GoroutineFilter
+		f.Func, _ = getQueryRaw(r, "func")
+		f.Package, _ = getQueryRaw(r, "pkg")
+		f.State, _ = getQueryRaw(r, "state")
+		if raw, has := getQueryRaw(r, "min_wait"); has && strings.TrimSpace(raw) != "" {
+			d, err := time.ParseDuration(strings.TrimSpace(raw))
+			if err != nil || d < 0 {
+				writeGoroutines(w, r, format, 0, http.StatusBadRequest, goroutinesResponse{
+					OK:    false,
+					Error: "invalid min_wait",
+				})
+				return
+			}
+			f.MinWait = d
+		}
+		if r.Method == http.MethodHead {
+			writeGoroutines(w, r, format, 0, http.StatusOK, goroutinesResponse{OK: true})
+			return
+		}
+
+		d := Goroutines(f)
+		writeGoroutines(w, r, format, cfg.maxBytes, http.StatusOK, goroutinesResponse{
+			OK:         true,
+			Goroutines: &d,
+		})
+	})
+}
+
+func writeGoroutines(w http.ResponseWriter, r *http.Request, f Format, maxBytes int, code int, resp goroutinesResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if resp.Goroutines != nil {
+			capGoroutineDumpJSON(resp.Goroutines, maxBytes)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if !resp.OK || resp.Goroutines == nil {
+			writeTextError(w, resp.Error)
+			return
+		}
+		_, _ = w.Write([]byte(renderGoroutinesText(*resp.Goroutines, maxBytes)))
+	}
+}
+
+// capGoroutineDumpJSON drops trailing groups until the encoded dump fits in maxBytes.
+func capGoroutineDumpJSON(d *GoroutineDump, maxBytes int) {
+	if maxBytes <= 0 {
+		return
+	}
+	budget := maxBytes - 256 // envelope and counters
+	for i, g := range d.Groups {
+		b, _ := json.Marshal(g)
+		budget -= len(b) + 1
+		if budget < 0 {
+			d.Groups = d.Groups[:i]
+			d.Truncated = true
+			return
+		}
+	}
+}
+
+func renderGoroutinesText(d GoroutineDump, maxBytes int) string {
+	// Stable and greppable:
+	//   goroutines\t<key>\t<value>\n
+	//   group\t<n>\t<field>\t<value>[\t<location>]\n
+	head := "goroutines\ttotal\t" + strconv.Itoa(d.Total) + "\n" +
+		"goroutines\tmatched\t" + strconv.Itoa(d.Matched) + "\n"
+
+	// Groups go to body first, so the groups line can count the ones shown (like JSON after
+	// capGoroutineDumpJSON).
+	var body strings.Builder
+	body.Grow(4096)
+	truncated := d.Truncated
+	shown := 0
+	var g strings.Builder
+	for i, gr := range d.Groups {
+		g.Reset()
+		n := "group\t" + strconv.Itoa(i+1) + "\t"
+		g.WriteString(n + "count\t" + strconv.Itoa(gr.Count) + "\n")
+		g.WriteString(n + "state\t" + escapeTextField(gr.State) + "\n")
+		if gr.WaitMax > 0 {
+			g.WriteString(n + "wait\t" + gr.WaitMin.String() + ".." + gr.WaitMax.String() + "\n")
+		}
+		if gr.LockedToThread {
+			g.WriteString(n + "locked_to_thread\ttrue\n")
+		}
+		ids := make([]string, 0, len(gr.IDs))
+		for _, id := range gr.IDs {
+			ids = append(ids, strconv.FormatUint(id, 10))
+		}
+		g.WriteString(n + "ids\t" + strings.Join(ids, ",") + "\n")
+		for _, fr := range gr.Stack {
+			g.WriteString(n + "frame\t" + escapeTextField(fr.Func) + "\t" + frameLocation(fr) + "\n")
+		}
+		if gr.CreatedBy != nil {
+			g.WriteString(n + "created_by\t" + escapeTextField(gr.CreatedBy.Func) + "\t" + frameLocation(*gr.CreatedBy) + "\n")
+		}
+		if maxBytes > 0 && len(head)+body.Len()+g.Len()+64 > maxBytes { // 64: groups and truncated lines
+			truncated = true
+			break
+		}
+		body.WriteString(g.String())
+		shown++
+	}
+
+	var b strings.Builder
+	b.Grow(len(head) + body.Len() + 64)
+	b.WriteString(head)
+	b.WriteString("goroutines\tgroups\t" + strconv.Itoa(shown) + "\n")
+	b.WriteString(body.String())
+	if truncated {
+		b.WriteString("goroutines\ttruncated\ttrue\n")
+	}
+	return b.String()
+}
+
+func frameLocation(fr GoroutineFrame) string {
+	if fr.File == "" {
+		return "-"
+	}
+	return escapeTextField(fr.File) + ":" + strconv.Itoa(fr.Line)
+}
@@ -0,0 +1,143 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testGoroutineDump = `goroutine 1 [running]:
+main.main()
+	/src/main.go:10 +0x1d
+
+goroutine 7 [chan receive, 5 minutes]:
+example.com/app/queue.(*Worker).run(0xc000012345)
+	/src/queue/worker.go:42 +0x1d
+created by example.com/app/queue.Start in goroutine 1
+	/src/queue/start.go:17 +0x25
+
+goroutine 8 [chan receive, 12 minutes]:
+example.com/app/queue.(*Worker).run(0xc000012399)
+	/src/queue/worker.go:42 +0x1d
+created by example.com/app/queue.Start in goroutine 1
+	/src/queue/start.go:17 +0x25
+
+goroutine 9 [IO wait, locked to thread]:
+internal/poll.runtime_pollWait(0x7f, 0x72)
+	/go/src/runtime/netpoll.go:343 +0x85
+net/http.(*conn).serve(0xc0001)
+	/go/src/net/http/server.go:2009 +0x5f4
+`
+
+func TestGroupGoroutineStacks(t *testing.T) {
+	d := GroupGoroutineStacks([]byte(testGoroutineDump), GoroutineFilter{})
+	if d.Total != 4 || d.Matched != 4 || len(d.Groups) != 3 {
+		t.Fatalf("unexpected dump: %+v", d)
+	}
+	g := d.Groups[0]
+	if g.Count != 2 || g.State != "chan receive" || g.WaitMin != 5*time.Minute || g.WaitMax != 12*time.Minute {
+		t.Fatalf("unexpected first group: %+v", g)
+	}
+	if len(g.IDs) != 2 || g.IDs[0] != 7 || g.IDs[1] != 8 {
+		t.Fatalf("unexpected ids: %v", g.IDs)
+	}
+	if len(g.Stack) != 1 || g.Stack[0].Func != "example.com/app/queue.(*Worker).run" || g.Stack[0].File != "/src/queue/worker.go" || g.Stack[0].Line != 42 {
+		t.Fatalf("unexpected stack: %+v", g.Stack)
+	}
+	if g.CreatedBy == nil || g.CreatedBy.Func != "example.com/app/queue.Start" || g.CreatedBy.Line != 17 {
+		t.Fatalf("unexpected created_by: %+v", g.CreatedBy)
+	}
+
+	cases := []struct {
+		name string
+		f    GoroutineFilter
+		want int
+	}{
+		{"func", GoroutineFilter{Func: "(*conn).serve"}, 1},
+		{"pkg", GoroutineFilter{Package: "app/queue"}, 2},
+		{"pkg does not match func names", GoroutineFilter{Package: "Worker"}, 0},
+		{"state", GoroutineFilter{State: "IO wait"}, 1},
+		{"min wait", GoroutineFilter{MinWait: 10 * time.Minute}, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := GroupGoroutineStacks([]byte(testGoroutineDump), tc.f)
+			if d.Total != 4 || d.Matched != tc.want {
+				t.Fatalf("total=%d matched=%d, want 4/%d", d.Total, d.Matched, tc.want)
+			}
+		})
+	}
+}
+
+func TestGoroutines_Handler(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	for i := 0; i < 3; i++ {
+		go func() { <-block }()
+	}
+
+	h := GoroutinesHandler()
+	// The goroutines may not have parked yet; retry briefly until they share one group.
+	var body string
+	for i := 0; i < 100; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/goroutines?func=TestGoroutines_Handler&state=chan", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status=%d, want=%d", w.Code, http.StatusOK)
+		}
+		body = w.Body.String()
+		if strings.Contains(body, "group\t1\tcount\t3\n") {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(body, "goroutines\tmatched\t3\n") || !strings.Contains(body, "group\t1\tcount\t3\n") {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/goroutines?format=json", nil))
+	var resp goroutinesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.OK || resp.Goroutines == nil || resp.Goroutines.Total < 4 {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/goroutines?min_wait=soon", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusBadRequest)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/goroutines", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGoroutines_MaxBytes(t *testing.T) {
+	d := GroupGoroutineStacks([]byte(testGoroutineDump), GoroutineFilter{})
+	text := renderGoroutinesText(d, 400)
+	if !strings.Contains(text, "goroutines\tgroups\t1\n") || !strings.Contains(text, "group\t1\t") || strings.Contains(text, "group\t2\t") ||
+		!strings.HasSuffix(text, "goroutines\ttruncated\ttrue\n") || len(text) > 400 {
+		t.Fatalf("unexpected truncated text:\n%s", text)
+	}
+
+	// The cap applies to the first group too.
+	text = renderGoroutinesText(d, 100)
+	if !strings.Contains(text, "goroutines\tgroups\t0\n") || strings.Contains(text, "group\t1\t") ||
+		!strings.HasSuffix(text, "goroutines\ttruncated\ttrue\n") || len(text) > 100 {
+		t.Fatalf("unexpected truncated text:\n%s", text)
+	}
+
+	capGoroutineDumpJSON(&d, 600)
+	if !d.Truncated || len(d.Groups) == 0 || len(d.Groups) == 3 {
+		t.Fatalf("unexpected truncated dump: truncated=%v groups=%d", d.Truncated, len(d.Groups))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -85,42 +86,88 @@ type ReadyCheck struct {
 	Name    string
 	Func    ReadyCheckFunc
 	Timeout time.Duration // optional per-check timeout; <= 0 means "no extra timeout"
+
+	// NonCritical marks a check whose failure degrades readiness instead of failing it:
+	// the report status is "degraded" and the probe still returns 200.
+	NonCritical bool
+
+	// Interval is the refresh interval used by ReadyzMonitor; <= 0 means the monitor default.
+	// It is ignored by ReadyzHandler.
+	Interval time.Duration
 }
 
+// Readiness statuses (ReadyzReport.Status).
+const (
+	ReadyStatusOK       = "ok"
+	ReadyStatusDegraded = "degraded" // only non-critical checks fail; still ready
+	ReadyStatusFail     = "fail"
+)
+
 // ReadyCheckResult is a single check execution result.
 type ReadyCheckResult struct {
 	Name string `json:"name"`
 	OK   bool   `json:"ok"`
 	// Duration is encoded as an integer number of nanoseconds in JSON.
-	Duration time.Duration `json:"duration"`
-	Error    string        `json:"error,omitempty"`
-	TimedOut bool          `json:"timed_out,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	Error       string        `json:"error,omitempty"`
+	TimedOut    bool          `json:"timed_out,omitempty"`
+	NonCritical bool          `json:"non_critical,omitempty"`
+
+	// The fields below are only set by ReadyzMonitor (cached mode).
+
+	// CheckedAt is when the cached result was produced (zero = not checked yet).
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+	// Age is encoded as an integer number of nanoseconds in JSON.
+	Age time.Duration `json:"age,omitempty"`
+	// ConsecutiveFailures counts failed runs since the last success.
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
+	// LastTransition is when the check last flipped between OK and failing.
+	LastTransition time.Time `json:"last_transition,omitempty"`
+	// Transitions counts OK <-> failing flips since the monitor started.
+	Transitions int `json:"transitions,omitempty"`
 }
 
 // ReadyzReport is a point-in-time readiness execution report.
 type ReadyzReport struct {
-	OK bool `json:"ok"`
+	// OK is false when any critical check fails.
+	OK     bool   `json:"ok"`
+	Status string `json:"status"` // ReadyStatusOK | ReadyStatusDegraded | ReadyStatusFail
 	// Duration is encoded as an integer number of nanoseconds in JSON.
 	Duration time.Duration      `json:"duration"`
 	Checks   []ReadyCheckResult `json:"checks,omitempty"`
+
+	// Cached is true for ReadyzMonitor reports; Age is the age of the oldest check result.
+	Cached bool          `json:"cached,omitempty"`
+	Age    time.Duration `json:"age,omitempty"`
+}
+
+// finalize derives OK and Status from the check results.
+func (rep *ReadyzReport) finalize() {
+	rep.OK, rep.Status = true, ReadyStatusOK
+	for _, c := range rep.Checks {
+		switch {
+		case c.OK:
+		case c.NonCritical:
+			if rep.Status == ReadyStatusOK {
+				rep.Status = ReadyStatusDegraded
+			}
+		default:
+			rep.OK, rep.Status = false, ReadyStatusFail
+		}
+	}
 }
 
 // ReadyzHandler returns a readiness handler that runs checks sequentially.
 //
 // It responds:
-//   - 200 OK if all checks pass
-//   - 503 Service Unavailable if any check fails or times out
+//   - 200 OK if all critical checks pass (status "ok", or "degraded" when a non-critical check fails)
+//   - 503 Service Unavailable if any critical check fails or times out
 //
 // GET/HEAD only; other methods return 405.
+//
+// Checks run on every probe. See ReadyzMonitor for background-refreshed, cached checks.
 func ReadyzHandler(checks []ReadyCheck, opts ...HealthOption) http.Handler {
-	for i, c := range checks {
-		if c.Name == "" {
-			panic(fmt.Sprintf("ops: ready check[%d] has empty Name", i))
-		}
-		if c.Func == nil {
-			panic(fmt.Sprintf("ops: ready check[%d] %q has nil Func", i, c.Name))
-		}
-	}
+	validateReadyChecks(checks)
 	cfg := applyHealthOptions(opts)
 
 	// Snapshot to keep handler stable if caller mutates the slice later.
@@ -135,7 +182,8 @@ func ReadyzHandler(checks []ReadyCheck, opts ...HealthOption) http.Handler {
 			w.Header().Set("Allow", "GET, HEAD")
 			// Keep JSON shape consistent: always return ReadyzReport for ReadyzHandler.
 			writeReady(w, r, format, http.StatusMethodNotAllowed, ReadyzReport{
-				OK: false,
+				OK:     false,
+				Status: ReadyStatusFail,
 				Checks: []ReadyCheckResult{
 					{Name: "method", OK: false, Error: "method not allowed"},
 				},
@@ -144,14 +192,28 @@ func ReadyzHandler(checks []ReadyCheck, opts ...HealthOption) http.Handler {
 		}
 
 		rep := RunReadyzChecks(r.Context(), snapshot)
-		code := http.StatusOK
-		if !rep.OK {
-			code = http.StatusServiceUnavailable
-		}
-		writeReady(w, r, format, code, rep)
+		writeReady(w, r, format, readyStatusCode(rep), rep)
 	})
 }
 
+func validateReadyChecks(checks []ReadyCheck) {
+	for i, c := range checks {
+		if c.Name == "" {
+			panic(fmt.Sprintf("ops: ready check[%d] has empty Name", i))
+		}
+		if c.Func == nil {
+			panic(fmt.Sprintf("ops: ready check[%d] %q has nil Func", i, c.Name))
+		}
+	}
+}
+
+func readyStatusCode(rep ReadyzReport) int {
+	if !rep.OK {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
 func formatFromRequest(r *http.Request, def Format) Format {
 	if r == nil || r.URL == nil {
 		return def
@@ -173,24 +235,21 @@ func RunReadyzChecks(ctx context.Context, checks []ReadyCheck) ReadyzReport {
 	}
 	start := time.Now()
 	out := ReadyzReport{
-		OK:     true,
 		Checks: make([]ReadyCheckResult, 0, len(checks)),
 	}
 
 	for _, c := range checks {
-		cr := runOneCheck(ctx, c)
-		out.Checks = append(out.Checks, cr)
-		if !cr.OK {
-			out.OK = false
-		}
+		out.Checks = append(out.Checks, runOneCheck(ctx, c))
 	}
 
+	out.finalize()
 	out.Duration = time.Since(start)
 	return out
 }
 
 func runOneCheck(parent context.Context, c ReadyCheck) (cr ReadyCheckResult) {
 	cr.Name = c.Name
+	cr.NonCritical = c.NonCritical
 
 	start := time.Now()
 	ctx := parent
@@ -281,19 +340,38 @@ func writeReady(w http.ResponseWriter, r *http.Request, f Format, code int, rep
 		if r.Method == http.MethodHead {
 			return
 		}
-		if rep.OK {
-			_, _ = w.Write([]byte("ok\n"))
-			return
+		_, _ = w.Write([]byte(renderReadyText(rep)))
+	}
+}
+
+func renderReadyText(rep ReadyzReport) string {
+	// Format:
+	//   ok | degraded          (only when rep.OK)
+	//   age <duration>         (cached reports only)
+	//   fail <name>[ (non-critical)][: <error>]
+	var b strings.Builder
+	if rep.OK {
+		if rep.Status == ReadyStatusDegraded {
+			b.WriteString("degraded\n")
+		} else {
+			b.WriteString("ok\n")
 		}
-		for _, c := range rep.Checks {
-			if c.OK {
-				continue
-			}
-			if c.Error != "" {
-				_, _ = w.Write([]byte("fail " + c.Name + ": " + c.Error + "\n"))
-			} else {
-				_, _ = w.Write([]byte("fail " + c.Name + "\n"))
-			}
+	}
+	if rep.Cached {
+		b.WriteString("age " + rep.Age.Round(time.Millisecond).String() + "\n")
+	}
+	for _, c := range rep.Checks {
+		if c.OK {
+			continue
+		}
+		b.WriteString("fail " + c.Name)
+		if c.NonCritical {
+			b.WriteString(" (non-critical)")
+		}
+		if c.Error != "" {
+			b.WriteString(": " + c.Error)
 		}
+		b.WriteString("\n")
 	}
+	return b.String()
 }
@@ -3,6 +3,7 @@ package ops
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -377,3 +378,28 @@ func TestReadyz_CheckSliceIsSnapshotted(t *testing.T) {
 		t.Fatalf("body=%q, want not contain %q", body, "fail b")
 	}
 }
+
+func TestReadyz_NonCriticalFailureIsDegraded(t *testing.T) {
+	h := ReadyzHandler([]ReadyCheck{
+		{Name: "db", Func: func(ctx context.Context) error { return nil }},
+		{Name: "cache", NonCritical: true, Func: func(ctx context.Context) error { return errors.New("down") }},
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/readyz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusOK)
+	}
+	if body := w.Body.String(); body != "degraded\nfail cache (non-critical): down\n" {
+		t.Fatalf("body=%q", body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/readyz?format=json", nil))
+	var rep ReadyzReport
+	if err := json.Unmarshal(w.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !rep.OK || rep.Status != ReadyStatusDegraded || !rep.Checks[1].NonCritical {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+}
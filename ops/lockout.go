@@ -0,0 +1,271 @@
+package ops
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+type lockoutConfig struct {
+	format Format
+}
+
+// LockoutOption configures LockoutSnapshotHandler / LockoutClearHandler.
+type LockoutOption func(*lockoutConfig)
+
+// WithLockoutDefaultFormat sets the default response format for lockout handlers.
+//
+// This default can be overridden per request by URL query:
+//   - ?format=json
+//   - ?format=text
+//
+// Default is FormatText.
+func WithLockoutDefaultFormat(f Format) LockoutOption {
+	return func(c *lockoutConfig) { c.format = f }
+}
+
+func applyLockoutOptions(opts []LockoutOption) lockoutConfig {
+	cfg := lockoutConfig{
+		format: FormatText,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.format != FormatText && cfg.format != FormatJSON {
+		cfg.format = FormatText
+	}
+	return cfg
+}
+
+type lockoutSnapshotResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	Lockout *httpx.LockoutSnapshot `json:"lockout,omitempty"`
+}
+
+type lockoutClearResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	// Target is the cleared IP, or "global" / "all".
+	Target string `json:"target,omitempty"`
+	// Tracked reports whether the IP had recorded failures (IP target only).
+	Tracked bool `json:"tracked,omitempty"`
+}
+
+// LockoutSnapshotHandler returns a handler that lists currently locked-out client IPs,
+// the global lockout state, and deny counts by httpx.DenyReason.
+//
+// Behavior:
+//   - GET/HEAD only; other methods return 405.
+//   - By default, it renders text. You can change the default with options.
+//   - The response format can be overridden per request by URL query (?format=json|text).
+func LockoutSnapshotHandler(l *httpx.Lockout, opts ...LockoutOption) http.Handler {
+	if l == nil {
+		panic("ops: nil httpx.Lockout")
+	}
+	cfg := applyLockoutOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeLockoutSnapshot(w, r, format, http.StatusMethodNotAllowed, lockoutSnapshotResponse{
+				OK:    false,
+				Error: "method not allowed",
+			})
+			return
+		}
+		snap := l.Snapshot()
+		writeLockoutSnapshot(w, r, format, http.StatusOK, lockoutSnapshotResponse{
+			OK:      true,
+			Lockout: &snap,
+		})
+	})
+}
+
+// LockoutClearHandler returns a handler that clears lockouts.
+//
+// Input:
+//   - POST only
+//   - URL query (exactly one of):
+//     ?ip=<client ip> (clears one client IP)
+//     ?global=true (clears the global lockout)
+//     ?all=true (clears every lockout; deny counts are kept)
+//
+// Output:
+//   - Text or JSON (controlled by option or ?format=)
+func LockoutClearHandler(l *httpx.Lockout, opts ...LockoutOption) http.Handler {
+	if l == nil {
+		panic("ops: nil httpx.Lockout")
+	}
+	cfg := applyLockoutOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeLockoutClear(w, r, format, http.StatusMethodNotAllowed, lockoutClearResponse{
+				OK:    false,
+				Error: "method not allowed",
+			})
+			return
+		}
+
+		ip, haveIP := getQueryRequired(r, "ip")
+		global := queryBool(r, "global")
+		all := queryBool(r, "all")
+		n := 0
+		for _, b := range []bool{haveIP, global, all} {
+			if b {
+				n++
+			}
+		}
+		if n != 1 {
+			writeLockoutClear(w, r, format, http.StatusBadRequest, lockoutClearResponse{
+				OK:    false,
+				Error: "want exactly one of: ip, global=true, all=true",
+			})
+			return
+		}
+
+		switch {
+		case all:
+			l.ClearAll()
+			writeLockoutClear(w, r, format, http.StatusOK, lockoutClearResponse{OK: true, Target: "all"})
+		case global:
+			l.ClearGlobal()
+			writeLockoutClear(w, r, format, http.StatusOK, lockoutClearResponse{OK: true, Target: "global"})
+		default:
+			parsed := net.ParseIP(strings.TrimSpace(ip))
+			if parsed == nil {
+				writeLockoutClear(w, r, format, http.StatusBadRequest, lockoutClearResponse{
+					OK:    false,
+					Error: "invalid ip",
+				})
+				return
+			}
+			if ip4 := parsed.To4(); ip4 != nil {
+				parsed = ip4
+			}
+			tracked := l.Clear(parsed.String())
+			writeLockoutClear(w, r, format, http.StatusOK, lockoutClearResponse{
+				OK:      true,
+				Target:  parsed.String(),
+				Tracked: tracked,
+			})
+		}
+	})
+}
+
+func queryBool(r *http.Request, name string) bool {
+	v, ok := getQueryRequired(r, name)
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	return err == nil && b
+}
+
+func writeLockoutSnapshot(w http.ResponseWriter, r *http.Request, f Format, code int, resp lockoutSnapshotResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if !resp.OK || resp.Lockout == nil {
+			writeTextError(w, resp.Error)
+			return
+		}
+		_, _ = w.Write([]byte(renderLockoutSnapshotText(*resp.Lockout)))
+	}
+}
+
+func writeLockoutClear(w http.ResponseWriter, r *http.Request, f Format, code int, resp lockoutClearResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if !resp.OK {
+			writeTextError(w, resp.Error)
+			return
+		}
+		var b strings.Builder
+		b.WriteString("lockout\tcleared\t")
+		b.WriteString(resp.Target)
+		b.WriteByte('\n')
+		if resp.Target != "all" && resp.Target != "global" {
+			b.WriteString("lockout\ttracked\t")
+			b.WriteString(strconv.FormatBool(resp.Tracked))
+			b.WriteByte('\n')
+		}
+		_, _ = w.Write([]byte(b.String()))
+	}
+}
+
+func renderLockoutSnapshotText(s httpx.LockoutSnapshot) string {
+	// Stable and greppable:
+	//   lockout\t<key>\t<value>\n
+	//   locked\t<ip>\t<field>\t<value>\n
+	//   deny\t<reason>\t<count>\n
+	var b strings.Builder
+	b.Grow(256)
+	b.WriteString("lockout\ttracked\t")
+	b.WriteString(strconv.Itoa(s.Tracked))
+	b.WriteByte('\n')
+	b.WriteString("lockout\tlocked\t")
+	b.WriteString(strconv.Itoa(len(s.Locked)))
+	b.WriteByte('\n')
+	if !s.GlobalLockedUntil.IsZero() {
+		b.WriteString("lockout\tglobal_locked_until\t")
+		b.WriteString(s.GlobalLockedUntil.Format(time.RFC3339Nano))
+		b.WriteByte('\n')
+	}
+	for _, e := range s.Locked {
+		ip := escapeTextField(e.IP)
+		b.WriteString("locked\t" + ip + "\tuntil\t" + e.LockedUntil.Format(time.RFC3339Nano) + "\n")
+		b.WriteString("locked\t" + ip + "\tretry_after\t" + e.RetryAfter.Round(time.Second).String() + "\n")
+		b.WriteString("locked\t" + ip + "\tstrikes\t" + strconv.Itoa(e.Strikes) + "\n")
+	}
+	reasons := make([]string, 0, len(s.DenyCounts))
+	for k := range s.DenyCounts {
+		reasons = append(reasons, string(k))
+	}
+	sort.Strings(reasons)
+	for _, k := range reasons {
+		b.WriteString("deny\t")
+		b.WriteString(escapeTextField(k))
+		b.WriteByte('\t')
+		b.WriteString(strconv.FormatUint(s.DenyCounts[httpx.DenyReason(k)], 10))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
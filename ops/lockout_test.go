@@ -0,0 +1,101 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+func lockedOutForTest(t *testing.T) *httpx.Lockout {
+	t.Helper()
+	l := httpx.NewLockout(httpx.WithLockoutThreshold(1))
+	req := httptest.NewRequest(http.MethodGet, "http://example/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	l.OnDeny(req, httpx.DenyReasonTokenNotAllowed)
+	return l
+}
+
+func TestLockoutSnapshotHandler_Text(t *testing.T) {
+	l := lockedOutForTest(t)
+	w := httptest.NewRecorder()
+	LockoutSnapshotHandler(l).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusOK)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"lockout\ttracked\t1\n",
+		"lockout\tlocked\t1\n",
+		"locked\t10.0.0.1\tstrikes\t1\n",
+		"deny\ttoken-not-allowed\t1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestLockoutSnapshotHandler_JSONAndMethod(t *testing.T) {
+	l := lockedOutForTest(t)
+	h := LockoutSnapshotHandler(l, WithLockoutDefaultFormat(FormatJSON))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/", nil))
+	var got struct {
+		OK      bool                  `json:"ok"`
+		Lockout httpx.LockoutSnapshot `json:"lockout"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !got.OK || len(got.Lockout.Locked) != 1 || got.Lockout.Locked[0].IP != "10.0.0.1" {
+		t.Fatalf("unexpected response: %+v", got)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestLockoutClearHandler(t *testing.T) {
+	l := lockedOutForTest(t)
+	h := LockoutClearHandler(l)
+
+	for _, q := range []string{"", "?ip=nope", "?ip=10.0.0.1&all=true"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/"+q, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("query %q: status=%d, want=%d", q, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/?ip=10.0.0.1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusOK)
+	}
+	if got, want := w.Body.String(), "lockout\tcleared\t10.0.0.1\nlockout\ttracked\ttrue\n"; got != want {
+		t.Fatalf("body=%q, want %q", got, want)
+	}
+	if snap := l.Snapshot(); len(snap.Locked) != 0 {
+		t.Fatalf("expected no lockouts, got %+v", snap.Locked)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/?all=true", nil))
+	if got, want := w.Body.String(), "lockout\tcleared\tall\n"; got != want {
+		t.Fatalf("body=%q, want %q", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/?all=true", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
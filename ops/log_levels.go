@@ -0,0 +1,296 @@
+package ops
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/slogx"
+)
+
+// LogComponentLevel is a point-in-time snapshot of one slogx.Levels component.
+type LogComponentLevel struct {
+	Name    string           `json:"name"`
+	Level   LogLevelSnapshot `json:"level"`
+	Initial LogLevelSnapshot `json:"initial"`
+
+	// ExpiresAt / RevertTo are set while a temporary level (?ttl=) is pending.
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+	RevertTo  *LogLevelSnapshot `json:"revert_to,omitempty"`
+}
+
+// LogLevels returns a snapshot of every component in levels (sorted by name).
+func LogLevels(levels *slogx.Levels) []LogComponentLevel {
+	if levels == nil {
+		return nil
+	}
+	infos := levels.Snapshot()
+	out := make([]LogComponentLevel, 0, len(infos))
+	for _, it := range infos {
+		out = append(out, logComponentLevel(it))
+	}
+	return out
+}
+
+func logComponentLevel(it slogx.LevelInfo) LogComponentLevel {
+	c := LogComponentLevel{
+		Name:    it.Name,
+		Level:   levelSnapshot(it.Level),
+		Initial: levelSnapshot(it.Initial),
+	}
+	if !it.ExpiresAt.IsZero() {
+		at := it.ExpiresAt
+		rt := levelSnapshot(it.RevertTo)
+		c.ExpiresAt, c.RevertTo = &at, &rt
+	}
+	return c
+}
+
+func levelSnapshot(l slog.Level) LogLevelSnapshot {
+	return LogLevelSnapshot{Level: levelToEnum(l), LevelValue: int(l)}
+}
+
+func lookupLogComponentLevel(levels *slogx.Levels, name string) *LogComponentLevel {
+	for _, it := range levels.Snapshot() {
+		if it.Name == name {
+			c := logComponentLevel(it)
+			return &c
+		}
+	}
+	return nil
+}
+
+type logLevelsResponse struct {
+	OK     bool                `json:"ok"`
+	Error  string              `json:"error,omitempty"`
+	Levels []LogComponentLevel `json:"levels,omitempty"`
+}
+
+type logLevelsWriteResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	Name string             `json:"name,omitempty"`
+	Old  *LogLevelSnapshot  `json:"old,omitempty"`
+	New  *LogComponentLevel `json:"new,omitempty"`
+}
+
+// LogLevelsHandler returns a handler that lists the component levels of a slogx.Levels
+// registry, including "default" and any pending temporary level.
+//
+// Behavior:
+//   - GET/HEAD only; other methods return 405.
+//   - Text or JSON (controlled by option or ?format=).
+func LogLevelsHandler(levels *slogx.Levels, opts ...LogLevelOption) http.Handler {
+	if levels == nil {
+		panic("ops: nil slogx.Levels")
+	}
+	cfg := applyLogLevelOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeLogLevels(w, r, format, http.StatusMethodNotAllowed, logLevelsResponse{Error: "method not allowed"})
+			return
+		}
+		writeLogLevels(w, r, format, http.StatusOK, logLevelsResponse{OK: true, Levels: LogLevels(levels)})
+	})
+}
+
+// LogLevelsSetHandler returns a handler that sets one component's level.
+//
+// Input:
+//   - POST only
+//   - URL query: ?name=<component>&level=debug|info|warn|error[&ttl=<duration>]
+//     (ttl > 0 reverts the level automatically, e.g. ttl=15m)
+//
+// Unknown components return 404.
+func LogLevelsSetHandler(levels *slogx.Levels, opts ...LogLevelOption) http.Handler {
+	if levels == nil {
+		panic("ops: nil slogx.Levels")
+	}
+	cfg := applyLogLevelOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeLogLevelsWrite(w, format, http.StatusMethodNotAllowed, logLevelsWriteResponse{Error: "method not allowed"})
+			return
+		}
+		q := r.URL.Query()
+		name := strings.TrimSpace(q.Get("name"))
+		if name == "" {
+			writeLogLevelsWrite(w, format, http.StatusBadRequest, logLevelsWriteResponse{Error: "missing name"})
+			return
+		}
+		enum, ok := normalizeLevelEnum(q.Get("level"))
+		if !ok {
+			writeLogLevelsWrite(w, format, http.StatusBadRequest, logLevelsWriteResponse{
+				Error: "invalid level (want one of: debug, info, warn, error)",
+			})
+			return
+		}
+		var ttl time.Duration
+		if v := strings.TrimSpace(q.Get("ttl")); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				writeLogLevelsWrite(w, format, http.StatusBadRequest, logLevelsWriteResponse{
+					Error: "invalid ttl (want a duration, e.g. 15m)",
+				})
+				return
+			}
+			ttl = d
+		}
+
+		old, err := levels.Set(name, enumToLevel(enum), ttl)
+		if err != nil {
+			writeLogLevelsWrite(w, format, logLevelsErrorStatus(err), logLevelsWriteResponse{Name: name, Error: err.Error()})
+			return
+		}
+		oldSnap := levelSnapshot(old)
+		writeLogLevelsWrite(w, format, http.StatusOK, logLevelsWriteResponse{
+			OK:   true,
+			Name: name,
+			Old:  &oldSnap,
+			New:  lookupLogComponentLevel(levels, name),
+		})
+	})
+}
+
+// LogLevelsResetHandler returns a handler that restores one component's registration level
+// and cancels any pending temporary level.
+//
+// Input:
+//   - POST only
+//   - URL query: ?name=<component>
+//
+// Unknown components return 404.
+func LogLevelsResetHandler(levels *slogx.Levels, opts ...LogLevelOption) http.Handler {
+	if levels == nil {
+		panic("ops: nil slogx.Levels")
+	}
+	cfg := applyLogLevelOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeLogLevelsWrite(w, format, http.StatusMethodNotAllowed, logLevelsWriteResponse{Error: "method not allowed"})
+			return
+		}
+		name := strings.TrimSpace(r.URL.Query().Get("name"))
+		if name == "" {
+			writeLogLevelsWrite(w, format, http.StatusBadRequest, logLevelsWriteResponse{Error: "missing name"})
+			return
+		}
+		before := lookupLogComponentLevel(levels, name)
+		if err := levels.Reset(name); err != nil {
+			writeLogLevelsWrite(w, format, logLevelsErrorStatus(err), logLevelsWriteResponse{Name: name, Error: err.Error()})
+			return
+		}
+		resp := logLevelsWriteResponse{OK: true, Name: name, New: lookupLogComponentLevel(levels, name)}
+		if before != nil {
+			resp.Old = &before.Level
+		}
+		writeLogLevelsWrite(w, format, http.StatusOK, resp)
+	})
+}
+
+func logLevelsErrorStatus(err error) int {
+	if errors.Is(err, slogx.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func writeLogLevels(w http.ResponseWriter, r *http.Request, f Format, code int, resp logLevelsResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if resp.OK && resp.Levels == nil {
+			resp.Levels = []LogComponentLevel{}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if !resp.OK {
+			_, _ = w.Write([]byte(resp.Error + "\n"))
+			return
+		}
+		var b strings.Builder
+		b.Grow(64 * len(resp.Levels))
+		for _, c := range resp.Levels {
+			appendLogComponentLines(&b, c, "")
+		}
+		_, _ = w.Write([]byte(b.String()))
+	}
+}
+
+func writeLogLevelsWrite(w http.ResponseWriter, f Format, code int, resp logLevelsWriteResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if !resp.OK {
+			_, _ = w.Write([]byte(resp.Error + "\n"))
+			return
+		}
+		var b strings.Builder
+		b.Grow(256)
+		if resp.Old != nil {
+			writeLogComponentField(&b, resp.Name, "old.level", resp.Old.Level)
+			writeLogComponentField(&b, resp.Name, "old.level_value", strconv.Itoa(resp.Old.LevelValue))
+		}
+		if resp.New != nil {
+			appendLogComponentLines(&b, *resp.New, "new.")
+		}
+		_, _ = w.Write([]byte(b.String()))
+	}
+}
+
+func appendLogComponentLines(b *strings.Builder, c LogComponentLevel, fieldPrefix string) {
+	// Stable and greppable, same shape as tuning: log_level\t<name>\t<field>\t<value>\n
+	writeLogComponentField(b, c.Name, fieldPrefix+"level", c.Level.Level)
+	writeLogComponentField(b, c.Name, fieldPrefix+"level_value", strconv.Itoa(c.Level.LevelValue))
+	writeLogComponentField(b, c.Name, fieldPrefix+"initial", c.Initial.Level)
+	if c.ExpiresAt != nil && c.RevertTo != nil {
+		writeLogComponentField(b, c.Name, fieldPrefix+"expires_at", c.ExpiresAt.UTC().Format(time.RFC3339))
+		writeLogComponentField(b, c.Name, fieldPrefix+"revert_to", c.RevertTo.Level)
+	}
+}
+
+func writeLogComponentField(b *strings.Builder, name, field, value string) {
+	b.WriteString("log_level\t")
+	b.WriteString(escapeTextField(name))
+	b.WriteByte('\t')
+	b.WriteString(field)
+	b.WriteByte('\t')
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
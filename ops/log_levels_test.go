@@ -0,0 +1,132 @@
+package ops
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evan-idocoding/zkit/slogx"
+)
+
+func newTestLevels(t *testing.T) *slogx.Levels {
+	t.Helper()
+	levels := slogx.NewLevels()
+	if _, err := levels.Register("db", slog.LevelInfo); err != nil {
+		t.Fatal(err)
+	}
+	return levels
+}
+
+func TestLogLevels_ListText(t *testing.T) {
+	levels := newTestLevels(t)
+	_, _ = levels.Set("db", slog.LevelDebug, time.Hour)
+
+	w := httptest.NewRecorder()
+	LogLevelsHandler(levels).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/log/levels", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"log_level\tdb\tlevel\tdebug\n",
+		"log_level\tdb\tlevel_value\t-4\n",
+		"log_level\tdb\tinitial\tinfo\n",
+		"log_level\tdb\trevert_to\tinfo\n",
+		"log_level\tdb\texpires_at\t",
+		"log_level\tdefault\tlevel\tinfo\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body=%q, want contain %q", body, want)
+		}
+	}
+	if strings.Contains(body, "log_level\tdefault\texpires_at") {
+		t.Fatalf("default has no pending revert: %q", body)
+	}
+
+	w = httptest.NewRecorder()
+	LogLevelsHandler(levels).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/log/levels", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD" {
+		t.Fatalf("status=%d allow=%q", w.Code, w.Header().Get("Allow"))
+	}
+}
+
+func TestLogLevels_SetAndResetJSON(t *testing.T) {
+	levels := newTestLevels(t)
+	set := LogLevelsSetHandler(levels)
+	reset := LogLevelsResetHandler(levels)
+
+	w := httptest.NewRecorder()
+	set.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/log/levels/set?name=db&level=debug&ttl=1h&format=json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var resp logLevelsWriteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.OK || resp.Old.Level != "info" || resp.New.Level.Level != "debug" || resp.New.ExpiresAt == nil || resp.New.RevertTo.Level != "info" {
+		t.Fatalf("resp=%+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	reset.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/log/levels/reset?name=db", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("reset status=%d body=%s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "log_level\tdb\told.level\tdebug\n") || !strings.Contains(body, "log_level\tdb\tnew.level\tinfo\n") {
+		t.Fatalf("reset body=%q", body)
+	}
+	if lv, _ := levels.Var("db"); lv.Level() != slog.LevelInfo {
+		t.Fatalf("level=%v", lv.Level())
+	}
+
+	for _, tc := range []struct {
+		h    http.Handler
+		url  string
+		code int
+	}{
+		{set, "/log/levels/set?name=nope&level=debug", http.StatusNotFound},
+		{set, "/log/levels/set?level=debug", http.StatusBadRequest},
+		{set, "/log/levels/set?name=db&level=loud", http.StatusBadRequest},
+		{set, "/log/levels/set?name=db&level=debug&ttl=-1s", http.StatusBadRequest},
+		{reset, "/log/levels/reset?name=nope", http.StatusNotFound},
+		{reset, "/log/levels/reset", http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		tc.h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example"+tc.url, nil))
+		if w.Code != tc.code {
+			t.Fatalf("%s: status=%d, want %d (body=%q)", tc.url, w.Code, tc.code, w.Body.String())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	set.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/log/levels/set?name=db&level=debug", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "POST" {
+		t.Fatalf("status=%d allow=%q", w.Code, w.Header().Get("Allow"))
+	}
+}
+
+func TestEventHub_ObserveLogLevels(t *testing.T) {
+	levels := newTestLevels(t)
+	hub := NewEventHub()
+	stop := hub.ObserveLogLevels(levels)
+	defer stop()
+	sub := hub.subscribe(eventFilter{}, 4)
+	defer hub.unsubscribe(sub)
+
+	_, _ = levels.Set("db", slog.LevelDebug, 0)
+	select {
+	case e := <-sub.ch:
+		data := e.Data.(logLevelEvent)
+		if e.Type != EventLogLevel || e.Key != "db" || data.Old.Level != "info" || data.New.Level != "debug" || data.Cause != slogx.LevelCauseSet {
+			t.Fatalf("event=%+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no event")
+	}
+}
@@ -0,0 +1,327 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/slogx"
+)
+
+type logTailConfig struct {
+	format    Format
+	defaultN  int
+	maxN      int
+	followBuf int
+}
+
+// LogTailOption configures LogTailHandler.
+type LogTailOption func(*logTailConfig)
+
+// WithLogTailDefaultFormat sets the default response format. Default is FormatText.
+func WithLogTailDefaultFormat(f Format) LogTailOption {
+	return func(c *logTailConfig) { c.format = f }
+}
+
+// WithLogTailMaxRecords caps ?n=. <= 0 means default (1000).
+func WithLogTailMaxRecords(n int) LogTailOption {
+	return func(c *logTailConfig) { c.maxN = n }
+}
+
+func applyLogTailOptions(opts []LogTailOption) logTailConfig {
+	cfg := logTailConfig{format: FormatText, defaultN: 100, maxN: 1000, followBuf: 256}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.format != FormatText && cfg.format != FormatJSON {
+		cfg.format = FormatText
+	}
+	if cfg.maxN <= 0 {
+		cfg.maxN = 1000
+	}
+	if cfg.defaultN > cfg.maxN {
+		cfg.defaultN = cfg.maxN
+	}
+	return cfg
+}
+
+// LogTailRecord is the JSON form of a slogx.RingRecord.
+type LogTailRecord struct {
+	Seq       uint64            `json:"seq"`
+	Time      time.Time         `json:"time"`
+	Level     string            `json:"level"` // slog.Level.String(), e.g. "INFO", "DEBUG-2"
+	Message   string            `json:"msg"`
+	RequestID string            `json:"request_id,omitempty"`
+	Attrs     map[string]string `json:"attrs,omitempty"`
+}
+
+type logTailResponse struct {
+	OK      bool            `json:"ok"`
+	Error   string          `json:"error,omitempty"`
+	Records []LogTailRecord `json:"records,omitempty"`
+
+	recs []slogx.RingRecord // text rendering keeps attribute order
+}
+
+type logTailFilter struct {
+	minLevel  int
+	hasLevel  bool
+	since     time.Time
+	contains  string
+	requestID string
+}
+
+func (f logTailFilter) match(r slogx.RingRecord) bool {
+	if f.hasLevel && int(r.Level) < f.minLevel {
+		return false
+	}
+	if !f.since.IsZero() && r.Time.Before(f.since) {
+		return false
+	}
+	if f.contains != "" && !strings.Contains(r.Message, f.contains) {
+		return false
+	}
+	if f.requestID != "" && r.RequestID != f.requestID {
+		return false
+	}
+	return true
+}
+
+// LogTailHandler returns a handler that serves the most recent records of ring.
+//
+// Input (all optional):
+//   - ?n=: number of records (default 100, capped by WithLogTailMaxRecords)
+//   - ?level=debug|info|warn|error: minimum level
+//   - ?since=: a duration (5m => the last 5 minutes) or an RFC 3339 time
+//   - ?q=: message substring
+//   - ?request_id=: exact request ID
+//   - ?follow=1: after the matching records, keep streaming new ones until the client goes away
+//     (text lines, or one JSON object per line with ?format=json). Clients that fall behind are
+//     dropped with a final "dropped" line.
+//
+// Text output is one record per line:
+//
+//	<time>\t<LEVEL>\t<msg>[\trequest_id=<id>]\t<key>=<value>...
+func LogTailHandler(ring *slogx.Ring, opts ...LogTailOption) http.Handler {
+	if ring == nil {
+		panic("ops: nil slogx.Ring")
+	}
+	cfg := applyLogTailOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeLogTail(w, r, format, http.StatusMethodNotAllowed, logTailResponse{Error: "method not allowed"})
+			return
+		}
+
+		q := r.URL.Query()
+		n := cfg.defaultN
+		if v := strings.TrimSpace(q.Get("n")); v != "" {
+			i, err := strconv.Atoi(v)
+			if err != nil || i <= 0 {
+				writeLogTail(w, r, format, http.StatusBadRequest, logTailResponse{Error: "invalid n (want a positive integer)"})
+				return
+			}
+			n = i
+		}
+		if n > cfg.maxN {
+			n = cfg.maxN
+		}
+		var f logTailFilter
+		if v := q.Get("level"); v != "" {
+			enum, ok := normalizeLevelEnum(v)
+			if !ok {
+				writeLogTail(w, r, format, http.StatusBadRequest, logTailResponse{
+					Error: "invalid level (want one of: debug, info, warn, error)",
+				})
+				return
+			}
+			f.minLevel, f.hasLevel = int(enumToLevel(enum)), true
+		}
+		if v := strings.TrimSpace(q.Get("since")); v != "" {
+			if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+				f.since = time.Now().Add(-d)
+			} else if t, err := time.Parse(time.RFC3339, v); err == nil {
+				f.since = t
+			} else {
+				writeLogTail(w, r, format, http.StatusBadRequest, logTailResponse{
+					Error: "invalid since (want a duration like 5m or an RFC 3339 time)",
+				})
+				return
+			}
+		}
+		f.contains = q.Get("q")
+		f.requestID = strings.TrimSpace(q.Get("request_id"))
+
+		follow := q.Get("follow")
+		if follow == "1" || follow == "true" {
+			serveLogTailFollow(w, r, format, ring, f, n, cfg.followBuf)
+			return
+		}
+		recs := tailRecords(ring.Records(), f, n)
+		resp := logTailResponse{OK: true, recs: recs, Records: make([]LogTailRecord, 0, len(recs))}
+		for _, rec := range recs {
+			resp.Records = append(resp.Records, logTailRecord(rec))
+		}
+		writeLogTail(w, r, format, http.StatusOK, resp)
+	})
+}
+
+// tailRecords returns the last n records matching f, oldest first.
+func tailRecords(all []slogx.RingRecord, f logTailFilter, n int) []slogx.RingRecord {
+	picked := make([]slogx.RingRecord, 0, n)
+	for i := len(all) - 1; i >= 0 && len(picked) < n; i-- {
+		if f.match(all[i]) {
+			picked = append(picked, all[i])
+		}
+	}
+	for i, j := 0, len(picked)-1; i < j; i, j = i+1, j-1 {
+		picked[i], picked[j] = picked[j], picked[i]
+	}
+	return picked
+}
+
+func logTailRecord(r slogx.RingRecord) LogTailRecord {
+	out := LogTailRecord{
+		Seq:       r.Seq,
+		Time:      r.Time,
+		Level:     r.Level.String(),
+		Message:   r.Message,
+		RequestID: r.RequestID,
+	}
+	if len(r.Attrs) > 0 {
+		out.Attrs = make(map[string]string, len(r.Attrs))
+		for _, a := range r.Attrs {
+			out.Attrs[a.Key] = a.Value
+		}
+	}
+	return out
+}
+
+func serveLogTailFollow(w http.ResponseWriter, r *http.Request, format Format, ring *slogx.Ring, f logTailFilter, n, buffer int) {
+	fl, ok := w.(http.Flusher)
+	if !ok {
+		writeLogTail(w, r, format, http.StatusInternalServerError, logTailResponse{Error: "streaming not supported"})
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	if format == FormatJSON {
+		w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	backlog, ch, cancel := ring.Subscribe(buffer)
+	defer cancel()
+
+	write := func(rec slogx.RingRecord) bool {
+		var line []byte
+		if format == FormatJSON {
+			line, _ = json.Marshal(logTailRecord(rec))
+			line = append(line, '\n')
+		} else {
+			line = []byte(renderLogTailLine(rec))
+		}
+		_, err := w.Write(line)
+		return err == nil
+	}
+	for _, rec := range tailRecords(backlog, f, n) {
+		if !write(rec) {
+			return
+		}
+	}
+	fl.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case rec, ok := <-ch:
+			if !ok {
+				if format == FormatJSON {
+					_, _ = w.Write([]byte(`{"ok":false,"error":"dropped: slow consumer"}` + "\n"))
+				} else {
+					_, _ = w.Write([]byte("dropped: slow consumer\n"))
+				}
+				fl.Flush()
+				return
+			}
+			if !f.match(rec) {
+				continue
+			}
+			if !write(rec) {
+				return
+			}
+			fl.Flush()
+		}
+	}
+}
+
+func writeLogTail(w http.ResponseWriter, r *http.Request, f Format, code int, resp logTailResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if resp.OK && resp.Records == nil {
+			resp.Records = []LogTailRecord{}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if !resp.OK {
+			_, _ = w.Write([]byte(resp.Error + "\n"))
+			return
+		}
+		var b strings.Builder
+		for _, rec := range resp.recs {
+			b.WriteString(renderLogTailLine(rec))
+		}
+		_, _ = w.Write([]byte(b.String()))
+	}
+}
+
+func renderLogTailLine(rec slogx.RingRecord) string {
+	var b strings.Builder
+	b.Grow(128)
+	b.WriteString(rec.Time.UTC().Format(time.RFC3339Nano))
+	b.WriteByte('\t')
+	b.WriteString(rec.Level.String())
+	b.WriteByte('\t')
+	b.WriteString(escapeTextField(rec.Message))
+	if rec.RequestID != "" {
+		b.WriteString("\trequest_id=")
+		b.WriteString(escapeTextField(rec.RequestID))
+	}
+	for _, a := range rec.Attrs {
+		if a.Key == slogx.RequestIDKey && a.Value == rec.RequestID {
+			continue
+		}
+		b.WriteByte('\t')
+		b.WriteString(escapeTextField(a.Key))
+		b.WriteByte('=')
+		b.WriteString(escapeTextField(a.Value))
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
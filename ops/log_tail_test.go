@@ -0,0 +1,126 @@
+package ops
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/slogx"
+)
+
+func newTestRing() (*slogx.Ring, *slog.Logger) {
+	ring := slogx.NewRing(slogx.WithRingLevel(slog.LevelDebug))
+	return ring, slog.New(ring.Handler(nil))
+}
+
+func TestLogTail_FiltersText(t *testing.T) {
+	ring, logger := newTestRing()
+	logger.Debug("cache miss", "key", "a")
+	logger.Info("request done", slogx.RequestIDKey, "r-1", "status", 200)
+	logger.Warn("slow query", "ms", 900)
+	logger.Error("request failed", slogx.RequestIDKey, "r-2")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		LogTailHandler(ring).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/log/tail"+query, nil))
+		return w
+	}
+	lines := func(w *httptest.ResponseRecorder) []string {
+		return strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	}
+
+	if got := lines(get("")); len(got) != 4 || !strings.HasSuffix(got[0], "\tDEBUG\tcache miss\tkey=a") {
+		t.Fatalf("all: %q", got)
+	}
+	if got := lines(get("?n=2")); len(got) != 2 || !strings.Contains(got[0], "slow query") {
+		t.Fatalf("n=2: %q", got)
+	}
+	if got := lines(get("?level=warn")); len(got) != 2 || !strings.Contains(got[1], "request failed") {
+		t.Fatalf("level=warn: %q", got)
+	}
+	if got := lines(get("?q=request")); len(got) != 2 {
+		t.Fatalf("q=request: %q", got)
+	}
+	if got := lines(get("?request_id=r-1")); len(got) != 1 || !strings.HasSuffix(got[0], "\tINFO\trequest done\trequest_id=r-1\tstatus=200") {
+		t.Fatalf("request_id: %q", got)
+	}
+	if w := get("?since=1h&level=error"); len(lines(w)) != 1 {
+		t.Fatalf("since: %q", w.Body.String())
+	}
+	if w := get("?since=2999-01-01T00:00:00Z"); w.Body.String() != "" {
+		t.Fatalf("future since: %q", w.Body.String())
+	}
+
+	for _, q := range []string{"?n=0", "?n=x", "?level=loud", "?since=yesterday"} {
+		if w := get(q); w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status=%d", q, w.Code)
+		}
+	}
+	w := httptest.NewRecorder()
+	LogTailHandler(ring).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/log/tail", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD" {
+		t.Fatalf("POST: status=%d allow=%q", w.Code, w.Header().Get("Allow"))
+	}
+}
+
+func TestLogTail_JSONAndMaxRecords(t *testing.T) {
+	ring, logger := newTestRing()
+	for i := 0; i < 5; i++ {
+		logger.Info("tick", "i", i)
+	}
+	w := httptest.NewRecorder()
+	LogTailHandler(ring, WithLogTailMaxRecords(3)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/log/tail?format=json&n=100", nil))
+	var resp logTailResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.OK || len(resp.Records) != 3 || resp.Records[0].Attrs["i"] != "2" || resp.Records[2].Seq != 5 || resp.Records[0].Level != "INFO" {
+		t.Fatalf("resp=%+v", resp)
+	}
+}
+
+func TestLogTail_Follow(t *testing.T) {
+	ring, logger := newTestRing()
+	logger.Info("before")
+	srv := httptest.NewServer(LogTailHandler(ring))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?follow=1&format=json&level=info", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/x-ndjson") {
+		t.Fatalf("content type %q", ct)
+	}
+
+	sc := bufio.NewScanner(resp.Body)
+	next := func() LogTailRecord {
+		t.Helper()
+		if !sc.Scan() {
+			t.Fatalf("stream ended: %v", sc.Err())
+		}
+		var rec LogTailRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			t.Fatalf("decode %q: %v", sc.Text(), err)
+		}
+		return rec
+	}
+	if rec := next(); rec.Message != "before" {
+		t.Fatalf("backlog=%+v", rec)
+	}
+	// The backlog is flushed after subscribing, so these are delivered live.
+	logger.Debug("filtered out")
+	logger.Info("after")
+	if rec := next(); rec.Message != "after" {
+		t.Fatalf("live=%+v", rec)
+	}
+}
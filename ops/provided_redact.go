@@ -0,0 +1,305 @@
+package ops
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// ProvidedRedacted replaces redacted values in /provided output.
+const ProvidedRedacted = "[REDACTED]"
+
+// DefaultProvidedRedactKeys are the key patterns redacted by ProvidedSnapshotHandler unless
+// overridden with WithProvidedSnapshotRedactKeys.
+var DefaultProvidedRedactKeys = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token*",
+	"*api_key*",
+	"*apikey*",
+	"*credential*",
+	"*private_key*",
+}
+
+// providedRedactor redacts JSON object members by key pattern and by `zkit:"redact"` struct tags.
+type providedRedactor struct {
+	patterns []string // lower-case path.Match patterns
+}
+
+func newProvidedRedactor(patterns []string) *providedRedactor {
+	rd := &providedRedactor{}
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			panic("ops: invalid provided redact pattern " + fmt.Sprintf("%q", p) + ": " + err.Error())
+		}
+		rd.patterns = append(rd.patterns, p)
+	}
+	return rd
+}
+
+func (rd *providedRedactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range rd.patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redact rewrites raw (the JSON encoding of v) with sensitive members replaced by
+// ProvidedRedacted. raw is returned unchanged if nothing was redacted, so the output stays
+// byte-identical to json.Marshal.
+func (rd *providedRedactor) redact(raw json.RawMessage, v any) json.RawMessage {
+	if len(raw) == 0 || (raw[0] != '{' && raw[0] != '[') {
+		return raw
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	n, err := decodeJSONNode(dec)
+	if err != nil {
+		return raw
+	}
+	if !rd.node(n, reflect.ValueOf(v)) {
+		return raw
+	}
+	var buf bytes.Buffer
+	n.encode(&buf)
+	return json.RawMessage(buf.Bytes())
+}
+
+var redactedNode = func() *jsonNode {
+	b, _ := json.Marshal(ProvidedRedacted)
+	return &jsonNode{scalar: b}
+}()
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// node redacts n in place. v is the Go value n was encoded from; it is used to find tagged
+// struct fields and may be invalid when the correspondence is unknown (custom marshalers).
+func (rd *providedRedactor) node(n *jsonNode, v reflect.Value) (changed bool) {
+	v = derefValue(v)
+	if v.IsValid() && (v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) ||
+		reflect.PointerTo(v.Type()).Implements(jsonMarshalerType)) {
+		v = reflect.Value{}
+	}
+	switch {
+	case n.isObj:
+		fields := objectFields(v)
+		for i := range n.obj {
+			m := &n.obj[i]
+			f := fields[m.key]
+			if f.redact || rd.matchKey(m.key) {
+				m.val = redactedNode
+				changed = true
+				continue
+			}
+			if rd.node(m.val, f.v) {
+				changed = true
+			}
+		}
+	case n.isArr:
+		ok := v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Len() == len(n.arr)
+		for i, e := range n.arr {
+			var ev reflect.Value
+			if ok {
+				ev = v.Index(i)
+			}
+			if rd.node(e, ev) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+type redactField struct {
+	v      reflect.Value
+	redact bool
+}
+
+// objectFields maps the JSON member names of a struct or map value to their Go values.
+func objectFields(v reflect.Value) map[string]redactField {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]redactField)
+		collectStructFields(v, out)
+		return out
+	case reflect.Map:
+		out := make(map[string]redactField, v.Len())
+		it := v.MapRange()
+		for it.Next() {
+			k := it.Key()
+			var name string
+			if k.Kind() == reflect.String {
+				name = k.String()
+			} else {
+				name = fmt.Sprint(k.Interface())
+			}
+			out[name] = redactField{v: it.Value()}
+		}
+		return out
+	}
+	return nil
+}
+
+// collectStructFields follows encoding/json naming: json tag names, "-" skipped, unexported
+// fields skipped, untagged embedded structs promoted (outer fields win).
+func collectStructFields(v reflect.Value, out map[string]redactField) {
+	t := v.Type()
+	var embedded []reflect.Value
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if fv := derefValue(v.Field(i)); fv.IsValid() {
+					embedded = append(embedded, fv)
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		out[name] = redactField{v: v.Field(i), redact: hasRedactTag(sf.Tag)}
+	}
+	for _, ev := range embedded {
+		inner := make(map[string]redactField)
+		collectStructFields(ev, inner)
+		for k, f := range inner {
+			if _, ok := out[k]; !ok {
+				out[k] = f
+			}
+		}
+	}
+}
+
+func hasRedactTag(tag reflect.StructTag) bool {
+	for _, opt := range strings.Split(tag.Get("zkit"), ",") {
+		if strings.TrimSpace(opt) == "redact" {
+			return true
+		}
+	}
+	return false
+}
+
+func derefValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// jsonNode is an order-preserving JSON tree (encoding/json maps would sort object keys).
+type jsonNode struct {
+	isObj  bool
+	obj    []jsonMember
+	isArr  bool
+	arr    []*jsonNode
+	scalar json.RawMessage
+}
+
+type jsonMember struct {
+	key string
+	val *jsonNode
+}
+
+func decodeJSONNode(dec *json.Decoder) (*jsonNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		n := &jsonNode{isObj: true}
+		for dec.More() {
+			kt, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := kt.(string)
+			val, err := decodeJSONNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.obj = append(n.obj, jsonMember{key: key, val: val})
+		}
+		_, err := dec.Token() // '}'
+		return n, err
+	case json.Delim('['):
+		n := &jsonNode{isArr: true}
+		for dec.More() {
+			e, err := decodeJSONNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.arr = append(n.arr, e)
+		}
+		_, err := dec.Token() // ']'
+		return n, err
+	default:
+		b, err := json.Marshal(tok)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonNode{scalar: b}, nil
+	}
+}
+
+func (n *jsonNode) encode(buf *bytes.Buffer) {
+	switch {
+	case n.isObj:
+		buf.WriteByte('{')
+		for i, m := range n.obj {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			k, _ := json.Marshal(m.key)
+			buf.Write(k)
+			buf.WriteByte(':')
+			m.val.encode(buf)
+		}
+		buf.WriteByte('}')
+	case n.isArr:
+		buf.WriteByte('[')
+		for i, e := range n.arr {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			e.encode(buf)
+		}
+		buf.WriteByte(']')
+	default:
+		buf.Write(n.scalar)
+	}
+}
@@ -2,17 +2,29 @@ package ops
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
+// ProvidedFunc is a lazily evaluated /provided item: it is called on every request and its
+// result is rendered like a static value.
+//
+// ctx carries the per-item timeout (WithProvidedSnapshotTimeout); providers should honor it.
+// A provider that ignores ctx is abandoned (not waited for) once the timeout expires.
+type ProvidedFunc func(ctx context.Context) (any, error)
+
 type providedSnapshotConfig struct {
-	format   Format
-	maxBytes int
+	format     Format
+	maxBytes   int
+	timeout    time.Duration
+	redactKeys []string
 }
 
 // ProvidedSnapshotOption configures ProvidedSnapshotHandler.
@@ -42,10 +54,35 @@ func WithProvidedSnapshotMaxBytes(n int) ProvidedSnapshotOption {
 	return func(c *providedSnapshotConfig) { c.maxBytes = n }
 }
 
+// WithProvidedSnapshotTimeout bounds each ProvidedFunc evaluation.
+//
+// 0 means default (2s); < 0 disables the timeout.
+func WithProvidedSnapshotTimeout(d time.Duration) ProvidedSnapshotOption {
+	return func(c *providedSnapshotConfig) {
+		if d != 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithProvidedSnapshotRedactKeys replaces the key patterns whose values are redacted
+// (DefaultProvidedRedactKeys). Patterns use path.Match syntax and are case-insensitive,
+// e.g. "*password*". They apply to item names, map keys and JSON field names at any depth.
+//
+// Calling it with no patterns disables key-based redaction; `zkit:"redact"` struct tags
+// always apply. Invalid patterns panic.
+func WithProvidedSnapshotRedactKeys(patterns ...string) ProvidedSnapshotOption {
+	return func(c *providedSnapshotConfig) {
+		c.redactKeys = append([]string{}, patterns...)
+	}
+}
+
 func applyProvidedSnapshotOptions(opts []ProvidedSnapshotOption) providedSnapshotConfig {
 	cfg := providedSnapshotConfig{
-		format:   FormatText,
-		maxBytes: 4 << 20, // 4 MiB (conservative default; can be increased or disabled).
+		format:     FormatText,
+		maxBytes:   4 << 20, // 4 MiB (conservative default; can be increased or disabled).
+		timeout:    2 * time.Second,
+		redactKeys: DefaultProvidedRedactKeys,
 	}
 	for _, opt := range opts {
 		if opt != nil {
@@ -64,7 +101,8 @@ func applyProvidedSnapshotOptions(opts []ProvidedSnapshotOption) providedSnapsho
 // It does not perform authn/authz decisions; protect it with your own middleware.
 //
 // Input:
-//   - items: name -> any (static values; may include pointers / references)
+//   - items: name -> any (static values; may include pointers / references), or a
+//     ProvidedFunc / func(context.Context) (any, error) evaluated on every request
 //   - items is snapshotted at handler construction time: the map is copied and
 //     key ordering is fixed. Mutating the original map after creating the handler
 //     does not affect the output.
@@ -74,14 +112,23 @@ func applyProvidedSnapshotOptions(opts []ProvidedSnapshotOption) providedSnapsho
 //   - By default, it renders text. You can change the default with options.
 //   - The response format can be overridden per request by URL query (?format=json|text).
 //   - Best-effort safety: per-item marshal errors/panics do not crash the handler; they
-//     are reported in the response (partial success).
+//     are reported in the response (partial success). The same applies to providers, which
+//     run concurrently, each bounded by the timeout.
+//
+// Redaction (before JSON/text rendering):
+//   - Struct fields tagged `zkit:"redact"` are replaced by ProvidedRedacted.
+//   - Items, map keys and JSON field names matching a redact pattern (default
+//     DefaultProvidedRedactKeys, e.g. "*password*") are replaced by ProvidedRedacted.
+//   - Values with custom JSON/text marshalers are only redacted by key pattern.
 //
 // Notes on "live" references:
 //   - Passing pointers/maps/slices may appear "live", but can introduce data races if the
 //     underlying object is mutated concurrently.
-//   - Prefer copy-on-write snapshots (e.g. via *atomic.Value) if you need runtime updates.
+//   - Prefer a ProvidedFunc that takes a consistent copy under the owner's lock, or
+//     copy-on-write snapshots (e.g. via *atomic.Value).
 func ProvidedSnapshotHandler(items map[string]any, opts ...ProvidedSnapshotOption) http.Handler {
 	cfg := applyProvidedSnapshotOptions(opts)
+	rd := newProvidedRedactor(cfg.redactKeys)
 
 	// Snapshot names and values at handler construction time to avoid
 	// concurrent-map hazards and keep output stable.
@@ -91,6 +138,9 @@ func ProvidedSnapshotHandler(items map[string]any, opts ...ProvidedSnapshotOptio
 		if name == "" {
 			panic("ops: provided snapshot item has empty name")
 		}
+		if fn, ok := v.(func(context.Context) (any, error)); ok {
+			v = ProvidedFunc(fn)
+		}
 		names = append(names, name)
 		vals[name] = v
 	}
@@ -110,7 +160,7 @@ func ProvidedSnapshotHandler(items map[string]any, opts ...ProvidedSnapshotOptio
 			return
 		}
 
-		snap, errs := buildProvidedSnapshot(names, vals)
+		snap, errs := buildProvidedSnapshot(r.Context(), names, vals, cfg.timeout, rd)
 		code := http.StatusOK
 		ok := len(errs) == 0
 		msg := ""
@@ -140,19 +190,50 @@ type ProvidedSnapshotError struct {
 	Name     string `json:"name"`
 	Error    string `json:"error"`
 	Panicked bool   `json:"panicked,omitempty"`
+	TimedOut bool   `json:"timed_out,omitempty"`
 }
 
-func buildProvidedSnapshot(names []string, vals map[string]any) (map[string]json.RawMessage, []ProvidedSnapshotError) {
+func buildProvidedSnapshot(ctx context.Context, names []string, vals map[string]any, timeout time.Duration, rd *providedRedactor) (map[string]json.RawMessage, []ProvidedSnapshotError) {
 	out := make(map[string]json.RawMessage, len(names))
 	var errs []ProvidedSnapshotError
+
+	// Evaluate providers concurrently; a slow one only costs its own timeout.
+	results := make(map[string]*providedResult)
+	var wg sync.WaitGroup
+	for _, name := range names {
+		fn, ok := vals[name].(ProvidedFunc)
+		if !ok || rd.matchKey(name) {
+			continue
+		}
+		res := &providedResult{}
+		results[name] = res
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			*res = evalProvidedFunc(ctx, fn, timeout)
+		}()
+	}
+	wg.Wait()
+
 	for _, name := range names {
+		if rd.matchKey(name) {
+			out[name] = redactedNode.scalar
+			continue
+		}
 		v, ok := vals[name]
 		if !ok {
 			// Should not happen (names are derived from vals), but keep stable behavior.
 			errs = append(errs, ProvidedSnapshotError{Name: name, Error: "missing value"})
 			continue
 		}
-		raw, perr, panicked := safeMarshalSnapshotValue(v)
+		if res := results[name]; res != nil {
+			if res.err != "" {
+				errs = append(errs, ProvidedSnapshotError{Name: name, Error: res.err, Panicked: res.panicked, TimedOut: res.timedOut})
+				continue
+			}
+			v = res.v
+		}
+		raw, perr, panicked := safeMarshalSnapshotValue(v, rd)
 		if perr != "" {
 			errs = append(errs, ProvidedSnapshotError{Name: name, Error: perr, Panicked: panicked})
 			continue
@@ -165,7 +246,55 @@ func buildProvidedSnapshot(names []string, vals map[string]any) (map[string]json
 	return out, errs
 }
 
-func safeMarshalSnapshotValue(v any) (raw json.RawMessage, errMsg string, panicked bool) {
+type providedResult struct {
+	v        any
+	err      string
+	panicked bool
+	timedOut bool
+}
+
+func evalProvidedFunc(parent context.Context, fn ProvidedFunc, timeout time.Duration) providedResult {
+	if fn == nil {
+		return providedResult{err: "nil provider"}
+	}
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, timeout)
+	}
+	defer cancel()
+
+	done := make(chan providedResult, 1) // buffered: an abandoned provider must not block
+	go func() {
+		var res providedResult
+		defer func() {
+			if p := recover(); p != nil {
+				res = providedResult{err: fmt.Sprintf("panic: %v", p), panicked: true}
+			}
+			done <- res
+		}()
+		v, err := fn(ctx)
+		if err != nil {
+			res.err = err.Error()
+			return
+		}
+		res.v = v
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != "" && ctx.Err() == context.DeadlineExceeded {
+			res.timedOut = true
+		}
+		return res
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return providedResult{err: "timeout after " + timeout.String(), timedOut: true}
+		}
+		return providedResult{err: ctx.Err().Error()}
+	}
+}
+
+func safeMarshalSnapshotValue(v any, rd *providedRedactor) (raw json.RawMessage, errMsg string, panicked bool) {
 	defer func() {
 		if p := recover(); p != nil {
 			panicked = true
@@ -191,7 +320,7 @@ func safeMarshalSnapshotValue(v any) (raw json.RawMessage, errMsg string, panick
 	if err != nil {
 		return nil, err.Error(), false
 	}
-	return json.RawMessage(b), "", false
+	return rd.redact(json.RawMessage(b), v), "", false
 }
 
 func writeProvidedSnapshot(w http.ResponseWriter, r *http.Request, f Format, maxBytes int, code int, resp providedSnapshotResponse) {
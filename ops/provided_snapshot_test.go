@@ -1,12 +1,15 @@
 package ops
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestProvidedSnapshot_Text_OK_SortedAndStringUnquoted(t *testing.T) {
@@ -340,3 +343,114 @@ func TestProvidedSnapshot_JSON_AtomicValue_ByValue_Forbidden(t *testing.T) {
 		t.Fatalf("error=%q, want mention atomic.Value", got.Errors[0].Error)
 	}
 }
+
+func serveProvidedJSON(t *testing.T, h http.Handler) (int, providedSnapshotResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/provided_snapshot?format=json", nil))
+	var got providedSnapshotResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v (body=%q)", err, w.Body.String())
+	}
+	return w.Code, got
+}
+
+func TestProvidedSnapshot_Providers(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	h := ProvidedSnapshotHandler(map[string]any{
+		"live": ProvidedFunc(func(ctx context.Context) (any, error) {
+			return calls.Add(1), nil
+		}),
+		"plain": func(ctx context.Context) (any, error) { return "x", nil },
+		"err":   ProvidedFunc(func(ctx context.Context) (any, error) { return nil, errors.New("boom") }),
+		"panic": ProvidedFunc(func(ctx context.Context) (any, error) { panic("oops") }),
+		"slow": ProvidedFunc(func(ctx context.Context) (any, error) {
+			<-release // ignores ctx on purpose
+			return 1, nil
+		}),
+	}, WithProvidedSnapshotTimeout(20*time.Millisecond))
+
+	code, got := serveProvidedJSON(t, h)
+	if code != http.StatusInternalServerError {
+		t.Fatalf("status=%d", code)
+	}
+	if string(got.Snapshots["live"]) != "1" || string(got.Snapshots["plain"]) != `"x"` {
+		t.Fatalf("snapshots=%v", got.Snapshots)
+	}
+	byName := map[string]ProvidedSnapshotError{}
+	for _, e := range got.Errors {
+		byName[e.Name] = e
+	}
+	if byName["err"].Error != "boom" || !byName["panic"].Panicked || !byName["slow"].TimedOut || len(byName) != 3 {
+		t.Fatalf("errors=%+v", got.Errors)
+	}
+
+	// Providers are evaluated per request.
+	if _, got := serveProvidedJSON(t, h); string(got.Snapshots["live"]) != "2" {
+		t.Fatalf("live=%s, want 2", got.Snapshots["live"])
+	}
+}
+
+type redactInner struct {
+	Host     string
+	Password string
+}
+
+type redactEmbedded struct {
+	Region string `json:"region"`
+	Key    string `json:"key" zkit:"redact"`
+}
+
+type redactConfig struct {
+	redactEmbedded
+	Name    string            `json:"name"`
+	DSN     string            `json:"dsn" zkit:"redact"`
+	Port    int               `json:"port" zkit:"redact"`
+	Backups []redactInner     `json:"backups"`
+	Labels  map[string]string `json:"labels"`
+	Next    *redactConfig     `json:"next,omitempty"`
+}
+
+func TestProvidedSnapshot_Redaction(t *testing.T) {
+	cfg := redactConfig{
+		redactEmbedded: redactEmbedded{Region: "eu", Key: "k"},
+		Name:           "db",
+		DSN:            "postgres://u:p@h",
+		Port:           5432,
+		Backups:        []redactInner{{Host: "b1", Password: "pw"}},
+		Labels:         map[string]string{"team": "core", "API_Token": "t"},
+		Next:           &redactConfig{Name: "replica", DSN: "x"},
+	}
+	h := ProvidedSnapshotHandler(map[string]any{
+		"config":      cfg,
+		"provided":    ProvidedFunc(func(ctx context.Context) (any, error) { return &cfg, nil }),
+		"db_password": "hunter2",
+	})
+
+	code, got := serveProvidedJSON(t, h)
+	if code != http.StatusOK {
+		t.Fatalf("status=%d errors=%+v", code, got.Errors)
+	}
+	want := `{"region":"eu","key":"[REDACTED]","name":"db","dsn":"[REDACTED]","port":"[REDACTED]",` +
+		`"backups":[{"Host":"b1","Password":"[REDACTED]"}],"labels":{"API_Token":"[REDACTED]","team":"core"},` +
+		`"next":{"region":"","key":"[REDACTED]","name":"replica","dsn":"[REDACTED]","port":"[REDACTED]","backups":null,"labels":null}}`
+	if s := string(got.Snapshots["config"]); s != want {
+		t.Fatalf("config=\n%s\nwant\n%s", s, want)
+	}
+	if s := string(got.Snapshots["provided"]); s != want {
+		t.Fatalf("provided=\n%s\nwant\n%s", s, want)
+	}
+	if s := string(got.Snapshots["db_password"]); s != `"[REDACTED]"` {
+		t.Fatalf("db_password=%s", s)
+	}
+
+	// Custom patterns replace the defaults; tags still apply.
+	h = ProvidedSnapshotHandler(map[string]any{"config": cfg}, WithProvidedSnapshotRedactKeys("LABELS"))
+	_, got = serveProvidedJSON(t, h)
+	s := string(got.Snapshots["config"])
+	if !strings.Contains(s, `"labels":"[REDACTED]"`) || !strings.Contains(s, `"Password":"pw"`) || !strings.Contains(s, `"dsn":"[REDACTED]"`) {
+		t.Fatalf("config=%s", s)
+	}
+}
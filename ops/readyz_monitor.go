@@ -0,0 +1,289 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrReadyzMonitorStarted is returned by ReadyzMonitor.Start when called more than once.
+var ErrReadyzMonitorStarted = errors.New("ops: readyz monitor already started")
+
+type readyzMonitorConfig struct {
+	interval time.Duration
+	maxAge   time.Duration
+}
+
+// ReadyzMonitorOption configures NewReadyzMonitor.
+type ReadyzMonitorOption func(*readyzMonitorConfig)
+
+// WithReadyzMonitorInterval sets the default refresh interval for checks without their own
+// ReadyCheck.Interval.
+//
+// <= 0 means default (10s).
+func WithReadyzMonitorInterval(d time.Duration) ReadyzMonitorOption {
+	return func(c *readyzMonitorConfig) { c.interval = d }
+}
+
+// WithReadyzMonitorMaxAge sets how old a cached result may get before it is reported as failing
+// (error "stale ..."), e.g. because the check hangs: without a ReadyCheck.Timeout a hung check
+// never returns, and its last result would otherwise be served forever.
+//
+// <= 0 means default (3 times the check's interval).
+func WithReadyzMonitorMaxAge(d time.Duration) ReadyzMonitorOption {
+	return func(c *readyzMonitorConfig) { c.maxAge = d }
+}
+
+func applyReadyzMonitorOptions(opts []ReadyzMonitorOption) readyzMonitorConfig {
+	cfg := readyzMonitorConfig{interval: 10 * time.Second}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.interval <= 0 {
+		cfg.interval = 10 * time.Second
+	}
+	return cfg
+}
+
+// ReadyzMonitor runs readiness checks in the background, each on its own interval, and
+// serves the cached results.
+//
+// Probes never run checks themselves, so frequent probes do not hammer dependencies and a
+// slow check cannot make the probe time out. Until a check completes its first run it is
+// reported as failing with error "pending".
+//
+// A result older than the max age (WithReadyzMonitorMaxAge) is reported as failing, so a check
+// that hangs does not keep serving its last result.
+//
+// Each check also tracks flapping: consecutive failures, the last OK <-> failing transition
+// and the number of transitions.
+//
+// Lifecycle: Start launches the background loops; Shutdown stops them. Both match the
+// func(context.Context) error hook shape (e.g. ServiceSpec.OnStart / OnShutdown).
+type ReadyzMonitor struct {
+	checks    []ReadyCheck
+	intervals []time.Duration
+	maxAges   []time.Duration
+
+	mu      sync.Mutex
+	states  []readyCheckState
+	started bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type readyCheckState struct {
+	last           ReadyCheckResult
+	checkedAt      time.Time
+	consecFailures int
+	lastTransition time.Time
+	transitions    int
+}
+
+// NewReadyzMonitor creates a monitor for checks. It does not run anything until Start.
+//
+// Invalid checks (empty Name, nil Func) are assembly errors and will panic.
+func NewReadyzMonitor(checks []ReadyCheck, opts ...ReadyzMonitorOption) *ReadyzMonitor {
+	validateReadyChecks(checks)
+	cfg := applyReadyzMonitorOptions(opts)
+	m := &ReadyzMonitor{
+		checks:    append([]ReadyCheck(nil), checks...),
+		intervals: make([]time.Duration, len(checks)),
+		maxAges:   make([]time.Duration, len(checks)),
+		states:    make([]readyCheckState, len(checks)),
+	}
+	for i, c := range m.checks {
+		m.intervals[i] = c.Interval
+		if m.intervals[i] <= 0 {
+			m.intervals[i] = cfg.interval
+		}
+		m.maxAges[i] = cfg.maxAge
+		if m.maxAges[i] <= 0 {
+			m.maxAges[i] = 3 * m.intervals[i]
+		}
+	}
+	return m
+}
+
+// Start runs every check once immediately and then on its interval, until ctx is done or
+// Shutdown is called. It does not wait for the first results.
+func (m *ReadyzMonitor) Start(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	m.mu.Lock()
+	if m.started {
+		m.mu.Unlock()
+		return ErrReadyzMonitorStarted
+	}
+	m.started = true
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.mu.Unlock()
+
+	for i := range m.checks {
+		m.wg.Add(1)
+		go m.loop(ctx, i)
+	}
+	return nil
+}
+
+// Shutdown stops the background loops and waits for running checks to return (or ctx).
+func (m *ReadyzMonitor) Shutdown(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	m.mu.Lock()
+	cancel := m.cancel
+	m.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *ReadyzMonitor) loop(ctx context.Context, i int) {
+	defer m.wg.Done()
+	t := time.NewTicker(m.intervals[i])
+	defer t.Stop()
+	for {
+		m.runCheck(ctx, i)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// RunNow runs every check once, synchronously, and updates the cache.
+// It is useful before serving traffic and in tests.
+func (m *ReadyzMonitor) RunNow(ctx context.Context) {
+	if m == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for i := range m.checks {
+		m.runCheck(ctx, i)
+	}
+}
+
+func (m *ReadyzMonitor) runCheck(ctx context.Context, i int) {
+	cr := runOneCheck(ctx, m.checks[i])
+	if ctx.Err() != nil && !cr.OK {
+		// Stopping: do not record the cancellation as a failure.
+		return
+	}
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := &m.states[i]
+	if !st.checkedAt.IsZero() && st.last.OK != cr.OK {
+		st.transitions++
+		st.lastTransition = now
+	}
+	if cr.OK {
+		st.consecFailures = 0
+	} else {
+		st.consecFailures++
+	}
+	st.last = cr
+	st.checkedAt = now
+}
+
+// Report returns the cached readiness report.
+func (m *ReadyzMonitor) Report() ReadyzReport {
+	if m == nil {
+		return ReadyzReport{OK: true, Status: ReadyStatusOK, Cached: true}
+	}
+	now := time.Now()
+	rep := ReadyzReport{
+		Cached: true,
+		Checks: make([]ReadyCheckResult, 0, len(m.checks)),
+	}
+
+	m.mu.Lock()
+	for i, c := range m.checks {
+		st := m.states[i]
+		cr := st.last
+		if st.checkedAt.IsZero() {
+			cr = ReadyCheckResult{Name: c.Name, Error: "pending"}
+		} else {
+			cr.CheckedAt = st.checkedAt
+			cr.Age = now.Sub(st.checkedAt)
+			cr.ConsecutiveFailures = st.consecFailures
+			cr.LastTransition = st.lastTransition
+			cr.Transitions = st.transitions
+			if cr.Age > m.maxAges[i] {
+				cr.OK = false
+				cr.Error = "stale: no result for " + cr.Age.Round(time.Millisecond).String()
+			}
+			if cr.Age > rep.Age {
+				rep.Age = cr.Age
+			}
+		}
+		cr.NonCritical = c.NonCritical
+		rep.Checks = append(rep.Checks, cr)
+	}
+	m.mu.Unlock()
+
+	rep.finalize()
+	return rep
+}
+
+// ReadyzMonitorHandler returns a readiness handler that serves m's cached report.
+//
+// Status codes and output follow ReadyzHandler; the report is marked cached and carries
+// its age (JSON "age", text "age" line and the Age header in whole seconds).
+func ReadyzMonitorHandler(m *ReadyzMonitor, opts ...HealthOption) http.Handler {
+	if m == nil {
+		panic("ops: nil ReadyzMonitor")
+	}
+	cfg := applyHealthOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeReady(w, r, format, http.StatusMethodNotAllowed, ReadyzReport{
+				OK:     false,
+				Status: ReadyStatusFail,
+				Checks: []ReadyCheckResult{
+					{Name: "method", OK: false, Error: "method not allowed"},
+				},
+			})
+			return
+		}
+
+		rep := m.Report()
+		w.Header().Set("Age", strconv.Itoa(int(rep.Age/time.Second)))
+		writeReady(w, r, format, readyStatusCode(rep), rep)
+	})
+}
@@ -0,0 +1,127 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadyzMonitor_CachedAndFlapTracking(t *testing.T) {
+	var calls atomic.Int64
+	var failing atomic.Bool
+	m := NewReadyzMonitor([]ReadyCheck{
+		{Name: "db", Func: func(ctx context.Context) error {
+			calls.Add(1)
+			if failing.Load() {
+				return errors.New("down")
+			}
+			return nil
+		}},
+	})
+	h := ReadyzMonitorHandler(m)
+
+	// Not checked yet: pending => not ready.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "fail db: pending") {
+		t.Fatalf("status=%d body=%q", w.Code, w.Body.String())
+	}
+
+	m.RunNow(context.Background())
+	for i := 0; i < 5; i++ {
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/readyz", nil))
+	}
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "ok\nage ") || w.Header().Get("Age") == "" {
+		t.Fatalf("status=%d body=%q age=%q", w.Code, w.Body.String(), w.Header().Get("Age"))
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("probes must not run checks: calls=%d, want 1", n)
+	}
+
+	failing.Store(true)
+	m.RunNow(context.Background())
+	m.RunNow(context.Background())
+	rep := m.Report()
+	c := rep.Checks[0]
+	if rep.OK || rep.Status != ReadyStatusFail || !rep.Cached {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+	if c.ConsecutiveFailures != 2 || c.Transitions != 1 || c.LastTransition.IsZero() || c.CheckedAt.IsZero() {
+		t.Fatalf("unexpected flap tracking: %+v", c)
+	}
+
+	failing.Store(false)
+	m.RunNow(context.Background())
+	if c := m.Report().Checks[0]; !c.OK || c.ConsecutiveFailures != 0 || c.Transitions != 2 {
+		t.Fatalf("unexpected recovery: %+v", c)
+	}
+}
+
+func TestReadyzMonitor_BackgroundRefresh(t *testing.T) {
+	var calls atomic.Int64
+	m := NewReadyzMonitor([]ReadyCheck{
+		{Name: "fast", Interval: 5 * time.Millisecond, Func: func(ctx context.Context) error { calls.Add(1); return nil }},
+		{Name: "optional", NonCritical: true, Func: func(ctx context.Context) error { return errors.New("down") }},
+	}, WithReadyzMonitorInterval(time.Hour))
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := m.Start(context.Background()); !errors.Is(err, ErrReadyzMonitorStarted) {
+		t.Fatalf("Start(again) err=%v, want %v", err, ErrReadyzMonitorStarted)
+	}
+	for i := 0; calls.Load() < 3; i++ {
+		if i > 200 {
+			t.Fatalf("check did not refresh: calls=%d", calls.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	ReadyzMonitorHandler(m).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/readyz?format=json", nil))
+	var rep ReadyzReport
+	if err := json.Unmarshal(w.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if w.Code != http.StatusOK || !rep.OK || rep.Status != ReadyStatusDegraded || !rep.Cached {
+		t.Fatalf("status=%d report=%+v", w.Code, rep)
+	}
+}
+
+func TestReadyzMonitor_HungCheckGoesStale(t *testing.T) {
+	var calls atomic.Int64
+	m := NewReadyzMonitor([]ReadyCheck{
+		{Name: "hangs", Interval: 10 * time.Millisecond, Func: func(ctx context.Context) error {
+			if calls.Add(1) > 1 {
+				<-ctx.Done() // no Timeout: hangs until shutdown
+			}
+			return nil
+		}},
+	}, WithReadyzMonitorMaxAge(30*time.Millisecond))
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = m.Shutdown(context.Background()) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		rep := m.Report()
+		c := rep.Checks[0]
+		if !rep.OK && strings.HasPrefix(c.Error, "stale: ") && c.Age > 30*time.Millisecond {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("hung check still served as %+v", c)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
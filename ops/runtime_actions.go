@@ -0,0 +1,108 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RuntimeActionResult is the outcome of a runtime write action (GC / FreeOSMemory).
+type RuntimeActionResult struct {
+	Action string `json:"action"` // "gc" | "free-os-memory"
+	// Duration is encoded as an integer number of nanoseconds in JSON.
+	Duration time.Duration      `json:"duration"`
+	Before   RuntimeMemSnapshot `json:"before"`
+	After    RuntimeMemSnapshot `json:"after"`
+}
+
+type runtimeActionResponse struct {
+	OK     bool                 `json:"ok"`
+	Error  string               `json:"error,omitempty"`
+	Result *RuntimeActionResult `json:"result,omitempty"`
+}
+
+// RuntimeGCHandler returns a handler that forces a garbage collection (runtime.GC).
+//
+// runtime.GC blocks the caller until the collection completes; it is a write action
+// and should be protected by a write guard.
+//
+// Behavior:
+//   - POST only; other methods return 405.
+//   - The response reports memory stats before/after and the duration.
+//   - The response format can be overridden per request by URL query (?format=json|text).
+func RuntimeGCHandler(opts ...RuntimeOption) http.Handler {
+	return runtimeActionHandler("gc", runtime.GC, opts)
+}
+
+// RuntimeFreeOSMemoryHandler returns a handler that forces a garbage collection and
+// returns as much memory to the operating system as possible (debug.FreeOSMemory).
+//
+// Behavior is the same as RuntimeGCHandler.
+func RuntimeFreeOSMemoryHandler(opts ...RuntimeOption) http.Handler {
+	return runtimeActionHandler("free-os-memory", debug.FreeOSMemory, opts)
+}
+
+func runtimeActionHandler(action string, fn func(), opts []RuntimeOption) http.Handler {
+	cfg := applyRuntimeOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeRuntimeAction(w, format, http.StatusMethodNotAllowed, runtimeActionResponse{
+				OK:    false,
+				Error: "method not allowed",
+			})
+			return
+		}
+
+		res := RuntimeActionResult{Action: action, Before: Runtime().Mem}
+		start := time.Now()
+		fn()
+		res.Duration = time.Since(start)
+		res.After = Runtime().Mem
+		writeRuntimeAction(w, format, http.StatusOK, runtimeActionResponse{OK: true, Result: &res})
+	})
+}
+
+func writeRuntimeAction(w http.ResponseWriter, f Format, code int, resp runtimeActionResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if !resp.OK || resp.Result == nil {
+			writeTextError(w, resp.Error)
+			return
+		}
+		_, _ = w.Write([]byte(renderRuntimeActionText(*resp.Result)))
+	}
+}
+
+func renderRuntimeActionText(res RuntimeActionResult) string {
+	// Format:
+	//   action\t<name>\t<duration>\n
+	//   mem\t<key>\t<before>\t<after>\n
+	var b strings.Builder
+	b.Grow(256)
+	b.WriteString("action\t" + res.Action + "\t" + res.Duration.String() + "\n")
+	mem := func(key string, before, after uint64) {
+		b.WriteString("mem\t" + key + "\t" + strconv.FormatUint(before, 10) + "\t" + strconv.FormatUint(after, 10) + "\n")
+	}
+	mem("heap_alloc_bytes", res.Before.HeapAllocBytes, res.After.HeapAllocBytes)
+	mem("heap_sys_bytes", res.Before.HeapSysBytes, res.After.HeapSysBytes)
+	mem("heap_idle_bytes", res.Before.HeapIdleBytes, res.After.HeapIdleBytes)
+	mem("heap_released_bytes", res.Before.HeapReleasedBytes, res.After.HeapReleasedBytes)
+	mem("sys_bytes", res.Before.SysBytes, res.After.SysBytes)
+	return b.String()
+}
@@ -152,3 +152,35 @@ func TestRuntime_InvalidFormatFallsBackToText(t *testing.T) {
 		t.Fatalf("Content-Type=%q, want text/plain", ct)
 	}
 }
+
+func TestRuntimeActions(t *testing.T) {
+	for _, tc := range []struct {
+		h      http.Handler
+		action string
+	}{
+		{RuntimeGCHandler(), "gc"},
+		{RuntimeFreeOSMemoryHandler(), "free-os-memory"},
+	} {
+		w := httptest.NewRecorder()
+		tc.h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/x", nil))
+		if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "POST" {
+			t.Fatalf("%s: status=%d allow=%q, want 405/POST", tc.action, w.Code, w.Header().Get("Allow"))
+		}
+
+		w = httptest.NewRecorder()
+		tc.h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/x", nil))
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "action\t"+tc.action+"\t") {
+			t.Fatalf("%s: status=%d body=%q", tc.action, w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), "mem\theap_alloc_bytes\t") {
+			t.Fatalf("%s: body=%q, want mem lines", tc.action, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		tc.h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/x?format=json", nil))
+		var resp runtimeActionResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.OK || resp.Result == nil || resp.Result.Action != tc.action {
+			t.Fatalf("%s: unexpected json %q (%v)", tc.action, w.Body.String(), err)
+		}
+	}
+}
@@ -253,9 +253,11 @@ func TuningLookupHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
 //
 // Input:
 //   - POST only
-//   - URL query: ?key=<tuning key>&value=<string representation>
+//   - URL query: ?key=<tuning key>&value=<string representation>[&ttl=<duration>]
 //
 // value can be empty string if the underlying variable type allows it.
+// With ttl (e.g. "15m"), the value is temporary and reverts when it expires
+// (tuning.Tuning.SetFromStringWithTTL).
 func TuningSetHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
 	if t == nil {
 		panic("ops: nil tuning.Tuning")
@@ -307,6 +309,19 @@ func TuningSetHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
 			return
 		}
 
+		var ttl time.Duration
+		if raw, hasTTL := getQueryRaw(r, "ttl"); hasTTL {
+			d, err := time.ParseDuration(raw)
+			if err != nil || d <= 0 {
+				writeTuningWrite(w, r, format, http.StatusBadRequest, tuningWriteResponse{
+					OK:    false,
+					Error: "invalid ttl (want a duration, e.g. 15m)",
+				})
+				return
+			}
+			ttl = d
+		}
+
 		old, found := TuningLookup(t, key)
 		if !found {
 			writeTuningWrite(w, r, format, http.StatusNotFound, tuningWriteResponse{
@@ -316,7 +331,13 @@ func TuningSetHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
 			return
 		}
 
-		if err := t.SetFromString(key, value); err != nil {
+		var err error
+		if ttl > 0 {
+			err = t.SetFromStringWithTTLContext(tuningWriteContext(r), key, value, ttl)
+		} else {
+			err = t.SetFromStringContext(tuningWriteContext(r), key, value)
+		}
+		if err != nil {
 			code := mapTuningWriteErrorToStatus(err)
 			errMsg := err.Error()
 			if isRedactedItem(old) {
@@ -397,7 +418,7 @@ func TuningResetToDefaultHandler(t *tuning.Tuning, opts ...TuningOption) http.Ha
 			return
 		}
 
-		if err := t.ResetToDefault(key); err != nil {
+		if err := t.ResetToDefaultContext(tuningWriteContext(r), key); err != nil {
 			code := mapTuningWriteErrorToStatus(err)
 			errMsg := err.Error()
 			if isRedactedItem(old) {
@@ -426,7 +447,9 @@ func TuningResetToDefaultHandler(t *tuning.Tuning, opts ...TuningOption) http.Ha
 //
 // Input:
 //   - POST only
-//   - URL query: ?key=<tuning key>
+//   - URL query: ?key=<tuning key>[&steps=<n>]
+//
+// With steps, the key is rolled back n writes using its history (tuning.Tuning.Undo).
 func TuningResetToLastValueHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
 	if t == nil {
 		panic("ops: nil tuning.Tuning")
@@ -469,6 +492,19 @@ func TuningResetToLastValueHandler(t *tuning.Tuning, opts ...TuningOption) http.
 			return
 		}
 
+		steps := 0
+		if raw, hasSteps := getQueryRaw(r, "steps"); hasSteps {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				writeTuningWrite(w, r, format, http.StatusBadRequest, tuningWriteResponse{
+					OK:    false,
+					Error: "invalid steps (want an integer >= 1)",
+				})
+				return
+			}
+			steps = n
+		}
+
 		old, found := TuningLookup(t, key)
 		if !found {
 			writeTuningWrite(w, r, format, http.StatusNotFound, tuningWriteResponse{
@@ -478,7 +514,13 @@ func TuningResetToLastValueHandler(t *tuning.Tuning, opts ...TuningOption) http.
 			return
 		}
 
-		if err := t.ResetToLastValue(key); err != nil {
+		var err error
+		if steps > 0 {
+			err = t.Undo(tuningWriteContext(r), key, steps)
+		} else {
+			err = t.ResetToLastValueContext(tuningWriteContext(r), key)
+		}
+		if err != nil {
 			code := mapTuningWriteErrorToStatus(err)
 			errMsg := err.Error()
 			if isRedactedItem(old) {
@@ -719,12 +761,34 @@ func appendTuningItemLines(b *strings.Builder, it tuning.Item) {
 	}
 
 	write("type", string(it.Type))
+	if it.Description != "" {
+		write("description", escapeTextField(it.Description))
+	}
+	if it.Unit != "" {
+		write("unit", escapeTextField(it.Unit))
+	}
+	if it.Owner != "" {
+		write("owner", escapeTextField(it.Owner))
+	}
+	if it.Dangerous {
+		write("dangerous", "true")
+	}
+	if len(it.Tags) > 0 {
+		write("tags", escapeTextField(strings.Join(it.Tags, ",")))
+	}
 	write("value", formatTuningAny(it.Value))
 	write("default", formatTuningAny(it.DefaultValue))
 	write("source", it.Source.String())
+	if it.LayerValue != nil {
+		write("layer_value", formatTuningAny(it.LayerValue))
+	}
 	if !it.LastUpdatedAt.IsZero() {
 		write("last_updated_at", it.LastUpdatedAt.Format(time.RFC3339Nano))
 	}
+	if it.ExpiresAt != nil {
+		write("expires_at", it.ExpiresAt.Format(time.RFC3339Nano))
+		write("revert_value", formatTuningAny(it.RevertValue))
+	}
 }
 
 func renderTuningWriteText(key string, old, newIt *tuning.Item) string {
@@ -766,18 +830,32 @@ func renderTuningWriteText(key string, old, newIt *tuning.Item) string {
 		write("old.value", formatTuningAny(old.Value))
 		write("old.default", formatTuningAny(old.DefaultValue))
 		write("old.source", old.Source.String())
+		if old.LayerValue != nil {
+			write("old.layer_value", formatTuningAny(old.LayerValue))
+		}
 		if !old.LastUpdatedAt.IsZero() {
 			write("old.last_updated_at", old.LastUpdatedAt.Format(time.RFC3339Nano))
 		}
+		if old.ExpiresAt != nil {
+			write("old.expires_at", old.ExpiresAt.Format(time.RFC3339Nano))
+			write("old.revert_value", formatTuningAny(old.RevertValue))
+		}
 	}
 	if newIt != nil {
 		write("new.type", string(newIt.Type))
 		write("new.value", formatTuningAny(newIt.Value))
 		write("new.default", formatTuningAny(newIt.DefaultValue))
 		write("new.source", newIt.Source.String())
+		if newIt.LayerValue != nil {
+			write("new.layer_value", formatTuningAny(newIt.LayerValue))
+		}
 		if !newIt.LastUpdatedAt.IsZero() {
 			write("new.last_updated_at", newIt.LastUpdatedAt.Format(time.RFC3339Nano))
 		}
+		if newIt.ExpiresAt != nil {
+			write("new.expires_at", newIt.ExpiresAt.Format(time.RFC3339Nano))
+			write("new.revert_value", formatTuningAny(newIt.RevertValue))
+		}
 	}
 	return b.String()
 }
@@ -799,6 +877,9 @@ func formatTuningAny(v any) string {
 		return strconv.FormatFloat(x, 'g', -1, 64)
 	case time.Duration:
 		return x.String()
+	case interface{ String() string }:
+		// Custom types with a readable form (e.g. tuning.FlagSpec).
+		return escapeTextField(x.String())
 	default:
 		// Fallback (should rarely happen).
 		return stringifyAny(v)
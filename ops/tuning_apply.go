@@ -0,0 +1,154 @@
+package ops
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+// tuningApplyMaxBody bounds the JSON body accepted by TuningApplyHandler.
+const tuningApplyMaxBody = 1 << 20
+
+// TuningApplyHandler returns a handler that sets several tuning keys at once (tuning.Apply):
+// either every item is applied or nothing changes.
+//
+// Input:
+//   - POST only
+//   - Body: JSON array in the ExportOverrides format: [{"key":"pool.min","value":"4"}, ...]
+//     ("type" is optional and checked when present).
+//   - URL query: ?dry_run=1 validates and returns the diff without applying it.
+//
+// Every key must pass the key guards (403 otherwise). Validation failures are reported per key;
+// errors for redacted keys do not echo the submitted value.
+func TuningApplyHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
+	if t == nil {
+		panic("ops: nil tuning.Tuning")
+	}
+	cfg := applyTuningOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeTuningApply(w, format, http.StatusMethodNotAllowed, tuningApplyResponse{Error: "method not allowed"})
+			return
+		}
+
+		var items []tuning.OverrideItem
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, tuningApplyMaxBody))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&items); err != nil {
+			writeTuningApply(w, format, http.StatusBadRequest, tuningApplyResponse{Error: "invalid body: " + err.Error()})
+			return
+		}
+		if len(items) == 0 {
+			writeTuningApply(w, format, http.StatusBadRequest, tuningApplyResponse{Error: "no items"})
+			return
+		}
+		dryRun := queryBool(r, "dry_run")
+
+		if cfg.guard != nil {
+			var denied []tuningApplyItemError
+			for _, it := range items {
+				if !cfg.guard(it.Key) {
+					denied = append(denied, tuningApplyItemError{Key: it.Key, Error: "key not allowed"})
+				}
+			}
+			if len(denied) > 0 {
+				writeTuningApply(w, format, http.StatusForbidden, tuningApplyResponse{
+					Error:  "key not allowed",
+					DryRun: dryRun,
+					Errors: denied,
+				})
+				return
+			}
+		}
+
+		var applyOpts []tuning.ApplyOption
+		if dryRun {
+			applyOpts = append(applyOpts, tuning.WithApplyDryRun())
+		}
+		changes, err := t.ApplyContext(tuningWriteContext(r), items, applyOpts...)
+		if err != nil {
+			resp := tuningApplyResponse{Error: "apply failed", DryRun: dryRun}
+			var ae *tuning.ApplyError
+			if errors.As(err, &ae) {
+				for _, ie := range ae.Items {
+					msg := ie.Err.Error()
+					if it, ok := TuningLookup(t, ie.Key); ok && isRedactedItem(it) {
+						msg = sanitizeTuningWriteError(ie.Err)
+					}
+					resp.Errors = append(resp.Errors, tuningApplyItemError{Key: ie.Key, Error: msg})
+				}
+			} else {
+				resp.Error = err.Error()
+			}
+			writeTuningApply(w, format, mapTuningWriteErrorToStatus(err), resp)
+			return
+		}
+		writeTuningApply(w, format, http.StatusOK, tuningApplyResponse{OK: true, DryRun: dryRun, Changes: changes})
+	})
+}
+
+type tuningApplyResponse struct {
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+	DryRun bool   `json:"dry_run,omitempty"`
+
+	Changes []tuning.Change        `json:"changes,omitempty"`
+	Errors  []tuningApplyItemError `json:"errors,omitempty"`
+}
+
+type tuningApplyItemError struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+func writeTuningApply(w http.ResponseWriter, f Format, code int, resp tuningApplyResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if !resp.OK {
+			writeTextError(w, resp.Error)
+			for _, e := range resp.Errors {
+				_, _ = w.Write([]byte("error\t" + escapeTextField(e.Key) + "\t" + escapeTextField(e.Error) + "\n"))
+			}
+			return
+		}
+		_, _ = w.Write([]byte(renderTuningApplyText(resp)))
+	}
+}
+
+func renderTuningApplyText(resp tuningApplyResponse) string {
+	// Format:
+	//   tuning_apply\tdry_run\t<bool>\n
+	//   tuning\t<key>\t<old.value|new.value|new.source|new.last_updated_at>\t<value>\n
+	var b strings.Builder
+	b.Grow(64 + 96*len(resp.Changes))
+	b.WriteString("tuning_apply\tdry_run\t" + strconv.FormatBool(resp.DryRun) + "\n")
+	for _, c := range resp.Changes {
+		write := func(field, value string) {
+			b.WriteString("tuning\t" + c.Key + "\t" + field + "\t" + value + "\n")
+		}
+		write("old.value", formatTuningAny(c.Old))
+		write("new.value", formatTuningAny(c.New))
+		write("new.source", c.Source.String())
+		if !c.At.IsZero() {
+			write("new.last_updated_at", c.At.Format(time.RFC3339Nano))
+		}
+	}
+	return b.String()
+}
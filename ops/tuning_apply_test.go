@@ -0,0 +1,72 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+func TestTuningApplyHandler(t *testing.T) {
+	tr := tuning.New()
+	lo, _ := tr.Int64("pool.min", 1, tuning.WithMaxInt64(100))
+	hi, _ := tr.Int64("pool.max", 10)
+	_, _ = tr.String("secret", "x", tuning.WithRedactString(), tuning.WithNonEmptyString())
+	_, _ = tr.Bool("other", false)
+	h := TuningApplyHandler(tr, WithTuningAllowPrefixes("pool.", "secret"))
+	post := func(query, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/?"+query, strings.NewReader(body)))
+		return w
+	}
+
+	w := post("dry_run=1", `[{"key":"pool.min","value":"20"},{"key":"pool.max","type":"int64","value":"40"}]`)
+	if w.Code != http.StatusOK || lo.Get() != 1 ||
+		!strings.HasPrefix(w.Body.String(), "tuning_apply\tdry_run\ttrue\ntuning\tpool.min\told.value\t1\ntuning\tpool.min\tnew.value\t20\n") {
+		t.Fatalf("dry run: status=%d body=%q", w.Code, w.Body.String())
+	}
+
+	w = post("format=json", `[{"key":"pool.min","value":"20"},{"key":"pool.max","value":"40"}]`)
+	var resp struct {
+		OK      bool            `json:"ok"`
+		Changes []tuning.Change `json:"changes"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.OK || len(resp.Changes) != 2 {
+		t.Fatalf("apply: status=%d body=%q", w.Code, w.Body.String())
+	}
+	if lo.Get() != 20 || hi.Get() != 40 {
+		t.Fatalf("values: %d %d", lo.Get(), hi.Get())
+	}
+
+	// One invalid item rejects the batch; redacted values are not echoed.
+	w = post("", `[{"key":"pool.min","value":"200"},{"key":"secret","value":""}]`)
+	body := w.Body.String()
+	if w.Code != http.StatusBadRequest || lo.Get() != 20 ||
+		!strings.Contains(body, "error\tpool.min\t") || !strings.Contains(body, "error\tsecret\tinvalid value\n") {
+		t.Fatalf("invalid: status=%d body=%q", w.Code, body)
+	}
+
+	for _, tc := range []struct {
+		body string
+		code int
+	}{
+		{`[{"key":"other","value":"true"}]`, http.StatusForbidden},
+		{`[{"key":"pool.none","value":"1"}]`, http.StatusNotFound},
+		{`[]`, http.StatusBadRequest},
+		{`{"key":"pool.min"}`, http.StatusBadRequest},
+		{`[{"key":"pool.min","val":"1"}]`, http.StatusBadRequest},
+	} {
+		if w := post("", tc.body); w.Code != tc.code {
+			t.Fatalf("body %s: status=%d, want %d (%q)", tc.body, w.Code, tc.code, w.Body.String())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "POST" {
+		t.Fatalf("GET: status=%d allow=%q", w.Code, w.Header().Get("Allow"))
+	}
+}
@@ -0,0 +1,96 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+// TuningFlagsHandler returns a handler that lists the feature flags of t (tuning.Tuning.Flag)
+// with their current spec and evaluation counters.
+//
+// Input:
+//   - GET/HEAD only
+//
+// Flags are filtered by the key guard, if any. Flags are changed through the regular tuning
+// write endpoints (e.g. /tuning/set?key=<flag>&value=25%;allow=alice).
+func TuningFlagsHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
+	if t == nil {
+		panic("ops: nil tuning.Tuning")
+	}
+	cfg := applyTuningOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeTuningFlags(w, r, format, http.StatusMethodNotAllowed, tuningFlagsResponse{Error: "method not allowed"})
+			return
+		}
+
+		flags := t.Flags()
+		if cfg.guard != nil {
+			kept := flags[:0]
+			for _, f := range flags {
+				if cfg.guard(f.Key) {
+					kept = append(kept, f)
+				}
+			}
+			flags = kept
+		}
+		writeTuningFlags(w, r, format, http.StatusOK, tuningFlagsResponse{OK: true, Flags: flags})
+	})
+}
+
+type tuningFlagsResponse struct {
+	OK    bool              `json:"ok"`
+	Error string            `json:"error,omitempty"`
+	Flags []tuning.FlagInfo `json:"flags"`
+}
+
+func writeTuningFlags(w http.ResponseWriter, r *http.Request, f Format, code int, resp tuningFlagsResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if !resp.OK {
+			writeTextError(w, resp.Error)
+			return
+		}
+		_, _ = w.Write([]byte(renderTuningFlagsText(resp.Flags)))
+	}
+}
+
+func renderTuningFlagsText(flags []tuning.FlagInfo) string {
+	// Format: tuning_flag\t<key>\t<field>\t<value>\n
+	var b strings.Builder
+	b.Grow(192 * len(flags))
+	for _, f := range flags {
+		write := func(field, value string) {
+			b.WriteString("tuning_flag\t" + f.Key + "\t" + field + "\t" + value + "\n")
+		}
+		write("spec", escapeTextField(f.Spec))
+		write("evaluations", strconv.FormatUint(f.Stats.Evaluations, 10))
+		write("enabled", strconv.FormatUint(f.Stats.Enabled, 10))
+		write("deny", strconv.FormatUint(f.Stats.Deny, 10))
+		write("allow", strconv.FormatUint(f.Stats.Allow, 10))
+		write("rule", strconv.FormatUint(f.Stats.Rule, 10))
+	}
+	return b.String()
+}
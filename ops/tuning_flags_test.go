@@ -0,0 +1,47 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+func TestTuningFlags_TextJSONAndGuard(t *testing.T) {
+	tr := tuning.New()
+	f, _ := tr.Flag("flags.checkout", tuning.FlagSpec{Percent: 10, Allow: []string{"alice"}})
+	_, _ = tr.Flag("internal.x", tuning.FlagSpec{})
+	f.Enabled(tuning.Subject{ID: "alice"})
+
+	h := TuningFlagsHandler(tr, WithTuningAllowPrefixes("flags."))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning/flags", nil))
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, "tuning_flag\tflags.checkout\tspec\t10%; allow=alice\n") ||
+		!strings.Contains(body, "tuning_flag\tflags.checkout\tallow\t1\n") || strings.Contains(body, "internal.x") {
+		t.Fatalf("status=%d body=%q", w.Code, body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning/flags?format=json", nil))
+	var resp tuningFlagsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.OK || len(resp.Flags) != 1 || resp.Flags[0].Stats.Evaluations != 1 {
+		t.Fatalf("json=%q (%v)", w.Body.String(), err)
+	}
+
+	// Snapshot renders the flag spec in its readable form.
+	w = httptest.NewRecorder()
+	TuningSnapshotHandler(tr).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning/snapshot", nil))
+	if !strings.Contains(w.Body.String(), "tuning\tflags.checkout\tvalue\t10%; allow=alice\n") {
+		t.Fatalf("snapshot=%q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/tuning/flags", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status=%d", w.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/httpx"
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+// TuningHistoryHandler returns a handler that lists the recorded writes of a key
+// (tuning.Tuning.History), oldest first. Values are redacted like Snapshot.
+//
+// Input:
+//   - GET/HEAD only
+//   - URL query: ?key=<tuning key>
+func TuningHistoryHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
+	if t == nil {
+		panic("ops: nil tuning.Tuning")
+	}
+	cfg := applyTuningOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeTuningHistory(w, r, format, http.StatusMethodNotAllowed, tuningHistoryResponse{Error: "method not allowed"})
+			return
+		}
+
+		key, ok := getQueryRequired(r, "key")
+		if !ok || key == "" {
+			writeTuningHistory(w, r, format, http.StatusBadRequest, tuningHistoryResponse{Error: "missing key"})
+			return
+		}
+		if err := validateTuningKey(key); err != nil {
+			writeTuningHistory(w, r, format, http.StatusBadRequest, tuningHistoryResponse{Error: err.Error()})
+			return
+		}
+		if cfg.guard != nil && !cfg.guard(key) {
+			writeTuningHistory(w, r, format, http.StatusForbidden, tuningHistoryResponse{Error: "key not allowed"})
+			return
+		}
+
+		h, err := t.History(key)
+		if err != nil {
+			writeTuningHistory(w, r, format, http.StatusNotFound, tuningHistoryResponse{Error: "key not found"})
+			return
+		}
+		writeTuningHistory(w, r, format, http.StatusOK, tuningHistoryResponse{OK: true, Key: key, History: h})
+	})
+}
+
+// tuningWriteContext returns the context for a tuning write made by r. If no actor was attached
+// upstream (tuning.ContextWithActor), the client certificate accepted by httpx.AccessGuard, if
+// any, is recorded as "cert:<common name>".
+func tuningWriteContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	if tuning.ActorFromContext(ctx) == "" {
+		if id, ok := httpx.ClientCertIdentityFromContext(ctx); ok && id.CommonName != "" {
+			return tuning.ContextWithActor(ctx, "cert:"+id.CommonName)
+		}
+	}
+	return ctx
+}
+
+type tuningHistoryResponse struct {
+	OK      bool                  `json:"ok"`
+	Error   string                `json:"error,omitempty"`
+	Key     string                `json:"key,omitempty"`
+	History []tuning.HistoryEntry `json:"history,omitempty"`
+}
+
+func writeTuningHistory(w http.ResponseWriter, r *http.Request, f Format, code int, resp tuningHistoryResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if !resp.OK {
+			writeTextError(w, resp.Error)
+			return
+		}
+		_, _ = w.Write([]byte(renderTuningHistoryText(resp.Key, resp.History)))
+	}
+}
+
+func renderTuningHistoryText(key string, h []tuning.HistoryEntry) string {
+	// Format: tuning_history\t<key>\t<at>\t<cause>\t<old>\t<new>\t<source>\t<actor>\n
+	var b strings.Builder
+	b.Grow(128 * len(h))
+	for _, e := range h {
+		b.WriteString("tuning_history\t" + key + "\t" + e.At.Format(time.RFC3339Nano) + "\t" + string(e.Cause) + "\t" +
+			formatTuningAny(e.Old) + "\t" + formatTuningAny(e.New) + "\t" + e.Source.String() + "\t" + escapeTextField(e.Actor) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+func TestTuningHistory_RecordsActorAndUndoSteps(t *testing.T) {
+	tr := tuning.New()
+	n, _ := tr.Int64("limit", 1)
+	set := TuningSetHandler(tr)
+	for _, v := range []string{"2", "3", "4"} {
+		r := httptest.NewRequest(http.MethodPost, "http://example/tuning/set?key=limit&value="+v, nil)
+		r = r.WithContext(tuning.ContextWithActor(r.Context(), "alice"))
+		w := httptest.NewRecorder()
+		set.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("set status=%d", w.Code)
+		}
+	}
+
+	undo := TuningResetToLastValueHandler(tr)
+	for _, steps := range []string{"0", "x", "-1"} {
+		w := httptest.NewRecorder()
+		undo.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/tuning/reset-last?key=limit&steps="+steps, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("steps=%q status=%d", steps, w.Code)
+		}
+	}
+	w := httptest.NewRecorder()
+	undo.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/tuning/reset-last?key=limit&steps=9", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status=%d, want=%d", w.Code, http.StatusConflict)
+	}
+	w = httptest.NewRecorder()
+	undo.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/tuning/reset-last?key=limit&steps=2", nil))
+	if w.Code != http.StatusOK || n.Get() != 2 {
+		t.Fatalf("status=%d limit=%d", w.Code, n.Get())
+	}
+
+	h := TuningHistoryHandler(tr)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning/history?key=limit", nil))
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if w.Code != http.StatusOK || len(lines) != 4 {
+		t.Fatalf("status=%d body=%q", w.Code, w.Body.String())
+	}
+	if f := strings.Split(lines[0], "\t"); len(f) != 8 || f[0] != "tuning_history" || f[3] != "set" || f[4] != "1" || f[5] != "2" || f[7] != "alice" {
+		t.Fatalf("line=%q", lines[0])
+	}
+	if f := strings.Split(lines[3], "\t"); f[3] != "undo" || f[4] != "4" || f[5] != "2" || f[7] != "" {
+		t.Fatalf("line=%q", lines[3])
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning/history?key=limit&format=json", nil))
+	var resp tuningHistoryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.OK || len(resp.History) != 4 {
+		t.Fatalf("json=%q (%v)", w.Body.String(), err)
+	}
+}
+
+func TestTuningHistory_Errors(t *testing.T) {
+	tr := tuning.New()
+	_, _ = tr.Bool("feature.x", false)
+	h := TuningHistoryHandler(tr, WithTuningKeyGuard(func(key string) bool { return key != "feature.x" }))
+
+	cases := []struct {
+		method, url string
+		want        int
+	}{
+		{http.MethodPost, "http://example/tuning/history?key=feature.x", http.StatusMethodNotAllowed},
+		{http.MethodGet, "http://example/tuning/history", http.StatusBadRequest},
+		{http.MethodGet, "http://example/tuning/history?key=feature.x", http.StatusForbidden},
+		{http.MethodGet, "http://example/tuning/history?key=nope", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(c.method, c.url, nil))
+		if w.Code != c.want {
+			t.Fatalf("%s %s: status=%d, want=%d", c.method, c.url, w.Code, c.want)
+		}
+	}
+}
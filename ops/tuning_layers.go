@@ -0,0 +1,123 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+// TuningLayersHandler returns a handler that reports the layer sources of t (e.g.
+// tuning.FileSource, tuningremote.Source): where they load from, the last load and check, the
+// content checksum and version, the keys they provide, the last error and the number of
+// consecutive failures.
+//
+// Input:
+//   - GET/HEAD only
+//
+// Keys are filtered by the key guard, if any.
+func TuningLayersHandler(t *tuning.Tuning, opts ...TuningOption) http.Handler {
+	if t == nil {
+		panic("ops: nil tuning.Tuning")
+	}
+	cfg := applyTuningOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r == nil {
+			panic("ops: nil request")
+		}
+		format := formatFromRequest(r, cfg.format)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeTuningLayers(w, r, format, http.StatusMethodNotAllowed, tuningLayersResponse{Error: "method not allowed"})
+			return
+		}
+
+		layers := t.Layers()
+		if cfg.guard != nil {
+			for i := range layers {
+				layers[i].Keys = filterTuningKeys(layers[i].Keys, cfg.guard)
+				layers[i].Unknown = filterTuningKeys(layers[i].Unknown, cfg.guard)
+			}
+		}
+		writeTuningLayers(w, r, format, http.StatusOK, tuningLayersResponse{OK: true, Layers: layers})
+	})
+}
+
+func filterTuningKeys(keys []string, guard func(string) bool) []string {
+	kept := keys[:0]
+	for _, k := range keys {
+		if guard(k) {
+			kept = append(kept, k)
+		}
+	}
+	return kept
+}
+
+type tuningLayersResponse struct {
+	OK     bool                 `json:"ok"`
+	Error  string               `json:"error,omitempty"`
+	Layers []tuning.LayerStatus `json:"layers"`
+}
+
+func writeTuningLayers(w http.ResponseWriter, r *http.Request, f Format, code int, resp tuningLayersResponse) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch f {
+	case FormatJSON:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if !resp.OK {
+			writeTextError(w, resp.Error)
+			return
+		}
+		_, _ = w.Write([]byte(renderTuningLayersText(resp.Layers)))
+	}
+}
+
+func renderTuningLayersText(layers []tuning.LayerStatus) string {
+	// Format: tuning_layer\t<location>\t<field>\t<value>\n
+	var b strings.Builder
+	b.Grow(256 * len(layers))
+	for _, l := range layers {
+		loc := escapeTextField(l.Location)
+		write := func(field, value string) {
+			b.WriteString("tuning_layer\t" + loc + "\t" + field + "\t" + value + "\n")
+		}
+		write("kind", escapeTextField(l.Kind))
+		write("source", l.Source.String())
+		if !l.LastLoad.IsZero() {
+			write("last_load", l.LastLoad.Format(time.RFC3339Nano))
+		}
+		if !l.LastCheck.IsZero() {
+			write("last_check", l.LastCheck.Format(time.RFC3339Nano))
+		}
+		write("checksum", l.Checksum)
+		if l.Version != "" {
+			write("version", escapeTextField(l.Version))
+		}
+		write("keys", strings.Join(l.Keys, ","))
+		if len(l.Unknown) > 0 {
+			write("unknown", strings.Join(l.Unknown, ","))
+		}
+		if l.Error != "" {
+			write("error", escapeTextField(l.Error))
+			write("error_at", l.ErrorAt.Format(time.RFC3339Nano))
+		}
+		if l.Failures > 0 {
+			write("failures", strconv.Itoa(l.Failures))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+func TestTuningLayers_TextJSONAndGuard(t *testing.T) {
+	tr := tuning.New()
+	_, _ = tr.Int64("app.limit", 1)
+	_, _ = tr.Int64("internal.x", 1)
+	path := filepath.Join(t.TempDir(), "tuning.conf")
+	if err := os.WriteFile(path, []byte("app.limit=5\ninternal.x=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src, err := tuning.OpenFileSource(tr, path, tuning.WithFileSourceInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	h := TuningLayersHandler(tr, WithTuningAllowPrefixes("app."))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning/layers", nil))
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, "tuning_layer\t"+path+"\tkind\tfile\n") ||
+		!strings.Contains(body, "tuning_layer\t"+path+"\tkeys\tapp.limit\n") ||
+		!strings.Contains(body, "tuning_layer\t"+path+"\tchecksum\t"+src.Status().Checksum+"\n") {
+		t.Fatalf("status=%d body=%q", w.Code, body)
+	}
+
+	// A rejected reload is reported with the previous content still in effect.
+	if err := os.WriteFile(path, []byte("app.limit=x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_ = src.Reload()
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning/layers?format=json", nil))
+	var resp tuningLayersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.OK || len(resp.Layers) != 1 ||
+		resp.Layers[0].Error == "" || resp.Layers[0].Failures != 1 || len(resp.Layers[0].Keys) != 1 {
+		t.Fatalf("json=%q (%v)", w.Body.String(), err)
+	}
+
+	// Lookup shows the file source and value.
+	w = httptest.NewRecorder()
+	TuningLookupHandler(tr).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning/lookup?key=app.limit", nil))
+	if body := w.Body.String(); !strings.Contains(body, "tuning\tapp.limit\tsource\tfile\n") ||
+		!strings.Contains(body, "tuning\tapp.limit\tlayer_value\t5\n") {
+		t.Fatalf("lookup=%q", body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://example/tuning/layers", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status=%d", w.Code)
+	}
+}
@@ -73,6 +73,54 @@ func TestTuningSnapshot_JSON_OK(t *testing.T) {
 	}
 }
 
+func TestTuningSnapshot_Meta_TextAndJSON(t *testing.T) {
+	tr := tuning.New()
+	_, _ = tr.Float64("cache.ttl_factor", 1, tuning.WithMetaFloat64(tuning.Meta{
+		Description: "Multiplier\tfor cache TTLs",
+		Unit:        "ratio",
+		Owner:       "team-storage",
+		Dangerous:   true,
+		Tags:        []string{"cache", "perf"},
+	}))
+	_, _ = tr.Bool("plain", false)
+
+	h := TuningSnapshotHandler(tr)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning_snapshot", nil))
+	body := w.Body.String()
+	for _, line := range []string{
+		"tuning\tcache.ttl_factor\tdescription\tMultiplier\\tfor cache TTLs\n",
+		"tuning\tcache.ttl_factor\tunit\tratio\n",
+		"tuning\tcache.ttl_factor\towner\tteam-storage\n",
+		"tuning\tcache.ttl_factor\tdangerous\ttrue\n",
+		"tuning\tcache.ttl_factor\ttags\tcache,perf\n",
+	} {
+		if !strings.Contains(body, line) {
+			t.Fatalf("body=%q, want contain %q", body, line)
+		}
+	}
+	if strings.Contains(body, "tuning\tplain\tdescription") || strings.Contains(body, "tuning\tplain\tdangerous") {
+		t.Fatalf("body=%q, want no metadata lines for plain", body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example/tuning_snapshot?format=json", nil))
+	var got struct {
+		Tuning struct {
+			Items []map[string]any `json:"items"`
+		} `json:"tuning"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || len(got.Tuning.Items) != 2 {
+		t.Fatalf("json=%q (%v)", w.Body.String(), err)
+	}
+	if it := got.Tuning.Items[0]; it["unit"] != "ratio" || it["owner"] != "team-storage" || it["dangerous"] != true {
+		t.Fatalf("item=%v", it)
+	}
+	if _, ok := got.Tuning.Items[1]["description"]; ok {
+		t.Fatalf("item=%v, want no description", got.Tuning.Items[1])
+	}
+}
+
 func TestTuningSnapshot_QueryFormatOverridesOption(t *testing.T) {
 	tr := tuning.New()
 	_, _ = tr.Bool("feature.x", false)
@@ -300,6 +348,38 @@ func TestTuningSet_OK_Text_AndAllowsEmptyValueWhenPresent(t *testing.T) {
 	}
 }
 
+func TestTuningSet_TTL(t *testing.T) {
+	tr := tuning.New()
+	n, _ := tr.Int64("limit", 10)
+	h := TuningSetHandler(tr)
+
+	r := httptest.NewRequest(http.MethodPost, "http://example/tuning_set?key=limit&value=20&ttl=1h", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Result().StatusCode != http.StatusOK {
+		t.Fatalf("status=%d, want=%d", w.Result().StatusCode, http.StatusOK)
+	}
+	if got := n.Get(); got != 20 {
+		t.Fatalf("limit=%d, want=20", got)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "tuning\tlimit\tnew.expires_at\t") || !strings.Contains(body, "tuning\tlimit\tnew.revert_value\t10\n") {
+		t.Fatalf("body=%q, want expires_at and revert_value lines", body)
+	}
+
+	for _, ttl := range []string{"abc", "0s", "-1m", ""} {
+		r := httptest.NewRequest(http.MethodPost, "http://example/tuning_set?key=limit&value=30&ttl="+ttl, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Result().StatusCode != http.StatusBadRequest {
+			t.Fatalf("ttl=%q status=%d, want=%d", ttl, w.Result().StatusCode, http.StatusBadRequest)
+		}
+	}
+	if got := n.Get(); got != 20 {
+		t.Fatalf("limit=%d, want=20", got)
+	}
+}
+
 func TestTuningSet_MissingValue_400(t *testing.T) {
 	tr := tuning.New()
 	_, _ = tr.Bool("feature.x", false)
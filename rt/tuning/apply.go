@@ -0,0 +1,140 @@
+package tuning
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ApplyError is returned by Apply when one or more items fail validation.
+// Nothing was changed.
+type ApplyError struct {
+	Items []ItemError
+}
+
+func (e *ApplyError) Error() string {
+	msgs := make([]string, 0, len(e.Items))
+	for _, it := range e.Items {
+		msgs = append(msgs, it.Err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the item errors, so errors.Is matches the sentinel errors of any item.
+func (e *ApplyError) Unwrap() []error {
+	out := make([]error, 0, len(e.Items))
+	for _, it := range e.Items {
+		out = append(out, it)
+	}
+	return out
+}
+
+type applyConfig struct {
+	dryRun bool
+}
+
+// ApplyOption configures Apply.
+type ApplyOption func(*applyConfig)
+
+// WithApplyDryRun validates the items and returns the diff without changing anything.
+func WithApplyDryRun() ApplyOption {
+	return func(c *applyConfig) { c.dryRun = true }
+}
+
+// Apply sets several keys as one change, e.g. related knobs like "pool.min" and "pool.max" that
+// must not be observed in an invalid intermediate state.
+//
+// Every item is validated first (key registered, Type if non-empty, Value parsed and checked as in
+// SetFromString); a key may appear only once. If any item fails, Apply returns an *ApplyError and
+// nothing is changed. Otherwise all values are committed under the write lock, and only then are
+// the onChange callbacks and observers run, item by item in input order. Each key's last value
+// is updated, so ResetToLastValue undoes the batch key by key.
+//
+// The returned changes describe each item (redacted like Snapshot), in input order. With
+// WithApplyDryRun, nothing is changed and the changes describe what would happen (At is zero).
+func (t *Tuning) Apply(items []OverrideItem, opts ...ApplyOption) ([]Change, error) {
+	return t.ApplyContext(context.Background(), items, opts...)
+}
+
+// ApplyContext is Apply; the actor in ctx (ContextWithActor) is recorded in the history.
+func (t *Tuning) ApplyContext(ctx context.Context, items []OverrideItem, opts ...ApplyOption) ([]Change, error) {
+	var cfg applyConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
+	}
+
+	type planned struct {
+		v varEntry
+		x any
+	}
+	plan := make([]planned, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	var bad []ItemError
+	for _, it := range items {
+		if err := validateKey(it.Key); err != nil {
+			bad = append(bad, ItemError{Key: it.Key, Err: err})
+			continue
+		}
+		if _, dup := seen[it.Key]; dup {
+			bad = append(bad, ItemError{Key: it.Key, Err: fmt.Errorf("%w: %q appears more than once", ErrInvalidValue, it.Key)})
+			continue
+		}
+		seen[it.Key] = struct{}{}
+		t.mu.RLock()
+		v, ok := t.vars[it.Key]
+		t.mu.RUnlock()
+		if !ok {
+			bad = append(bad, ItemError{Key: it.Key, Err: fmt.Errorf("%w: %q", ErrNotFound, it.Key)})
+			continue
+		}
+		x, err := parseOverride(v, it)
+		if err != nil {
+			bad = append(bad, ItemError{Key: it.Key, Err: err})
+			continue
+		}
+		plan = append(plan, planned{v: v, x: x})
+	}
+	if len(bad) > 0 {
+		return nil, &ApplyError{Items: bad}
+	}
+
+	changes := make([]Change, len(plan))
+	if cfg.dryRun {
+		for i, p := range plan {
+			changes[i] = newChange(p.v, p.v.load(), p.x, sourceFor(p.v, p.x), time.Time{})
+		}
+		return changes, nil
+	}
+
+	if err := t.lockWrite(); err != nil {
+		return nil, err
+	}
+	defer t.unlockWrite()
+
+	now := time.Now()
+	m := metaFrom(ctx, CauseApply)
+	olds := make([]any, len(plan))
+	for i, p := range plan {
+		olds[i] = t.commitLocked(p.v, p.x, now, m)
+		st := p.v.state()
+		st.hasLast, st.last = true, olds[i]
+	}
+	for i, p := range plan {
+		t.notifyLocked(p.v, olds[i])
+		changes[i] = newChange(p.v, olds[i], p.x, p.v.state().loadSource(), now)
+	}
+	return changes, nil
+}
+
+func newChange(v varEntry, oldV, newV any, src Source, at time.Time) Change {
+	if v.redacted() {
+		oldV, newV = "<redacted>", "<redacted>"
+	}
+	return Change{Key: v.key(), Type: v.typ(), Old: oldV, New: newV, Source: src, At: at}
+}
@@ -0,0 +1,78 @@
+package tuning
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tu := New()
+	var seen []string
+	lo, _ := tu.Int64("pool.min", 1, WithMinInt64(0), WithOnChangeInt64(func(int64) {
+		// Callbacks run after the whole batch is committed.
+		seen = append(seen, "min")
+	}))
+	hi, _ := tu.Int64("pool.max", 10, WithMinInt64(1))
+	tok, _ := tu.String("token", "", WithRedactString())
+	var pairs [][2]int64
+	tu.OnChange(func(Change) { pairs = append(pairs, [2]int64{lo.Get(), hi.Get()}) })
+
+	// Any invalid item rejects the whole batch.
+	_, err := tu.Apply([]OverrideItem{
+		{Key: "pool.min", Value: "20"},
+		{Key: "pool.max", Value: "0"},
+		{Key: "nope", Value: "1"},
+		{Key: "pool.min", Value: "3"},
+	})
+	var ae *ApplyError
+	if !errors.As(err, &ae) || len(ae.Items) != 3 || !errors.Is(err, ErrNotFound) || !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if lo.Get() != 1 || hi.Get() != 10 || lo.Source() != SourceDefault || len(seen) != 0 {
+		t.Fatalf("state changed after failed apply: %d %d", lo.Get(), hi.Get())
+	}
+
+	items := []OverrideItem{
+		{Key: "pool.min", Type: TypeInt64, Value: "20"},
+		{Key: "pool.max", Value: "40"},
+		{Key: "token", Value: "s3cret"},
+	}
+	diff, err := tu.Apply(items, WithApplyDryRun())
+	if err != nil || len(diff) != 3 || diff[0].Old != int64(1) || diff[0].New != int64(20) || diff[0].Source != SourceRuntimeSet ||
+		diff[2].New != "<redacted>" || !diff[0].At.IsZero() {
+		t.Fatalf("dry run diff=%+v err=%v", diff, err)
+	}
+	if lo.Get() != 1 || tok.Get() != "" {
+		t.Fatalf("dry run changed state")
+	}
+
+	changes, err := tu.Apply(items)
+	if err != nil || len(changes) != 3 || changes[1].Old != int64(10) || changes[1].New != int64(40) || changes[1].At.IsZero() {
+		t.Fatalf("changes=%+v err=%v", changes, err)
+	}
+	if lo.Get() != 20 || hi.Get() != 40 || tok.Get() != "s3cret" || len(seen) != 1 {
+		t.Fatalf("state: %d %d %q seen=%v", lo.Get(), hi.Get(), tok.Get(), seen)
+	}
+	for _, p := range pairs {
+		if p != [2]int64{20, 40} {
+			t.Fatalf("observer saw an intermediate state: %v", pairs)
+		}
+	}
+	if err := tu.ResetToLastValue("pool.max"); err != nil || hi.Get() != 10 {
+		t.Fatalf("undo: %v max=%d", err, hi.Get())
+	}
+}
+
+func TestApplyReentrant(t *testing.T) {
+	tu := New()
+	var got error
+	_, _ = tu.Bool("a", false, WithOnChangeBool(func(bool) {
+		_, got = tu.Apply([]OverrideItem{{Key: "a", Value: "false"}})
+	}))
+	if _, err := tu.Apply([]OverrideItem{{Key: "a", Value: "true"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(got, ErrReentrantWrite) {
+		t.Fatalf("got=%v, want ErrReentrantWrite", got)
+	}
+}
@@ -2,12 +2,14 @@ package tuning
 
 import (
 	"fmt"
+	"strconv"
 	"sync/atomic"
 	"time"
 )
 
 type boolConfig struct {
 	redact   bool
+	meta     Meta
 	onChange []func(bool)
 }
 
@@ -19,6 +21,11 @@ func WithRedactBool() BoolOption {
 	return func(c *boolConfig) { c.redact = true }
 }
 
+// WithMetaBool sets descriptive metadata (description, unit, owner, ...) shown in Snapshot.
+func WithMetaBool(m Meta) BoolOption {
+	return func(c *boolConfig) { c.meta = m }
+}
+
 // WithOnChangeBool appends an onChange callback.
 //
 // Callbacks are executed synchronously inside Set after the value is applied.
@@ -63,9 +70,9 @@ func (t *Tuning) Bool(key string, defaultValue bool, opts ...BoolOption) (*BoolV
 		onChange: cfg.onChange,
 	}
 	v.cur.Store(defaultValue)
-	v.source.Store(int32(SourceDefault))
 	// lastUpdatedAt stays zero until the first runtime write.
 
+	v.meta = cfg.meta.clone()
 	if err := t.register(key, v); err != nil {
 		return nil, err
 	}
@@ -80,20 +87,15 @@ type BoolVar struct {
 	def    bool
 	redact bool
 
-	cur    atomic.Bool
-	source atomic.Int32 // Source
-
-	lastUpdatedAtUnixNano atomic.Int64
-
-	// last/hasLast are protected by Tuning's write gate.
-	hasLast bool
-	last    bool
+	cur atomic.Bool
+	varState
 
 	onChange []func(bool)
 }
 
-func (v *BoolVar) key() string { return v.k }
-func (v *BoolVar) typ() Type   { return TypeBool }
+func (v *BoolVar) key() string    { return v.k }
+func (v *BoolVar) redacted() bool { return v.redact }
+func (v *BoolVar) typ() Type      { return TypeBool }
 
 // Key returns the variable key.
 func (v *BoolVar) Key() string { return v.k }
@@ -104,17 +106,11 @@ func (v *BoolVar) Key() string { return v.k }
 func (v *BoolVar) Get() bool { return v.cur.Load() }
 
 // Source returns where the current effective value comes from.
-func (v *BoolVar) Source() Source { return Source(v.source.Load()) }
+func (v *BoolVar) Source() Source { return v.loadSource() }
 
 // LastUpdatedAt returns the timestamp of the last successful runtime write (Set/Reset*).
 // Zero means never updated.
-func (v *BoolVar) LastUpdatedAt() time.Time {
-	ns := v.lastUpdatedAtUnixNano.Load()
-	if ns == 0 {
-		return time.Time{}
-	}
-	return time.Unix(0, ns)
-}
+func (v *BoolVar) LastUpdatedAt() time.Time { return v.updatedAt() }
 
 // Set updates the value.
 //
@@ -123,31 +119,14 @@ func (v *BoolVar) Set(newValue bool) error {
 	if v.t == nil {
 		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	old := v.cur.Load()
-	v.hasLast = true
-	v.last = old
-
-	v.cur.Store(newValue)
-	if newValue == v.def {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
-
-	for _, cb := range v.onChange {
-		safeCallBool(cb, newValue)
-	}
-	return nil
+	return v.t.setEntry(v, newValue, writeMeta{cause: CauseSet})
 }
 
-// ResetToDefault sets the value back to the registered default value.
-func (v *BoolVar) ResetToDefault() error { return v.Set(v.def) }
+// ResetToDefault sets the value back to the registered default value (or to the FileSource
+// value, if any).
+func (v *BoolVar) ResetToDefault() error {
+	return v.t.resetEntryToBase(v, writeMeta{cause: CauseResetDefault})
+}
 
 // ResetToLastValue restores the previous effective value (undo one step).
 //
@@ -156,37 +135,25 @@ func (v *BoolVar) ResetToLastValue() error {
 	if v.t == nil {
 		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	if !v.hasLast {
-		return fmt.Errorf("%w: %q", ErrNoLastValue, v.k)
-	}
-	newValue := v.last
-	v.hasLast = false
-
-	v.cur.Store(newValue)
-	if newValue == v.def {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
-
-	for _, cb := range v.onChange {
-		safeCallBool(cb, newValue)
-	}
-	return nil
+	return v.t.resetEntryToLast(v, writeMeta{cause: CauseResetLast})
 }
 
-func (v *BoolVar) setFromString(s string) error {
+func (v *BoolVar) parse(s string) (any, error) {
 	b, ok := parseBoolLoose(s)
 	if !ok {
-		return fmt.Errorf("%w: %q expects bool (true/false, t/f, 1/0, yes/no, on/off), got %q", ErrInvalidValue, v.k, s)
+		return nil, fmt.Errorf("%w: %q expects bool (true/false, t/f, 1/0, yes/no, on/off), got %q", ErrInvalidValue, v.k, s)
+	}
+	return b, nil
+}
+
+func (v *BoolVar) load() any         { return v.Get() }
+func (v *BoolVar) defaultValue() any { return v.def }
+func (v *BoolVar) store(x any)       { v.cur.Store(x.(bool)) }
+
+func (v *BoolVar) callOnChange(x any) {
+	for _, cb := range v.onChange {
+		safeCallBool(cb, x.(bool))
 	}
-	return v.Set(b)
 }
 
 func (v *BoolVar) snapshot() Item {
@@ -207,19 +174,7 @@ func (v *BoolVar) snapshot() Item {
 	}
 }
 
-func (v *BoolVar) override() (OverrideItem, bool) {
-	cur := v.Get()
-	if cur == v.def {
-		return OverrideItem{}, false
-	}
-	if v.redact {
-		return OverrideItem{Key: v.k, Type: TypeBool, Value: "<redacted>"}, true
-	}
-	if cur {
-		return OverrideItem{Key: v.k, Type: TypeBool, Value: "true"}, true
-	}
-	return OverrideItem{Key: v.k, Type: TypeBool, Value: "false"}, true
-}
+func (v *BoolVar) format(x any) string { return strconv.FormatBool(x.(bool)) }
 
 func safeCallBool(fn func(bool), v bool) {
 	if fn == nil {
@@ -0,0 +1,193 @@
+package tuning
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types reported by the built-in codecs.
+const (
+	TypeStringList Type = "[]string"
+	TypeInt64List  Type = "[]int64"
+	TypeStringMap  Type = "map[string]string"
+	TypeJSON       Type = "json"
+)
+
+// StringListCodec is the Codec for []string.
+//
+// Values are comma-separated ("a,b,c"); items are trimmed and empty items are dropped, so ""
+// is the empty list. A JSON array (`["a","b,c"]`) is accepted too, and is the format used for
+// items that could not round-trip through the comma form (items containing ',', or with
+// leading/trailing spaces, or empty, or a first item starting with '[').
+func StringListCodec() Codec[[]string] {
+	return Codec[[]string]{
+		Type: TypeStringList,
+		Parse: func(s string) ([]string, error) {
+			if isJSONValue(s, '[') {
+				var out []string
+				if err := decodeJSONStrict(s, &out); err != nil {
+					return nil, err
+				}
+				if out == nil {
+					out = []string{}
+				}
+				return out, nil
+			}
+			out := []string{}
+			for _, p := range strings.Split(s, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					out = append(out, p)
+				}
+			}
+			return out, nil
+		},
+		Format: func(x []string) string {
+			if len(x) > 0 && strings.HasPrefix(x[0], "[") { // would parse as JSON
+				return mustJSON(x)
+			}
+			for _, s := range x {
+				if !plainListItem(s) {
+					return mustJSON(x)
+				}
+			}
+			return strings.Join(x, ",")
+		},
+	}
+}
+
+// Int64ListCodec is the Codec for []int64: comma-separated base-10 integers ("1,2,3"); spaces
+// around items are ignored and "" is the empty list.
+func Int64ListCodec() Codec[[]int64] {
+	return Codec[[]int64]{
+		Type: TypeInt64List,
+		Parse: func(s string) ([]int64, error) {
+			out := []int64{}
+			if strings.TrimSpace(s) == "" {
+				return out, nil
+			}
+			for _, p := range strings.Split(s, ",") {
+				n, err := parseInt64Base10(strings.TrimSpace(p))
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, n)
+			}
+			return out, nil
+		},
+		Format: func(x []int64) string {
+			parts := make([]string, len(x))
+			for i, n := range x {
+				parts[i] = strconv.FormatInt(n, 10)
+			}
+			return strings.Join(parts, ",")
+		},
+	}
+}
+
+// StringMapCodec is the Codec for map[string]string.
+//
+// Values are comma-separated key=value pairs ("a=1,b=2"); keys and values are trimmed, keys must
+// be non-empty and unique, and "" is the empty map. Format sorts by key. A JSON object is
+// accepted too, and is the format used when a key or value could not round-trip through the
+// pair form (including a first key starting with '{').
+func StringMapCodec() Codec[map[string]string] {
+	return Codec[map[string]string]{
+		Type: TypeStringMap,
+		Parse: func(s string) (map[string]string, error) {
+			if isJSONValue(s, '{') {
+				var out map[string]string
+				if err := decodeJSONStrict(s, &out); err != nil {
+					return nil, err
+				}
+				if out == nil {
+					out = map[string]string{}
+				}
+				return out, nil
+			}
+			out := map[string]string{}
+			for _, p := range strings.Split(s, ",") {
+				if strings.TrimSpace(p) == "" {
+					continue
+				}
+				k, val, ok := strings.Cut(p, "=")
+				k, val = strings.TrimSpace(k), strings.TrimSpace(val)
+				if !ok || k == "" {
+					return nil, errors.New("want key=value pairs")
+				}
+				if _, dup := out[k]; dup {
+					return nil, errors.New("duplicate key " + strconv.Quote(k))
+				}
+				out[k] = val
+			}
+			return out, nil
+		},
+		Format: func(x map[string]string) string {
+			keys := make([]string, 0, len(x))
+			for k, val := range x {
+				if !plainListItem(k) || strings.Contains(k, "=") || (val != "" && !plainListItem(val)) {
+					return mustJSON(x) // encoding/json sorts map keys
+				}
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			if len(keys) > 0 && strings.HasPrefix(keys[0], "{") { // would parse as JSON
+				return mustJSON(x)
+			}
+			parts := make([]string, len(keys))
+			for i, k := range keys {
+				parts[i] = k + "=" + x[k]
+			}
+			return strings.Join(parts, ",")
+		},
+	}
+}
+
+// JSONCodec is a Codec for values encoded as JSON, typically structs. Unknown fields and
+// trailing data are rejected.
+func JSONCodec[T any]() Codec[T] {
+	return Codec[T]{
+		Type: TypeJSON,
+		Parse: func(s string) (T, error) {
+			var x T
+			err := decodeJSONStrict(s, &x)
+			return x, err
+		},
+		Format: func(x T) string { return mustJSON(x) },
+	}
+}
+
+// plainListItem reports whether s survives the comma-separated list form unchanged.
+func plainListItem(s string) bool {
+	return s != "" && s == strings.TrimSpace(s) && !strings.Contains(s, ",")
+}
+
+func isJSONValue(s string, open byte) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && s[0] == open
+}
+
+func decodeJSONStrict(s string, x any) error {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(x); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("trailing data after JSON value")
+	}
+	return nil
+}
+
+func mustJSON(x any) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(x); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
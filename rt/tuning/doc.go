@@ -31,6 +31,23 @@
 // Important: callbacks must be fast and must not block (no I/O, no sleep, no waiting), because a
 // slow callback blocks the current Set and all other writes in the same Tuning instance.
 //
+// Tuning.OnChange registers a registry-wide observer that receives a Change (key, old/new value,
+// source) after the per-variable callbacks, with the same rules. Redacted values are reported
+// as "<redacted>".
+//
+// Work that may block (rebuilding a pool, reconnecting) belongs off the write path:
+//   - Tuning.Watch(prefix) returns a Subscription whose channel delivers changes asynchronously.
+//     Pending changes are coalesced per key and bounded; overflow is counted (Dropped), never
+//     waited for.
+//   - Var.Changes(ctx) (e.g. Int64Var.Changes) delivers the latest value of one variable.
+//
+// For example:
+//
+//	ch := poolSize.Changes(ctx)
+//	for n := range ch {
+//		pool.Resize(int(n))
+//	}
+//
 // # Quick start
 //
 //	tu := tuning.New()
@@ -64,7 +81,10 @@
 //
 // Source indicates where the current effective value comes from:
 //   - SourceDefault: current value equals the registered default value
-//   - SourceRuntimeSet: current value differs from the registered default value
+//   - SourceFile / SourceRemote: current value equals the value provided by a layer source
+//     (FileSource, package tuningremote)
+//   - SourceRuntimeSet: current value differs from the base value (the layer value if any,
+//     else the default)
 //
 // This means Source reflects the current effective state (not historical actions).
 //
@@ -83,20 +103,124 @@
 // slightly lenient and accepts common forms (case-insensitive): true/false, t/f, 1/0,
 // yes/no, y/n, on/off.
 //
+// # Custom types
+//
+// Register adds a variable of any type T with a Codec (string parse/format) and an optional
+// validator (WithValidateVar). Get stays lock-free (atomic pointer), and the variable works with
+// SetFromString, Snapshot, ExportOverrides and redaction like the built-in types. Built-in
+// codecs: StringListCodec ("a,b"), Int64ListCodec ("1,2"), StringMapCodec ("k=v,k2=v2") and
+// JSONCodec (e.g. structs):
+//
+//	hosts, err := tuning.Register(tu, "upstream.hosts", []string{"a:80"}, tuning.StringListCodec())
+//	...
+//	for _, h := range hosts.Get() { ... } // parsed once per write, not per Get
+//
+// # Feature flags
+//
+// Flag registers a flag (a variable of type TypeFlag holding a FlagSpec) evaluated per Subject:
+// deny list, allow list, attribute rules, then a percentage rollout by stable hash of the
+// subject ID. It is set like any other key, in a readable form:
+//
+//	checkout, _ := tu.Flag("checkout.v2", tuning.FlagSpec{})
+//	_ = tu.SetFromString("checkout.v2", "10%; allow=alice; attr:plan=beta")
+//	if checkout.Enabled(tuning.Subject{ID: userID, Attrs: map[string]string{"plan": plan}}) { ... }
+//
+// EnabledContext evaluates for the Subject attached with ContextWithSubject. Stats and
+// Tuning.Flags report evaluation counters.
+//
 // # SetAny
 //
-// SetAny allows setting a value from typed Go values (bool/int64/float64/string/duration).
-// If the input type does not match the registered variable type, SetAny returns ErrTypeMismatch.
+// SetAny allows setting a value from typed Go values (bool/int64/float64/string/duration,
+// or T for a Var[T]). If the input type does not match the registered variable type, SetAny
+// returns ErrTypeMismatch.
 //
 // # Key-based helpers
 //
 // tuning is designed around strong-typed variable handles (BoolVar/Int64Var/...), but some
 // ops/admin workflows only have a key string at runtime. For these cases, Tuning also provides:
 //   - Lookup(key) (Item, bool): a point-in-time view for a single key (redaction rules apply)
-//   - ResetToDefault(key) error: reset a key back to its registered default value
+//   - ResetToDefault(key) error: reset a key back to its registered default value (or file value)
 //   - ResetToLastValue(key) error: undo one step for a key (ErrNoLastValue if none)
 //
 // Redaction:
 // If a variable is registered with WithRedact*, Snapshot/Lookup and ExportOverrides will replace
 // its Value/DefaultValue (and override Value) with "<redacted>".
+//
+// # Batch apply
+//
+// Apply sets several keys as one change: every item is validated first, then all values are
+// committed under the write lock before any callback runs, so callbacks and observers never see a
+// half-applied batch. If any item is invalid, Apply returns an *ApplyError and nothing changes.
+// WithApplyDryRun returns the would-be changes without applying them.
+//
+// # Temporary values
+//
+// SetWithTTL / SetFromStringWithTTL set a value that reverts automatically when the TTL expires:
+// to the value before the first of a series of temporary writes, so stacked TTL writes unwind to
+// the last permanent value. The revert is an ordinary write (through the write gate, with
+// callbacks and observers); any later write without a TTL cancels it. While a temporary value is
+// in effect, Item.ExpiresAt and Item.RevertValue describe the pending revert, and FileStore
+// persists the revert value rather than the temporary one.
+//
+// # History
+//
+// Every write is recorded in a bounded per-key history (32 entries by default; WithHistoryLimit):
+// time, old/new value (redacted like Snapshot), the new Source, the Cause (set, set-ttl,
+// ttl-expiry, reset-default, reset-last, undo, import, apply, file) and, for writes made through
+// the *Context methods, the actor attached with ContextWithActor. History(key) returns it, and
+// Undo(ctx, key, n) rolls a key back n writes.
+//
+// # File layers
+//
+// OpenFileSource applies a file (key=value lines or JSON) or a directory of files as a layer
+// beneath runtime writes, and polls it for changes:
+//
+//	src, err := tuning.OpenFileSource(tu, "/etc/app/tuning.conf")
+//	if err != nil {
+//		return err
+//	}
+//	defer src.Close()
+//
+// Variables without a runtime write in effect take the file value (SourceFile); runtime writes
+// stay in effect when the file changes, and ResetToDefault returns to the file value. A change
+// applies atomically (all values are committed before any callback runs); content that fails to
+// parse or validate is rejected as a whole and the previous content stays in effect. Status and
+// Tuning.Layers report the last load and check, the content checksum and the last error.
+// Replace the file atomically (write a temporary file, then rename it) so a poll never reads a
+// partial write.
+//
+// FileSource is built on Layer, which other sources can use too: package tuningremote polls an
+// HTTP config service (ETag, backoff) and applies its document the same way.
+//
+// # Metadata and scopes
+//
+// WithMetaBool, WithMetaInt64, ... (WithMetaVar, WithMetaFlag) attach a Meta to a variable: a
+// description, unit, owner, tags and a Dangerous mark for knobs that need care. Meta is reported
+// in Snapshot and Lookup items, and by the ops tuning handlers (and so in admin /report); it is
+// never part of exported overrides.
+//
+// Sub returns a Scope that registers variables under a key prefix, so a library can own a
+// namespace without hard-coding where it is mounted:
+//
+//	cache := tu.Sub("cache")
+//	ttl, err := cache.Duration("ttl", time.Minute) // key "cache.ttl"
+//
+// # Import and persistence
+//
+// ImportOverrides / ImportOverridesJSON apply overrides produced by ExportOverrides. Each item is
+// checked against the registered variable (type and value); the ImportResult lists applied,
+// unknown and invalid keys, and invalid items never affect the others. With WithImportPending,
+// overrides for keys that are not registered yet are kept and applied on registration.
+//
+// FileStore builds on this to persist overrides across restarts:
+//
+//	store, err := tuning.OpenFileStore(tu, "/var/lib/app/tuning.json")
+//	if err != nil {
+//		return err
+//	}
+//	defer store.Close()
+//
+// OpenFileStore restores the file (pending for late keys) and then rewrites it atomically after
+// every successful write, from a background goroutine so the write path never waits for disk.
+// The file holds unredacted values and is created with mode 0600.
 package tuning
@@ -8,6 +8,7 @@ import (
 
 type durationConfig struct {
 	redact bool
+	meta   Meta
 
 	hasMin bool
 	min    time.Duration
@@ -25,6 +26,11 @@ func WithRedactDuration() DurationOption {
 	return func(c *durationConfig) { c.redact = true }
 }
 
+// WithMetaDuration sets descriptive metadata (description, unit, owner, ...) shown in Snapshot.
+func WithMetaDuration(m Meta) DurationOption {
+	return func(c *durationConfig) { c.meta = m }
+}
+
 // WithMinDuration sets a minimum constraint (inclusive).
 func WithMinDuration(min time.Duration) DurationOption {
 	return func(c *durationConfig) {
@@ -87,8 +93,8 @@ func (t *Tuning) Duration(key string, defaultValue time.Duration, opts ...Durati
 		onChange: cfg.onChange,
 	}
 	v.curNanos.Store(v.defNanos)
-	v.source.Store(int32(SourceDefault))
 
+	v.meta = cfg.meta.clone()
 	if err := t.register(key, v); err != nil {
 		return nil, err
 	}
@@ -110,19 +116,14 @@ type DurationVar struct {
 	max    time.Duration
 
 	curNanos atomic.Int64
-	source   atomic.Int32 // Source
-
-	lastUpdatedAtUnixNano atomic.Int64
-
-	// last/hasLast are protected by Tuning's write gate.
-	hasLast bool
-	last    time.Duration
+	varState
 
 	onChange []func(time.Duration)
 }
 
-func (v *DurationVar) key() string { return v.k }
-func (v *DurationVar) typ() Type   { return TypeDuration }
+func (v *DurationVar) key() string    { return v.k }
+func (v *DurationVar) redacted() bool { return v.redact }
+func (v *DurationVar) typ() Type      { return TypeDuration }
 
 func (v *DurationVar) Key() string { return v.k }
 
@@ -133,15 +134,9 @@ func (v *DurationVar) Get() time.Duration {
 	return time.Duration(v.curNanos.Load())
 }
 
-func (v *DurationVar) Source() Source { return Source(v.source.Load()) }
+func (v *DurationVar) Source() Source { return v.loadSource() }
 
-func (v *DurationVar) LastUpdatedAt() time.Time {
-	ns := v.lastUpdatedAtUnixNano.Load()
-	if ns == 0 {
-		return time.Time{}
-	}
-	return time.Unix(0, ns)
-}
+func (v *DurationVar) LastUpdatedAt() time.Time { return v.updatedAt() }
 
 func (v *DurationVar) Set(newValue time.Duration) error {
 	if v.t == nil {
@@ -153,75 +148,42 @@ func (v *DurationVar) Set(newValue time.Duration) error {
 	}); err != nil {
 		return err
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	old := v.Get()
-	v.hasLast = true
-	v.last = old
-
-	nanos := newValue.Nanoseconds()
-	v.curNanos.Store(nanos)
-	if nanos == v.defNanos {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
-
-	for _, cb := range v.onChange {
-		safeCallDuration(cb, newValue)
-	}
-	return nil
+	return v.t.setEntry(v, newValue, writeMeta{cause: CauseSet})
 }
 
-func (v *DurationVar) ResetToDefault() error { return v.Set(v.def) }
+func (v *DurationVar) ResetToDefault() error {
+	return v.t.resetEntryToBase(v, writeMeta{cause: CauseResetDefault})
+}
 
 func (v *DurationVar) ResetToLastValue() error {
 	if v.t == nil {
 		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
+	return v.t.resetEntryToLast(v, writeMeta{cause: CauseResetLast})
+}
 
-	if !v.hasLast {
-		return fmt.Errorf("%w: %q", ErrNoLastValue, v.k)
+func (v *DurationVar) parse(s string) (any, error) {
+	d, err := parseDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q expects duration (Go format), got %q: %v", ErrInvalidValue, v.k, s, err)
 	}
-	newValue := v.last
-	v.hasLast = false
-
-	if err := validateDurationValue(v.k, newValue, durationConfig{
+	if err := validateDurationValue(v.k, d, durationConfig{
 		hasMin: v.hasMin, min: v.min,
 		hasMax: v.hasMax, max: v.max,
 	}); err != nil {
-		return err
+		return nil, err
 	}
+	return d, nil
+}
 
-	nanos := newValue.Nanoseconds()
-	v.curNanos.Store(nanos)
-	if nanos == v.defNanos {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
+func (v *DurationVar) load() any         { return v.Get() }
+func (v *DurationVar) defaultValue() any { return v.def }
+func (v *DurationVar) store(x any)       { v.curNanos.Store(x.(time.Duration).Nanoseconds()) }
 
+func (v *DurationVar) callOnChange(x any) {
 	for _, cb := range v.onChange {
-		safeCallDuration(cb, newValue)
+		safeCallDuration(cb, x.(time.Duration))
 	}
-	return nil
-}
-
-func (v *DurationVar) setFromString(s string) error {
-	d, err := parseDuration(s)
-	if err != nil {
-		return fmt.Errorf("%w: %q expects duration (Go format), got %q: %v", ErrInvalidValue, v.k, s, err)
-	}
-	return v.Set(d)
 }
 
 func (v *DurationVar) snapshot() Item {
@@ -252,16 +214,7 @@ func (v *DurationVar) snapshot() Item {
 	}
 }
 
-func (v *DurationVar) override() (OverrideItem, bool) {
-	curNanos := v.curNanos.Load()
-	if curNanos == v.defNanos {
-		return OverrideItem{}, false
-	}
-	if v.redact {
-		return OverrideItem{Key: v.k, Type: TypeDuration, Value: "<redacted>"}, true
-	}
-	return OverrideItem{Key: v.k, Type: TypeDuration, Value: time.Duration(curNanos).String()}, true
-}
+func (v *DurationVar) format(x any) string { return x.(time.Duration).String() }
 
 func validateDurationValue(key string, v time.Duration, cfg durationConfig) error {
 	if cfg.hasMin && v < cfg.min {
@@ -8,6 +8,7 @@ import (
 
 type enumConfig struct {
 	redact bool
+	meta   Meta
 
 	allowed []string
 	// normalize can be used to implement case-insensitive or alias-friendly enums.
@@ -25,6 +26,11 @@ func WithRedactEnum() EnumOption {
 	return func(c *enumConfig) { c.redact = true }
 }
 
+// WithMetaEnum sets descriptive metadata (description, unit, owner, ...) shown in Snapshot.
+func WithMetaEnum(m Meta) EnumOption {
+	return func(c *enumConfig) { c.meta = m }
+}
+
 // WithEnumAllowed sets the allowed values for an enum.
 //
 // allowed must be non-empty and must not contain duplicates.
@@ -111,8 +117,8 @@ func (t *Tuning) Enum(key string, defaultValue string, opts ...EnumOption) (*Enu
 		onChange:  cfg.onChange,
 	}
 	v.curIdx.Store(defIdx)
-	v.source.Store(int32(SourceDefault))
 
+	v.meta = cfg.meta.clone()
 	if err := t.register(key, v); err != nil {
 		return nil, err
 	}
@@ -135,19 +141,14 @@ type EnumVar struct {
 	normalize func(string) (string, bool)
 
 	curIdx atomic.Uint32
-	source atomic.Int32 // Source
-
-	lastUpdatedAtUnixNano atomic.Int64
-
-	// last/hasLast are protected by Tuning's write gate.
-	hasLast bool
-	lastIdx uint32
+	varState
 
 	onChange []func(string)
 }
 
-func (v *EnumVar) key() string { return v.k }
-func (v *EnumVar) typ() Type   { return TypeEnum }
+func (v *EnumVar) key() string    { return v.k }
+func (v *EnumVar) redacted() bool { return v.redact }
+func (v *EnumVar) typ() Type      { return TypeEnum }
 
 func (v *EnumVar) Key() string { return v.k }
 
@@ -165,15 +166,9 @@ func (v *EnumVar) Get() string {
 	return v.allowed[i]
 }
 
-func (v *EnumVar) Source() Source { return Source(v.source.Load()) }
+func (v *EnumVar) Source() Source { return v.loadSource() }
 
-func (v *EnumVar) LastUpdatedAt() time.Time {
-	ns := v.lastUpdatedAtUnixNano.Load()
-	if ns == 0 {
-		return time.Time{}
-	}
-	return time.Unix(0, ns)
-}
+func (v *EnumVar) LastUpdatedAt() time.Time { return v.updatedAt() }
 
 func (v *EnumVar) Set(newValue string) error {
 	if v.t == nil {
@@ -184,68 +179,36 @@ func (v *EnumVar) Set(newValue string) error {
 		return err
 	}
 
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	old := v.curIdx.Load()
-	v.hasLast = true
-	v.lastIdx = old
-
-	v.curIdx.Store(idx)
-	if idx == v.defIdx {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
-
-	val := v.allowed[idx]
-	for _, cb := range v.onChange {
-		safeCallEnum(cb, val)
-	}
-	return nil
+	return v.t.setEntry(v, v.allowed[idx], writeMeta{cause: CauseSet})
 }
 
-func (v *EnumVar) ResetToDefault() error { return v.Set(v.defValue) }
+func (v *EnumVar) ResetToDefault() error {
+	return v.t.resetEntryToBase(v, writeMeta{cause: CauseResetDefault})
+}
 
 func (v *EnumVar) ResetToLastValue() error {
 	if v.t == nil {
 		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	if !v.hasLast {
-		return fmt.Errorf("%w: %q", ErrNoLastValue, v.k)
-	}
-	idx := v.lastIdx
-	v.hasLast = false
+	return v.t.resetEntryToLast(v, writeMeta{cause: CauseResetLast})
+}
 
-	if int(idx) >= len(v.allowed) {
-		return fmt.Errorf("%w: %q last enum index out of range", ErrInvalidConfig, v.k)
+func (v *EnumVar) parse(s string) (any, error) {
+	idx, err := v.parseValue(s)
+	if err != nil {
+		return nil, err
 	}
+	return v.allowed[idx], nil
+}
 
-	v.curIdx.Store(idx)
-	if idx == v.defIdx {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
+func (v *EnumVar) load() any         { return v.Get() }
+func (v *EnumVar) defaultValue() any { return v.defValue }
+func (v *EnumVar) store(x any)       { v.curIdx.Store(v.index[x.(string)]) }
 
-	val := v.allowed[idx]
+func (v *EnumVar) callOnChange(x any) {
 	for _, cb := range v.onChange {
-		safeCallEnum(cb, val)
+		safeCallEnum(cb, x.(string))
 	}
-	return nil
-}
-
-func (v *EnumVar) setFromString(s string) error {
-	return v.Set(s)
 }
 
 func (v *EnumVar) snapshot() Item {
@@ -267,19 +230,7 @@ func (v *EnumVar) snapshot() Item {
 	}
 }
 
-func (v *EnumVar) override() (OverrideItem, bool) {
-	idx := v.curIdx.Load()
-	if idx == v.defIdx {
-		return OverrideItem{}, false
-	}
-	if v.redact {
-		return OverrideItem{Key: v.k, Type: TypeEnum, Value: "<redacted>"}, true
-	}
-	if int(idx) >= len(v.allowed) {
-		return OverrideItem{Key: v.k, Type: TypeEnum, Value: ""}, true
-	}
-	return OverrideItem{Key: v.k, Type: TypeEnum, Value: v.allowed[idx]}, true
-}
+func (v *EnumVar) format(x any) string { return x.(string) }
 
 func (v *EnumVar) parseValue(s string) (uint32, error) {
 	if v.normalize != nil {
@@ -0,0 +1,211 @@
+package tuning
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultFileSourceInterval is how often a FileSource checks for changes by default.
+const defaultFileSourceInterval = 5 * time.Second
+
+type fileSourceConfig struct {
+	interval time.Duration
+	onError  func(error)
+}
+
+// FileSourceOption configures a FileSource.
+type FileSourceOption func(*fileSourceConfig)
+
+// WithFileSourceInterval sets how often the file is checked for changes (default 5s).
+// d <= 0 means default.
+func WithFileSourceInterval(d time.Duration) FileSourceOption {
+	return func(c *fileSourceConfig) { c.interval = d }
+}
+
+// WithFileSourceOnError sets a function that receives background errors: an unreadable or
+// malformed file, or content rejected by validation (see WithLayerOnError).
+func WithFileSourceOnError(fn func(error)) FileSourceOption {
+	return func(c *fileSourceConfig) { c.onError = fn }
+}
+
+// FileSource applies the values of a file (or a directory of files) as a layer beneath runtime
+// writes: a variable that has no runtime write in effect takes the file value (Source is
+// SourceFile), ResetToDefault returns to it, and runtime writes stay in effect when the file
+// changes. The file is polled for changes; a change applies atomically, and a file that fails
+// to parse or validate is rejected as a whole (the previous content stays in effect).
+//
+// Values use the SetFromString form, in one of these formats:
+//
+//	# key=value lines
+//	http.timeout=800ms
+//	feature.x=on
+//
+//	{"http.timeout": "800ms", "feature.x": true}
+//
+// or the JSON array written by ExportOverridesJSON. For a directory, every regular file (or
+// symlink to one, as in a Kubernetes ConfigMap mount) whose name does not start with '.' is read,
+// in name order; a key may only appear once.
+type FileSource struct {
+	path string
+	cfg  fileSourceConfig
+	l    *Layer
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	reloadMu  sync.Mutex
+}
+
+// OpenFileSource loads path into t and starts polling it for changes. Keys that are not
+// registered yet are applied when they are registered.
+//
+// A missing, unreadable, malformed or invalid file is returned as an error and nothing is
+// applied. Later errors are reported to WithFileSourceOnError and by Status. Call Close to stop
+// polling and remove the layer.
+func OpenFileSource(t *Tuning, path string, opts ...FileSourceOption) (*FileSource, error) {
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w: empty file source path", ErrInvalidConfig)
+	}
+	s := &FileSource{path: path, done: make(chan struct{})}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&s.cfg)
+		}
+	}
+	if s.cfg.interval <= 0 {
+		s.cfg.interval = defaultFileSourceInterval
+	}
+	s.l = t.NewLayer("file", path, SourceFile, WithLayerOnError(s.cfg.onError))
+
+	items, sum, err := readFileSource(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.l.Load(LayerContent{Items: items, Checksum: sum}); err != nil {
+		return nil, fmt.Errorf("tuning: file source: %s: %w", path, err)
+	}
+
+	s.wg.Add(1)
+	go s.loop()
+	return s, nil
+}
+
+// Path returns the file or directory path.
+func (s *FileSource) Path() string { return s.path }
+
+// Status returns the current status of the source (it is also listed by Tuning.Layers).
+func (s *FileSource) Status() LayerStatus { return s.l.Status() }
+
+// Reload checks the file now and applies it if its content changed.
+func (s *FileSource) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	items, sum, err := readFileSource(s.path)
+	if err != nil {
+		return s.l.Fail(err)
+	}
+	if err := s.l.Load(LayerContent{Items: items, Checksum: sum}); err != nil {
+		return fmt.Errorf("tuning: file source: %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// Close stops polling and removes the layer: its variables without a runtime write return to
+// their default values. It is idempotent.
+func (s *FileSource) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+		err = s.l.Remove()
+	})
+	return err
+}
+
+func (s *FileSource) loop() {
+	defer s.wg.Done()
+	tk := time.NewTicker(s.cfg.interval)
+	defer tk.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-tk.C:
+			_ = s.Reload() // recorded in Status and reported to onError
+		}
+	}
+}
+
+// readFileSource reads and parses a file source and returns its items and checksum.
+func readFileSource(path string) ([]OverrideItem, string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("tuning: file source: %w", err)
+	}
+	if !fi.IsDir() {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("tuning: file source: %w", err)
+		}
+		sum := sha256.Sum256(b)
+		items, err := ParseLayerContent(b)
+		if err != nil {
+			return nil, "", fmt.Errorf("tuning: file source: %s: %w", path, err)
+		}
+		return items, hex.EncodeToString(sum[:]), nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("tuning: file source: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	h := sha256.New()
+	var items []OverrideItem
+	from := make(map[string]string) // key -> file name
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		name := filepath.Join(path, e.Name())
+		if !e.Type().IsRegular() {
+			// Follow symlinks: a Kubernetes ConfigMap mount is made of key -> ..data/key links.
+			fi, err := os.Stat(name)
+			if err != nil {
+				return nil, "", fmt.Errorf("tuning: file source: %w", err)
+			}
+			if !fi.Mode().IsRegular() {
+				continue
+			}
+		}
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return nil, "", fmt.Errorf("tuning: file source: %w", err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", e.Name(), len(b))
+		h.Write(b)
+		fileItems, err := ParseLayerContent(b)
+		if err != nil {
+			return nil, "", fmt.Errorf("tuning: file source: %s: %w", name, err)
+		}
+		for _, it := range fileItems {
+			if prev, dup := from[it.Key]; dup {
+				return nil, "", fmt.Errorf("tuning: file source: %s: key %q already set in %s", name, it.Key, prev)
+			}
+			from[it.Key] = e.Name()
+			items = append(items, it)
+		}
+	}
+	return items, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,272 @@
+package tuning
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileSource_LayerBeneathRuntimeWrites(t *testing.T) {
+	tu := New()
+	n, _ := tu.Int64("n", 1)
+	s, _ := tu.String("s", "a")
+	path := filepath.Join(t.TempDir(), "tuning.conf")
+	writeTestFile(t, path, "# defaults for prod\nn = 5\ns=b\n")
+
+	src, err := OpenFileSource(tu, path, WithFileSourceInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if n.Get() != 5 || n.Source() != SourceFile || s.Get() != "b" {
+		t.Fatalf("n=%d (%v) s=%q", n.Get(), n.Source(), s.Get())
+	}
+	if it, _ := tu.Lookup("n"); it.LayerValue != int64(5) || it.Source.String() != "file" {
+		t.Fatalf("item=%+v", it)
+	}
+	if ov := tu.ExportOverrides(); len(ov) != 0 {
+		t.Fatalf("file values exported as overrides: %+v", ov)
+	}
+
+	// A runtime write stays in effect when the file changes.
+	_ = n.Set(7)
+	writeTestFile(t, path, "n=6\ns=c\n")
+	if err := src.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if n.Get() != 7 || n.Source() != SourceRuntimeSet || s.Get() != "c" || s.Source() != SourceFile {
+		t.Fatalf("n=%d (%v) s=%q (%v)", n.Get(), n.Source(), s.Get(), s.Source())
+	}
+	if ov := tu.ExportOverrides(); len(ov) != 1 || ov[0].Key != "n" {
+		t.Fatalf("overrides=%+v", ov)
+	}
+
+	// ResetToDefault drops the runtime write and returns to the file value.
+	_ = n.ResetToDefault()
+	if n.Get() != 6 || n.Source() != SourceFile {
+		t.Fatalf("after reset: n=%d (%v)", n.Get(), n.Source())
+	}
+	h, _ := tu.History("s")
+	if len(h) != 2 || h[1].Cause != CauseFile || h[1].Source != SourceFile {
+		t.Fatalf("history=%+v", h)
+	}
+
+	st := src.Status()
+	if st.Kind != "file" || st.Location != path || len(st.Checksum) != 64 || st.LastLoad.IsZero() ||
+		!reflect.DeepEqual(st.Keys, []string{"n", "s"}) || st.Error != "" {
+		t.Fatalf("status=%+v", st)
+	}
+	if ls := tu.Layers(); len(ls) != 1 || ls[0].Checksum != st.Checksum {
+		t.Fatalf("layers=%+v", ls)
+	}
+
+	// Close removes the layer.
+	if err := src.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n.Get() != 1 || n.Source() != SourceDefault || s.Get() != "a" || len(tu.Layers()) != 0 {
+		t.Fatalf("after close: n=%d (%v) s=%q layers=%d", n.Get(), n.Source(), s.Get(), len(tu.Layers()))
+	}
+}
+
+func TestFileSource_InvalidContentIsRejectedAsAWhole(t *testing.T) {
+	tu := New()
+	a, _ := tu.Int64("a", 1, WithMinInt64(0))
+	b, _ := tu.Int64("b", 1)
+	path := filepath.Join(t.TempDir(), "tuning.conf")
+	writeTestFile(t, path, "a=2\nb=2\n")
+
+	var reported []error
+	src, err := OpenFileSource(tu, path, WithFileSourceInterval(time.Hour), WithFileSourceOnError(func(err error) {
+		reported = append(reported, err)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	sum := src.Status().Checksum
+
+	var changes int
+	tu.OnChange(func(Change) { changes++ })
+	writeTestFile(t, path, "a=-1\nb=3\n")
+	if err := src.Reload(); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if a.Get() != 2 || b.Get() != 2 || changes != 0 {
+		t.Fatalf("partial apply: a=%d b=%d changes=%d", a.Get(), b.Get(), changes)
+	}
+	st := src.Status()
+	if st.Error == "" || st.ErrorAt.IsZero() || st.Checksum != sum || len(reported) != 1 {
+		t.Fatalf("status=%+v reported=%v", st, reported)
+	}
+	_ = src.Reload() // same content: not reported again
+	if len(reported) != 1 {
+		t.Fatalf("reported=%v", reported)
+	}
+
+	writeTestFile(t, path, "a=3\nb=3\n")
+	if err := src.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if a.Get() != 3 || b.Get() != 3 || changes != 2 || src.Status().Error != "" {
+		t.Fatalf("a=%d b=%d changes=%d status=%+v", a.Get(), b.Get(), changes, src.Status())
+	}
+
+	// A missing file keeps the last content.
+	_ = os.Remove(path)
+	if err := src.Reload(); err == nil || a.Get() != 3 || src.Status().Error == "" {
+		t.Fatalf("err=%v a=%d status=%+v", err, a.Get(), src.Status())
+	}
+}
+
+func TestFileSource_DirectoryAndLateKeys(t *testing.T) {
+	tu := New()
+	d, _ := tu.Duration("timeout", time.Second)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "10-base.json"), `{"timeout": "2s", "late.list": ["x", "y"]}`)
+	writeTestFile(t, filepath.Join(dir, "20-extra.conf"), "late.flag=on\n")
+	writeTestFile(t, filepath.Join(dir, ".hidden"), "timeout=9s\n")
+
+	src, err := OpenFileSource(tu, dir, WithFileSourceInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if d.Get() != 2*time.Second {
+		t.Fatalf("timeout=%v", d.Get())
+	}
+	if st := src.Status(); !reflect.DeepEqual(st.Unknown, []string{"late.flag", "late.list"}) {
+		t.Fatalf("status=%+v", st)
+	}
+
+	flag, _ := tu.Bool("late.flag", false)
+	list, _ := Register(tu, "late.list", nil, StringListCodec())
+	if !flag.Get() || flag.Source() != SourceFile || !reflect.DeepEqual(list.Get(), []string{"x", "y"}) {
+		t.Fatalf("flag=%v (%v) list=%v", flag.Get(), flag.Source(), list.Get())
+	}
+	if st := src.Status(); len(st.Unknown) != 0 || len(st.Keys) != 3 {
+		t.Fatalf("status=%+v", st)
+	}
+
+	// A key set in two files is an error.
+	writeTestFile(t, filepath.Join(dir, "30-dup.conf"), "timeout=3s\n")
+	if err := src.Reload(); err == nil || d.Get() != 2*time.Second {
+		t.Fatalf("err=%v timeout=%v", err, d.Get())
+	}
+}
+
+func TestFileSource_TTLRevertsToNewFileValue(t *testing.T) {
+	tu := New()
+	n, _ := tu.Int64("n", 1)
+	path := filepath.Join(t.TempDir(), "tuning.conf")
+	writeTestFile(t, path, "n=5\n")
+	src, err := OpenFileSource(tu, path, WithFileSourceInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	_ = n.SetWithTTL(9, 50*time.Millisecond)
+	writeTestFile(t, path, "n=6\n")
+	if err := src.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if n.Get() != 9 {
+		t.Fatalf("n=%d", n.Get())
+	}
+	waitFor(t, func() bool { return n.Get() == 6 })
+	if n.Source() != SourceFile {
+		t.Fatalf("source=%v", n.Source())
+	}
+}
+
+func TestOpenFileSource_Errors(t *testing.T) {
+	tu := New()
+	_, _ = tu.Int64("n", 1)
+	dir := t.TempDir()
+	if _, err := OpenFileSource(tu, filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected error for a missing file")
+	}
+	path := filepath.Join(dir, "bad.conf")
+	writeTestFile(t, path, "n=x\n")
+	if _, err := OpenFileSource(tu, path); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	writeTestFile(t, path, "n\n")
+	if _, err := OpenFileSource(tu, path); err == nil {
+		t.Fatal("expected error for a malformed line")
+	}
+	if len(tu.Layers()) != 0 {
+		t.Fatalf("layers=%+v", tu.Layers())
+	}
+}
+
+func TestFileSource_Polls(t *testing.T) {
+	tu := New()
+	n, _ := tu.Int64("n", 1)
+	path := filepath.Join(t.TempDir(), "tuning.conf")
+	writeTestFile(t, path, "n=2\n")
+	src, err := OpenFileSource(tu, path, WithFileSourceInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	writeTestFile(t, path, "n=3\n")
+	waitFor(t, func() bool { return n.Get() == 3 })
+}
+
+func TestParseLayerContent(t *testing.T) {
+	items, err := ParseLayerContent([]byte(`{"b": 5, "a": "x", "c": ["u","v"]}`))
+	want := []OverrideItem{{Key: "a", Value: "x"}, {Key: "b", Value: "5"}, {Key: "c", Value: `["u","v"]`}}
+	if err != nil || !reflect.DeepEqual(items, want) {
+		t.Fatalf("items=%+v err=%v", items, err)
+	}
+	for name, in := range map[string]string{
+		"object":   `{"a": 1, "b": 2, "a": 3}`,
+		"lines":    "a=1\nb=2\na=3\n",
+		"trailing": `{"a": 1} {"b": 2}`,
+	} {
+		if _, err := ParseLayerContent([]byte(in)); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFileSource_ConfigMapSymlinks(t *testing.T) {
+	// Kubernetes ConfigMap layout: key -> ..data/key, ..data -> ..<timestamp>/.
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "..2024_01_01"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, "..2024_01_01", "limits.conf"), "limit=7\n")
+	if err := os.Symlink("..2024_01_01", filepath.Join(dir, "..data")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "limits.conf"), filepath.Join(dir, "limits.conf")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("..data", filepath.Join(dir, "subdir")); err != nil { // a link to a directory is skipped
+		t.Fatal(err)
+	}
+
+	tu := New()
+	limit, _ := tu.Int64("limit", 1)
+	src, err := OpenFileSource(tu, dir, WithFileSourceInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if limit.Get() != 7 || !reflect.DeepEqual(src.Status().Keys, []string{"limit"}) {
+		t.Fatalf("limit=%d status=%+v", limit.Get(), src.Status())
+	}
+}
@@ -0,0 +1,195 @@
+package tuning
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+type fileStoreConfig struct {
+	onError func(error)
+}
+
+// FileStoreOption configures a FileStore.
+type FileStoreOption func(*fileStoreConfig)
+
+// WithFileStoreOnError sets a function that receives background errors: failed rewrites of the
+// overrides file and pending overrides that cannot be applied when their key is registered.
+//
+// By default such errors are dropped (a failed rewrite is retried on the next change).
+func WithFileStoreOnError(fn func(error)) FileStoreOption {
+	return func(c *fileStoreConfig) { c.onError = fn }
+}
+
+// FileStore persists the overrides of a Tuning to a JSON file, so runtime changes survive
+// restarts.
+//
+// OpenFileStore restores the overrides from the file, then rewrites the file after every
+// successful write to the Tuning. The file has the format of ExportOverridesJSON, but values
+// are NOT redacted, so it is written with mode 0600.
+type FileStore struct {
+	t    *Tuning
+	path string
+	cfg  fileStoreConfig
+
+	loaded ImportResult
+
+	remove    func()
+	dirty     chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+
+	writeMu sync.Mutex
+	written []byte // last content written (or read); protected by writeMu
+}
+
+// OpenFileStore restores the overrides stored at path into t and starts persisting changes.
+//
+// A missing or empty file means no overrides. Overrides for keys that are not registered yet
+// are kept pending and applied when the key is registered (see WithImportPending), so the store
+// can be opened before all variables exist. Overrides that fail validation are skipped and
+// reported by Loaded; they are dropped from the file on the next rewrite.
+//
+// An unreadable or malformed file is returned as an error and left untouched.
+//
+// Rewrites happen on a background goroutine (writes to t never wait for disk I/O) and replace
+// the file atomically (temp file + rename). Call Close to stop the store and flush the final
+// state.
+func OpenFileStore(t *Tuning, path string, opts ...FileStoreOption) (*FileStore, error) {
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w: empty file store path", ErrInvalidConfig)
+	}
+	s := &FileStore{
+		t:     t,
+		path:  path,
+		dirty: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&s.cfg)
+		}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("tuning: file store: %w", err)
+	}
+	if len(bytes.TrimSpace(b)) > 0 {
+		var items []OverrideItem
+		if err := json.Unmarshal(b, &items); err != nil {
+			return nil, fmt.Errorf("tuning: file store: %s: %w", path, err)
+		}
+		s.loaded = t.ImportOverrides(items, WithImportPending(func(key string, err error) {
+			s.reportError(fmt.Errorf("tuning: file store: pending override: %w", ItemError{Key: key, Err: err}))
+		}))
+		s.written = b
+	}
+
+	s.remove = t.OnChange(func(Change) {
+		select {
+		case s.dirty <- struct{}{}:
+		default:
+		}
+	})
+	s.wg.Add(1)
+	go s.loop()
+	return s, nil
+}
+
+// Path returns the overrides file path.
+func (s *FileStore) Path() string { return s.path }
+
+// Loaded returns the result of restoring the overrides file in OpenFileStore.
+func (s *FileStore) Loaded() ImportResult { return s.loaded }
+
+// Flush writes the current overrides (including pending ones) to the file now.
+// The file is not rewritten if its content would not change.
+func (s *FileStore) Flush() error {
+	// Snapshot under writeMu, so a concurrent Flush never writes an older snapshot after a newer one.
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	items := append(s.t.exportOverrides(true), s.t.pendingOverrides()...)
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	b, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("tuning: file store: %w", err)
+	}
+	b = append(b, '\n')
+	if bytes.Equal(b, s.written) {
+		return nil
+	}
+	if err := writeFileAtomic(s.path, b); err != nil {
+		return fmt.Errorf("tuning: file store: %w", err)
+	}
+	s.written = b
+	return nil
+}
+
+// Close stops persisting changes and flushes the final state. It is idempotent.
+func (s *FileStore) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.remove()
+		close(s.done)
+		s.wg.Wait()
+		err = s.Flush()
+	})
+	return err
+}
+
+func (s *FileStore) loop() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.dirty:
+			if err := s.Flush(); err != nil {
+				s.reportError(err)
+			}
+		}
+	}
+}
+
+func (s *FileStore) reportError(err error) {
+	if s.cfg.onError == nil {
+		return
+	}
+	defer func() { _ = recover() }()
+	s.cfg.onError(err)
+}
+
+// writeFileAtomic replaces path with b via a synced temp file in the same directory.
+func writeFileAtomic(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package tuning
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readOverridesFile(t *testing.T, path string) []OverrideItem {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []OverrideItem
+	if err := json.Unmarshal(b, &items); err != nil {
+		t.Fatalf("unmarshal %q: %v", b, err)
+	}
+	return items
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+
+	tu := New()
+	n, _ := tu.Int64("n", 1)
+	secret, _ := tu.String("secret", "", WithRedactString())
+	s, err := OpenFileStore(tu, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Set(7); err != nil {
+		t.Fatal(err)
+	}
+	if err := secret.Set("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	items := readOverridesFile(t, path)
+	if len(items) != 2 || items[0] != (OverrideItem{Key: "n", Type: TypeInt64, Value: "7"}) ||
+		items[1].Value != "hunter2" {
+		t.Fatalf("file=%+v", items)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("stat: %v %v", fi, err)
+	}
+
+	// Restart: registered keys are restored immediately, late keys on registration.
+	tu2 := New()
+	n2, _ := tu2.Int64("n", 1)
+	s2, err := OpenFileStore(tu2, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s2.Close()
+	if n2.Get() != 7 || len(s2.Loaded().Applied) != 1 || len(s2.Loaded().Pending) != 1 {
+		t.Fatalf("n=%d loaded=%+v", n2.Get(), s2.Loaded())
+	}
+	// Pending overrides survive rewrites that happen before registration.
+	if err := n2.ResetToDefault(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if items := readOverridesFile(t, path); len(items) != 1 || items[0].Key != "secret" {
+		t.Fatalf("file after reset=%+v", items)
+	}
+	secret2, _ := tu2.String("secret", "", WithRedactString())
+	if secret2.Get() != "hunter2" {
+		t.Fatalf("secret not restored: %q", secret2.Get())
+	}
+}
+
+func TestFileStore_MissingAndMalformed(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenFileStore(New(), filepath.Join(dir, "none.json"))
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileStore(New(), bad); err == nil {
+		t.Fatalf("expected error for malformed file")
+	}
+	if b, _ := os.ReadFile(bad); string(b) != "{" {
+		t.Fatalf("malformed file was rewritten: %q", b)
+	}
+}
@@ -0,0 +1,391 @@
+package tuning
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// TypeFlag is the type of flags registered with Tuning.Flag.
+const TypeFlag Type = "flag"
+
+// FlagSpec is the configuration of a flag (see Tuning.Flag). It is evaluated per Subject, in order:
+//  1. Deny: subjects whose ID is listed are off.
+//  2. Allow: subjects whose ID is listed are on.
+//  3. Rules: subjects matching any attribute rule are on.
+//  4. Percent: the remaining subjects are on if a stable hash of (flag key, subject ID) falls
+//     below Percent. Raising Percent only adds subjects. Subjects without an ID are on only at 100.
+//
+// Its string form (SetFromString, ExportOverrides, /tuning/snapshot) is a list of clauses
+// separated by ";", e.g. "25%; allow=alice,bob; deny=eve; attr:region=eu,us". "on" and "off"
+// are shorthands for 100% and 0%. The JSON form of FlagSpec is accepted as well.
+type FlagSpec struct {
+	Percent float64    `json:"percent"` // 0..100
+	Allow   []string   `json:"allow,omitempty"`
+	Deny    []string   `json:"deny,omitempty"`
+	Rules   []FlagRule `json:"rules,omitempty"`
+}
+
+// FlagRule matches subjects whose attribute Attr is one of Values.
+type FlagRule struct {
+	Attr   string   `json:"attr"`
+	Values []string `json:"values"`
+}
+
+// String returns the clause form of s (see FlagSpec).
+func (s FlagSpec) String() string {
+	parts := []string{strconv.FormatFloat(s.Percent, 'g', -1, 64) + "%"}
+	if len(s.Allow) > 0 {
+		parts = append(parts, "allow="+strings.Join(s.Allow, ","))
+	}
+	if len(s.Deny) > 0 {
+		parts = append(parts, "deny="+strings.Join(s.Deny, ","))
+	}
+	for _, r := range s.Rules {
+		parts = append(parts, "attr:"+r.Attr+"="+strings.Join(r.Values, ","))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseFlagSpec parses the string form of a FlagSpec (see FlagSpec) and validates it.
+func ParseFlagSpec(s string) (FlagSpec, error) {
+	var spec FlagSpec
+	if isJSONValue(s, '{') {
+		if err := decodeJSONStrict(s, &spec); err != nil {
+			return FlagSpec{}, err
+		}
+		return spec, spec.validate()
+	}
+	for _, clause := range strings.Split(s, ";") {
+		clause = strings.TrimSpace(clause)
+		switch {
+		case clause == "":
+		case strings.EqualFold(clause, "on"):
+			spec.Percent = 100
+		case strings.EqualFold(clause, "off"):
+			spec.Percent = 0
+		case strings.HasSuffix(clause, "%"):
+			p, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(clause, "%")), 64)
+			if err != nil {
+				return FlagSpec{}, fmt.Errorf("invalid percentage %q", clause)
+			}
+			spec.Percent = p
+		default:
+			name, list, ok := strings.Cut(clause, "=")
+			if !ok {
+				return FlagSpec{}, fmt.Errorf("invalid clause %q (want N%%, allow=, deny= or attr:NAME=)", clause)
+			}
+			name = strings.TrimSpace(name)
+			vals := splitFlagList(list)
+			switch {
+			case name == "allow":
+				spec.Allow = append(spec.Allow, vals...)
+			case name == "deny":
+				spec.Deny = append(spec.Deny, vals...)
+			case strings.HasPrefix(name, "attr:"):
+				spec.Rules = append(spec.Rules, FlagRule{Attr: strings.TrimSpace(strings.TrimPrefix(name, "attr:")), Values: vals})
+			default:
+				return FlagSpec{}, fmt.Errorf("unknown clause %q (want N%%, allow=, deny= or attr:NAME=)", name)
+			}
+		}
+	}
+	return spec, spec.validate()
+}
+
+func splitFlagList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (s FlagSpec) validate() error {
+	if math.IsNaN(s.Percent) || s.Percent < 0 || s.Percent > 100 {
+		return fmt.Errorf("percent must be within [0, 100], got %v", s.Percent)
+	}
+	for _, id := range append(append([]string(nil), s.Allow...), s.Deny...) {
+		if id == "" || strings.ContainsAny(id, ",;") {
+			return fmt.Errorf("invalid subject id %q", id)
+		}
+	}
+	for _, r := range s.Rules {
+		if r.Attr == "" || strings.ContainsAny(r.Attr, "=;") {
+			return fmt.Errorf("invalid rule attribute %q", r.Attr)
+		}
+		if len(r.Values) == 0 {
+			return fmt.Errorf("rule %q has no values", r.Attr)
+		}
+		for _, v := range r.Values {
+			if v == "" || strings.ContainsAny(v, ",;") {
+				return fmt.Errorf("rule %q: invalid value %q", r.Attr, v)
+			}
+		}
+	}
+	return nil
+}
+
+// FlagCodec is the Codec of FlagSpec (the clause form; see FlagSpec).
+func FlagCodec() Codec[FlagSpec] {
+	return Codec[FlagSpec]{
+		Type:   TypeFlag,
+		Parse:  ParseFlagSpec,
+		Format: FlagSpec.String,
+	}
+}
+
+// Subject is what a flag is evaluated for: a stable ID (user, tenant, ...) and optional
+// attributes matched by FlagSpec.Rules.
+type Subject struct {
+	ID    string
+	Attrs map[string]string
+}
+
+type subjectKey struct{}
+
+// ContextWithSubject returns a copy of ctx carrying s, for FlagVar.EnabledContext.
+func ContextWithSubject(ctx context.Context, s Subject) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, subjectKey{}, s)
+}
+
+// SubjectFromContext returns the Subject set by ContextWithSubject.
+func SubjectFromContext(ctx context.Context) (Subject, bool) {
+	if ctx == nil {
+		return Subject{}, false
+	}
+	s, ok := ctx.Value(subjectKey{}).(Subject)
+	return s, ok
+}
+
+// FlagStats are the evaluation counters of a flag since it was registered.
+type FlagStats struct {
+	Evaluations uint64 `json:"evaluations"`
+	Enabled     uint64 `json:"enabled"` // evaluations that returned true
+
+	// Evaluations decided by the deny list, the allow list and the attribute rules;
+	// the rest were decided by the percentage.
+	Deny  uint64 `json:"deny"`
+	Allow uint64 `json:"allow"`
+	Rule  uint64 `json:"rule"`
+}
+
+type flagConfig struct {
+	meta     Meta
+	onChange []func(FlagSpec)
+}
+
+// FlagOption configures a FlagVar at registration time.
+type FlagOption func(*flagConfig)
+
+// WithMetaFlag sets descriptive metadata (description, unit, owner, ...) shown in Snapshot.
+func WithMetaFlag(m Meta) FlagOption {
+	return func(c *flagConfig) { c.meta = m }
+}
+
+// WithOnChangeFlag appends an onChange callback (same rules as WithOnChangeBool).
+func WithOnChangeFlag(fn func(newValue FlagSpec)) FlagOption {
+	return func(c *flagConfig) {
+		if fn != nil {
+			c.onChange = append(c.onChange, fn)
+		}
+	}
+}
+
+// Flag registers a feature flag evaluated per Subject (see FlagSpec) and returns its handle.
+//
+// The flag is an ordinary variable of type TypeFlag: it is set from admin like any other key
+// (SetFromString with the clause form, e.g. "10%; allow=alice"), and its spec is shown in
+// Snapshot. Evaluation is lock-free.
+func (t *Tuning) Flag(key string, defaultValue FlagSpec, opts ...FlagOption) (*FlagVar, error) {
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
+	}
+	var cfg flagConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	vopts := []VarOption[FlagSpec]{WithValidateVar(FlagSpec.validate), WithMetaVar[FlagSpec](cfg.meta)}
+	for _, fn := range cfg.onChange {
+		vopts = append(vopts, WithOnChangeVar(fn))
+	}
+
+	v, err := Register(t, key, defaultValue, FlagCodec(), vopts...)
+	if err != nil {
+		return nil, err
+	}
+	f := &FlagVar{Var: v}
+	t.mu.Lock()
+	if t.flags == nil {
+		t.flags = make(map[string]*FlagVar)
+	}
+	t.flags[key] = f
+	t.mu.Unlock()
+	return f, nil
+}
+
+// FlagVar is a feature flag registered with Tuning.Flag. The embedded Var holds its FlagSpec
+// (Get / Set / ResetToDefault / ...).
+type FlagVar struct {
+	*Var[FlagSpec]
+
+	compiled atomic.Pointer[compiledFlag]
+
+	evals, enabled, deny, allow, rule atomic.Uint64
+}
+
+// compiledFlag is a FlagSpec prepared for evaluation.
+type compiledFlag struct {
+	src       *FlagSpec // the value it was built from
+	threshold uint64    // subjects with bucket < threshold are on (buckets: 0..9999)
+	allow     map[string]struct{}
+	deny      map[string]struct{}
+	rules     map[string]map[string]struct{} // attr -> values
+}
+
+const flagBuckets = 10000
+
+func compileFlag(src *FlagSpec) *compiledFlag {
+	c := &compiledFlag{
+		src:       src,
+		threshold: uint64(math.Round(src.Percent * flagBuckets / 100)),
+		allow:     toSet(src.Allow),
+		deny:      toSet(src.Deny),
+	}
+	if len(src.Rules) > 0 {
+		c.rules = make(map[string]map[string]struct{}, len(src.Rules))
+		for _, r := range src.Rules {
+			if c.rules[r.Attr] == nil {
+				c.rules[r.Attr] = make(map[string]struct{}, len(r.Values))
+			}
+			for _, v := range r.Values {
+				c.rules[r.Attr][v] = struct{}{}
+			}
+		}
+	}
+	return c
+}
+
+func toSet(xs []string) map[string]struct{} {
+	if len(xs) == 0 {
+		return nil
+	}
+	m := make(map[string]struct{}, len(xs))
+	for _, x := range xs {
+		m[x] = struct{}{}
+	}
+	return m
+}
+
+// Enabled evaluates the flag for s (see FlagSpec) and updates the counters.
+func (f *FlagVar) Enabled(s Subject) bool {
+	src := f.cur.Load()
+	c := f.compiled.Load()
+	if c == nil || c.src != src {
+		c = compileFlag(src)
+		f.compiled.Store(c)
+	}
+
+	f.evals.Add(1)
+	var on bool
+	switch {
+	case has(c.deny, s.ID):
+		f.deny.Add(1)
+	case has(c.allow, s.ID):
+		f.allow.Add(1)
+		on = true
+	case c.matchRule(s.Attrs):
+		f.rule.Add(1)
+		on = true
+	case c.threshold >= flagBuckets:
+		on = true
+	case s.ID != "" && c.threshold > 0:
+		on = flagBucket(f.k, s.ID) < c.threshold
+	}
+	if on {
+		f.enabled.Add(1)
+	}
+	return on
+}
+
+// EnabledContext evaluates the flag for the Subject in ctx (ContextWithSubject); without one,
+// it evaluates for the zero Subject (on only at 100%).
+func (f *FlagVar) EnabledContext(ctx context.Context) bool {
+	s, _ := SubjectFromContext(ctx)
+	return f.Enabled(s)
+}
+
+// Stats returns the evaluation counters.
+func (f *FlagVar) Stats() FlagStats {
+	return FlagStats{
+		Evaluations: f.evals.Load(),
+		Enabled:     f.enabled.Load(),
+		Deny:        f.deny.Load(),
+		Allow:       f.allow.Load(),
+		Rule:        f.rule.Load(),
+	}
+}
+
+func (c *compiledFlag) matchRule(attrs map[string]string) bool {
+	for attr, vals := range c.rules {
+		if v, ok := attrs[attr]; ok && has(vals, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func has(m map[string]struct{}, k string) bool {
+	if k == "" || m == nil {
+		return false
+	}
+	_, ok := m[k]
+	return ok
+}
+
+// flagBucket maps (key, id) to a stable bucket in [0, flagBuckets).
+func flagBucket(key, id string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(id))
+	return h.Sum64() % flagBuckets
+}
+
+// FlagInfo describes a flag for ops endpoints.
+type FlagInfo struct {
+	Key   string    `json:"key"`
+	Spec  string    `json:"spec"` // clause form
+	Stats FlagStats `json:"stats"`
+}
+
+// Flags returns the registered flags with their current spec and counters, sorted by key.
+func (t *Tuning) Flags() []FlagInfo {
+	if t == nil {
+		return nil
+	}
+	t.mu.RLock()
+	flags := make([]*FlagVar, 0, len(t.flags))
+	for _, f := range t.flags {
+		flags = append(flags, f)
+	}
+	t.mu.RUnlock()
+	sort.Slice(flags, func(i, j int) bool { return flags[i].k < flags[j].k })
+
+	out := make([]FlagInfo, 0, len(flags))
+	for _, f := range flags {
+		out = append(out, FlagInfo{Key: f.k, Spec: f.Get().String(), Stats: f.Stats()})
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package tuning
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseFlagSpec(t *testing.T) {
+	spec, err := ParseFlagSpec(" 25% ; allow=alice, bob; deny=eve; attr:region=eu,us ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := FlagSpec{Percent: 25, Allow: []string{"alice", "bob"}, Deny: []string{"eve"}, Rules: []FlagRule{{Attr: "region", Values: []string{"eu", "us"}}}}
+	if !reflect.DeepEqual(spec, want) {
+		t.Fatalf("spec=%+v", spec)
+	}
+	if s := spec.String(); s != "25%; allow=alice,bob; deny=eve; attr:region=eu,us" {
+		t.Fatalf("String()=%q", s)
+	}
+	if spec, err := ParseFlagSpec(`{"percent":5,"allow":["x"]}`); err != nil || spec.Percent != 5 {
+		t.Fatalf("spec=%+v err=%v", spec, err)
+	}
+	if spec, err := ParseFlagSpec("on"); err != nil || spec.Percent != 100 {
+		t.Fatalf("spec=%+v err=%v", spec, err)
+	}
+	for _, bad := range []string{"101%", "x%", "nope", "maybe=1", "attr:=a", "attr:r=", `{"percent":1,"x":1}`} {
+		if _, err := ParseFlagSpec(bad); err == nil {
+			t.Fatalf("%q: want error", bad)
+		}
+	}
+}
+
+func TestFlag_Evaluate(t *testing.T) {
+	tu := New()
+	f, err := tu.Flag("checkout.v2", FlagSpec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Enabled(Subject{ID: "alice"}) {
+		t.Fatalf("default spec must be off")
+	}
+
+	if err := tu.SetFromString("checkout.v2", "30%; allow=alice; deny=bob; attr:plan=beta"); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Enabled(Subject{ID: "alice"}) || f.Enabled(Subject{ID: "bob", Attrs: map[string]string{"plan": "beta"}}) {
+		t.Fatalf("allow/deny not applied")
+	}
+	if !f.EnabledContext(ContextWithSubject(context.Background(), Subject{ID: "carol", Attrs: map[string]string{"plan": "beta"}})) {
+		t.Fatalf("rule not applied")
+	}
+	if f.EnabledContext(context.Background()) {
+		t.Fatalf("no subject must be off below 100%%")
+	}
+
+	// The percentage is stable and monotonic.
+	on := map[string]bool{}
+	n := 0
+	for i := 0; i < 2000; i++ {
+		id := "u" + strconv.Itoa(i)
+		on[id] = f.Enabled(Subject{ID: id})
+		if on[id] {
+			n++
+		}
+	}
+	if n < 450 || n > 750 {
+		t.Fatalf("30%% rollout enabled %d of 2000", n)
+	}
+	_ = tu.SetFromString("checkout.v2", "60%")
+	for id, was := range on {
+		if was && !f.Enabled(Subject{ID: id}) {
+			t.Fatalf("%s dropped when raising the percentage", id)
+		}
+	}
+
+	st := f.Stats()
+	if st.Allow != 1 || st.Deny != 1 || st.Rule != 1 || st.Evaluations < 2004 || st.Enabled == 0 {
+		t.Fatalf("stats=%+v", st)
+	}
+	if fl := tu.Flags(); len(fl) != 1 || fl[0].Key != "checkout.v2" || fl[0].Spec != "60%" {
+		t.Fatalf("flags=%+v", fl)
+	}
+	if it, _ := tu.Lookup("checkout.v2"); it.Type != TypeFlag {
+		t.Fatalf("item=%+v", it)
+	}
+	if ovs := tu.ExportOverrides(); len(ovs) != 1 || ovs[0].Value != "60%" {
+		t.Fatalf("overrides=%+v", ovs)
+	}
+
+	if err := tu.SetFromString("checkout.v2", "150%"); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if _, err := tu.Flag("checkout.v2", FlagSpec{}); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("err=%v", err)
+	}
+	if _, err := tu.Flag("bad", FlagSpec{Percent: -1}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("err=%v", err)
+	}
+}
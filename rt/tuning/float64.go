@@ -10,6 +10,7 @@ import (
 
 type float64Config struct {
 	redact bool
+	meta   Meta
 
 	hasMin bool
 	min    float64
@@ -27,6 +28,11 @@ func WithRedactFloat64() Float64Option {
 	return func(c *float64Config) { c.redact = true }
 }
 
+// WithMetaFloat64 sets descriptive metadata (description, unit, owner, ...) shown in Snapshot.
+func WithMetaFloat64(m Meta) Float64Option {
+	return func(c *float64Config) { c.meta = m }
+}
+
 // WithMinFloat64 sets a minimum constraint (inclusive).
 func WithMinFloat64(min float64) Float64Option {
 	return func(c *float64Config) {
@@ -89,8 +95,8 @@ func (t *Tuning) Float64(key string, defaultValue float64, opts ...Float64Option
 		defForSnap: defaultValue,
 	}
 	v.curBits.Store(v.defBits)
-	v.source.Store(int32(SourceDefault))
 
+	v.meta = cfg.meta.clone()
 	if err := t.register(key, v); err != nil {
 		return nil, err
 	}
@@ -115,19 +121,14 @@ type Float64Var struct {
 	// defForSnap keeps the original default for Snapshot (to avoid bits->float surprises).
 	defForSnap float64
 
-	source atomic.Int32 // Source
-
-	lastUpdatedAtUnixNano atomic.Int64
-
-	// last/hasLast are protected by Tuning's write gate.
-	hasLast  bool
-	lastBits uint64
+	varState
 
 	onChange []func(float64)
 }
 
-func (v *Float64Var) key() string { return v.k }
-func (v *Float64Var) typ() Type   { return TypeFloat64 }
+func (v *Float64Var) key() string    { return v.k }
+func (v *Float64Var) redacted() bool { return v.redact }
+func (v *Float64Var) typ() Type      { return TypeFloat64 }
 
 func (v *Float64Var) Key() string { return v.k }
 
@@ -138,15 +139,9 @@ func (v *Float64Var) Get() float64 {
 	return math.Float64frombits(v.curBits.Load())
 }
 
-func (v *Float64Var) Source() Source { return Source(v.source.Load()) }
+func (v *Float64Var) Source() Source { return v.loadSource() }
 
-func (v *Float64Var) LastUpdatedAt() time.Time {
-	ns := v.lastUpdatedAtUnixNano.Load()
-	if ns == 0 {
-		return time.Time{}
-	}
-	return time.Unix(0, ns)
-}
+func (v *Float64Var) LastUpdatedAt() time.Time { return v.updatedAt() }
 
 func (v *Float64Var) Set(newValue float64) error {
 	if v.t == nil {
@@ -159,75 +154,42 @@ func (v *Float64Var) Set(newValue float64) error {
 		return err
 	}
 
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	old := v.curBits.Load()
-	v.hasLast = true
-	v.lastBits = old
-
-	nb := math.Float64bits(newValue)
-	v.curBits.Store(nb)
-	if nb == v.defBits {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
-
-	for _, cb := range v.onChange {
-		safeCallFloat64(cb, newValue)
-	}
-	return nil
+	return v.t.setEntry(v, newValue, writeMeta{cause: CauseSet})
 }
 
-func (v *Float64Var) ResetToDefault() error { return v.Set(v.defForSnap) }
+func (v *Float64Var) ResetToDefault() error {
+	return v.t.resetEntryToBase(v, writeMeta{cause: CauseResetDefault})
+}
 
 func (v *Float64Var) ResetToLastValue() error {
 	if v.t == nil {
 		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
+	return v.t.resetEntryToLast(v, writeMeta{cause: CauseResetLast})
+}
 
-	if !v.hasLast {
-		return fmt.Errorf("%w: %q", ErrNoLastValue, v.k)
+func (v *Float64Var) parse(s string) (any, error) {
+	f, err := parseFloat64(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q expects float64, got %q: %v", ErrInvalidValue, v.k, s, err)
 	}
-	nb := v.lastBits
-	v.hasLast = false
-
-	newValue := math.Float64frombits(nb)
-	if err := validateFloat64Value(v.k, newValue, float64Config{
+	if err := validateFloat64Value(v.k, f, float64Config{
 		hasMin: v.hasMin, min: v.min,
 		hasMax: v.hasMax, max: v.max,
 	}); err != nil {
-		return err
+		return nil, err
 	}
+	return f, nil
+}
 
-	v.curBits.Store(nb)
-	if nb == v.defBits {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
+func (v *Float64Var) load() any         { return v.Get() }
+func (v *Float64Var) defaultValue() any { return v.defForSnap }
+func (v *Float64Var) store(x any)       { v.curBits.Store(math.Float64bits(x.(float64))) }
 
+func (v *Float64Var) callOnChange(x any) {
 	for _, cb := range v.onChange {
-		safeCallFloat64(cb, newValue)
+		safeCallFloat64(cb, x.(float64))
 	}
-	return nil
-}
-
-func (v *Float64Var) setFromString(s string) error {
-	f, err := parseFloat64(s)
-	if err != nil {
-		return fmt.Errorf("%w: %q expects float64, got %q: %v", ErrInvalidValue, v.k, s, err)
-	}
-	return v.Set(f)
 }
 
 func (v *Float64Var) snapshot() Item {
@@ -258,17 +220,7 @@ func (v *Float64Var) snapshot() Item {
 	}
 }
 
-func (v *Float64Var) override() (OverrideItem, bool) {
-	cb := v.curBits.Load()
-	if cb == v.defBits {
-		return OverrideItem{}, false
-	}
-	if v.redact {
-		return OverrideItem{Key: v.k, Type: TypeFloat64, Value: "<redacted>"}, true
-	}
-	cur := math.Float64frombits(cb)
-	return OverrideItem{Key: v.k, Type: TypeFloat64, Value: strconv.FormatFloat(cur, 'g', -1, 64)}, true
-}
+func (v *Float64Var) format(x any) string { return strconv.FormatFloat(x.(float64), 'g', -1, 64) }
 
 func validateFloat64Value(key string, v float64, cfg float64Config) error {
 	if math.IsNaN(v) || math.IsInf(v, 0) {
@@ -0,0 +1,189 @@
+package tuning
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultHistoryLimit is the per-key history size unless WithHistoryLimit is used.
+const defaultHistoryLimit = 32
+
+// Cause identifies the API that made a write recorded in the history.
+type Cause string
+
+const (
+	CauseSet          Cause = "set"           // Set / SetFromString / SetAny
+	CauseSetTTL       Cause = "set-ttl"       // SetWithTTL / SetFromStringWithTTL
+	CauseTTLExpiry    Cause = "ttl-expiry"    // automatic revert of a temporary value
+	CauseResetDefault Cause = "reset-default" // ResetToDefault
+	CauseResetLast    Cause = "reset-last"    // ResetToLastValue
+	CauseUndo         Cause = "undo"          // Undo
+	CauseImport       Cause = "import"        // ImportOverrides / FileStore / pending overrides
+	CauseApply        Cause = "apply"         // Apply
+	CauseFile         Cause = "file"          // a FileSource load (or its removal)
+	CauseRemote       Cause = "remote"        // a remote source load (package tuningremote)
+)
+
+// HistoryEntry describes one recorded write of a variable.
+type HistoryEntry struct {
+	At time.Time `json:"at"`
+
+	// Old / New are the effective values before and after the write.
+	// If the variable is redacted, both are "<redacted>".
+	Old any `json:"old"`
+	New any `json:"new"`
+
+	// Source is the source of New.
+	Source Source `json:"source"`
+	Cause  Cause  `json:"cause"`
+
+	// Actor is the actor attached to the write's context (ContextWithActor), if any.
+	Actor string `json:"actor,omitempty"`
+}
+
+// historyRecord is a HistoryEntry with unredacted values (needed by Undo).
+type historyRecord struct {
+	at       time.Time
+	old, new any
+	source   Source
+	cause    Cause
+	actor    string
+}
+
+// writeMeta describes a write for the history.
+type writeMeta struct {
+	cause Cause
+	actor string
+}
+
+func metaFrom(ctx context.Context, c Cause) writeMeta {
+	return writeMeta{cause: c, actor: ActorFromContext(ctx)}
+}
+
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx carrying actor (e.g. a user or client identity).
+// Writes made through the *Context methods record it in the history.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by ContextWithActor, or "".
+func ActorFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	s, _ := ctx.Value(actorKey{}).(string)
+	return s
+}
+
+type config struct {
+	historyLimit int
+}
+
+// Option configures a Tuning created by New.
+type Option func(*config)
+
+// WithHistoryLimit sets how many writes are kept per key (default 32). n <= 0 disables the
+// history (and Undo).
+func WithHistoryLimit(n int) Option {
+	return func(c *config) {
+		if n <= 0 {
+			n = -1
+		}
+		c.historyLimit = n
+	}
+}
+
+func (t *Tuning) historyCap() int {
+	switch {
+	case t.historyLimit < 0:
+		return 0
+	case t.historyLimit == 0:
+		return defaultHistoryLimit
+	default:
+		return t.historyLimit
+	}
+}
+
+// recordLocked appends r to v's history. The caller holds the write gate.
+func (t *Tuning) recordLocked(v varEntry, r historyRecord) {
+	limit := t.historyCap()
+	if limit == 0 {
+		return
+	}
+	st := v.state()
+	st.histMu.Lock()
+	if len(st.history) >= limit {
+		n := copy(st.history, st.history[len(st.history)-limit+1:])
+		clear(st.history[n:])
+		st.history = st.history[:n]
+	}
+	st.history = append(st.history, r)
+	st.histMu.Unlock()
+}
+
+func (s *varState) historyRecords() []historyRecord {
+	s.histMu.Lock()
+	defer s.histMu.Unlock()
+	return append([]historyRecord(nil), s.history...)
+}
+
+// History returns the recorded writes of key, oldest first. Values are redacted following the
+// same rules as Snapshot().
+//
+// The history is bounded per key (WithHistoryLimit); it only covers writes since the process
+// started.
+func (t *Tuning) History(key string) ([]HistoryEntry, error) {
+	v, err := t.lookupEntry(key)
+	if err != nil {
+		return nil, err
+	}
+	recs := v.state().historyRecords()
+	out := make([]HistoryEntry, 0, len(recs))
+	for _, r := range recs {
+		e := HistoryEntry{At: r.at, Old: r.old, New: r.new, Source: r.source, Cause: r.cause, Actor: r.actor}
+		if v.redacted() {
+			e.Old, e.New = "<redacted>", "<redacted>"
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// Undo rolls key back steps writes: it restores the value key had before the steps-th most
+// recent write in its history. steps must be between 1 and the number of recorded writes
+// (ErrNoLastValue otherwise).
+//
+// Undo is itself a write (recorded with CauseUndo, and undoable with ResetToLastValue), so
+// Undo(ctx, key, 1) twice returns to where you started. The actor in ctx (ContextWithActor) is
+// recorded in the history.
+func (t *Tuning) Undo(ctx context.Context, key string, steps int) error {
+	v, err := t.lookupEntry(key)
+	if err != nil {
+		return err
+	}
+	if steps < 1 {
+		return fmt.Errorf("%w: %q undo steps must be >= 1, got %d", ErrInvalidValue, key, steps)
+	}
+	if err := t.lockWrite(); err != nil {
+		return err
+	}
+	defer t.unlockWrite()
+
+	st := v.state()
+	recs := st.historyRecords()
+	if steps > len(recs) {
+		return fmt.Errorf("%w: %q has %d recorded writes, cannot undo %d", ErrNoLastValue, key, len(recs), steps)
+	}
+	x := recs[len(recs)-steps].old
+
+	old := t.commitLocked(v, x, time.Now(), metaFrom(ctx, CauseUndo))
+	st.hasLast, st.last = true, old
+	t.notifyLocked(v, old)
+	return nil
+}
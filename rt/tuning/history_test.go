@@ -0,0 +1,102 @@
+package tuning
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestHistory_RecordsCauseActorAndRedacts(t *testing.T) {
+	tu := New()
+	n, _ := tu.Int64("n", 1)
+	_, _ = tu.String("secret", "a", WithRedactString())
+
+	ctx := ContextWithActor(context.Background(), "alice")
+	_ = n.Set(2)
+	_ = tu.SetFromStringContext(ctx, "n", "3")
+	_ = tu.ResetToLastValueContext(ctx, "n")
+	_ = n.ResetToDefault()
+	if _, err := tu.ApplyContext(ctx, []OverrideItem{{Key: "n", Value: "7"}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = tu.SetFromString("secret", "b")
+
+	h, err := tu.History("n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		cause    Cause
+		old, new int64
+		actor    string
+	}{
+		{CauseSet, 1, 2, ""},
+		{CauseSet, 2, 3, "alice"},
+		{CauseResetLast, 3, 2, "alice"},
+		{CauseResetDefault, 2, 1, ""},
+		{CauseApply, 1, 7, "alice"},
+	}
+	if len(h) != len(want) {
+		t.Fatalf("history=%+v", h)
+	}
+	for i, w := range want {
+		e := h[i]
+		if e.Cause != w.cause || e.Old != w.old || e.New != w.new || e.Actor != w.actor || e.At.IsZero() {
+			t.Fatalf("entry %d=%+v, want %+v", i, e, w)
+		}
+	}
+	if h[3].Source != SourceDefault || h[4].Source != SourceRuntimeSet {
+		t.Fatalf("sources: %v %v", h[3].Source, h[4].Source)
+	}
+
+	hs, _ := tu.History("secret")
+	if len(hs) != 1 || hs[0].Old != "<redacted>" || hs[0].New != "<redacted>" {
+		t.Fatalf("secret history=%+v", hs)
+	}
+	if _, err := tu.History("nope"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestHistory_BoundedAndUndo(t *testing.T) {
+	tu := New(WithHistoryLimit(3))
+	n, _ := tu.Int64("n", 0)
+	for i := int64(1); i <= 5; i++ {
+		_ = n.Set(i)
+	}
+	h, _ := tu.History("n")
+	if len(h) != 3 || h[0].Old != int64(2) || h[2].New != int64(5) {
+		t.Fatalf("history=%+v", h)
+	}
+
+	if err := tu.Undo(context.Background(), "n", 4); !errors.Is(err, ErrNoLastValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if err := tu.Undo(context.Background(), "n", 0); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if err := tu.Undo(context.Background(), "n", 3); err != nil {
+		t.Fatal(err)
+	}
+	if n.Get() != 2 {
+		t.Fatalf("n=%d, want 2", n.Get())
+	}
+	h, _ = tu.History("n")
+	if last := h[len(h)-1]; last.Cause != CauseUndo || last.Old != int64(5) || last.New != int64(2) {
+		t.Fatalf("last=%+v", last)
+	}
+	// Undo is a write: ResetToLastValue undoes it.
+	if err := n.ResetToLastValue(); err != nil || n.Get() != 5 {
+		t.Fatalf("n=%d err=%v", n.Get(), err)
+	}
+
+	off := New(WithHistoryLimit(0))
+	b, _ := off.Bool("b", false)
+	_ = b.Set(true)
+	if h, _ := off.History("b"); len(h) != 0 {
+		t.Fatalf("history=%+v", h)
+	}
+	if err := off.Undo(context.Background(), "b", 1); !errors.Is(err, ErrNoLastValue) {
+		t.Fatalf("err=%v", err)
+	}
+}
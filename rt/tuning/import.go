@@ -0,0 +1,178 @@
+package tuning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ItemError describes one override item rejected by ImportOverrides or Apply.
+type ItemError struct {
+	Key string
+	Err error
+}
+
+func (e ItemError) Error() string { return e.Key + ": " + e.Err.Error() }
+func (e ItemError) Unwrap() error { return e.Err }
+
+// ImportResult reports the outcome of ImportOverrides.
+type ImportResult struct {
+	// Applied lists the keys that were set, in input order.
+	Applied []string
+	// Pending lists unregistered keys kept until they are registered (see WithImportPending).
+	Pending []string
+	// Unknown lists unregistered keys that were skipped.
+	Unknown []string
+	// Invalid lists items rejected because of an invalid key, a type mismatch or an invalid value.
+	Invalid []ItemError
+}
+
+// Err returns nil if every item was applied or kept pending, otherwise an error joining all
+// problems (unknown keys wrap ErrNotFound).
+func (r ImportResult) Err() error {
+	var errs []error
+	for _, k := range r.Unknown {
+		errs = append(errs, fmt.Errorf("%w: %q", ErrNotFound, k))
+	}
+	for _, e := range r.Invalid {
+		errs = append(errs, e)
+	}
+	return errors.Join(errs...)
+}
+
+type importConfig struct {
+	pending        bool
+	onPendingError func(key string, err error)
+}
+
+// ImportOption configures ImportOverrides.
+type ImportOption func(*importConfig)
+
+// WithImportPending keeps overrides for unregistered keys and applies them when the key is
+// registered later, instead of reporting them as unknown.
+//
+// onError (optional) is called if a pending override cannot be applied at registration time
+// (type mismatch or invalid value); the override is then dropped. It runs on the registering
+// goroutine and must be fast.
+func WithImportPending(onError func(key string, err error)) ImportOption {
+	return func(c *importConfig) {
+		c.pending = true
+		c.onPendingError = onError
+	}
+}
+
+type pendingOverride struct {
+	item    OverrideItem
+	onError func(key string, err error)
+}
+
+// ImportOverrides applies previously exported overrides (see ExportOverrides), e.g. to restore
+// them at startup.
+//
+// Each item is validated against the registered variable: a non-empty Type must match the
+// registered type, and Value is parsed as in SetFromString. Items are applied one by one through
+// the normal write path (callbacks and observers run); invalid items are reported and skipped
+// without affecting the others. If the same key appears more than once, the last item wins.
+//
+// Redacted values ("<redacted>") cannot be imported and are reported as invalid.
+func (t *Tuning) ImportOverrides(items []OverrideItem, opts ...ImportOption) ImportResult {
+	var cfg importConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	var res ImportResult
+	if t == nil {
+		for _, it := range items {
+			res.Invalid = append(res.Invalid, ItemError{Key: it.Key, Err: fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)})
+		}
+		return res
+	}
+	for _, it := range items {
+		if err := validateKey(it.Key); err != nil {
+			res.Invalid = append(res.Invalid, ItemError{Key: it.Key, Err: err})
+			continue
+		}
+		t.mu.Lock()
+		v, ok := t.vars[it.Key]
+		if !ok && cfg.pending {
+			if t.pending == nil {
+				t.pending = make(map[string]pendingOverride)
+			}
+			t.pending[it.Key] = pendingOverride{item: it, onError: cfg.onPendingError}
+		}
+		t.mu.Unlock()
+		if !ok {
+			if cfg.pending {
+				res.Pending = append(res.Pending, it.Key)
+			} else {
+				res.Unknown = append(res.Unknown, it.Key)
+			}
+			continue
+		}
+		if err := t.importOverride(v, it); err != nil {
+			res.Invalid = append(res.Invalid, ItemError{Key: it.Key, Err: err})
+			continue
+		}
+		res.Applied = append(res.Applied, it.Key)
+	}
+	return res
+}
+
+// ImportOverridesJSON is ImportOverrides for the output of ExportOverridesJSON.
+func (t *Tuning) ImportOverridesJSON(b []byte, opts ...ImportOption) (ImportResult, error) {
+	var items []OverrideItem
+	if err := json.Unmarshal(b, &items); err != nil {
+		return ImportResult{}, fmt.Errorf("%w: overrides JSON: %v", ErrInvalidValue, err)
+	}
+	return t.ImportOverrides(items, opts...), nil
+}
+
+func (t *Tuning) importOverride(v varEntry, it OverrideItem) error {
+	x, err := parseOverride(v, it)
+	if err != nil {
+		return err
+	}
+	return t.setEntry(v, x, writeMeta{cause: CauseImport})
+}
+
+// parseOverride validates it against the registered variable v and returns the parsed value.
+func parseOverride(v varEntry, it OverrideItem) (any, error) {
+	if it.Type != "" && it.Type != v.typ() {
+		return nil, fmt.Errorf("%w: %q is %s, got %s", ErrTypeMismatch, it.Key, v.typ(), it.Type)
+	}
+	if v.redacted() && it.Value == "<redacted>" {
+		return nil, fmt.Errorf("%w: %q: redacted value cannot be imported", ErrInvalidValue, it.Key)
+	}
+	return v.parse(it.Value)
+}
+
+// applyPending applies a pending override for a newly registered variable, if any.
+func (t *Tuning) applyPending(v varEntry) {
+	t.mu.Lock()
+	p, ok := t.pending[v.key()]
+	if ok {
+		delete(t.pending, v.key())
+	}
+	t.mu.Unlock()
+	if !ok {
+		return
+	}
+	if err := t.importOverride(v, p.item); err != nil && p.onError != nil {
+		p.onError(p.item.Key, err)
+	}
+}
+
+// pendingOverrides returns the pending overrides sorted by key.
+func (t *Tuning) pendingOverrides() []OverrideItem {
+	t.mu.RLock()
+	out := make([]OverrideItem, 0, len(t.pending))
+	for _, p := range t.pending {
+		out = append(out, p.item)
+	}
+	t.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
@@ -0,0 +1,78 @@
+package tuning
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestImportOverrides(t *testing.T) {
+	tu := New()
+	n, _ := tu.Int64("n", 1)
+	d, _ := tu.Duration("d", time.Second)
+	secret, _ := tu.String("secret", "", WithRedactString())
+
+	res := tu.ImportOverrides([]OverrideItem{
+		{Key: "n", Type: TypeInt64, Value: "5"},
+		{Key: "d", Value: "250ms"}, // empty Type is accepted
+		{Key: "n", Type: TypeString, Value: "x"},
+		{Key: "d", Type: TypeDuration, Value: "soon"},
+		{Key: "secret", Type: TypeString, Value: "<redacted>"},
+		{Key: "bad key", Value: "1"},
+		{Key: "missing", Type: TypeBool, Value: "true"},
+	})
+	if n.Get() != 5 || d.Get() != 250*time.Millisecond || secret.Get() != "" {
+		t.Fatalf("values: n=%d d=%s secret=%q", n.Get(), d.Get(), secret.Get())
+	}
+	if len(res.Applied) != 2 || len(res.Unknown) != 1 || res.Unknown[0] != "missing" || len(res.Invalid) != 4 {
+		t.Fatalf("result=%+v", res)
+	}
+	for i, want := range []error{ErrTypeMismatch, ErrInvalidValue, ErrInvalidValue, ErrInvalidKey} {
+		if !errors.Is(res.Invalid[i], want) {
+			t.Fatalf("Invalid[%d]=%v, want %v", i, res.Invalid[i], want)
+		}
+	}
+	if err := res.Err(); !errors.Is(err, ErrNotFound) || !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("Err()=%v", err)
+	}
+
+	// Round trip through JSON.
+	b, err := tu.ExportOverridesJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tu2 := New()
+	n2, _ := tu2.Int64("n", 1)
+	_, _ = tu2.Duration("d", time.Second)
+	res, err = tu2.ImportOverridesJSON(b)
+	if err != nil || res.Err() != nil || n2.Get() != 5 {
+		t.Fatalf("round trip: res=%+v err=%v n=%d", res, err, n2.Get())
+	}
+	if _, err := tu2.ImportOverridesJSON([]byte("{")); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("malformed JSON err=%v", err)
+	}
+}
+
+func TestImportOverridesPending(t *testing.T) {
+	tu := New()
+	var pendingErrs []string
+	res := tu.ImportOverrides([]OverrideItem{
+		{Key: "late", Type: TypeBool, Value: "true"},
+		{Key: "late.bad", Type: TypeInt64, Value: "x"},
+	}, WithImportPending(func(key string, err error) { pendingErrs = append(pendingErrs, key) }))
+	if len(res.Pending) != 2 || res.Err() != nil {
+		t.Fatalf("result=%+v", res)
+	}
+
+	late, err := tu.Bool("late", false)
+	if err != nil || !late.Get() || late.Source() != SourceRuntimeSet {
+		t.Fatalf("late=%v source=%v err=%v", late.Get(), late.Source(), err)
+	}
+	bad, _ := tu.Int64("late.bad", 3)
+	if bad.Get() != 3 || len(pendingErrs) != 1 || pendingErrs[0] != "late.bad" {
+		t.Fatalf("bad=%d pendingErrs=%v", bad.Get(), pendingErrs)
+	}
+	if p := tu.pendingOverrides(); len(p) != 0 {
+		t.Fatalf("pending left: %+v", p)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 type int64Config struct {
 	redact bool
+	meta   Meta
 
 	hasMin bool
 	min    int64
@@ -26,6 +27,11 @@ func WithRedactInt64() Int64Option {
 	return func(c *int64Config) { c.redact = true }
 }
 
+// WithMetaInt64 sets descriptive metadata (description, unit, owner, ...) shown in Snapshot.
+func WithMetaInt64(m Meta) Int64Option {
+	return func(c *int64Config) { c.meta = m }
+}
+
 // WithMinInt64 sets a minimum constraint (inclusive).
 func WithMinInt64(min int64) Int64Option {
 	return func(c *int64Config) {
@@ -87,8 +93,8 @@ func (t *Tuning) Int64(key string, defaultValue int64, opts ...Int64Option) (*In
 		onChange: cfg.onChange,
 	}
 	v.cur.Store(defaultValue)
-	v.source.Store(int32(SourceDefault))
 
+	v.meta = cfg.meta.clone()
 	if err := t.register(key, v); err != nil {
 		return nil, err
 	}
@@ -108,20 +114,15 @@ type Int64Var struct {
 	hasMax bool
 	max    int64
 
-	cur    atomic.Int64
-	source atomic.Int32 // Source
-
-	lastUpdatedAtUnixNano atomic.Int64
-
-	// last/hasLast are protected by Tuning's write gate.
-	hasLast bool
-	last    int64
+	cur atomic.Int64
+	varState
 
 	onChange []func(int64)
 }
 
-func (v *Int64Var) key() string { return v.k }
-func (v *Int64Var) typ() Type   { return TypeInt64 }
+func (v *Int64Var) key() string    { return v.k }
+func (v *Int64Var) redacted() bool { return v.redact }
+func (v *Int64Var) typ() Type      { return TypeInt64 }
 
 func (v *Int64Var) Key() string { return v.k }
 
@@ -130,15 +131,9 @@ func (v *Int64Var) Key() string { return v.k }
 // It is lock-free, allocation-free and non-blocking.
 func (v *Int64Var) Get() int64 { return v.cur.Load() }
 
-func (v *Int64Var) Source() Source { return Source(v.source.Load()) }
+func (v *Int64Var) Source() Source { return v.loadSource() }
 
-func (v *Int64Var) LastUpdatedAt() time.Time {
-	ns := v.lastUpdatedAtUnixNano.Load()
-	if ns == 0 {
-		return time.Time{}
-	}
-	return time.Unix(0, ns)
-}
+func (v *Int64Var) LastUpdatedAt() time.Time { return v.updatedAt() }
 
 func (v *Int64Var) Set(newValue int64) error {
 	if v.t == nil {
@@ -150,73 +145,42 @@ func (v *Int64Var) Set(newValue int64) error {
 	}); err != nil {
 		return err
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	old := v.cur.Load()
-	v.hasLast = true
-	v.last = old
-
-	v.cur.Store(newValue)
-	if newValue == v.def {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
-
-	for _, cb := range v.onChange {
-		safeCallInt64(cb, newValue)
-	}
-	return nil
+	return v.t.setEntry(v, newValue, writeMeta{cause: CauseSet})
 }
 
-func (v *Int64Var) ResetToDefault() error { return v.Set(v.def) }
+func (v *Int64Var) ResetToDefault() error {
+	return v.t.resetEntryToBase(v, writeMeta{cause: CauseResetDefault})
+}
 
 func (v *Int64Var) ResetToLastValue() error {
 	if v.t == nil {
 		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
+	return v.t.resetEntryToLast(v, writeMeta{cause: CauseResetLast})
+}
 
-	if !v.hasLast {
-		return fmt.Errorf("%w: %q", ErrNoLastValue, v.k)
+func (v *Int64Var) parse(s string) (any, error) {
+	n, err := parseInt64Base10(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q expects int64 base10, got %q: %v", ErrInvalidValue, v.k, s, err)
 	}
-	newValue := v.last
-	v.hasLast = false
-
-	if err := validateInt64Value(v.k, newValue, int64Config{
+	if err := validateInt64Value(v.k, n, int64Config{
 		hasMin: v.hasMin, min: v.min,
 		hasMax: v.hasMax, max: v.max,
 	}); err != nil {
-		return err
+		return nil, err
 	}
+	return n, nil
+}
 
-	v.cur.Store(newValue)
-	if newValue == v.def {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
+func (v *Int64Var) load() any         { return v.Get() }
+func (v *Int64Var) defaultValue() any { return v.def }
+func (v *Int64Var) store(x any)       { v.cur.Store(x.(int64)) }
 
+func (v *Int64Var) callOnChange(x any) {
 	for _, cb := range v.onChange {
-		safeCallInt64(cb, newValue)
-	}
-	return nil
-}
-
-func (v *Int64Var) setFromString(s string) error {
-	n, err := parseInt64Base10(s)
-	if err != nil {
-		return fmt.Errorf("%w: %q expects int64 base10, got %q: %v", ErrInvalidValue, v.k, s, err)
+		safeCallInt64(cb, x.(int64))
 	}
-	return v.Set(n)
 }
 
 func (v *Int64Var) snapshot() Item {
@@ -246,16 +210,7 @@ func (v *Int64Var) snapshot() Item {
 	}
 }
 
-func (v *Int64Var) override() (OverrideItem, bool) {
-	cur := v.Get()
-	if cur == v.def {
-		return OverrideItem{}, false
-	}
-	if v.redact {
-		return OverrideItem{Key: v.k, Type: TypeInt64, Value: "<redacted>"}, true
-	}
-	return OverrideItem{Key: v.k, Type: TypeInt64, Value: strconv.FormatInt(cur, 10)}, true
-}
+func (v *Int64Var) format(x any) string { return strconv.FormatInt(x.(int64), 10) }
 
 func validateInt64Value(key string, v int64, cfg int64Config) error {
 	if cfg.hasMin && v < cfg.min {
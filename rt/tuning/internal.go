@@ -2,17 +2,221 @@ package tuning
 
 import (
 	"bytes"
+	"fmt"
 	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type varEntry interface {
 	key() string
 	typ() Type
+	redacted() bool
+	state() *varState
 
 	snapshot() Item
-	override() (OverrideItem, bool)
+	// format renders a value in the SetFromString / OverrideItem representation.
+	format(x any) string
 
-	setFromString(v string) error
+	// parse parses and validates s into a value accepted by store. It has no side effects.
+	parse(s string) (any, error)
+	// load returns the current value; defaultValue the registered default.
+	load() any
+	defaultValue() any
+	// store sets the current value. x comes from parse/load/defaultValue or a validated Set.
+	store(x any)
+	// callOnChange runs the variable's onChange callbacks.
+	callOnChange(x any)
+}
+
+// valueEqualer is implemented by variables whose values may not be comparable with == (Var).
+type valueEqualer interface {
+	equal(a, b any) bool
+}
+
+// equalValues reports whether a and b are the same value of v.
+func equalValues(v varEntry, a, b any) bool {
+	if e, ok := v.(valueEqualer); ok {
+		return e.equal(a, b)
+	}
+	return a == b
+}
+
+// baseValue returns the value of v without runtime writes: its layer value (FileSource, ...)
+// if any, else its default.
+func baseValue(v varEntry) any {
+	if lv := v.state().layer.Load(); lv != nil {
+		return lv.x
+	}
+	return v.defaultValue()
+}
+
+// sourceFor returns the Source of v when its value is x.
+func sourceFor(v varEntry, x any) Source {
+	if lv := v.state().layer.Load(); lv != nil {
+		if equalValues(v, x, lv.x) {
+			return lv.l.src
+		}
+		return SourceRuntimeSet
+	}
+	if equalValues(v, x, v.defaultValue()) {
+		return SourceDefault
+	}
+	return SourceRuntimeSet
+}
+
+// varState is the write-side state shared by all variable types.
+type varState struct {
+	source                atomic.Int32 // Source
+	lastUpdatedAtUnixNano atomic.Int64
+
+	// last/hasLast are protected by Tuning's write gate.
+	hasLast bool
+	last    any
+
+	// ttl is non-nil while a temporary value (SetWithTTL) is in effect.
+	ttl atomic.Pointer[ttlState]
+	// ttlTimer/ttlGen are protected by Tuning's write gate.
+	ttlTimer *time.Timer
+	ttlGen   uint64 // invalidates a timer that fired after being replaced
+
+	// meta is the descriptive metadata given at registration; it never changes.
+	meta Meta
+
+	// layer is the value set by a layer source (FileSource, ...), if any.
+	layer atomic.Pointer[layerValue]
+
+	// history holds the most recent writes, oldest first (see Tuning.History).
+	// It is appended under the write gate; histMu lets readers (including onChange
+	// callbacks, which run under the gate) read it without taking the write gate.
+	histMu  sync.Mutex
+	history []historyRecord
+}
+
+func (s *varState) state() *varState { return s }
+
+func (s *varState) loadSource() Source { return Source(s.source.Load()) }
+
+func (s *varState) updatedAt() time.Time {
+	ns := s.lastUpdatedAtUnixNano.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// commitLocked stores x as the current value of v and returns the previous value.
+// It cancels a pending TTL revert and records the write in v's history. The caller holds
+// the write gate and has validated x.
+func (t *Tuning) commitLocked(v varEntry, x any, now time.Time, m writeMeta) (old any) {
+	st := v.state()
+	st.stopTTLLocked()
+	old = v.load()
+	v.store(x)
+	st.source.Store(int32(sourceFor(v, x)))
+	st.lastUpdatedAtUnixNano.Store(now.UnixNano())
+	t.recordLocked(v, historyRecord{at: now, old: old, new: x, source: st.loadSource(), cause: m.cause, actor: m.actor})
+	return old
+}
+
+// notifyLocked runs v's onChange callbacks and the registry observers for a committed write.
+// The caller holds the write gate.
+func (t *Tuning) notifyLocked(v varEntry, old any) {
+	cur := v.load()
+	v.callOnChange(cur)
+	t.notifyChange(v.key(), v.typ(), v.redacted(), old, cur, v.state().loadSource())
+}
+
+// setEntry is the common write path for Set / ResetToDefault / SetFromString.
+func (t *Tuning) setEntry(v varEntry, x any, m writeMeta) error {
+	if t == nil {
+		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
+	}
+	if err := t.lockWrite(); err != nil {
+		return err
+	}
+	defer t.unlockWrite()
+
+	old := t.commitLocked(v, x, time.Now(), m)
+	st := v.state()
+	st.hasLast, st.last = true, old
+	t.notifyLocked(v, old)
+	return nil
+}
+
+// itemOf returns the snapshot of v including its metadata, layer value and a pending TTL
+// revert.
+func itemOf(v varEntry) Item {
+	it := v.snapshot()
+	it.Meta = v.state().meta.clone()
+	if lv := v.state().layer.Load(); lv != nil {
+		it.LayerValue = lv.x
+		if v.redacted() {
+			it.LayerValue = "<redacted>"
+		}
+	}
+	if ts := v.state().ttl.Load(); ts != nil {
+		exp := ts.expiresAt
+		it.ExpiresAt = &exp
+		it.RevertValue = ts.revertTo
+		if ts.toBase {
+			it.RevertValue = baseValue(v)
+		}
+		if v.redacted() {
+			it.RevertValue = "<redacted>"
+		}
+	}
+	return it
+}
+
+// resetEntryToBase is the write path for ResetToDefault: it drops runtime writes, so v falls
+// back to its layer value if any, else to its default.
+func (t *Tuning) resetEntryToBase(v varEntry, m writeMeta) error {
+	if t == nil {
+		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
+	}
+	if err := t.lockWrite(); err != nil {
+		return err
+	}
+	defer t.unlockWrite()
+
+	old := t.commitLocked(v, baseValue(v), time.Now(), m)
+	st := v.state()
+	st.hasLast, st.last = true, old
+	t.notifyLocked(v, old)
+	return nil
+}
+
+// setEntryFromString parses s and sets it.
+func (t *Tuning) setEntryFromString(v varEntry, s string, m writeMeta) error {
+	x, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	return t.setEntry(v, x, m)
+}
+
+// resetEntryToLast restores the previous value of v (undo one step).
+func (t *Tuning) resetEntryToLast(v varEntry, m writeMeta) error {
+	if t == nil {
+		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
+	}
+	if err := t.lockWrite(); err != nil {
+		return err
+	}
+	defer t.unlockWrite()
+
+	st := v.state()
+	if !st.hasLast {
+		return fmt.Errorf("%w: %q", ErrNoLastValue, v.key())
+	}
+	x := st.last
+	st.hasLast, st.last = false, nil
+
+	old := t.commitLocked(v, x, time.Now(), m)
+	t.notifyLocked(v, old)
+	return nil
 }
 
 func (t *Tuning) lockWrite() error {
@@ -0,0 +1,527 @@
+package tuning
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LayerStatus describes a layer source (FileSource, ...): a set of values applied beneath
+// runtime writes.
+type LayerStatus struct {
+	// Kind is the kind of source ("file", "remote"); Location is where it loads from (a path,
+	// a URL).
+	Kind     string `json:"kind"`
+	Location string `json:"location"`
+
+	// Source is the Source of the values it provides.
+	Source Source `json:"source"`
+
+	// LastLoad is the time of the last successful load, LastCheck the time of the last
+	// check for changes (successful or not).
+	LastLoad  time.Time `json:"lastLoad"`
+	LastCheck time.Time `json:"lastCheck"`
+
+	// Checksum is the checksum (SHA-256, hex) of the content of the last successful load, and
+	// Version its version as reported by the source (e.g. an HTTP ETag), if any.
+	Checksum string `json:"checksum"`
+	Version  string `json:"version,omitempty"`
+
+	// Keys lists the keys provided by the last successful load; Unknown the keys of that load
+	// that are not registered (they apply when registered). Both are sorted.
+	Keys    []string `json:"keys"`
+	Unknown []string `json:"unknown,omitempty"`
+
+	// Error is the last error (e.g. a malformed file or an invalid value), at ErrorAt, and
+	// Failures the number of consecutive failed checks (finding rejected content again is a
+	// failed check). Both are cleared by the next successful check; until then the previous
+	// content stays in effect.
+	Error    string    `json:"error,omitempty"`
+	ErrorAt  time.Time `json:"errorAt"`
+	Failures int       `json:"failures,omitempty"`
+}
+
+// LayerContent is the content of a layer source, for Layer.Load.
+type LayerContent struct {
+	// Items are the values, in the SetFromString form. A key may only appear once.
+	Items []OverrideItem
+	// Checksum identifies the content (e.g. the SHA-256 of the raw bytes): content with the
+	// checksum of the last loaded or rejected content is not processed again (Load returns nil,
+	// or the error of the rejection).
+	Checksum string
+	// Version is an optional version reported by the source (e.g. an HTTP ETag).
+	Version string
+}
+
+type layerConfig struct {
+	onError func(error)
+}
+
+// LayerOption configures a Layer.
+type LayerOption func(*layerConfig)
+
+// WithLayerOnError sets a function that receives the errors recorded by the layer. Each error
+// is reported once, when it first occurs; it is also visible in Status until the next successful
+// check.
+func WithLayerOnError(fn func(error)) LayerOption {
+	return func(c *layerConfig) { c.onError = fn }
+}
+
+// layerValue is the value a layer provides for a variable (see varState.layer).
+type layerValue struct {
+	x any
+	l *Layer
+}
+
+// Layer is a set of values applied beneath runtime writes. A variable that has no runtime write
+// in effect follows the value of the highest layer that provides it (layers added later are
+// higher), else its default; ResetToDefault returns to it.
+//
+// Layer is the building block of layer sources such as FileSource and package tuningremote: a
+// source fetches its content and calls Load, or Fail when the content cannot be fetched. A load
+// is all-or-nothing: every item is checked against its variable first, and values are only
+// committed (under the write gate, before any callback runs) if all items are valid; otherwise
+// the previous content stays in effect.
+type Layer struct {
+	t     *Tuning
+	src   Source
+	cause Cause
+
+	onError func(error)
+
+	// added, items and values are protected by the write gate. items / values are the content
+	// of the last successful load: items by key (including keys not registered yet), values the
+	// parsed values of registered keys.
+	added  bool
+	items  map[string]OverrideItem
+	values map[string]any
+
+	mu       sync.Mutex // protects status, seen and rejected
+	status   LayerStatus
+	seen     string // checksum of the last content loaded or rejected
+	rejected error  // why the content with checksum seen was rejected (nil if it was loaded)
+}
+
+// NewLayer returns a layer whose values have the given Source (e.g. SourceFile); kind and
+// location describe it in Layers. The layer is added to t by its first successful Load.
+func (t *Tuning) NewLayer(kind, location string, src Source, opts ...LayerOption) *Layer {
+	var cfg layerConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return &Layer{
+		t:       t,
+		src:     src,
+		cause:   Cause(src.String()),
+		onError: cfg.onError,
+		status:  LayerStatus{Kind: kind, Location: location, Source: src},
+	}
+}
+
+// Layers returns the status of the layers of t, lowest precedence first.
+func (t *Tuning) Layers() []LayerStatus {
+	if t == nil {
+		return nil
+	}
+	t.mu.RLock()
+	layers := append([]*Layer(nil), t.layers...)
+	t.mu.RUnlock()
+
+	out := make([]LayerStatus, 0, len(layers))
+	for _, l := range layers {
+		out = append(out, l.Status())
+	}
+	return out
+}
+
+// Status returns the current status of the layer.
+func (l *Layer) Status() LayerStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	st := l.status
+	st.Keys = append([]string(nil), st.Keys...)
+	st.Unknown = append([]string(nil), st.Unknown...)
+	return st
+}
+
+// Checked records a successful check that found the loaded content current (e.g. an HTTP 304).
+//
+// If the last content seen was rejected, the error is kept: only a successful Load clears it.
+func (l *Layer) Checked() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.status.LastCheck = time.Now()
+	if l.rejected != nil {
+		return
+	}
+	l.status.Error, l.status.ErrorAt, l.status.Failures = "", time.Time{}, 0
+}
+
+// Fail records a failed check (the content could not be fetched or parsed) and returns err.
+// The previous content stays in effect.
+func (l *Layer) Fail(err error) error {
+	return l.fail("", err)
+}
+
+// fail records a failed check or load. sum is the checksum of the rejected content ("" if
+// none).
+func (l *Layer) fail(sum string, err error) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.status.LastCheck = now
+	if sum != "" {
+		l.seen, l.rejected = sum, err
+	}
+	report := l.status.Error != err.Error()
+	l.status.Error, l.status.ErrorAt = err.Error(), now
+	l.status.Failures++
+	l.mu.Unlock()
+	if report && l.onError != nil {
+		func() {
+			defer func() { _ = recover() }()
+			l.onError(err)
+		}()
+	}
+	return err
+}
+
+// Load replaces the content of the layer (see LayerContent), or returns an error joining the
+// problems of every invalid item (as ItemError values) and leaves the previous content in effect.
+// Loading the rejected content again returns the same error and counts as another failure.
+// The first successful Load adds the layer to its Tuning.
+//
+// Load takes the write gate: it must not be called from onChange callbacks.
+func (l *Layer) Load(c LayerContent) error {
+	if l == nil || l.t == nil {
+		return fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
+	}
+	t := l.t
+
+	l.mu.Lock()
+	unchanged := c.Checksum != "" && c.Checksum == l.seen
+	rejected := l.rejected
+	if unchanged && rejected == nil { // the loaded content is current: the check succeeded
+		l.status.LastCheck = time.Now()
+		l.status.Error, l.status.ErrorAt, l.status.Failures = "", time.Time{}, 0
+	}
+	l.mu.Unlock()
+	if unchanged {
+		if rejected != nil { // still the rejected content: the check failed again
+			return l.fail("", rejected)
+		}
+		return nil
+	}
+
+	byKey := make(map[string]OverrideItem, len(c.Items))
+	var errs []error
+	for _, it := range c.Items {
+		if err := validateKey(it.Key); err != nil {
+			errs = append(errs, ItemError{Key: it.Key, Err: err})
+			continue
+		}
+		if _, dup := byKey[it.Key]; dup {
+			errs = append(errs, ItemError{Key: it.Key, Err: fmt.Errorf("%w: duplicate key", ErrInvalidValue)})
+			continue
+		}
+		byKey[it.Key] = it
+	}
+	if len(errs) > 0 {
+		return l.fail(c.Checksum, errors.Join(errs...))
+	}
+
+	if err := t.lockWrite(); err != nil {
+		return err
+	}
+	defer t.unlockWrite()
+
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make(map[string]any, len(byKey))
+	var applied, unknown []string
+	for _, k := range keys {
+		v, ok := t.entry(k)
+		if !ok {
+			unknown = append(unknown, k)
+			continue
+		}
+		x, err := parseOverride(v, byKey[k])
+		if err != nil {
+			errs = append(errs, ItemError{Key: k, Err: err})
+			continue
+		}
+		values[k] = x
+		applied = append(applied, k)
+	}
+	if len(errs) > 0 {
+		return l.fail(c.Checksum, errors.Join(errs...))
+	}
+
+	affected := make(map[string]struct{}, len(values)+len(l.values))
+	for k := range l.values {
+		affected[k] = struct{}{}
+	}
+	for k := range values {
+		affected[k] = struct{}{}
+	}
+	l.items, l.values = byKey, values
+	if !l.added {
+		l.added = true
+		t.mu.Lock()
+		t.layers = append(t.layers, l)
+		t.mu.Unlock()
+	}
+	now := time.Now()
+	t.relayerKeysLocked(affected, now, l.cause)
+
+	l.mu.Lock()
+	l.seen, l.rejected = c.Checksum, nil
+	l.status.LastLoad, l.status.LastCheck = now, now
+	l.status.Checksum, l.status.Version = c.Checksum, c.Version
+	l.status.Keys, l.status.Unknown = applied, unknown
+	l.status.Error, l.status.ErrorAt, l.status.Failures = "", time.Time{}, 0
+	l.mu.Unlock()
+	return nil
+}
+
+// Remove removes the layer from its Tuning: the variables it provided fall back to the layers
+// below, or to their defaults. It is idempotent.
+func (l *Layer) Remove() error {
+	if l == nil || l.t == nil {
+		return nil
+	}
+	t := l.t
+	if err := t.lockWrite(); err != nil {
+		return err
+	}
+	defer t.unlockWrite()
+	if !l.added {
+		return nil
+	}
+	l.added = false
+
+	t.mu.Lock()
+	for i, x := range t.layers {
+		if x == l {
+			t.layers = append(t.layers[:i:i], t.layers[i+1:]...)
+			break
+		}
+	}
+	t.mu.Unlock()
+
+	affected := make(map[string]struct{}, len(l.values))
+	for k := range l.values {
+		affected[k] = struct{}{}
+	}
+	l.items, l.values = nil, nil
+	t.relayerKeysLocked(affected, time.Now(), l.cause)
+	return nil
+}
+
+// attachLayers applies the layer values of a newly registered variable.
+func (t *Tuning) attachLayers(v varEntry) {
+	if err := t.lockWrite(); err != nil {
+		return
+	}
+	defer t.unlockWrite()
+
+	key := v.key()
+	var cause Cause
+	for _, l := range t.layers {
+		it, ok := l.items[key]
+		if !ok {
+			continue
+		}
+		x, err := parseOverride(v, it)
+		l.mu.Lock()
+		for i, k := range l.status.Unknown {
+			if k == key {
+				l.status.Unknown = append(l.status.Unknown[:i:i], l.status.Unknown[i+1:]...)
+				break
+			}
+		}
+		if err == nil {
+			l.status.Keys = insertSorted(l.status.Keys, key)
+		}
+		l.mu.Unlock()
+		if err != nil {
+			_ = l.fail("", ItemError{Key: key, Err: err})
+			continue
+		}
+		l.values[key] = x
+		cause = l.cause
+	}
+	if cause != "" {
+		t.relayerKeysLocked(map[string]struct{}{key: {}}, time.Now(), cause)
+	}
+}
+
+// relayerKeysLocked updates the layer value of the given keys after a layer changed, then runs
+// callbacks and observers for the variables whose value changed. The caller holds the write
+// gate.
+//
+// A variable that follows its base value (no runtime write in effect) moves to the new base;
+// a runtime write stays in effect. cause is recorded for variables no layer provides anymore.
+func (t *Tuning) relayerKeysLocked(keys map[string]struct{}, now time.Time, cause Cause) {
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	type changed struct {
+		v   varEntry
+		old any
+	}
+	var changes []changed
+	for _, k := range sorted {
+		v, ok := t.entry(k)
+		if !ok {
+			continue
+		}
+		st := v.state()
+		onBase := st.loadSource() != SourceRuntimeSet && st.ttl.Load() == nil
+
+		var top *layerValue
+		for i := len(t.layers) - 1; i >= 0; i-- {
+			if x, ok := t.layers[i].values[k]; ok {
+				top = &layerValue{x: x, l: t.layers[i]}
+				break
+			}
+		}
+		st.layer.Store(top)
+
+		if base := baseValue(v); onBase && !equalValues(v, v.load(), base) {
+			c := cause
+			if top != nil {
+				c = top.l.cause
+			}
+			old := t.commitLocked(v, base, now, writeMeta{cause: c})
+			changes = append(changes, changed{v: v, old: old})
+			continue
+		}
+		st.source.Store(int32(sourceFor(v, v.load())))
+	}
+	for _, c := range changes {
+		t.notifyLocked(c.v, c.old)
+	}
+}
+
+// entry returns the variable registered under key.
+func (t *Tuning) entry(key string) (varEntry, bool) {
+	t.mu.RLock()
+	v, ok := t.vars[key]
+	t.mu.RUnlock()
+	return v, ok
+}
+
+func insertSorted(keys []string, key string) []string {
+	i := sort.SearchStrings(keys, key)
+	if i < len(keys) && keys[i] == key {
+		return keys
+	}
+	keys = append(keys, "")
+	copy(keys[i+1:], keys[i:])
+	keys[i] = key
+	return keys
+}
+
+// ParseLayerContent parses the content of a layer source:
+//   - a JSON array of OverrideItem (the ExportOverridesJSON format);
+//   - a JSON object of key to value, where a string is the value itself and any other JSON value
+//     is used as its JSON text (e.g. 5, true, ["a","b"]);
+//   - otherwise key=value lines, where blank lines and lines starting with '#' are ignored and
+//     keys and values are trimmed.
+//
+// Duplicate keys are an error.
+func ParseLayerContent(b []byte) ([]OverrideItem, error) {
+	trimmed := bytes.TrimSpace(b)
+	switch {
+	case len(trimmed) == 0:
+		return nil, nil
+	case trimmed[0] == '[':
+		var items []OverrideItem
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	case trimmed[0] == '{':
+		return parseLayerObject(trimmed)
+	}
+
+	var items []OverrideItem
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, val, ok := strings.Cut(line, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("line %d: want key=value", n)
+		}
+		if seen[k] {
+			return nil, fmt.Errorf("line %d: duplicate key %q", n, k)
+		}
+		seen[k] = true
+		items = append(items, OverrideItem{Key: k, Value: strings.TrimSpace(val)})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// parseLayerObject parses a JSON object of key to value. It walks the tokens instead of
+// decoding into a map, which would silently keep the last of duplicate keys.
+func parseLayerObject(b []byte) ([]OverrideItem, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil { // '{'
+		return nil, err
+	}
+	var items []OverrideItem
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		k, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if seen[k] {
+			return nil, fmt.Errorf("duplicate key %q", k)
+		}
+		seen[k] = true
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			s = string(raw)
+		}
+		items = append(items, OverrideItem{Key: k, Value: s})
+	}
+	if _, err := dec.Token(); err != nil { // '}'
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	return items, nil
+}
@@ -0,0 +1,73 @@
+package tuning
+
+import "time"
+
+// Change describes one successful runtime write (Set/Reset*) of a variable.
+type Change struct {
+	Key  string `json:"key"`
+	Type Type   `json:"type"`
+
+	// Old / New are the effective values before and after the write.
+	// If the variable is redacted, both are "<redacted>".
+	Old any `json:"old"`
+	New any `json:"new"`
+
+	// Source is the source of New.
+	Source Source    `json:"source"`
+	At     time.Time `json:"at"`
+}
+
+// OnChange registers fn to be called after every successful runtime write of any
+// variable in t. It returns a function that removes the observer.
+//
+// Observers are called synchronously on the write path, after the variable's own
+// onChange callbacks, with the same rules: they must be fast, panics are swallowed,
+// and writing to t from an observer returns ErrReentrantWrite. Observers that need
+// to do real work should hand the Change off (e.g. to a buffered channel).
+//
+// Values are redacted following the same rules as Snapshot().
+func (t *Tuning) OnChange(fn func(Change)) (remove func()) {
+	if t == nil || fn == nil {
+		return func() {}
+	}
+	t.obsMu.Lock()
+	if t.observers == nil {
+		t.observers = make(map[uint64]func(Change))
+	}
+	t.obsNextID++
+	id := t.obsNextID
+	t.observers[id] = fn
+	t.obsMu.Unlock()
+	return func() {
+		t.obsMu.Lock()
+		delete(t.observers, id)
+		t.obsMu.Unlock()
+	}
+}
+
+// notifyChange is called by variables with the write lock held.
+func (t *Tuning) notifyChange(key string, typ Type, redact bool, oldV, newV any, src Source) {
+	t.obsMu.Lock()
+	if len(t.observers) == 0 {
+		t.obsMu.Unlock()
+		return
+	}
+	fns := make([]func(Change), 0, len(t.observers))
+	for _, fn := range t.observers {
+		fns = append(fns, fn)
+	}
+	t.obsMu.Unlock()
+
+	if redact {
+		oldV, newV = "<redacted>", "<redacted>"
+	}
+	c := Change{Key: key, Type: typ, Old: oldV, New: newV, Source: src, At: time.Now()}
+	for _, fn := range fns {
+		safeCallChange(fn, c)
+	}
+}
+
+func safeCallChange(fn func(Change), c Change) {
+	defer func() { _ = recover() }()
+	fn(c)
+}
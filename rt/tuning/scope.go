@@ -0,0 +1,107 @@
+package tuning
+
+import (
+	"strings"
+	"time"
+)
+
+// Scope registers and looks up variables under a key prefix, so a library can own a namespace
+// without knowing where it is mounted:
+//
+//	func NewCache(s *tuning.Scope) *Cache {
+//		ttl, _ := s.Duration("ttl", time.Minute) // "cache.ttl" when s = tu.Sub("cache")
+//		...
+//	}
+//
+// The variables are ordinary variables of the underlying Tuning (admin endpoints, Snapshot,
+// ExportOverrides, ... use their full keys). For Register and the other key-based APIs, use
+// Key to build the full key.
+type Scope struct {
+	t      *Tuning
+	prefix string // "" or ending with '.'
+}
+
+// Sub returns a scope for the keys "<prefix>.<name>". A trailing '.' in prefix is optional.
+// Sub("") is the whole Tuning.
+func (t *Tuning) Sub(prefix string) *Scope {
+	return &Scope{t: t, prefix: joinScopePrefix("", prefix)}
+}
+
+// Sub returns a nested scope for the keys "<s.Prefix()><prefix>.<name>".
+func (s *Scope) Sub(prefix string) *Scope {
+	return &Scope{t: s.t, prefix: joinScopePrefix(s.prefix, prefix)}
+}
+
+func joinScopePrefix(base, prefix string) string {
+	prefix = strings.TrimSuffix(prefix, ".")
+	if prefix == "" {
+		return base
+	}
+	return base + prefix + "."
+}
+
+// Tuning returns the underlying Tuning.
+func (s *Scope) Tuning() *Tuning { return s.t }
+
+// Prefix returns the key prefix of the scope ("" or ending with '.').
+func (s *Scope) Prefix() string { return s.prefix }
+
+// Key returns the full key of name in the scope, e.g. for Register:
+//
+//	hosts, err := tuning.Register(s.Tuning(), s.Key("hosts"), nil, tuning.StringListCodec())
+func (s *Scope) Key(name string) string { return s.prefix + name }
+
+// Bool registers a bool variable under the scope (see Tuning.Bool).
+func (s *Scope) Bool(name string, defaultValue bool, opts ...BoolOption) (*BoolVar, error) {
+	return s.t.Bool(s.Key(name), defaultValue, opts...)
+}
+
+// Int64 registers an int64 variable under the scope (see Tuning.Int64).
+func (s *Scope) Int64(name string, defaultValue int64, opts ...Int64Option) (*Int64Var, error) {
+	return s.t.Int64(s.Key(name), defaultValue, opts...)
+}
+
+// Float64 registers a float64 variable under the scope (see Tuning.Float64).
+func (s *Scope) Float64(name string, defaultValue float64, opts ...Float64Option) (*Float64Var, error) {
+	return s.t.Float64(s.Key(name), defaultValue, opts...)
+}
+
+// String registers a string variable under the scope (see Tuning.String).
+func (s *Scope) String(name string, defaultValue string, opts ...StringOption) (*StringVar, error) {
+	return s.t.String(s.Key(name), defaultValue, opts...)
+}
+
+// Duration registers a duration variable under the scope (see Tuning.Duration).
+func (s *Scope) Duration(name string, defaultValue time.Duration, opts ...DurationOption) (*DurationVar, error) {
+	return s.t.Duration(s.Key(name), defaultValue, opts...)
+}
+
+// Enum registers an enum variable under the scope (see Tuning.Enum).
+func (s *Scope) Enum(name string, defaultValue string, opts ...EnumOption) (*EnumVar, error) {
+	return s.t.Enum(s.Key(name), defaultValue, opts...)
+}
+
+// Flag registers a feature flag under the scope (see Tuning.Flag).
+func (s *Scope) Flag(name string, defaultValue FlagSpec, opts ...FlagOption) (*FlagVar, error) {
+	return s.t.Flag(s.Key(name), defaultValue, opts...)
+}
+
+// Lookup returns the variable name of the scope (see Tuning.Lookup). Item.Key is the full key.
+func (s *Scope) Lookup(name string) (Item, bool) {
+	return s.t.Lookup(s.Key(name))
+}
+
+// Snapshot returns the variables of the scope (see Tuning.Snapshot).
+func (s *Scope) Snapshot() Snapshot {
+	snap := s.t.Snapshot()
+	if s.prefix == "" {
+		return snap
+	}
+	items := snap.Items[:0]
+	for _, it := range snap.Items {
+		if strings.HasPrefix(it.Key, s.prefix) {
+			items = append(items, it)
+		}
+	}
+	return Snapshot{Items: items}
+}
@@ -0,0 +1,80 @@
+package tuning
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeta_InSnapshotAndLookup(t *testing.T) {
+	tu := New()
+	tags := []string{"cache"}
+	_, _ = tu.Float64("cache.ttl_factor", 1, WithMetaFloat64(Meta{
+		Description: "Multiplier applied to cache TTLs.",
+		Unit:        "ratio",
+		Owner:       "team-storage",
+		Tags:        tags,
+	}))
+	_, _ = tu.Bool("kill.switch", false, WithMetaBool(Meta{Dangerous: true}))
+	_, _ = tu.Flag("checkout.v2", FlagSpec{}, WithMetaFlag(Meta{Owner: "team-checkout"}))
+	_, _ = Register(tu, "upstream.hosts", []string{"a"}, StringListCodec(), WithMetaVar[[]string](Meta{Unit: "host:port"}))
+	_, _ = tu.Int64("plain", 1)
+	tags[0] = "mutated"
+
+	it, _ := tu.Lookup("cache.ttl_factor")
+	want := Meta{Description: "Multiplier applied to cache TTLs.", Unit: "ratio", Owner: "team-storage", Tags: []string{"cache"}}
+	if !reflect.DeepEqual(it.Meta, want) {
+		t.Fatalf("meta=%+v", it.Meta)
+	}
+	it.Tags[0] = "x" // items are copies
+	if it, _ := tu.Lookup("cache.ttl_factor"); it.Tags[0] != "cache" {
+		t.Fatalf("tags=%v", it.Tags)
+	}
+	if it, _ := tu.Lookup("kill.switch"); !it.Dangerous {
+		t.Fatalf("item=%+v", it)
+	}
+	if it, _ := tu.Lookup("checkout.v2"); it.Owner != "team-checkout" {
+		t.Fatalf("item=%+v", it)
+	}
+	if it, _ := tu.Lookup("upstream.hosts"); it.Unit != "host:port" {
+		t.Fatalf("item=%+v", it)
+	}
+
+	b, err := tu.ExportOverridesJSON()
+	if err != nil || strings.Contains(string(b), "description") {
+		t.Fatalf("overrides=%s (%v)", b, err)
+	}
+}
+
+func TestSub_RegistersUnderPrefix(t *testing.T) {
+	tu := New()
+	cache := tu.Sub("cache.")
+	ttl, err := cache.Duration("ttl", time.Minute)
+	if err != nil || ttl.Key() != "cache.ttl" {
+		t.Fatalf("key=%q err=%v", ttl.Key(), err)
+	}
+	l2 := cache.Sub("l2")
+	size, _ := l2.Int64("size", 10)
+	if size.Key() != "cache.l2.size" || l2.Prefix() != "cache.l2." || l2.Key("x") != "cache.l2.x" {
+		t.Fatalf("key=%q prefix=%q", size.Key(), l2.Prefix())
+	}
+	_, _ = tu.Int64("cachex", 1) // not under "cache."
+
+	if err := tu.SetFromString("cache.l2.size", "20"); err != nil || size.Get() != 20 {
+		t.Fatalf("size=%d err=%v", size.Get(), err)
+	}
+	if it, ok := l2.Lookup("size"); !ok || it.Key != "cache.l2.size" {
+		t.Fatalf("lookup=%+v %v", it, ok)
+	}
+	snap := cache.Snapshot()
+	if len(snap.Items) != 2 || snap.Items[0].Key != "cache.l2.size" || snap.Items[1].Key != "cache.ttl" {
+		t.Fatalf("snapshot=%+v", snap.Items)
+	}
+	if _, err := cache.Bool("ttl", false); err == nil {
+		t.Fatal("expected ErrAlreadyRegistered")
+	}
+	if tu.Sub("").Key("x") != "x" {
+		t.Fatal("Sub(\"\") must not add a prefix")
+	}
+}
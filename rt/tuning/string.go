@@ -8,6 +8,7 @@ import (
 
 type stringConfig struct {
 	redact   bool
+	meta     Meta
 	nonEmpty bool
 	onChange []func(string)
 }
@@ -20,6 +21,11 @@ func WithRedactString() StringOption {
 	return func(c *stringConfig) { c.redact = true }
 }
 
+// WithMetaString sets descriptive metadata (description, unit, owner, ...) shown in Snapshot.
+func WithMetaString(m Meta) StringOption {
+	return func(c *stringConfig) { c.meta = m }
+}
+
 // WithNonEmptyString enforces a non-empty string constraint.
 func WithNonEmptyString() StringOption {
 	return func(c *stringConfig) { c.nonEmpty = true }
@@ -64,8 +70,8 @@ func (t *Tuning) String(key string, defaultValue string, opts ...StringOption) (
 		onChange: cfg.onChange,
 	}
 	v.curPtr.Store(ptrToString(defaultValue))
-	v.source.Store(int32(SourceDefault))
 
+	v.meta = cfg.meta.clone()
 	if err := t.register(key, v); err != nil {
 		return nil, err
 	}
@@ -83,19 +89,14 @@ type StringVar struct {
 	nonEmpty bool
 
 	curPtr atomic.Pointer[string]
-	source atomic.Int32 // Source
-
-	lastUpdatedAtUnixNano atomic.Int64
-
-	// last/hasLast are protected by Tuning's write gate.
-	hasLast bool
-	last    string
+	varState
 
 	onChange []func(string)
 }
 
-func (v *StringVar) key() string { return v.k }
-func (v *StringVar) typ() Type   { return TypeString }
+func (v *StringVar) key() string    { return v.k }
+func (v *StringVar) redacted() bool { return v.redact }
+func (v *StringVar) typ() Type      { return TypeString }
 
 func (v *StringVar) Key() string { return v.k }
 
@@ -110,15 +111,9 @@ func (v *StringVar) Get() string {
 	return *p
 }
 
-func (v *StringVar) Source() Source { return Source(v.source.Load()) }
+func (v *StringVar) Source() Source { return v.loadSource() }
 
-func (v *StringVar) LastUpdatedAt() time.Time {
-	ns := v.lastUpdatedAtUnixNano.Load()
-	if ns == 0 {
-		return time.Time{}
-	}
-	return time.Unix(0, ns)
-}
+func (v *StringVar) LastUpdatedAt() time.Time { return v.updatedAt() }
 
 func (v *StringVar) Set(newValue string) error {
 	if v.t == nil {
@@ -127,67 +122,36 @@ func (v *StringVar) Set(newValue string) error {
 	if v.nonEmpty && newValue == "" {
 		return fmt.Errorf("%w: %q must be non-empty", ErrInvalidValue, v.k)
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	old := v.Get()
-	v.hasLast = true
-	v.last = old
-
-	v.curPtr.Store(ptrToString(newValue))
-	if newValue == v.def {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
-
-	for _, cb := range v.onChange {
-		safeCallString(cb, newValue)
-	}
-	return nil
+	return v.t.setEntry(v, newValue, writeMeta{cause: CauseSet})
 }
 
-func (v *StringVar) ResetToDefault() error { return v.Set(v.def) }
+func (v *StringVar) ResetToDefault() error {
+	return v.t.resetEntryToBase(v, writeMeta{cause: CauseResetDefault})
+}
 
 func (v *StringVar) ResetToLastValue() error {
 	if v.t == nil {
 		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
 	}
-	if err := v.t.lockWrite(); err != nil {
-		return err
-	}
-	defer v.t.unlockWrite()
-
-	if !v.hasLast {
-		return fmt.Errorf("%w: %q", ErrNoLastValue, v.k)
-	}
-	newValue := v.last
-	v.hasLast = false
+	return v.t.resetEntryToLast(v, writeMeta{cause: CauseResetLast})
+}
 
-	if v.nonEmpty && newValue == "" {
-		return fmt.Errorf("%w: %q must be non-empty", ErrInvalidValue, v.k)
+func (v *StringVar) parse(s string) (any, error) {
+	// String values are taken as-is (no trimming).
+	if v.nonEmpty && s == "" {
+		return nil, fmt.Errorf("%w: %q must be non-empty", ErrInvalidValue, v.k)
 	}
+	return s, nil
+}
 
-	v.curPtr.Store(ptrToString(newValue))
-	if newValue == v.def {
-		v.source.Store(int32(SourceDefault))
-	} else {
-		v.source.Store(int32(SourceRuntimeSet))
-	}
-	v.lastUpdatedAtUnixNano.Store(time.Now().UnixNano())
+func (v *StringVar) load() any         { return v.Get() }
+func (v *StringVar) defaultValue() any { return v.def }
+func (v *StringVar) store(x any)       { v.curPtr.Store(ptrToString(x.(string))) }
 
+func (v *StringVar) callOnChange(x any) {
 	for _, cb := range v.onChange {
-		safeCallString(cb, newValue)
+		safeCallString(cb, x.(string))
 	}
-	return nil
-}
-
-func (v *StringVar) setFromString(s string) error {
-	// String values are taken as-is (no trimming).
-	return v.Set(s)
 }
 
 func (v *StringVar) snapshot() Item {
@@ -209,16 +173,7 @@ func (v *StringVar) snapshot() Item {
 	}
 }
 
-func (v *StringVar) override() (OverrideItem, bool) {
-	cur := v.Get()
-	if cur == v.def {
-		return OverrideItem{}, false
-	}
-	if v.redact {
-		return OverrideItem{Key: v.k, Type: TypeString, Value: "<redacted>"}, true
-	}
-	return OverrideItem{Key: v.k, Type: TypeString, Value: cur}, true
-}
+func (v *StringVar) format(x any) string { return x.(string) }
 
 func ptrToString(s string) *string {
 	p := new(string)
@@ -0,0 +1,159 @@
+package tuning
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ttlState describes a pending revert of a temporary value.
+type ttlState struct {
+	expiresAt time.Time
+	revertTo  any
+	// toBase reverts to the base value at expiry (the layer value or default at that time)
+	// instead of revertTo, so a layer reload during the TTL is not undone by the revert.
+	toBase bool
+}
+
+// stopTTLLocked cancels a pending revert. The caller holds the write gate.
+func (s *varState) stopTTLLocked() {
+	if s.ttlTimer != nil {
+		s.ttlTimer.Stop()
+		s.ttlTimer = nil
+	}
+	s.ttlGen++
+	s.ttl.Store(nil)
+}
+
+// setEntryTTL is setEntry for a temporary value that reverts after ttl.
+//
+// The revert target is the value before the first of a series of temporary writes, so
+// stacked SetWithTTL calls unwind to the last permanent value; any write without a TTL makes
+// the current state permanent.
+func (t *Tuning) setEntryTTL(v varEntry, x any, ttl time.Duration, m writeMeta) error {
+	if t == nil {
+		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("%w: %q ttl must be > 0, got %s", ErrInvalidValue, v.key(), ttl)
+	}
+	if err := t.lockWrite(); err != nil {
+		return err
+	}
+	defer t.unlockWrite()
+
+	st := v.state()
+	revertTo, toBase := v.load(), st.loadSource() != SourceRuntimeSet
+	if ts := st.ttl.Load(); ts != nil {
+		revertTo, toBase = ts.revertTo, ts.toBase
+	}
+	now := time.Now()
+	old := t.commitLocked(v, x, now, m)
+	st.hasLast, st.last = true, old
+
+	st.ttl.Store(&ttlState{expiresAt: now.Add(ttl), revertTo: revertTo, toBase: toBase})
+	gen := st.ttlGen
+	st.ttlTimer = time.AfterFunc(ttl, func() { t.expireEntry(v, gen) })
+
+	t.notifyLocked(v, old)
+	return nil
+}
+
+// expireEntry reverts a temporary value. It runs on the timer goroutine and goes through the
+// write gate like any other write, so callbacks and observers run as usual.
+func (t *Tuning) expireEntry(v varEntry, gen uint64) {
+	if err := t.lockWrite(); err != nil {
+		return
+	}
+	defer t.unlockWrite()
+
+	st := v.state()
+	ts := st.ttl.Load()
+	if ts == nil || st.ttlGen != gen {
+		return
+	}
+	x := ts.revertTo
+	if ts.toBase {
+		x = baseValue(v)
+	}
+	old := t.commitLocked(v, x, time.Now(), writeMeta{cause: CauseTTLExpiry})
+	st.hasLast, st.last = true, old
+	t.notifyLocked(v, old)
+}
+
+// SetFromStringWithTTL is SetFromString for a temporary value: after ttl (> 0) the key reverts
+// to the value it had before the first of a series of temporary writes. Any later write without
+// a TTL (Set, ResetToDefault, ...) cancels the revert.
+func (t *Tuning) SetFromStringWithTTL(key, value string, ttl time.Duration) error {
+	return t.SetFromStringWithTTLContext(context.Background(), key, value, ttl)
+}
+
+// SetFromStringWithTTLContext is SetFromStringWithTTL; the actor in ctx (ContextWithActor) is
+// recorded in the history.
+func (t *Tuning) SetFromStringWithTTLContext(ctx context.Context, key, value string, ttl time.Duration) error {
+	v, err := t.lookupEntry(key)
+	if err != nil {
+		return err
+	}
+	x, err := v.parse(value)
+	if err != nil {
+		return err
+	}
+	return t.setEntryTTL(v, x, ttl, metaFrom(ctx, CauseSetTTL))
+}
+
+// SetWithTTL sets a temporary value that reverts after ttl (see Tuning.SetFromStringWithTTL).
+func (v *BoolVar) SetWithTTL(newValue bool, ttl time.Duration) error {
+	return v.t.setEntryTTL(v, newValue, ttl, writeMeta{cause: CauseSetTTL})
+}
+
+// SetWithTTL sets a temporary value that reverts after ttl (see Tuning.SetFromStringWithTTL).
+func (v *Int64Var) SetWithTTL(newValue int64, ttl time.Duration) error {
+	if err := validateInt64Value(v.k, newValue, int64Config{
+		hasMin: v.hasMin, min: v.min,
+		hasMax: v.hasMax, max: v.max,
+	}); err != nil {
+		return err
+	}
+	return v.t.setEntryTTL(v, newValue, ttl, writeMeta{cause: CauseSetTTL})
+}
+
+// SetWithTTL sets a temporary value that reverts after ttl (see Tuning.SetFromStringWithTTL).
+func (v *Float64Var) SetWithTTL(newValue float64, ttl time.Duration) error {
+	if err := validateFloat64Value(v.k, newValue, float64Config{
+		hasMin: v.hasMin, min: v.min,
+		hasMax: v.hasMax, max: v.max,
+	}); err != nil {
+		return err
+	}
+	return v.t.setEntryTTL(v, newValue, ttl, writeMeta{cause: CauseSetTTL})
+}
+
+// SetWithTTL sets a temporary value that reverts after ttl (see Tuning.SetFromStringWithTTL).
+func (v *StringVar) SetWithTTL(newValue string, ttl time.Duration) error {
+	x, err := v.parse(newValue)
+	if err != nil {
+		return err
+	}
+	return v.t.setEntryTTL(v, x, ttl, writeMeta{cause: CauseSetTTL})
+}
+
+// SetWithTTL sets a temporary value that reverts after ttl (see Tuning.SetFromStringWithTTL).
+func (v *DurationVar) SetWithTTL(newValue time.Duration, ttl time.Duration) error {
+	if err := validateDurationValue(v.k, newValue, durationConfig{
+		hasMin: v.hasMin, min: v.min,
+		hasMax: v.hasMax, max: v.max,
+	}); err != nil {
+		return err
+	}
+	return v.t.setEntryTTL(v, newValue, ttl, writeMeta{cause: CauseSetTTL})
+}
+
+// SetWithTTL sets a temporary value that reverts after ttl (see Tuning.SetFromStringWithTTL).
+func (v *EnumVar) SetWithTTL(newValue string, ttl time.Duration) error {
+	x, err := v.parse(newValue)
+	if err != nil {
+		return err
+	}
+	return v.t.setEntryTTL(v, x, ttl, writeMeta{cause: CauseSetTTL})
+}
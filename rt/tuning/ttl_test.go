@@ -0,0 +1,95 @@
+package tuning
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSetWithTTL_Reverts(t *testing.T) {
+	tu := New()
+	var calls atomic.Int64
+	n, _ := tu.Int64("n", 1, WithOnChangeInt64(func(int64) { calls.Add(1) }))
+	var changes atomic.Int64
+	tu.OnChange(func(Change) { changes.Add(1) })
+
+	if err := n.Set(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.SetWithTTL(9, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// A second temporary write keeps the original revert target.
+	if err := tu.SetFromStringWithTTL("n", "10", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	it, _ := tu.Lookup("n")
+	if it.Value != int64(10) || it.ExpiresAt == nil || it.RevertValue != int64(5) {
+		t.Fatalf("item=%+v", it)
+	}
+	if ovs := tu.exportOverrides(true); len(ovs) != 1 || ovs[0].Value != "5" {
+		t.Fatalf("persisted overrides=%+v", ovs)
+	}
+
+	waitFor(t, func() bool { return n.Get() == 5 })
+	if it, _ := tu.Lookup("n"); it.ExpiresAt != nil || it.Source != SourceRuntimeSet {
+		t.Fatalf("item after expiry=%+v", it)
+	}
+	if h, _ := tu.History("n"); len(h) != 4 || h[3].Cause != CauseTTLExpiry || h[3].Old != int64(10) {
+		t.Fatalf("history=%+v", h)
+	}
+	if calls.Load() != 4 || changes.Load() != 4 {
+		t.Fatalf("callbacks=%d observers=%d, want 4", calls.Load(), changes.Load())
+	}
+}
+
+func TestSetWithTTL_CanceledByPermanentWrite(t *testing.T) {
+	tu := New()
+	b, _ := tu.Bool("b", false)
+	s, _ := tu.String("s", "x", WithRedactString(), WithNonEmptyString())
+
+	if err := b.SetWithTTL(true, 30*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Set(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetWithTTL("y", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if it, _ := tu.Lookup("s"); it.RevertValue != "<redacted>" {
+		t.Fatalf("revert value not redacted: %+v", it)
+	}
+	if err := s.ResetToDefault(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if !b.Get() || s.Get() != "x" {
+		t.Fatalf("b=%v s=%q", b.Get(), s.Get())
+	}
+	if it, _ := tu.Lookup("s"); it.ExpiresAt != nil {
+		t.Fatalf("ttl not canceled: %+v", it)
+	}
+
+	if err := b.SetWithTTL(false, 0); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("ttl=0 err=%v", err)
+	}
+	if err := s.SetWithTTL("", time.Minute); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("invalid value err=%v", err)
+	}
+	if err := tu.SetFromStringWithTTL("nope", "1", time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unknown key err=%v", err)
+	}
+}
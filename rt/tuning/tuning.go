@@ -1,6 +1,7 @@
 package tuning
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -16,18 +17,35 @@ import (
 //
 // The zero value is ready to use.
 type Tuning struct {
-	mu   sync.RWMutex
-	vars map[string]varEntry
+	mu      sync.RWMutex
+	vars    map[string]varEntry
+	pending map[string]pendingOverride // imported overrides for keys not registered yet
+	flags   map[string]*FlagVar        // registered by Flag; also in vars
+	// layers are the layer sources (FileSource, ...), lowest precedence first.
+	// Changed under both mu and the write gate, so holding either is enough to read it.
+	layers []*Layer
 
 	// writeMu serializes writes (Set/Reset*) and onChange callbacks.
 	// It is intentionally a global gate to keep semantics simple and stable.
 	writeMu    sync.Mutex
 	writeOwner atomic.Uint64 // goroutine id (best-effort), for re-entrant write detection.
+
+	obsMu     sync.Mutex
+	obsNextID uint64
+	observers map[uint64]func(Change)
+
+	historyLimit int // 0: default, < 0: disabled
 }
 
 // New creates a new Tuning registry.
-func New() *Tuning {
-	return &Tuning{vars: make(map[string]varEntry)}
+func New(opts ...Option) *Tuning {
+	var cfg config
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return &Tuning{vars: make(map[string]varEntry), historyLimit: cfg.historyLimit}
 }
 
 var (
@@ -59,15 +77,23 @@ func (t *Tuning) Snapshot() Snapshot {
 
 	out := make([]Item, 0, len(items))
 	for _, v := range items {
-		out = append(out, v.snapshot())
+		out = append(out, itemOf(v))
 	}
 	return Snapshot{Items: out}
 }
 
-// ExportOverrides returns the current overrides (Value != DefaultValue).
+// ExportOverrides returns the current runtime overrides (values that differ from the file value
+// of a FileSource, if any, else from DefaultValue).
 //
-// Items are sorted by key (lexicographically) for stable output.
+// Items are sorted by key (lexicographically) for stable output. Redacted values are exported
+// as "<redacted>"; see ImportOverrides and FileStore to restore overrides.
 func (t *Tuning) ExportOverrides() []OverrideItem {
+	return t.exportOverrides(false)
+}
+
+// exportOverrides is ExportOverrides, or with persist the form used by FileStore: unredacted,
+// and with temporary values (SetWithTTL) replaced by the value they revert to.
+func (t *Tuning) exportOverrides(persist bool) []OverrideItem {
 	if t == nil {
 		return nil
 	}
@@ -82,9 +108,23 @@ func (t *Tuning) ExportOverrides() []OverrideItem {
 
 	out := make([]OverrideItem, 0, len(items))
 	for _, v := range items {
-		if ov, ok := v.override(); ok {
-			out = append(out, ov)
+		x := v.load()
+		if persist {
+			if ts := v.state().ttl.Load(); ts != nil {
+				if ts.toBase {
+					continue
+				}
+				x = ts.revertTo
+			}
+		}
+		if sourceFor(v, x) != SourceRuntimeSet {
+			continue
+		}
+		ov := OverrideItem{Key: v.key(), Type: v.typ(), Value: v.format(x)}
+		if !persist && v.redacted() {
+			ov.Value = "<redacted>"
 		}
+		out = append(out, ov)
 	}
 	return out
 }
@@ -104,19 +144,17 @@ func (t *Tuning) ExportOverridesJSON() ([]byte, error) {
 // Bool values are parsed in a slightly lenient way: case-insensitive true/false, t/f, 1/0,
 // yes/no, y/n, on/off.
 func (t *Tuning) SetFromString(key, value string) error {
-	if t == nil {
-		return fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
-	}
-	if err := validateKey(key); err != nil {
+	return t.SetFromStringContext(context.Background(), key, value)
+}
+
+// SetFromStringContext is SetFromString; the actor in ctx (ContextWithActor) is recorded in the
+// history.
+func (t *Tuning) SetFromStringContext(ctx context.Context, key, value string) error {
+	v, err := t.lookupEntry(key)
+	if err != nil {
 		return err
 	}
-	t.mu.RLock()
-	v, ok := t.vars[key]
-	t.mu.RUnlock()
-	if !ok {
-		return fmt.Errorf("%w: %q", ErrNotFound, key)
-	}
-	return v.setFromString(value)
+	return t.setEntryFromString(v, value, metaFrom(ctx, CauseSet))
 }
 
 // SetAny sets a registered key from a typed Go value (programmatic usage).
@@ -131,19 +169,12 @@ func (t *Tuning) SetFromString(key, value string) error {
 //   - float64
 //   - string (for both StringVar and EnumVar)
 //   - time.Duration
+//   - T for a Var[T] (see Register)
 func (t *Tuning) SetAny(key string, value any) error {
-	if t == nil {
-		return fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
-	}
-	if err := validateKey(key); err != nil {
+	v, err := t.lookupEntry(key)
+	if err != nil {
 		return err
 	}
-	t.mu.RLock()
-	v, ok := t.vars[key]
-	t.mu.RUnlock()
-	if !ok {
-		return fmt.Errorf("%w: %q", ErrNotFound, key)
-	}
 
 	switch vv := v.(type) {
 	case *BoolVar:
@@ -182,6 +213,8 @@ func (t *Tuning) SetAny(key string, value any) error {
 			return fmt.Errorf("%w: %q expects %s, got %T", ErrTypeMismatch, key, vv.typ(), value)
 		}
 		return vv.Set(x)
+	case interface{ setAny(any) error }:
+		return vv.setAny(value) // Var[T]
 	default:
 		// Should not happen.
 		return fmt.Errorf("%w: %q unknown var type", ErrInvalidConfig, key)
@@ -205,73 +238,55 @@ func (t *Tuning) Lookup(key string) (it Item, ok bool) {
 	if !ok {
 		return Item{}, false
 	}
-	return v.snapshot(), true
+	return itemOf(v), true
 }
 
-// ResetToDefault resets a registered key back to its default value.
+// ResetToDefault resets a registered key back to its default value (or to the FileSource value,
+// if any): it drops runtime writes.
 func (t *Tuning) ResetToDefault(key string) error {
-	if t == nil {
-		return fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
-	}
-	if err := validateKey(key); err != nil {
+	return t.ResetToDefaultContext(context.Background(), key)
+}
+
+// ResetToDefaultContext is ResetToDefault; the actor in ctx (ContextWithActor) is recorded in the
+// history.
+func (t *Tuning) ResetToDefaultContext(ctx context.Context, key string) error {
+	v, err := t.lookupEntry(key)
+	if err != nil {
 		return err
 	}
-	t.mu.RLock()
-	v, ok := t.vars[key]
-	t.mu.RUnlock()
-	if !ok {
-		return fmt.Errorf("%w: %q", ErrNotFound, key)
-	}
-	switch vv := v.(type) {
-	case *BoolVar:
-		return vv.ResetToDefault()
-	case *Int64Var:
-		return vv.ResetToDefault()
-	case *Float64Var:
-		return vv.ResetToDefault()
-	case *StringVar:
-		return vv.ResetToDefault()
-	case *DurationVar:
-		return vv.ResetToDefault()
-	case *EnumVar:
-		return vv.ResetToDefault()
-	default:
-		// Should not happen.
-		return fmt.Errorf("%w: %q unknown var type", ErrInvalidConfig, key)
-	}
+	return t.resetEntryToBase(v, metaFrom(ctx, CauseResetDefault))
 }
 
 // ResetToLastValue restores the previous effective value for a registered key (undo one step).
 func (t *Tuning) ResetToLastValue(key string) error {
+	return t.ResetToLastValueContext(context.Background(), key)
+}
+
+// ResetToLastValueContext is ResetToLastValue; the actor in ctx (ContextWithActor) is recorded in
+// the history.
+func (t *Tuning) ResetToLastValueContext(ctx context.Context, key string) error {
+	v, err := t.lookupEntry(key)
+	if err != nil {
+		return err
+	}
+	return t.resetEntryToLast(v, metaFrom(ctx, CauseResetLast))
+}
+
+// lookupEntry returns the registered variable for key (ErrInvalidKey / ErrNotFound otherwise).
+func (t *Tuning) lookupEntry(key string) (varEntry, error) {
 	if t == nil {
-		return fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
+		return nil, fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
 	}
 	if err := validateKey(key); err != nil {
-		return err
+		return nil, err
 	}
 	t.mu.RLock()
 	v, ok := t.vars[key]
 	t.mu.RUnlock()
 	if !ok {
-		return fmt.Errorf("%w: %q", ErrNotFound, key)
-	}
-	switch vv := v.(type) {
-	case *BoolVar:
-		return vv.ResetToLastValue()
-	case *Int64Var:
-		return vv.ResetToLastValue()
-	case *Float64Var:
-		return vv.ResetToLastValue()
-	case *StringVar:
-		return vv.ResetToLastValue()
-	case *DurationVar:
-		return vv.ResetToLastValue()
-	case *EnumVar:
-		return vv.ResetToLastValue()
-	default:
-		// Should not happen.
-		return fmt.Errorf("%w: %q unknown var type", ErrInvalidConfig, key)
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, key)
 	}
+	return v, nil
 }
 
 func (t *Tuning) register(key string, v varEntry) error {
@@ -282,14 +297,23 @@ func (t *Tuning) register(key string, v varEntry) error {
 		return err
 	}
 	t.mu.Lock()
-	defer t.mu.Unlock()
 	if t.vars == nil {
 		t.vars = make(map[string]varEntry)
 	}
 	if _, ok := t.vars[key]; ok {
+		t.mu.Unlock()
 		return fmt.Errorf("%w: %q", ErrAlreadyRegistered, key)
 	}
 	t.vars[key] = v
+	_, hasPending := t.pending[key]
+	hasLayers := len(t.layers) > 0
+	t.mu.Unlock()
+	if hasLayers {
+		t.attachLayers(v)
+	}
+	if hasPending {
+		t.applyPending(v)
+	}
 	return nil
 }
 
@@ -573,3 +573,43 @@ func TestSnapshotSortedByKey(t *testing.T) {
 		t.Fatalf("items not sorted: %+v", snap.Items)
 	}
 }
+
+func TestOnChange(t *testing.T) {
+	tu := New()
+	b, _ := tu.Bool("feature.x", false)
+	s, _ := tu.String("secret.token", "a", WithRedactString())
+
+	var got []Change
+	remove := tu.OnChange(func(c Change) { got = append(got, c) })
+	tu.OnChange(func(Change) { panic("boom") })
+
+	if err := b.Set(true); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := b.ResetToLastValue(); err != nil {
+		t.Fatalf("ResetToLastValue: %v", err)
+	}
+	if err := s.Set("b"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 changes, got %d", len(got))
+	}
+	if c := got[0]; c.Key != "feature.x" || c.Type != TypeBool || c.Old != false || c.New != true || c.Source != SourceRuntimeSet || c.At.IsZero() {
+		t.Fatalf("unexpected change: %+v", c)
+	}
+	if c := got[1]; c.Old != true || c.New != false || c.Source != SourceDefault {
+		t.Fatalf("unexpected change: %+v", c)
+	}
+	if c := got[2]; c.Old != "<redacted>" || c.New != "<redacted>" {
+		t.Fatalf("expected redacted change, got %+v", c)
+	}
+
+	remove()
+	if err := b.Set(true); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected no change after remove, got %d", len(got))
+	}
+}
@@ -0,0 +1,25 @@
+// Package tuningremote applies tuning values served by an HTTP config service as a layer beneath
+// runtime writes (see tuning.Layer).
+//
+// A Source polls a URL with conditional requests (If-None-Match with the ETag of the applied
+// document, so an unchanged document costs a 304), validates each new document as a whole and
+// applies it atomically: variables without a runtime write in effect take the remote value
+// (tuning.SourceRemote). A fetch or validation error leaves the previous document in effect and
+// the next attempts back off exponentially. Tuning.Layers (and the admin /tuning/layers
+// endpoint) report the sync status: last load and check, checksum, ETag, consecutive failures
+// and the last error.
+//
+// The document uses the formats of tuning.ParseLayerContent: a JSON object of key to value, the
+// JSON array written by tuning.ExportOverridesJSON, or key=value lines.
+//
+//	src, err := tuningremote.Open(tu, "https://config.internal/v1/apps/myapp/tuning",
+//		tuningremote.WithClient(client.New(
+//			client.WithTimeout(5*time.Second),
+//			client.WithMiddlewares(client.SetHeader("Authorization", "Bearer "+token)),
+//		)),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	defer src.Close()
+package tuningremote
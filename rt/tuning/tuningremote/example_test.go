@@ -0,0 +1,33 @@
+package tuningremote_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+	"github.com/evan-idocoding/zkit/rt/tuning/tuningremote"
+)
+
+func ExampleOpen() {
+	// A stand-in for the config service.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"http.timeout": "800ms"}`))
+	}))
+	defer srv.Close()
+
+	tu := tuning.New()
+	timeout, _ := tu.Duration("http.timeout", 0)
+
+	src, err := tuningremote.Open(tu, srv.URL)
+	if err != nil {
+		panic(err)
+	}
+	defer src.Close()
+
+	fmt.Println(timeout.Get(), timeout.Source(), src.Status().Version)
+
+	// Output:
+	// 800ms remote "v1"
+}
@@ -0,0 +1,272 @@
+package tuningremote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/evan-idocoding/zkit/httpx/client"
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+const (
+	defaultInterval     = 30 * time.Second
+	defaultMaxBackoff   = 5 * time.Minute
+	defaultMaxBodyBytes = 1 << 20
+	defaultTimeout      = 10 * time.Second
+)
+
+type config struct {
+	client        *http.Client
+	interval      time.Duration
+	maxBackoff    time.Duration
+	maxBodyBytes  int64
+	onError       func(error)
+	startUnsynced bool
+}
+
+// Option configures a Source.
+type Option func(*config)
+
+// WithClient sets the HTTP client (e.g. built with httpx/client, with auth middlewares).
+// Default: client.New(client.WithTimeout(10 * time.Second)).
+func WithClient(c *http.Client) Option {
+	return func(cfg *config) { cfg.client = c }
+}
+
+// WithInterval sets how often the document is checked for changes (default 30s).
+// d <= 0 means default.
+func WithInterval(d time.Duration) Option {
+	return func(c *config) { c.interval = d }
+}
+
+// WithMaxBackoff caps the delay between attempts after consecutive errors (default 5m).
+// After an error the delay starts at the interval and doubles with each further error, with
+// jitter. d <= 0 means default.
+func WithMaxBackoff(d time.Duration) Option {
+	return func(c *config) { c.maxBackoff = d }
+}
+
+// WithMaxBodyBytes limits the size of the document (default 1 MiB). n <= 0 means default.
+func WithMaxBodyBytes(n int64) Option {
+	return func(c *config) { c.maxBodyBytes = n }
+}
+
+// WithOnError sets a function that receives background errors: failed fetches and documents
+// rejected by validation (see tuning.WithLayerOnError).
+func WithOnError(fn func(error)) Option {
+	return func(c *config) { c.onError = fn }
+}
+
+// WithStartUnsynced makes Open succeed when the first fetch fails: the source then keeps
+// retrying in the background (with backoff) and the variables keep their defaults until the
+// first successful load. By default Open returns the error.
+func WithStartUnsynced() Option {
+	return func(c *config) { c.startUnsynced = true }
+}
+
+// Source polls an HTTP config service and applies its document as a tuning layer.
+type Source struct {
+	url      string
+	location string // url without credentials, for display
+	cfg      config
+	l        *tuning.Layer
+
+	mu   sync.Mutex // serializes Sync; protects etag
+	etag string     // ETag of the applied document
+
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// Open fetches the document at rawURL, applies it to t and starts polling it for changes.
+// Keys that are not registered yet are applied when they are registered.
+//
+// A failed first fetch, or an invalid first document, is returned as an error and nothing is
+// applied (see WithStartUnsynced). Call Close to stop polling and remove the layer.
+//
+// The layer location (shown by Tuning.Layers and the admin endpoints) and fetch errors carry
+// the URL with its password and query values redacted, so a credential such as ?token=... is
+// not exposed.
+func Open(t *tuning.Tuning, rawURL string, opts ...Option) (*Source, error) {
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil Tuning", tuning.ErrInvalidConfig)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: remote source: invalid URL %q", tuning.ErrInvalidConfig, rawURL)
+	}
+	s := &Source{url: rawURL, location: redactURL(u)}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&s.cfg)
+		}
+	}
+	if s.cfg.client == nil {
+		s.cfg.client = client.New(client.WithTimeout(defaultTimeout))
+	}
+	if s.cfg.interval <= 0 {
+		s.cfg.interval = defaultInterval
+	}
+	if s.cfg.maxBackoff <= 0 {
+		s.cfg.maxBackoff = defaultMaxBackoff
+	}
+	if s.cfg.maxBodyBytes <= 0 {
+		s.cfg.maxBodyBytes = defaultMaxBodyBytes
+	}
+	s.l = t.NewLayer("remote", s.location, tuning.SourceRemote, tuning.WithLayerOnError(s.cfg.onError))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.Sync(ctx); err != nil && !s.cfg.startUnsynced {
+		cancel()
+		return nil, err
+	}
+	s.cancel = cancel
+	s.wg.Add(1)
+	go s.loop(ctx)
+	return s, nil
+}
+
+// URL returns the document URL.
+func (s *Source) URL() string { return s.url }
+
+// Status returns the current sync status (it is also listed by Tuning.Layers).
+func (s *Source) Status() tuning.LayerStatus { return s.l.Status() }
+
+// Sync fetches the document now and applies it if it changed.
+func (s *Source) Sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, notModified, err := s.fetch(ctx)
+	if err != nil {
+		if ctx.Err() != nil { // canceled (Close): not a sync failure
+			return err
+		}
+		return s.l.Fail(fmt.Errorf("tuning: remote source: %w", err))
+	}
+	if notModified {
+		s.l.Checked()
+		return nil
+	}
+	if err := s.l.Load(content); err != nil {
+		return fmt.Errorf("tuning: remote source: %w", err)
+	}
+	// Only the ETag of an applied document: a rejected one must be fetched (and fail) again,
+	// not answered with 304.
+	s.etag = content.Version
+	return nil
+}
+
+// fetch gets the document, conditionally on the ETag of the applied document.
+func (s *Source) fetch(ctx context.Context) (c tuning.LayerContent, notModified bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return c, false, err
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	resp, err := s.cfg.client.Do(req)
+	if err != nil {
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			ue.URL = s.location // the error ends up in Status
+		}
+		return c, false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		_ = client.DrainAndClose(resp.Body, 8<<10)
+		if s.etag == "" {
+			return c, false, fmt.Errorf("unexpected status %s for an unconditional request", resp.Status)
+		}
+		return c, true, nil
+	default:
+		_ = client.DrainAndClose(resp.Body, 8<<10)
+		return c, false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	b, err := client.ReadAllAndCloseLimit(resp.Body, s.cfg.maxBodyBytes)
+	if err != nil {
+		return c, false, err
+	}
+	items, err := tuning.ParseLayerContent(b)
+	if err != nil {
+		return c, false, fmt.Errorf("malformed document: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return tuning.LayerContent{
+		Items:    items,
+		Checksum: hex.EncodeToString(sum[:]),
+		Version:  resp.Header.Get("ETag"),
+	}, false, nil
+}
+
+// redactURL returns u for display by the admin endpoints: the password and query values
+// (e.g. ?token=...) are replaced with "xxxxx".
+func redactURL(u *url.URL) string {
+	r := *u
+	if r.RawQuery != "" {
+		q := r.Query()
+		for _, vs := range q {
+			for i := range vs {
+				vs[i] = "xxxxx"
+			}
+		}
+		r.RawQuery = q.Encode()
+	}
+	return r.Redacted()
+}
+
+// Close stops polling and removes the layer: its variables without a runtime write return to
+// their default values (or to lower layers). It is idempotent.
+func (s *Source) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.cancel()
+		s.wg.Wait()
+		err = s.l.Remove()
+	})
+	return err
+}
+
+func (s *Source) loop(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		tm := time.NewTimer(s.nextDelay())
+		select {
+		case <-ctx.Done():
+			tm.Stop()
+			return
+		case <-tm.C:
+			_ = s.Sync(ctx) // recorded in Status and reported to onError
+		}
+	}
+}
+
+// nextDelay returns the delay before the next attempt: the interval, or after consecutive
+// failures an exponential backoff capped at maxBackoff, with jitter.
+func (s *Source) nextDelay() time.Duration {
+	n := s.l.Status().Failures
+	if n == 0 {
+		return s.cfg.interval
+	}
+	d := s.cfg.interval
+	for i := 1; i < n && d < s.cfg.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > s.cfg.maxBackoff {
+		d = s.cfg.maxBackoff
+	}
+	// Jitter in [d/2, d] so a fleet does not retry in lockstep.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
@@ -0,0 +1,280 @@
+package tuningremote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+// configService is an httptest stand-in for a config service serving one document.
+type configService struct {
+	mu     sync.Mutex
+	doc    string
+	etag   string
+	status int // non-zero: fail with this status
+
+	requests    atomic.Int64
+	notModified atomic.Int64
+}
+
+func (c *configService) set(doc, etag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.doc, c.etag, c.status = doc, etag, 0
+}
+
+func (c *configService) fail(status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+func (c *configService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.requests.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.status != 0 {
+		http.Error(w, "unavailable", c.status)
+		return
+	}
+	if c.etag != "" {
+		w.Header().Set("ETag", c.etag)
+		if r.Header.Get("If-None-Match") == c.etag {
+			c.notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	_, _ = w.Write([]byte(c.doc))
+}
+
+func newConfigService(t *testing.T, doc, etag string) (*configService, *httptest.Server) {
+	t.Helper()
+	cs := &configService{doc: doc, etag: etag}
+	srv := httptest.NewServer(cs)
+	t.Cleanup(srv.Close)
+	return cs, srv
+}
+
+func TestSource_ETagAndLayer(t *testing.T) {
+	tu := tuning.New()
+	limit, _ := tu.Int64("limit", 1)
+	mode, _ := tu.Enum("mode", "a", tuning.WithEnumAllowed("a", "b"))
+	cs, srv := newConfigService(t, `{"limit": 10, "mode": "b"}`, `"v1"`)
+
+	src, err := Open(tu, srv.URL, WithInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if limit.Get() != 10 || limit.Source() != tuning.SourceRemote || mode.Get() != "b" {
+		t.Fatalf("limit=%d (%v) mode=%q", limit.Get(), limit.Source(), mode.Get())
+	}
+
+	// Unchanged document: conditional request, 304.
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cs.notModified.Load() != 1 {
+		t.Fatalf("notModified=%d", cs.notModified.Load())
+	}
+
+	// A runtime write stays in effect; the other key follows the new document.
+	_ = limit.Set(99)
+	cs.set(`{"limit": 20, "mode": "a"}`, `"v2"`)
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if limit.Get() != 99 || mode.Get() != "a" {
+		t.Fatalf("limit=%d mode=%q", limit.Get(), mode.Get())
+	}
+	_ = limit.ResetToDefault()
+	if limit.Get() != 20 || limit.Source() != tuning.SourceRemote {
+		t.Fatalf("after reset: limit=%d (%v)", limit.Get(), limit.Source())
+	}
+
+	st := src.Status()
+	if st.Kind != "remote" || st.Location != srv.URL || st.Version != `"v2"` || st.Checksum == "" || st.Error != "" {
+		t.Fatalf("status=%+v", st)
+	}
+	if ls := tu.Layers(); len(ls) != 1 || ls[0].Version != `"v2"` {
+		t.Fatalf("layers=%+v", ls)
+	}
+
+	if err := src.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if limit.Get() != 1 || mode.Get() != "a" || len(tu.Layers()) != 0 {
+		t.Fatalf("after close: limit=%d mode=%q layers=%d", limit.Get(), mode.Get(), len(tu.Layers()))
+	}
+}
+
+func TestSource_ErrorsKeepPreviousDocument(t *testing.T) {
+	tu := tuning.New()
+	a, _ := tu.Int64("a", 1, tuning.WithMinInt64(0))
+	b, _ := tu.Int64("b", 1)
+	cs, srv := newConfigService(t, "a=2\nb=2\n", "")
+
+	var reported []error
+	src, err := Open(tu, srv.URL, WithInterval(time.Hour), WithOnError(func(err error) { reported = append(reported, err) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	// Invalid document: rejected as a whole.
+	cs.set("a=-1\nb=3\n", "")
+	if err := src.Sync(context.Background()); !errors.Is(err, tuning.ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if a.Get() != 2 || b.Get() != 2 {
+		t.Fatalf("partial apply: a=%d b=%d", a.Get(), b.Get())
+	}
+
+	// Service errors: counted as consecutive failures.
+	cs.fail(http.StatusServiceUnavailable)
+	_ = src.Sync(context.Background())
+	if err := src.Sync(context.Background()); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("err=%v", err)
+	}
+	if st := src.Status(); st.Failures != 3 || !strings.Contains(st.Error, "503") || a.Get() != 2 {
+		t.Fatalf("status=%+v a=%d", st, a.Get())
+	}
+	if len(reported) != 2 { // the invalid document, then the 503 (reported once)
+		t.Fatalf("reported=%v", reported)
+	}
+
+	// Recovery clears the error.
+	cs.set("a=4\nb=4\n", "")
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if st := src.Status(); st.Failures != 0 || st.Error != "" || a.Get() != 4 || b.Get() != 4 {
+		t.Fatalf("status=%+v a=%d b=%d", st, a.Get(), b.Get())
+	}
+}
+
+func TestSource_RejectedDocumentStaysFailed(t *testing.T) {
+	tu := tuning.New()
+	a, _ := tu.Int64("a", 1, tuning.WithMinInt64(0))
+	cs, srv := newConfigService(t, "a=2\n", `"v1"`)
+	src, err := Open(tu, srv.URL, WithInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	// Bad document, then the same bad document, then a poll that would be a 304 had its ETag
+	// been taken: every check fails and the previous document stays in effect.
+	cs.set("a=-1\n", `"v2"`)
+	for i := 1; i <= 3; i++ {
+		if err := src.Sync(context.Background()); !errors.Is(err, tuning.ErrInvalidValue) {
+			t.Fatalf("sync %d: err=%v", i, err)
+		}
+		if st := src.Status(); st.Failures != i || st.Error == "" || st.Version != `"v1"` || a.Get() != 2 {
+			t.Fatalf("sync %d: status=%+v a=%d", i, st, a.Get())
+		}
+	}
+	if cs.notModified.Load() != 0 {
+		t.Fatalf("notModified=%d", cs.notModified.Load())
+	}
+
+	// A check that finds the loaded content current does not hide the rejection.
+	src.l.Checked()
+	if st := src.Status(); st.Failures != 3 || st.Error == "" {
+		t.Fatalf("after Checked: status=%+v", st)
+	}
+
+	cs.set("a=3\n", `"v3"`)
+	if err := src.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if st := src.Status(); st.Failures != 0 || st.Error != "" || a.Get() != 3 {
+		t.Fatalf("status=%+v a=%d", st, a.Get())
+	}
+}
+
+func TestSource_BackoffDelay(t *testing.T) {
+	tu := tuning.New()
+	_, srv := newConfigService(t, "", "")
+	src, err := Open(tu, srv.URL, WithInterval(time.Second), WithMaxBackoff(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if d := src.nextDelay(); d != time.Second {
+		t.Fatalf("delay=%v", d)
+	}
+	for i, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		_ = src.l.Fail(errors.New("boom"))
+		if d := src.nextDelay(); d < max/2 || d > max {
+			t.Fatalf("failure %d: delay=%v, want in [%v, %v]", i+1, d, max/2, max)
+		}
+	}
+}
+
+func TestOpen_FirstFetch(t *testing.T) {
+	tu := tuning.New()
+	n, _ := tu.Int64("n", 1)
+	cs, srv := newConfigService(t, "n=2\n", "")
+	cs.fail(http.StatusInternalServerError)
+
+	if _, err := Open(tu, srv.URL); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := Open(tu, "ftp://example"); !errors.Is(err, tuning.ErrInvalidConfig) {
+		t.Fatalf("err=%v", err)
+	}
+
+	// WithStartUnsynced: retry in the background until the service recovers.
+	src, err := Open(tu, srv.URL, WithStartUnsynced(), WithInterval(10*time.Millisecond), WithMaxBackoff(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if st := src.Status(); st.Error == "" || n.Get() != 1 {
+		t.Fatalf("status=%+v n=%d", st, n.Get())
+	}
+	cs.set("n=2\n", "")
+	deadline := time.Now().Add(2 * time.Second)
+	for n.Get() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("not synced: status=%+v", src.Status())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOpen_RedactsLocation(t *testing.T) {
+	tu := tuning.New()
+	_, srv := newConfigService(t, "n=2\n", "")
+	u, _ := url.Parse(srv.URL)
+	u.User = url.UserPassword("user", "secret")
+	u.RawQuery = "token=secret&env=prod"
+	src, err := Open(tu, u.String(), WithInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if st := src.Status(); strings.Contains(st.Location, "secret") || !strings.Contains(st.Location, "token=xxxxx") {
+		t.Fatalf("location=%q", st.Location)
+	}
+
+	// Transport errors embed the URL.
+	srv.Close()
+	if err := src.Sync(context.Background()); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("err=%v", err)
+	}
+	if st := src.Status(); st.Error == "" || strings.Contains(st.Error, "secret") {
+		t.Fatalf("status=%+v", st)
+	}
+}
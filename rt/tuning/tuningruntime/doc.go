@@ -0,0 +1,15 @@
+// Package tuningruntime binds Go runtime memory and scheduler knobs to tuning variables.
+//
+// It registers Int64 variables whose onChange callbacks apply the value to the running process:
+//   - runtime.gogc: debug.SetGCPercent (-1 disables the GC)
+//   - runtime.memlimit: debug.SetMemoryLimit in bytes (math.MaxInt64 = no limit)
+//   - runtime.maxprocs: runtime.GOMAXPROCS
+//
+// Defaults are seeded from the current process values (GOGC / GOMEMLIMIT / GOMAXPROCS
+// environment or earlier calls), so ResetToDefault restores the startup configuration. GOGC can
+// only be read by setting it, so it is read once per process; register before other code
+// changes it (see GCPercent).
+//
+// Note: tuning's Set is a blocking model and executes callbacks synchronously. Do NOT call
+// Set/SetFromString on latency-sensitive hot paths.
+package tuningruntime
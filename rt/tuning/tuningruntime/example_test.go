@@ -0,0 +1,22 @@
+package tuningruntime_test
+
+import (
+	"fmt"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+	"github.com/evan-idocoding/zkit/rt/tuning/tuningruntime"
+)
+
+func ExampleRegister() {
+	tu := tuning.New()
+	vars, err := tuningruntime.Register(tu)
+	if err != nil {
+		panic(err)
+	}
+	// During an incident: make the GC more aggressive, then restore the startup value.
+	_ = tu.SetFromString(tuningruntime.KeyGCPercent, "50")
+	fmt.Println(vars.GCPercent.Get())
+	_ = vars.GCPercent.ResetToDefault()
+	// Output:
+	// 50
+}
@@ -0,0 +1,141 @@
+package tuningruntime
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+// Default keys used by Register.
+const (
+	KeyGCPercent   = "runtime.gogc"
+	KeyMemoryLimit = "runtime.memlimit"
+	KeyMaxProcs    = "runtime.maxprocs"
+)
+
+// Vars holds the variables registered by Register.
+type Vars struct {
+	GCPercent   *tuning.Int64Var
+	MemoryLimit *tuning.Int64Var
+	MaxProcs    *tuning.Int64Var
+}
+
+// Register registers runtime.gogc, runtime.memlimit and runtime.maxprocs on t.
+//
+// If t is nil, tuning.Default() is used.
+func Register(t *tuning.Tuning) (*Vars, error) {
+	gc, err := GCPercent(t, KeyGCPercent)
+	if err != nil {
+		return nil, err
+	}
+	ml, err := MemoryLimit(t, KeyMemoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	mp, err := MaxProcs(t, KeyMaxProcs)
+	if err != nil {
+		return nil, err
+	}
+	return &Vars{GCPercent: gc, MemoryLimit: ml, MaxProcs: mp}, nil
+}
+
+// GCPercent registers an Int64 bound to debug.SetGCPercent.
+//
+// The default is the current GC percent: the value read at the first GCPercent call in the
+// process, or the last one set through a variable registered here. Accepted values are >= -1
+// (-1 disables the GC).
+func GCPercent(t *tuning.Tuning, key string, opts ...tuning.Int64Option) (*tuning.Int64Var, error) {
+	if t == nil {
+		t = tuning.Default()
+	}
+	cur := gcPercent.get()
+
+	base := []tuning.Int64Option{
+		tuning.WithMinInt64(-1),
+		tuning.WithMetaInt64(tuning.Meta{Description: "GC target percentage (GOGC); -1 disables the GC.", Unit: "percent"}),
+		// Apply to the runtime before user callbacks.
+		tuning.WithOnChangeInt64(func(v int64) {
+			gcPercent.set(int(v))
+		}),
+	}
+	return t.Int64(key, int64(cur), append(base, opts...)...)
+}
+
+// gcPercent tracks the GC percent so that it is read from the runtime only once.
+//
+// debug.SetGCPercent is the only way to read the value, and reading it briefly sets GOGC to 100
+// for the whole process. The mutex serializes that with the writes made here; a concurrent
+// debug.SetGCPercent call elsewhere can still race with the first read (and is not seen by later
+// ones), so register early, before other code changes GOGC.
+var gcPercent gcPercentState
+
+type gcPercentState struct {
+	mu    sync.Mutex
+	known bool
+	v     int
+}
+
+func (g *gcPercentState) get() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.known {
+		g.v = debug.SetGCPercent(100)
+		debug.SetGCPercent(g.v)
+		g.known = true
+	}
+	return g.v
+}
+
+func (g *gcPercentState) set(v int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	debug.SetGCPercent(v)
+	g.v, g.known = v, true
+}
+
+// MemoryLimit registers an Int64 (bytes) bound to debug.SetMemoryLimit.
+//
+// The default is the current soft memory limit (math.MaxInt64 when unset).
+// Accepted values are >= 0; use math.MaxInt64 to remove the limit.
+func MemoryLimit(t *tuning.Tuning, key string, opts ...tuning.Int64Option) (*tuning.Int64Var, error) {
+	if t == nil {
+		t = tuning.Default()
+	}
+	// A negative input reads the limit without changing it.
+	cur := debug.SetMemoryLimit(-1)
+
+	base := []tuning.Int64Option{
+		tuning.WithMinInt64(0),
+		tuning.WithMaxInt64(math.MaxInt64),
+		tuning.WithMetaInt64(tuning.Meta{Description: "Soft memory limit (GOMEMLIMIT).", Unit: "bytes"}),
+		tuning.WithOnChangeInt64(func(v int64) {
+			debug.SetMemoryLimit(v)
+		}),
+	}
+	return t.Int64(key, cur, append(base, opts...)...)
+}
+
+// MaxProcs registers an Int64 bound to runtime.GOMAXPROCS.
+//
+// The default is the current GOMAXPROCS. Accepted values are >= 1.
+func MaxProcs(t *tuning.Tuning, key string, opts ...tuning.Int64Option) (*tuning.Int64Var, error) {
+	if t == nil {
+		t = tuning.Default()
+	}
+	cur := runtime.GOMAXPROCS(0)
+
+	base := []tuning.Int64Option{
+		tuning.WithMinInt64(1),
+		tuning.WithMetaInt64(tuning.Meta{Description: "Maximum number of CPUs executing Go code (GOMAXPROCS).", Dangerous: true}),
+		tuning.WithOnChangeInt64(func(v int64) {
+			if v > math.MaxInt32 {
+				v = math.MaxInt32
+			}
+			runtime.GOMAXPROCS(int(v))
+		}),
+	}
+	return t.Int64(key, int64(cur), append(base, opts...)...)
+}
@@ -0,0 +1,100 @@
+package tuningruntime
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/rt/tuning"
+)
+
+func TestRegister_SeedsAndApplies(t *testing.T) {
+	origGC := debug.SetGCPercent(100)
+	debug.SetGCPercent(origGC)
+	origLimit := debug.SetMemoryLimit(-1)
+	origProcs := runtime.GOMAXPROCS(0)
+	t.Cleanup(func() {
+		gcPercent.set(origGC)
+		debug.SetMemoryLimit(origLimit)
+		runtime.GOMAXPROCS(origProcs)
+	})
+
+	tu := tuning.New()
+	vars, err := Register(tu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := vars.GCPercent.Get(); got != int64(origGC) {
+		t.Fatalf("gogc default=%d, want %d", got, origGC)
+	}
+	if got := vars.MemoryLimit.Get(); got != origLimit {
+		t.Fatalf("memlimit default=%d, want %d", got, origLimit)
+	}
+	if got := vars.MaxProcs.Get(); got != int64(origProcs) {
+		t.Fatalf("maxprocs default=%d, want %d", got, origProcs)
+	}
+
+	if err := tu.SetFromString(KeyGCPercent, "250"); err != nil {
+		t.Fatal(err)
+	}
+	if got := debug.SetGCPercent(250); got != 250 {
+		t.Fatalf("GC percent=%d, want 250", got)
+	}
+	if err := tu.SetFromString(KeyMemoryLimit, "1073741824"); err != nil {
+		t.Fatal(err)
+	}
+	if got := debug.SetMemoryLimit(-1); got != 1<<30 {
+		t.Fatalf("memory limit=%d, want %d", got, 1<<30)
+	}
+	if err := tu.SetFromString(KeyMaxProcs, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := runtime.GOMAXPROCS(0); got != 1 {
+		t.Fatalf("GOMAXPROCS=%d, want 1", got)
+	}
+
+	// Reset restores the startup values.
+	if err := vars.MaxProcs.ResetToDefault(); err != nil {
+		t.Fatal(err)
+	}
+	if got := runtime.GOMAXPROCS(0); got != origProcs {
+		t.Fatalf("GOMAXPROCS=%d, want %d", got, origProcs)
+	}
+}
+
+func TestGCPercent_ReadOnce(t *testing.T) {
+	orig := gcPercent.get()
+	t.Cleanup(func() { gcPercent.set(orig) })
+
+	a, err := GCPercent(tuning.New(), KeyGCPercent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Set(150); err != nil {
+		t.Fatal(err)
+	}
+	// Not read again from the runtime: a later registration sees the last value set here.
+	debug.SetGCPercent(77)
+	b, err := GCPercent(tuning.New(), KeyGCPercent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Get(); got != 150 {
+		t.Fatalf("gogc default=%d, want 150", got)
+	}
+	if got := debug.SetGCPercent(77); got != 77 {
+		t.Fatalf("GC percent=%d, want 77 (registration must not touch it)", got)
+	}
+}
+
+func TestRegister_RejectsOutOfRange(t *testing.T) {
+	tu := tuning.New()
+	if _, err := Register(tu); err != nil {
+		t.Fatal(err)
+	}
+	for key, v := range map[string]string{KeyGCPercent: "-2", KeyMemoryLimit: "-1", KeyMaxProcs: "0"} {
+		if err := tu.SetFromString(key, v); err == nil {
+			t.Fatalf("%s=%s: expected error", key, v)
+		}
+	}
+}
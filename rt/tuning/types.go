@@ -8,6 +8,8 @@ type Source int
 const (
 	SourceDefault Source = iota
 	SourceRuntimeSet
+	SourceFile   // provided by a FileSource
+	SourceRemote // provided by a remote source (package tuningremote)
 )
 
 func (s Source) String() string {
@@ -16,6 +18,10 @@ func (s Source) String() string {
 		return "default"
 	case SourceRuntimeSet:
 		return "runtime-set"
+	case SourceFile:
+		return "file"
+	case SourceRemote:
+		return "remote"
 	default:
 		return "unknown"
 	}
@@ -45,12 +51,37 @@ type Constraints struct {
 	EnumAllowed []string `json:"enumAllowed,omitempty"`
 }
 
+// Meta is descriptive metadata of a variable, given at registration (WithMetaBool,
+// WithMetaInt64, ...) for the people operating it. It has no effect on values.
+type Meta struct {
+	// Description says what the variable controls.
+	Description string `json:"description,omitempty"`
+	// Unit is the unit of the value, e.g. "ms", "bytes", "ratio".
+	Unit string `json:"unit,omitempty"`
+	// Owner is the team or person to ask before changing it.
+	Owner string `json:"owner,omitempty"`
+	// Dangerous marks variables whose changes can cause an outage (e.g. a kill switch).
+	Dangerous bool `json:"dangerous,omitempty"`
+	// Tags group related variables, e.g. "cache", "rollout".
+	Tags []string `json:"tags,omitempty"`
+}
+
+func (m Meta) clone() Meta {
+	if m.Tags != nil {
+		m.Tags = append([]string(nil), m.Tags...)
+	}
+	return m
+}
+
 // Item is a point-in-time view of a single variable.
 type Item struct {
 	Key string `json:"key"`
 
 	Type Type `json:"type"`
 
+	// Meta is the metadata given at registration (its fields are inlined in JSON).
+	Meta
+
 	// Value is the current effective value.
 	// If the variable is redacted, Value is "<redacted>".
 	Value any `json:"value"`
@@ -61,10 +92,19 @@ type Item struct {
 
 	Source Source `json:"source"`
 
+	// LayerValue is the value provided by a layer source (FileSource, ...), if any: the value the
+	// variable falls back to when runtime writes are reset. Redacted like Value.
+	LayerValue any `json:"layerValue,omitempty"`
+
 	// LastUpdatedAt is the timestamp of the last successful runtime write (Set/Reset*).
 	// Zero means never updated.
 	LastUpdatedAt time.Time `json:"lastUpdatedAt"`
 
+	// ExpiresAt / RevertValue are set while a temporary value (SetWithTTL) is in effect:
+	// at ExpiresAt the variable reverts to RevertValue (redacted like Value).
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	RevertValue any        `json:"revertValue,omitempty"`
+
 	Constraints Constraints `json:"constraints"`
 }
 
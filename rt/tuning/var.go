@@ -0,0 +1,253 @@
+package tuning
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Codec converts the values of a Var between T and the string form used by SetFromString,
+// ExportOverrides and the admin endpoints. See StringListCodec, Int64ListCodec, StringMapCodec
+// and JSONCodec for the built-in codecs.
+type Codec[T any] struct {
+	// Type is reported in Snapshot / ExportOverrides (e.g. TypeStringList). Required.
+	Type Type
+	// Parse converts a string into a value. Required.
+	Parse func(s string) (T, error)
+	// Format converts a value into a string that Parse accepts. Required.
+	Format func(x T) string
+	// Equal reports whether two values are the same (used to detect the default value).
+	// Optional: by default values are equal when they format to the same string.
+	Equal func(a, b T) bool
+}
+
+type varConfig[T any] struct {
+	redact   bool
+	meta     Meta
+	validate func(T) error
+	onChange []func(T)
+}
+
+// VarOption configures a Var at registration time.
+type VarOption[T any] func(*varConfig[T])
+
+// WithRedactVar enables redaction for Snapshot / ExportOverrides.
+func WithRedactVar[T any]() VarOption[T] {
+	return func(c *varConfig[T]) { c.redact = true }
+}
+
+// WithMetaVar sets descriptive metadata (description, unit, owner, ...) shown in Snapshot.
+func WithMetaVar[T any](m Meta) VarOption[T] {
+	return func(c *varConfig[T]) { c.meta = m }
+}
+
+// WithValidateVar sets a validator, called for the default value and for every new value
+// (after Parse). A non-nil error rejects the value.
+func WithValidateVar[T any](fn func(T) error) VarOption[T] {
+	return func(c *varConfig[T]) { c.validate = fn }
+}
+
+// WithOnChangeVar appends an onChange callback.
+//
+// Callbacks are executed synchronously inside Set after the value is applied.
+// Callbacks run even if the new value equals the current value.
+// Callbacks must be fast and must not block. Panics are recovered and swallowed.
+func WithOnChangeVar[T any](fn func(newValue T)) VarOption[T] {
+	return func(c *varConfig[T]) {
+		if fn != nil {
+			c.onChange = append(c.onChange, fn)
+		}
+	}
+}
+
+// Register registers a variable of any type T, converted to and from strings by codec, and
+// returns its handle:
+//
+//	hosts, err := tuning.Register(tu, "upstream.hosts", []string{"a:80"}, tuning.StringListCodec())
+//
+// The variable works like the built-in ones (SetFromString, Snapshot, ExportOverrides, Apply,
+// TTLs, history, redaction). Values are shared, not copied: callers must not modify a value
+// passed to Set or returned by Get.
+func Register[T any](t *Tuning, key string, defaultValue T, codec Codec[T], opts ...VarOption[T]) (*Var[T], error) {
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil Tuning", ErrInvalidConfig)
+	}
+	if codec.Type == "" || codec.Parse == nil || codec.Format == nil {
+		return nil, fmt.Errorf("%w: %q codec needs Type, Parse and Format", ErrInvalidConfig, key)
+	}
+	var cfg varConfig[T]
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	v := &Var[T]{
+		t:        t,
+		k:        key,
+		def:      defaultValue,
+		codec:    codec,
+		redact:   cfg.redact,
+		validate: cfg.validate,
+		onChange: cfg.onChange,
+	}
+	if err := v.check(defaultValue); err != nil {
+		return nil, fmt.Errorf("%w: default value: %v", ErrInvalidConfig, err)
+	}
+	v.cur.Store(&v.def)
+
+	v.meta = cfg.meta.clone()
+	if err := t.register(key, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Var is a runtime-tunable parameter of type T (see Register).
+type Var[T any] struct {
+	t *Tuning
+	k string
+
+	def      T
+	codec    Codec[T]
+	redact   bool
+	validate func(T) error
+
+	cur atomic.Pointer[T]
+	varState
+
+	onChange []func(T)
+}
+
+func (v *Var[T]) key() string    { return v.k }
+func (v *Var[T]) redacted() bool { return v.redact }
+func (v *Var[T]) typ() Type      { return v.codec.Type }
+
+func (v *Var[T]) Key() string { return v.k }
+
+// Get returns the current effective value.
+//
+// It is lock-free and non-blocking. The value is shared: do not modify it.
+func (v *Var[T]) Get() T { return *v.cur.Load() }
+
+// Source returns where the current effective value comes from.
+func (v *Var[T]) Source() Source { return v.loadSource() }
+
+// LastUpdatedAt returns the timestamp of the last successful runtime write (Set/Reset*).
+// Zero means never updated.
+func (v *Var[T]) LastUpdatedAt() time.Time { return v.updatedAt() }
+
+// Set validates and updates the value.
+//
+// It is thread-safe and blocking. It also triggers onChange callbacks synchronously.
+func (v *Var[T]) Set(newValue T) error {
+	if err := v.check(newValue); err != nil {
+		return err
+	}
+	return v.t.setEntry(v, newValue, writeMeta{cause: CauseSet})
+}
+
+// SetWithTTL sets a temporary value that reverts after ttl (see Tuning.SetFromStringWithTTL).
+func (v *Var[T]) SetWithTTL(newValue T, ttl time.Duration) error {
+	if err := v.check(newValue); err != nil {
+		return err
+	}
+	return v.t.setEntryTTL(v, newValue, ttl, writeMeta{cause: CauseSetTTL})
+}
+
+// ResetToDefault sets the value back to the registered default value (or to the FileSource
+// value, if any).
+func (v *Var[T]) ResetToDefault() error {
+	return v.t.resetEntryToBase(v, writeMeta{cause: CauseResetDefault})
+}
+
+// ResetToLastValue restores the previous effective value (undo one step).
+func (v *Var[T]) ResetToLastValue() error {
+	if v.t == nil {
+		return fmt.Errorf("%w: nil tuning", ErrInvalidConfig)
+	}
+	return v.t.resetEntryToLast(v, writeMeta{cause: CauseResetLast})
+}
+
+// Changes returns a channel of the latest value after each change (see BoolVar.Changes).
+func (v *Var[T]) Changes(ctx context.Context) <-chan T {
+	return watchValue(ctx, v.t, v.k, v.Get)
+}
+
+func (v *Var[T]) check(x T) error {
+	if v.validate == nil {
+		return nil
+	}
+	if err := v.validate(x); err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrInvalidValue, v.k, err)
+	}
+	return nil
+}
+
+func (v *Var[T]) parse(s string) (any, error) {
+	x, err := v.codec.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q expects %s, got %q: %v", ErrInvalidValue, v.k, v.codec.Type, s, err)
+	}
+	if err := v.check(x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (v *Var[T]) load() any         { return v.Get() }
+func (v *Var[T]) defaultValue() any { return v.def }
+func (v *Var[T]) store(x any) {
+	y := x.(T)
+	v.cur.Store(&y)
+}
+
+func (v *Var[T]) equal(a, b any) bool {
+	x, y := a.(T), b.(T)
+	if v.codec.Equal != nil {
+		return v.codec.Equal(x, y)
+	}
+	return v.codec.Format(x) == v.codec.Format(y)
+}
+
+func (v *Var[T]) setAny(value any) error {
+	x, ok := value.(T)
+	if !ok {
+		return fmt.Errorf("%w: %q expects %s, got %T", ErrTypeMismatch, v.k, v.codec.Type, value)
+	}
+	return v.Set(x)
+}
+
+func (v *Var[T]) callOnChange(x any) {
+	for _, cb := range v.onChange {
+		safeCallVar(cb, x.(T))
+	}
+}
+
+func (v *Var[T]) snapshot() Item {
+	val := any(v.Get())
+	def := any(v.def)
+	if v.redact {
+		val = "<redacted>"
+		def = "<redacted>"
+	}
+	return Item{
+		Key:           v.k,
+		Type:          v.codec.Type,
+		Value:         val,
+		DefaultValue:  def,
+		Source:        v.Source(),
+		LastUpdatedAt: v.LastUpdatedAt(),
+	}
+}
+
+func (v *Var[T]) format(x any) string { return v.codec.Format(x.(T)) }
+
+func safeCallVar[T any](fn func(T), v T) {
+	if fn == nil {
+		return
+	}
+	defer func() { _ = recover() }()
+	fn(v)
+}
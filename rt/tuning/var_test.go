@@ -0,0 +1,201 @@
+package tuning
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVar_StringList(t *testing.T) {
+	tu := New()
+	var got []string
+	hosts, err := Register(tu, "hosts", []string{"a:80"}, StringListCodec(),
+		WithValidateVar(func(x []string) error {
+			if len(x) > 3 {
+				return errors.New("at most 3 hosts")
+			}
+			return nil
+		}),
+		WithOnChangeVar(func(x []string) { got = x }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tu.SetFromString("hosts", " b:80, c:80 ,"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"b:80", "c:80"}; !reflect.DeepEqual(hosts.Get(), want) || !reflect.DeepEqual(got, want) {
+		t.Fatalf("Get=%v callback=%v", hosts.Get(), got)
+	}
+	it, _ := tu.Lookup("hosts")
+	if it.Type != TypeStringList || !reflect.DeepEqual(it.Value, []string{"b:80", "c:80"}) || it.Source != SourceRuntimeSet {
+		t.Fatalf("item=%+v", it)
+	}
+	if ovs := tu.ExportOverrides(); len(ovs) != 1 || ovs[0].Value != "b:80,c:80" || ovs[0].Type != TypeStringList {
+		t.Fatalf("overrides=%+v", ovs)
+	}
+
+	if err := tu.SetFromString("hosts", "a,b,c,d"); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if err := tu.SetAny("hosts", "a"); !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("err=%v", err)
+	}
+	// An equal value (not the same slice) is the default.
+	if err := tu.SetAny("hosts", []string{"a:80"}); err != nil {
+		t.Fatal(err)
+	}
+	if hosts.Source() != SourceDefault || len(tu.ExportOverrides()) != 0 {
+		t.Fatalf("source=%v overrides=%+v", hosts.Source(), tu.ExportOverrides())
+	}
+
+	// Items that do not survive the comma form round-trip as JSON.
+	if err := hosts.Set([]string{"x,y", " z"}); err != nil {
+		t.Fatal(err)
+	}
+	ovs := tu.ExportOverrides()
+	tu2 := New()
+	h2, _ := Register(tu2, "hosts", []string{"a:80"}, StringListCodec())
+	if res := tu2.ImportOverrides(ovs); res.Err() != nil {
+		t.Fatal(res.Err())
+	}
+	if !reflect.DeepEqual(h2.Get(), []string{"x,y", " z"}) {
+		t.Fatalf("round trip=%q (from %q)", h2.Get(), ovs[0].Value)
+	}
+
+	if err := hosts.SetWithTTL([]string{"t"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := hosts.ResetToLastValue(); err != nil || !reflect.DeepEqual(hosts.Get(), []string{"x,y", " z"}) {
+		t.Fatalf("Get=%v err=%v", hosts.Get(), err)
+	}
+}
+
+func TestVar_MapInt64ListJSONAndRedact(t *testing.T) {
+	tu := New()
+	m, _ := Register(tu, "weights", map[string]string{}, StringMapCodec())
+	ports, _ := Register(tu, "ports", []int64{80}, Int64ListCodec())
+	type limits struct {
+		QPS   int    `json:"qps"`
+		Burst int    `json:"burst"`
+		Mode  string `json:"mode,omitempty"`
+	}
+	lim, err := Register(tu, "limits", limits{QPS: 10, Burst: 20}, JSONCodec[limits](), WithRedactVar[limits]())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tu.SetFromString("weights", "b=2, a=1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tu.SetFromString("weights", "a"); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if err := tu.SetFromString("ports", "80, 443"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tu.SetFromString("ports", "80,x"); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if err := tu.SetFromString("limits", `{"qps":5,"burst":9}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tu.SetFromString("limits", `{"qps":5,"nope":1}`); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err=%v", err)
+	}
+	if !reflect.DeepEqual(m.Get(), map[string]string{"a": "1", "b": "2"}) || !reflect.DeepEqual(ports.Get(), []int64{80, 443}) || lim.Get() != (limits{QPS: 5, Burst: 9}) {
+		t.Fatalf("weights=%v ports=%v limits=%+v", m.Get(), ports.Get(), lim.Get())
+	}
+
+	got := map[string]string{}
+	for _, ov := range tu.ExportOverrides() {
+		got[ov.Key] = ov.Value
+	}
+	want := map[string]string{"weights": "a=1,b=2", "ports": "80,443", "limits": "<redacted>"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("overrides=%v", got)
+	}
+	if persisted := tu.exportOverrides(true); persisted[0].Key != "limits" || persisted[0].Value != `{"qps":5,"burst":9}` {
+		t.Fatalf("persisted=%+v", persisted)
+	}
+	if it, _ := tu.Lookup("limits"); it.Value != "<redacted>" || it.DefaultValue != "<redacted>" {
+		t.Fatalf("item=%+v", it)
+	}
+
+	if _, err := Register(tu, "bad", 1, Codec[int]{}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("err=%v", err)
+	}
+	if _, err := Register(tu, "ports", nil, Int64ListCodec()); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestCodecs_FormatParseRoundTrip(t *testing.T) {
+	roundTrip := func(t *testing.T, name string, x any, format func() string, parse func(string) (any, error)) {
+		t.Helper()
+		s := format()
+		got, err := parse(s)
+		if err != nil || !reflect.DeepEqual(got, x) {
+			t.Fatalf("%s: %q -> %v (%v), want %v", name, s, got, err, x)
+		}
+	}
+
+	lc := StringListCodec()
+	for _, x := range [][]string{
+		{}, {"a", "b"}, {"[x"}, {"[x", "y"}, {"a", "[x"}, {"a,b", " c"}, {""}, {`"q"`, "{"},
+	} {
+		x := x
+		roundTrip(t, "list", x, func() string { return lc.Format(x) }, func(s string) (any, error) { return lc.Parse(s) })
+	}
+
+	ic := Int64ListCodec()
+	for _, x := range [][]int64{{}, {1}, {-1, 0, 9223372036854775807}} {
+		x := x
+		roundTrip(t, "int64 list", x, func() string { return ic.Format(x) }, func(s string) (any, error) { return ic.Parse(s) })
+	}
+
+	mc := StringMapCodec()
+	for _, x := range []map[string]string{
+		{}, {"a": "1", "b": ""}, {"{k": "v"}, {"{k": "v", "a": "1"}, {"a": "{v"}, {"a=b": "c"}, {"a": "x,y"},
+	} {
+		x := x
+		roundTrip(t, "map", x, func() string { return mc.Format(x) }, func(s string) (any, error) { return mc.Parse(s) })
+	}
+
+	type cfg struct {
+		Name string `json:"name"`
+		N    int    `json:"n"`
+	}
+	jc := JSONCodec[cfg]()
+	x := cfg{Name: "[{", N: 2}
+	roundTrip(t, "json", x, func() string { return jc.Format(x) }, func(s string) (any, error) { return jc.Parse(s) })
+}
+
+func TestVar_ExportImportRoundTrip_JSONLikeItems(t *testing.T) {
+	tu := New()
+	list, _ := Register(tu, "list", nil, StringListCodec())
+	m, _ := Register(tu, "map", nil, StringMapCodec())
+	if err := list.Set([]string{"[x"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set(map[string]string{"{k": "v"}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := tu.ExportOverridesJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tu2 := New()
+	list2, _ := Register(tu2, "list", nil, StringListCodec())
+	m2, _ := Register(tu2, "map", nil, StringMapCodec())
+	res, err := tu2.ImportOverridesJSON(b)
+	if err != nil || res.Err() != nil {
+		t.Fatalf("import: %v %v", err, res.Err())
+	}
+	if !reflect.DeepEqual(list2.Get(), []string{"[x"}) || !reflect.DeepEqual(m2.Get(), map[string]string{"{k": "v"}) {
+		t.Fatalf("list=%q map=%v", list2.Get(), m2.Get())
+	}
+}
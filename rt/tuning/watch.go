@@ -0,0 +1,222 @@
+package tuning
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultWatchBuffer is the number of keys a Subscription holds undelivered by default.
+const defaultWatchBuffer = 64
+
+type watchConfig struct {
+	buffer int
+}
+
+// WatchOption configures Watch.
+type WatchOption func(*watchConfig)
+
+// WithWatchBuffer sets how many keys with undelivered changes a subscription holds
+// (default 64). n <= 0 means default.
+func WithWatchBuffer(n int) WatchOption {
+	return func(c *watchConfig) { c.buffer = n }
+}
+
+// Subscription delivers the changes of the keys watched by Watch.
+//
+// Changes are queued per key: while a change of a key is waiting to be delivered, further
+// changes of that key are merged into it (Old stays the value before the first one; New, Source
+// and At are the latest), so a slow consumer sees at most one pending change per key. The queue
+// holds at most the configured number of keys; a change of another key is then dropped and
+// counted by Dropped. A consumer that sees Dropped grow should resync from Snapshot.
+//
+// Writers never wait for the consumer.
+type Subscription struct {
+	ch     chan Change
+	signal chan struct{} // cap 1: the queue became non-empty
+	done   chan struct{}
+	remove func()
+	once   sync.Once
+
+	mu      sync.Mutex
+	keys    []string          // delivery order
+	pending map[string]Change // by key
+	limit   int
+
+	coalesced atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// Watch subscribes to the changes of every key with the given prefix ("" = all keys).
+// Values are redacted following the same rules as Snapshot().
+//
+// The subscription runs until Close; the caller must Close it when done.
+func (t *Tuning) Watch(prefix string, opts ...WatchOption) *Subscription {
+	cfg := watchConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.buffer <= 0 {
+		cfg.buffer = defaultWatchBuffer
+	}
+	s := &Subscription{
+		ch:      make(chan Change),
+		signal:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		pending: make(map[string]Change),
+		limit:   cfg.buffer,
+	}
+	s.remove = t.OnChange(func(c Change) {
+		if strings.HasPrefix(c.Key, prefix) {
+			s.enqueue(c)
+		}
+	})
+	go s.run()
+	return s
+}
+
+// C returns the channel of changes. It is closed after Close.
+func (s *Subscription) C() <-chan Change { return s.ch }
+
+// Close stops the subscription. Undelivered changes are discarded. It is safe to call
+// Close more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.remove()
+		close(s.done)
+	})
+}
+
+// Coalesced returns how many changes were merged into a pending change of the same key.
+func (s *Subscription) Coalesced() uint64 { return s.coalesced.Load() }
+
+// Dropped returns how many changes were discarded because the queue was full.
+func (s *Subscription) Dropped() uint64 { return s.dropped.Load() }
+
+// enqueue runs on the write path: it must not block.
+func (s *Subscription) enqueue(c Change) {
+	s.mu.Lock()
+	if p, ok := s.pending[c.Key]; ok {
+		c.Old = p.Old
+		s.pending[c.Key] = c
+		s.mu.Unlock()
+		s.coalesced.Add(1)
+		return
+	}
+	if len(s.keys) >= s.limit {
+		s.mu.Unlock()
+		s.dropped.Add(1)
+		return
+	}
+	s.keys = append(s.keys, c.Key)
+	s.pending[c.Key] = c
+	s.mu.Unlock()
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Subscription) run() {
+	defer close(s.ch)
+	for {
+		s.mu.Lock()
+		if len(s.keys) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.signal:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		key := s.keys[0]
+		s.keys = s.keys[1:]
+		c := s.pending[key]
+		delete(s.pending, key)
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- c:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// watchValue delivers the latest value of key on the returned channel until ctx is done.
+// The channel holds one value: a value the consumer has not received yet is replaced by a
+// newer one, so the consumer always sees the current value and writers never wait.
+func watchValue[T any](ctx context.Context, t *Tuning, key string, get func() T) <-chan T {
+	ch := make(chan T, 1)
+	if t == nil {
+		close(ch)
+		return ch
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+	remove := t.OnChange(func(c Change) {
+		if c.Key != key {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		x := get()
+		select {
+		case <-ch: // replace an undelivered value
+		default:
+		}
+		ch <- x // never blocks: this is the only sender and the buffer is empty
+	})
+	context.AfterFunc(ctx, func() {
+		remove()
+		mu.Lock()
+		closed = true
+		close(ch)
+		mu.Unlock()
+	})
+	return ch
+}
+
+// Changes returns a channel that receives the new value after each change, until ctx is done
+// (the channel is then closed). Only the latest value is kept if the consumer falls behind.
+func (v *BoolVar) Changes(ctx context.Context) <-chan bool {
+	return watchValue(ctx, v.t, v.k, v.Get)
+}
+
+// Changes returns a channel of the latest value after each change (see BoolVar.Changes).
+func (v *Int64Var) Changes(ctx context.Context) <-chan int64 {
+	return watchValue(ctx, v.t, v.k, v.Get)
+}
+
+// Changes returns a channel of the latest value after each change (see BoolVar.Changes).
+func (v *Float64Var) Changes(ctx context.Context) <-chan float64 {
+	return watchValue(ctx, v.t, v.k, v.Get)
+}
+
+// Changes returns a channel of the latest value after each change (see BoolVar.Changes).
+func (v *StringVar) Changes(ctx context.Context) <-chan string {
+	return watchValue(ctx, v.t, v.k, v.Get)
+}
+
+// Changes returns a channel of the latest value after each change (see BoolVar.Changes).
+func (v *DurationVar) Changes(ctx context.Context) <-chan time.Duration {
+	return watchValue(ctx, v.t, v.k, v.Get)
+}
+
+// Changes returns a channel of the latest value after each change (see BoolVar.Changes).
+func (v *EnumVar) Changes(ctx context.Context) <-chan string {
+	return watchValue(ctx, v.t, v.k, v.Get)
+}
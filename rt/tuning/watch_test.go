@@ -0,0 +1,116 @@
+package tuning
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func recvChange(t *testing.T, ch <-chan Change) Change {
+	t.Helper()
+	select {
+	case c, ok := <-ch:
+		if !ok {
+			t.Fatalf("channel closed")
+		}
+		return c
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no change received")
+	}
+	return Change{}
+}
+
+func TestWatch_PrefixCoalesceAndDrop(t *testing.T) {
+	tu := New()
+	a, _ := tu.Int64("pool.a", 0)
+	b, _ := tu.Int64("pool.b", 0)
+	c, _ := tu.Int64("pool.c", 0)
+	other, _ := tu.Bool("other", false)
+
+	s := tu.Watch("pool.", WithWatchBuffer(1))
+	defer s.Close()
+
+	_ = other.Set(true) // filtered out
+	_ = a.Set(1)
+	// Wait until the change of a is in flight (taken off the queue, not yet received).
+	waitFor(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.keys) == 0
+	})
+	_ = b.Set(1)
+	_ = b.Set(2) // coalesced into the pending change of b
+	_ = c.Set(1) // queue full: dropped
+
+	if got := recvChange(t, s.C()); got.Key != "pool.a" || got.New != int64(1) {
+		t.Fatalf("got %+v", got)
+	}
+	if got := recvChange(t, s.C()); got.Key != "pool.b" || got.Old != int64(0) || got.New != int64(2) {
+		t.Fatalf("got %+v", got)
+	}
+	if s.Coalesced() != 1 || s.Dropped() != 1 {
+		t.Fatalf("coalesced=%d dropped=%d", s.Coalesced(), s.Dropped())
+	}
+
+	s.Close()
+	s.Close()
+	waitFor(t, func() bool {
+		select {
+		case _, ok := <-s.C():
+			return !ok
+		default:
+			return false
+		}
+	})
+}
+
+func TestWatch_SlowConsumerDoesNotBlockSet(t *testing.T) {
+	tu := New()
+	n, _ := tu.Int64("n", 0)
+	s := tu.Watch("")
+	defer s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := int64(1); i <= 1000; i++ {
+			_ = n.Set(i)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Set blocked on an unread subscription")
+	}
+	if got := recvChange(t, s.C()); got.New == nil {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestVarChanges_LatestValue(t *testing.T) {
+	tu := New()
+	d, _ := tu.Duration("d", time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := d.Changes(ctx)
+
+	_ = d.Set(2 * time.Second)
+	_ = d.Set(3 * time.Second)
+	if got := <-ch; got != 3*time.Second {
+		t.Fatalf("got %s, want latest value 3s", got)
+	}
+	_ = d.Set(4 * time.Second)
+	if got := <-ch; got != 4*time.Second {
+		t.Fatalf("got %s", got)
+	}
+
+	cancel()
+	waitFor(t, func() bool {
+		select {
+		case _, ok := <-ch:
+			return !ok
+		default:
+			return false
+		}
+	})
+	_ = d.Set(5 * time.Second) // no panic after close
+}
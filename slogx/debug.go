@@ -0,0 +1,57 @@
+package slogx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+// DebugHandler wraps next so that records at slog.LevelDebug and above are emitted for
+// contexts marked by httpx.DebugLog (httpx.DebugFromContext), whatever next's own level is.
+// Unmarked contexts are filtered by next as usual.
+//
+// Only logging calls that carry the request context see the mark (DebugContext, InfoContext,
+// Log, ...). next.Handle is called without consulting next.Enabled for marked records, which
+// relies on handlers filtering by level in Enabled only (as the log/slog handlers do).
+//
+// Levels.Handler and Ring.Handler honor the mark on their own, so DebugHandler is only needed
+// around handlers that do not, typically the outermost one:
+//
+//	logger := slog.New(slogx.DebugHandler(slog.NewJSONHandler(os.Stderr, nil)))
+//	logger.DebugContext(r.Context(), "cache miss", "key", k) // emitted for marked requests only
+func DebugHandler(next slog.Handler) slog.Handler {
+	if next == nil {
+		panic("slogx: nil slog.Handler")
+	}
+	return &debugHandler{next: next}
+}
+
+type debugHandler struct {
+	next slog.Handler
+}
+
+func (h *debugHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return debugForced(ctx, level) || h.next.Enabled(ctx, level)
+}
+
+func (h *debugHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *debugHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &debugHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *debugHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &debugHandler{next: h.next.WithGroup(name)}
+}
+
+// debugForced reports whether a record at level must be emitted regardless of configured
+// levels because ctx is marked for debug logging.
+func debugForced(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelDebug && httpx.DebugFromContext(ctx)
+}
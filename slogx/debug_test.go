@@ -0,0 +1,51 @@
+package slogx
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+func TestDebugHandler(t *testing.T) {
+	var out strings.Builder
+	logger := slog.New(DebugHandler(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	marked := httpx.WithDebug(context.Background())
+
+	logger.DebugContext(context.Background(), "plain debug")
+	logger.With("k", "v").WithGroup("g").DebugContext(marked, "marked debug", "n", 1)
+	logger.Log(marked, slog.LevelDebug-4, "below debug")
+
+	got := out.String()
+	if strings.Contains(got, "plain debug") || strings.Contains(got, "below debug") {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+	if !strings.Contains(got, "marked debug") || !strings.Contains(got, "k=v g.n=1") {
+		t.Fatalf("marked record missing:\n%s", got)
+	}
+}
+
+func TestDebugMark_LevelsAndRing(t *testing.T) {
+	levels := NewLevels()
+	if _, err := levels.Register("db", slog.LevelWarn); err != nil {
+		t.Fatal(err)
+	}
+	ring := NewRing() // captures Info and above
+	var out strings.Builder
+	next := slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := levels.Logger(slog.New(levels.Handler(ring.Handler(next))), "db")
+	marked := httpx.WithDebug(context.Background())
+
+	logger.InfoContext(context.Background(), "filtered")
+	logger.DebugContext(marked, "forced")
+
+	if strings.Contains(out.String(), "filtered") || !strings.Contains(out.String(), "forced") {
+		t.Fatalf("output:\n%s", out.String())
+	}
+	recs := ring.Records()
+	if len(recs) != 1 || recs[0].Message != "forced" {
+		t.Fatalf("ring=%+v", recs)
+	}
+}
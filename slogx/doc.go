@@ -0,0 +1,46 @@
+// Package slogx provides small log/slog building blocks for operational control.
+//
+// # Per-component levels
+//
+// Levels is a registry of named slog.LevelVars ("db", "http.access", "cache", ...) plus a
+// reserved "default" level for everything else. Levels.Handler wraps any slog.Handler and
+// filters each record by the level of its component:
+//
+//	levels := slogx.NewLevels(slogx.WithLevelsDefault(lv))
+//	_, _ = levels.Register("db", slog.LevelInfo)
+//	logger := slog.New(levels.Handler(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+//
+//	dbLog := levels.Logger(logger, "db") // same as logger.With("component", "db")
+//	dbLog.Debug("query", "sql", q)       // emitted only while "db" is at debug
+//
+// A record's component is the value of the component attribute (default key "component"),
+// bound with Logger.With or passed on the call itself. Records without it, or naming an
+// unregistered component, use the default level.
+//
+// Levels can be changed at runtime with Set (optionally reverting after a TTL) and Reset;
+// ops.LogLevelsHandler and the admin /log/levels endpoints expose them.
+//
+// The wrapped handler still applies its own level, so configure it with the lowest level
+// any component may need (typically slog.LevelDebug).
+//
+// # Recent records
+//
+// Ring keeps the most recent records in memory, bounded by count and bytes, so they can be
+// inspected when the log pipeline is unreachable (ops.LogTailHandler, admin /log/tail).
+// Ring.Handler tees records into the ring and on to the wrapped handler:
+//
+//	ring := slogx.NewRing(slogx.WithRingMaxRecords(5000), slogx.WithRingLevel(slog.LevelDebug))
+//	logger := slog.New(ring.Handler(slog.NewJSONHandler(os.Stderr, nil)))
+//
+// Records carry the request ID of httpx.RequestID (or a "request_id" attribute).
+//
+// # Per-request debug logging
+//
+// httpx.DebugLog marks selected requests (a signed header, or a request ID / client IP
+// registered in httpx.DebugTargets). Records logged with a marked context are emitted at debug
+// level whatever the configured levels are: Levels.Handler and Ring.Handler honor the mark, and
+// DebugHandler adds it to any other handler:
+//
+//	logger := slog.New(slogx.DebugHandler(slog.NewJSONHandler(os.Stderr, nil)))
+//	logger.DebugContext(r.Context(), "cache miss", "key", k) // emitted for marked requests only
+package slogx
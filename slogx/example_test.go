@@ -0,0 +1,29 @@
+package slogx_test
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/evan-idocoding/zkit/slogx"
+)
+
+func ExampleLevels() {
+	levels := slogx.NewLevels()
+	_, _ = levels.Register("db", slog.LevelDebug)
+
+	logger := slog.New(levels.Handler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	levels.Logger(logger, "db").Debug("query")
+	logger.Debug("dropped: default level is info")
+
+	// Output:
+	// level=DEBUG msg=query component=db
+}
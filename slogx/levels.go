@@ -0,0 +1,474 @@
+package slogx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultComponent is the reserved name of the fallback level used by records without a
+	// (registered) component.
+	DefaultComponent = "default"
+	// DefaultAttrKey is the default attribute key naming a record's component.
+	DefaultAttrKey = "component"
+)
+
+var (
+	// ErrInvalidName indicates a component name is empty or contains invalid characters.
+	ErrInvalidName = errors.New("slogx: invalid component name")
+	// ErrAlreadyRegistered indicates the same component is registered more than once.
+	ErrAlreadyRegistered = errors.New("slogx: already registered")
+	// ErrNotFound indicates the component is not registered.
+	ErrNotFound = errors.New("slogx: component not found")
+)
+
+// Causes of a LevelChange.
+const (
+	LevelCauseSet    = "set"    // Set
+	LevelCauseExpire = "expire" // a Set TTL elapsed and the level reverted
+	LevelCauseReset  = "reset"  // Reset
+)
+
+// LevelChange describes one level change made through Levels (Set, TTL expiry or Reset).
+type LevelChange struct {
+	Name  string
+	Old   slog.Level
+	New   slog.Level
+	Cause string // LevelCauseSet / LevelCauseExpire / LevelCauseReset
+	At    time.Time
+}
+
+// LevelInfo is a point-in-time view of one component level.
+type LevelInfo struct {
+	Name    string
+	Level   slog.Level
+	Initial slog.Level // level at registration; Reset restores it
+
+	// ExpiresAt is when a temporary Set reverts to RevertTo (zero = no pending revert).
+	ExpiresAt time.Time
+	RevertTo  slog.Level
+}
+
+type levelsConfig struct {
+	attrKey string
+	def     *slog.LevelVar
+}
+
+// LevelsOption configures NewLevels.
+type LevelsOption func(*levelsConfig)
+
+// WithLevelsAttrKey sets the attribute key naming a record's component.
+//
+// Empty means default (DefaultAttrKey).
+func WithLevelsAttrKey(key string) LevelsOption {
+	return func(c *levelsConfig) { c.attrKey = key }
+}
+
+// WithLevelsDefault uses lv as the default level (e.g. an existing LevelVar already exposed
+// as /log/level). Its current level becomes the default's initial level.
+//
+// nil means default (a new LevelVar at slog.LevelInfo).
+func WithLevelsDefault(lv *slog.LevelVar) LevelsOption {
+	return func(c *levelsConfig) { c.def = lv }
+}
+
+// Levels is a registry of named log levels. It is safe for concurrent use.
+//
+// The zero value is not usable; use NewLevels.
+type Levels struct {
+	attrKey string
+	def     *component
+
+	mu    sync.RWMutex
+	comps map[string]*component
+
+	// compMin is the lowest level of the registered components other than default, kept up to
+	// date by Register, Set, Reset and TTL expiry so Enabled never takes mu.
+	compMin atomic.Int64
+
+	obsMu     sync.Mutex
+	obsNextID uint64
+	observers map[uint64]func(LevelChange)
+}
+
+type component struct {
+	name    string
+	lv      *slog.LevelVar
+	initial slog.Level
+
+	// Guarded by Levels.mu.
+	revert    *time.Timer
+	revertGen uint64 // invalidates a timer that fired after being replaced
+	revertTo  slog.Level
+	expiresAt time.Time
+}
+
+// NewLevels creates a registry with only the default component.
+func NewLevels(opts ...LevelsOption) *Levels {
+	cfg := levelsConfig{attrKey: DefaultAttrKey}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.attrKey == "" {
+		cfg.attrKey = DefaultAttrKey
+	}
+	if cfg.def == nil {
+		cfg.def = new(slog.LevelVar)
+	}
+	def := &component{name: DefaultComponent, lv: cfg.def, initial: cfg.def.Level()}
+	l := &Levels{
+		attrKey: cfg.attrKey,
+		def:     def,
+		comps:   map[string]*component{DefaultComponent: def},
+	}
+	l.compMin.Store(math.MaxInt64)
+	return l
+}
+
+// AttrKey returns the attribute key naming a record's component.
+func (l *Levels) AttrKey() string { return l.attrKey }
+
+// Default returns the default component's LevelVar.
+func (l *Levels) Default() *slog.LevelVar { return l.def.lv }
+
+// Register adds a component starting at initial and returns its LevelVar.
+//
+// Names are non-empty and consist of ASCII letters, digits, '.', '_' and '-'.
+// "default" is reserved.
+func (l *Levels) Register(name string, initial slog.Level) (*slog.LevelVar, error) {
+	if !validName(name) {
+		return nil, ErrInvalidName
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.comps[name]; ok {
+		return nil, ErrAlreadyRegistered
+	}
+	c := &component{name: name, lv: new(slog.LevelVar), initial: initial}
+	c.lv.Set(initial)
+	l.comps[name] = c
+	l.updateMinLocked()
+	return c.lv, nil
+}
+
+// Var returns the LevelVar of a component (including "default").
+//
+// Setting it directly works but bypasses TTLs and OnChange observers. For components other than
+// default it also bypasses the lowest-level tracking of Handler: a direct change below the
+// current lowest level is not seen by loggers without a bound component until the next Set,
+// Reset or Register.
+func (l *Levels) Var(name string) (*slog.LevelVar, bool) {
+	c := l.lookup(name)
+	if c == nil {
+		return nil, false
+	}
+	return c.lv, true
+}
+
+// Set sets a component's level and returns the previous one.
+//
+// If ttl > 0 the level reverts after ttl to what it was before the first of a series of
+// temporary Sets; a later Set without ttl makes the change permanent.
+func (l *Levels) Set(name string, level slog.Level, ttl time.Duration) (old slog.Level, err error) {
+	l.mu.Lock()
+	c := l.comps[name]
+	if c == nil {
+		l.mu.Unlock()
+		return 0, ErrNotFound
+	}
+	old = c.lv.Level()
+	revertTo := old
+	if c.revert != nil {
+		revertTo = c.revertTo
+	}
+	l.stopRevertLocked(c)
+	c.lv.Set(level)
+	if ttl > 0 {
+		c.revertTo = revertTo
+		c.expiresAt = time.Now().Add(ttl)
+		gen := c.revertGen
+		c.revert = time.AfterFunc(ttl, func() { l.expire(c, gen) })
+	}
+	l.updateMinLocked()
+	l.mu.Unlock()
+
+	l.notify(name, old, level, LevelCauseSet)
+	return old, nil
+}
+
+// Reset restores a component's registration level and cancels a pending revert.
+func (l *Levels) Reset(name string) error {
+	l.mu.Lock()
+	c := l.comps[name]
+	if c == nil {
+		l.mu.Unlock()
+		return ErrNotFound
+	}
+	l.stopRevertLocked(c)
+	old := c.lv.Level()
+	c.lv.Set(c.initial)
+	l.updateMinLocked()
+	l.mu.Unlock()
+
+	l.notify(name, old, c.initial, LevelCauseReset)
+	return nil
+}
+
+func (l *Levels) expire(c *component, gen uint64) {
+	l.mu.Lock()
+	if c.revert == nil || c.revertGen != gen {
+		l.mu.Unlock()
+		return
+	}
+	l.stopRevertLocked(c)
+	old := c.lv.Level()
+	c.lv.Set(c.revertTo)
+	newLevel := c.revertTo
+	l.updateMinLocked()
+	l.mu.Unlock()
+
+	l.notify(c.name, old, newLevel, LevelCauseExpire)
+}
+
+func (l *Levels) stopRevertLocked(c *component) {
+	if c.revert != nil {
+		c.revert.Stop()
+		c.revert = nil
+	}
+	c.revertGen++
+	c.expiresAt = time.Time{}
+}
+
+// Snapshot returns all components (including "default") sorted by name.
+func (l *Levels) Snapshot() []LevelInfo {
+	l.mu.RLock()
+	out := make([]LevelInfo, 0, len(l.comps))
+	for _, c := range l.comps {
+		it := LevelInfo{Name: c.name, Level: c.lv.Level(), Initial: c.initial}
+		if c.revert != nil {
+			it.ExpiresAt = c.expiresAt
+			it.RevertTo = c.revertTo
+		}
+		out = append(out, it)
+	}
+	l.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// OnChange registers fn to be called after every level change made through l (Set, TTL
+// expiry, Reset). It returns a function that removes the observer.
+//
+// Observers are called synchronously and must be fast; panics are swallowed.
+func (l *Levels) OnChange(fn func(LevelChange)) (remove func()) {
+	if l == nil || fn == nil {
+		return func() {}
+	}
+	l.obsMu.Lock()
+	if l.observers == nil {
+		l.observers = make(map[uint64]func(LevelChange))
+	}
+	l.obsNextID++
+	id := l.obsNextID
+	l.observers[id] = fn
+	l.obsMu.Unlock()
+	return func() {
+		l.obsMu.Lock()
+		delete(l.observers, id)
+		l.obsMu.Unlock()
+	}
+}
+
+func (l *Levels) notify(name string, oldLevel, newLevel slog.Level, cause string) {
+	l.obsMu.Lock()
+	if len(l.observers) == 0 {
+		l.obsMu.Unlock()
+		return
+	}
+	fns := make([]func(LevelChange), 0, len(l.observers))
+	for _, fn := range l.observers {
+		fns = append(fns, fn)
+	}
+	l.obsMu.Unlock()
+
+	c := LevelChange{Name: name, Old: oldLevel, New: newLevel, Cause: cause, At: time.Now()}
+	for _, fn := range fns {
+		safeCallLevelChange(fn, c)
+	}
+}
+
+func safeCallLevelChange(fn func(LevelChange), c LevelChange) {
+	defer func() { _ = recover() }()
+	fn(c)
+}
+
+func (l *Levels) lookup(name string) *component {
+	l.mu.RLock()
+	c := l.comps[name]
+	l.mu.RUnlock()
+	return c
+}
+
+// minLevel is the lowest level of any component: the most a record without a bound
+// component could need before its own attributes are seen.
+//
+// The default level is read directly, since its LevelVar may be shared and set elsewhere
+// (WithLevelsDefault).
+func (l *Levels) minLevel() slog.Level {
+	min := l.def.lv.Level()
+	if m := l.compMin.Load(); m < int64(min) {
+		min = slog.Level(m)
+	}
+	return min
+}
+
+func (l *Levels) updateMinLocked() {
+	min := int64(math.MaxInt64)
+	for _, c := range l.comps {
+		if c == l.def {
+			continue
+		}
+		if lv := int64(c.lv.Level()); lv < min {
+			min = lv
+		}
+	}
+	l.compMin.Store(min)
+}
+
+func validName(s string) bool {
+	if s == "" || s == DefaultComponent {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '.', ch == '_', ch == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// --- slog integration ---
+
+// Logger returns base with the component attribute set to name.
+// A nil base means slog.Default().
+func (l *Levels) Logger(base *slog.Logger, name string) *slog.Logger {
+	if base == nil {
+		base = slog.Default()
+	}
+	return base.With(l.attrKey, name)
+}
+
+// Handler wraps next so that records are filtered by their component's level.
+//
+// Loggers with a bound component (Logger / With) are checked up front in Enabled. For other
+// loggers Enabled admits anything at or above the lowest component level, and Handle reads the
+// component attribute of the record itself. Attributes inside groups are not considered.
+//
+// Records logged with a context marked by httpx.DebugLog pass at slog.LevelDebug and above,
+// whatever the component's level (see DebugHandler).
+func (l *Levels) Handler(next slog.Handler) slog.Handler {
+	if l == nil {
+		panic("slogx: nil Levels")
+	}
+	if next == nil {
+		panic("slogx: nil slog.Handler")
+	}
+	return &levelsHandler{l: l, next: next}
+}
+
+type levelsHandler struct {
+	l    *Levels
+	next slog.Handler
+
+	name    string // bound component name ("" = none)
+	comp    *atomic.Pointer[component]
+	grouped bool
+}
+
+// bound resolves the bound component. Unregistered names fall back to default until they are
+// registered; registered components are never removed, so a resolution is cached.
+func (h *levelsHandler) bound() *component {
+	if h.name == "" {
+		return nil
+	}
+	if c := h.comp.Load(); c != nil {
+		return c
+	}
+	if c := h.l.lookup(h.name); c != nil {
+		h.comp.Store(c)
+		return c
+	}
+	return h.l.def
+}
+
+func (h *levelsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if debugForced(ctx, level) {
+		return true
+	}
+	min := h.l.minLevel()
+	if c := h.bound(); c != nil {
+		min = c.lv.Level()
+	}
+	return level >= min && h.next.Enabled(ctx, level)
+}
+
+func (h *levelsHandler) Handle(ctx context.Context, r slog.Record) error {
+	if debugForced(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	c := h.bound()
+	if c == nil {
+		c = h.l.def
+		if !h.grouped {
+			r.Attrs(func(a slog.Attr) bool {
+				if a.Key != h.l.attrKey {
+					return true
+				}
+				if rc := h.l.lookup(a.Value.Resolve().String()); rc != nil {
+					c = rc
+				}
+				return false
+			})
+		}
+	}
+	if r.Level < c.lv.Level() {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *levelsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := *h
+	nh.next = h.next.WithAttrs(attrs)
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == h.l.attrKey {
+				nh.name = a.Value.Resolve().String()
+				nh.comp = new(atomic.Pointer[component])
+			}
+		}
+	}
+	return &nh
+}
+
+func (h *levelsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	nh.next = h.next.WithGroup(name)
+	nh.grouped = true
+	return &nh
+}
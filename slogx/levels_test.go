@@ -0,0 +1,219 @@
+package slogx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestLogger(l *Levels) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return slog.New(l.Handler(h)), &buf
+}
+
+func TestLevels_Register(t *testing.T) {
+	l := NewLevels()
+	lv, err := l.Register("db", slog.LevelWarn)
+	if err != nil || lv.Level() != slog.LevelWarn {
+		t.Fatalf("Register: lv=%v err=%v", lv, err)
+	}
+	if _, err := l.Register("db", slog.LevelInfo); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("duplicate err=%v", err)
+	}
+	for _, name := range []string{"", "default", "a b", "x/y"} {
+		if _, err := l.Register(name, slog.LevelInfo); !errors.Is(err, ErrInvalidName) {
+			t.Fatalf("Register(%q) err=%v", name, err)
+		}
+	}
+	if got, ok := l.Var("db"); !ok || got != lv {
+		t.Fatalf("Var(db)=%v,%v", got, ok)
+	}
+	if got, ok := l.Var(DefaultComponent); !ok || got != l.Default() {
+		t.Fatalf("Var(default)=%v,%v", got, ok)
+	}
+	if _, ok := l.Var("nope"); ok {
+		t.Fatalf("Var(nope) found")
+	}
+}
+
+func TestLevels_HandlerRoutesByComponent(t *testing.T) {
+	def := new(slog.LevelVar)
+	def.Set(slog.LevelWarn)
+	l := NewLevels(WithLevelsDefault(def))
+	_, _ = l.Register("db", slog.LevelDebug)
+	_, _ = l.Register("cache", slog.LevelError)
+	logger, buf := newTestLogger(l)
+
+	l.Logger(logger, "db").Debug("db debug")
+	l.Logger(logger, "cache").Warn("cache warn")           // dropped: cache is at error
+	logger.Info("plain info")                              // dropped: default is warn
+	logger.Warn("plain warn")                              //
+	logger.Debug("inline db", "component", "db")           // routed by the record attribute
+	logger.Debug("inline unknown", "component", "nope")    // unknown => default
+	l.Logger(logger, "later").Info("unregistered binding") // default (warn)
+	logger.WithGroup("g").Debug("grouped", "component", "db")
+
+	out := buf.String()
+	for _, want := range []string{"db debug", "plain warn", "inline db"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"cache warn", "plain info", "inline unknown", "unregistered binding", "grouped"} {
+		if strings.Contains(out, unwanted) {
+			t.Fatalf("unexpected %q in:\n%s", unwanted, out)
+		}
+	}
+
+	// A binding made before registration resolves once the component exists.
+	later := l.Logger(logger, "later")
+	_, _ = l.Register("later", slog.LevelDebug)
+	later.Debug("later debug")
+	if !strings.Contains(buf.String(), "later debug") {
+		t.Fatalf("late registration not honored:\n%s", buf.String())
+	}
+}
+
+func TestLevels_UnboundEnabledTracksLowestLevel(t *testing.T) {
+	l := NewLevels()
+	logger, _ := newTestLogger(l)
+	ctx := context.Background()
+	enabled := func(level slog.Level) bool { return logger.Handler().Enabled(ctx, level) }
+
+	if enabled(slog.LevelDebug) || !enabled(slog.LevelInfo) {
+		t.Fatal("default only: want info")
+	}
+	_, _ = l.Register("db", slog.LevelWarn)
+	if enabled(slog.LevelDebug) {
+		t.Fatal("db at warn must not lower the minimum")
+	}
+	_, _ = l.Set("db", slog.LevelDebug, 20*time.Millisecond)
+	if !enabled(slog.LevelDebug) {
+		t.Fatal("Set: want debug")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for enabled(slog.LevelDebug) {
+		if time.Now().After(deadline) {
+			t.Fatal("TTL expiry did not raise the minimum")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	_, _ = l.Set("db", slog.LevelDebug, 0)
+	_ = l.Reset("db")
+	if enabled(slog.LevelDebug) {
+		t.Fatal("Reset: want info")
+	}
+	_, _ = l.Register("cache", slog.LevelDebug)
+	if !enabled(slog.LevelDebug) {
+		t.Fatal("Register: want debug")
+	}
+
+	// The default LevelVar may be set directly (e.g. by /log/level).
+	l2 := NewLevels()
+	logger2, _ := newTestLogger(l2)
+	l2.Default().Set(slog.LevelDebug)
+	if !logger2.Handler().Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("direct default change not seen")
+	}
+}
+
+func TestLevels_SetTTLAndReset(t *testing.T) {
+	l := NewLevels()
+	_, _ = l.Register("db", slog.LevelInfo)
+
+	var mu sync.Mutex
+	var changes []LevelChange
+	remove := l.OnChange(func(c LevelChange) {
+		mu.Lock()
+		changes = append(changes, c)
+		mu.Unlock()
+	})
+	defer remove()
+
+	if _, err := l.Set("nope", slog.LevelDebug, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Set(nope) err=%v", err)
+	}
+	if err := l.Reset("nope"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Reset(nope) err=%v", err)
+	}
+
+	// Permanent set, then two temporary sets: the revert goes back to the pre-TTL level.
+	if old, err := l.Set("db", slog.LevelWarn, 0); err != nil || old != slog.LevelInfo {
+		t.Fatalf("Set old=%v err=%v", old, err)
+	}
+	_, _ = l.Set("db", slog.LevelDebug, time.Hour)
+	_, _ = l.Set("db", slog.LevelError, 30*time.Millisecond)
+	var snap LevelInfo
+	for _, it := range l.Snapshot() {
+		if it.Name == "db" {
+			snap = it
+		}
+	}
+	if snap.Level != slog.LevelError || snap.RevertTo != slog.LevelWarn || snap.ExpiresAt.IsZero() || snap.Initial != slog.LevelInfo {
+		t.Fatalf("snapshot=%+v", snap)
+	}
+
+	lv, _ := l.Var("db")
+	deadline := time.Now().Add(2 * time.Second)
+	for lv.Level() != slog.LevelWarn {
+		if time.Now().After(deadline) {
+			t.Fatalf("did not revert, level=%v", lv.Level())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// A temporary set is cancelled by Reset.
+	_, _ = l.Set("db", slog.LevelDebug, 20*time.Millisecond)
+	if err := l.Reset("db"); err != nil {
+		t.Fatalf("Reset err=%v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if lv.Level() != slog.LevelInfo {
+		t.Fatalf("level after reset=%v, want info", lv.Level())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var causes []string
+	for _, c := range changes {
+		causes = append(causes, c.Cause+":"+c.New.String())
+	}
+	want := "set:WARN,set:DEBUG,set:ERROR,expire:WARN,set:DEBUG,reset:INFO"
+	if got := strings.Join(causes, ","); got != want {
+		t.Fatalf("changes=%s, want %s", got, want)
+	}
+}
+
+func TestLevels_AttrKeyAndSnapshotOrder(t *testing.T) {
+	l := NewLevels(WithLevelsAttrKey("subsystem"))
+	_, _ = l.Register("zeta", slog.LevelInfo)
+	_, _ = l.Register("alpha", slog.LevelDebug)
+	logger, buf := newTestLogger(l)
+	logger.Debug("alpha via custom key", "subsystem", "alpha")
+	logger.Debug("ignored key", "component", "alpha")
+	if out := buf.String(); !strings.Contains(out, "alpha via custom key") || strings.Contains(out, "ignored key") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	var names []string
+	for _, it := range l.Snapshot() {
+		names = append(names, it.Name)
+	}
+	if got := strings.Join(names, ","); got != "alpha,default,zeta" {
+		t.Fatalf("snapshot order=%s", got)
+	}
+}
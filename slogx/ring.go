@@ -0,0 +1,291 @@
+package slogx
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+// RequestIDKey is the attribute key read as a record's request ID when the context carries
+// none (see httpx.RequestID).
+const RequestIDKey = "request_id"
+
+// RingAttr is one flattened attribute of a RingRecord. Keys inside groups are dotted
+// ("req.method"); values are rendered with slog.Value.String.
+type RingAttr struct {
+	Key   string
+	Value string
+}
+
+// RingRecord is one log record captured by a Ring.
+type RingRecord struct {
+	Seq       uint64 // increasing, starting at 1
+	Time      time.Time
+	Level     slog.Level
+	Message   string
+	RequestID string // from httpx.RequestIDFromContext, else the request_id attribute
+	Attrs     []RingAttr
+}
+
+// size approximates the memory held by r; it is what WithRingMaxBytes bounds.
+func (r *RingRecord) size() int {
+	n := 64 + len(r.Message) + len(r.RequestID)
+	for _, a := range r.Attrs {
+		n += 32 + len(a.Key) + len(a.Value)
+	}
+	return n
+}
+
+type ringConfig struct {
+	maxRecords int
+	maxBytes   int
+	level      slog.Leveler
+}
+
+// RingOption configures NewRing.
+type RingOption func(*ringConfig)
+
+// WithRingMaxRecords bounds the number of records kept. <= 0 means default (1000).
+func WithRingMaxRecords(n int) RingOption {
+	return func(c *ringConfig) { c.maxRecords = n }
+}
+
+// WithRingMaxBytes bounds the approximate memory held by records. <= 0 means default (1 MiB).
+func WithRingMaxBytes(n int) RingOption {
+	return func(c *ringConfig) { c.maxBytes = n }
+}
+
+// WithRingLevel sets the minimum level captured, independently of the wrapped handler
+// (e.g. keep debug records in memory while only info reaches stderr). nil means default
+// (slog.LevelInfo).
+func WithRingLevel(l slog.Leveler) RingOption {
+	return func(c *ringConfig) { c.level = l }
+}
+
+// Ring is a bounded in-memory buffer of recent log records, filled by Ring.Handler.
+// When full, the oldest records are evicted. It is safe for concurrent use.
+type Ring struct {
+	maxRecords int
+	maxBytes   int
+	level      slog.Leveler
+
+	mu    sync.Mutex
+	buf   []RingRecord // circular; len(buf) == maxRecords once grown
+	head  int          // index of the oldest record
+	n     int
+	bytes int
+	seq   uint64
+	subs  map[*ringSub]struct{}
+}
+
+type ringSub struct {
+	ch chan RingRecord
+}
+
+// NewRing creates an empty ring.
+func NewRing(opts ...RingOption) *Ring {
+	cfg := ringConfig{maxRecords: 1000, maxBytes: 1 << 20}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.maxRecords <= 0 {
+		cfg.maxRecords = 1000
+	}
+	if cfg.maxBytes <= 0 {
+		cfg.maxBytes = 1 << 20
+	}
+	if cfg.level == nil {
+		cfg.level = slog.LevelInfo
+	}
+	return &Ring{
+		maxRecords: cfg.maxRecords,
+		maxBytes:   cfg.maxBytes,
+		level:      cfg.level,
+		subs:       make(map[*ringSub]struct{}),
+	}
+}
+
+// Records returns the buffered records, oldest first.
+func (r *Ring) Records() []RingRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RingRecord, 0, r.n)
+	for i := 0; i < r.n; i++ {
+		out = append(out, r.buf[(r.head+i)%len(r.buf)])
+	}
+	return out
+}
+
+// Len returns the number of buffered records and their approximate size in bytes.
+func (r *Ring) Len() (records, bytes int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.n, r.bytes
+}
+
+// Subscribe returns the records buffered so far and a channel of records added afterwards,
+// with no gap or overlap between the two.
+//
+// The channel holds up to buffer records (<= 0 means 256); a subscriber that falls behind
+// is dropped and its channel closed. cancel unsubscribes and is safe to call more than once.
+func (r *Ring) Subscribe(buffer int) (backlog []RingRecord, ch <-chan RingRecord, cancel func()) {
+	if buffer <= 0 {
+		buffer = 256
+	}
+	s := &ringSub{ch: make(chan RingRecord, buffer)}
+	r.mu.Lock()
+	backlog = make([]RingRecord, 0, r.n)
+	for i := 0; i < r.n; i++ {
+		backlog = append(backlog, r.buf[(r.head+i)%len(r.buf)])
+	}
+	r.subs[s] = struct{}{}
+	r.mu.Unlock()
+	return backlog, s.ch, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if _, ok := r.subs[s]; ok {
+			delete(r.subs, s)
+			close(s.ch)
+		}
+	}
+}
+
+func (r *Ring) add(rec RingRecord) {
+	size := rec.size()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	rec.Seq = r.seq
+
+	if r.buf == nil {
+		r.buf = make([]RingRecord, r.maxRecords)
+	}
+	// A record larger than the whole budget is only streamed to subscribers.
+	if size <= r.maxBytes {
+		for r.n > 0 && (r.n == len(r.buf) || r.bytes+size > r.maxBytes) {
+			r.bytes -= r.buf[r.head].size()
+			r.buf[r.head] = RingRecord{}
+			r.head = (r.head + 1) % len(r.buf)
+			r.n--
+		}
+		r.buf[(r.head+r.n)%len(r.buf)] = rec
+		r.n++
+		r.bytes += size
+	}
+
+	for s := range r.subs {
+		select {
+		case s.ch <- rec:
+		default:
+			// Slow consumer: drop it rather than block logging.
+			delete(r.subs, s)
+			close(s.ch)
+		}
+	}
+}
+
+// Handler returns a handler that captures records at or above the ring level into r and
+// passes records enabled by next on to next. next may be nil to only capture.
+//
+// Records logged with a context marked by httpx.DebugLog are captured and passed on at
+// slog.LevelDebug and above (see DebugHandler).
+func (r *Ring) Handler(next slog.Handler) slog.Handler {
+	if r == nil {
+		panic("slogx: nil Ring")
+	}
+	return &ringHandler{r: r, next: next}
+}
+
+type ringHandler struct {
+	r      *Ring
+	next   slog.Handler
+	attrs  []RingAttr // bound with WithAttrs, already group-qualified
+	prefix string     // current group prefix ("a.b.")
+}
+
+func (h *ringHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= h.r.level.Level() || debugForced(ctx, level) {
+		return true
+	}
+	return h.next != nil && h.next.Enabled(ctx, level)
+}
+
+func (h *ringHandler) Handle(ctx context.Context, rec slog.Record) error {
+	forced := debugForced(ctx, rec.Level)
+	if rec.Level >= h.r.level.Level() || forced {
+		rr := RingRecord{
+			Time:    rec.Time,
+			Level:   rec.Level,
+			Message: rec.Message,
+			Attrs:   make([]RingAttr, 0, len(h.attrs)+rec.NumAttrs()),
+		}
+		rr.Attrs = append(rr.Attrs, h.attrs...)
+		rec.Attrs(func(a slog.Attr) bool {
+			rr.Attrs = appendRingAttr(rr.Attrs, h.prefix, a)
+			return true
+		})
+		if id, ok := httpx.RequestIDFromContext(ctx); ok {
+			rr.RequestID = id
+		} else {
+			for _, a := range rr.Attrs {
+				if a.Key == RequestIDKey {
+					rr.RequestID = a.Value
+					break
+				}
+			}
+		}
+		h.r.add(rr)
+	}
+	if h.next != nil && (forced || h.next.Enabled(ctx, rec.Level)) {
+		return h.next.Handle(ctx, rec)
+	}
+	return nil
+}
+
+func (h *ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := *h
+	nh.attrs = make([]RingAttr, 0, len(h.attrs)+len(attrs))
+	nh.attrs = append(nh.attrs, h.attrs...)
+	for _, a := range attrs {
+		nh.attrs = appendRingAttr(nh.attrs, h.prefix, a)
+	}
+	if h.next != nil {
+		nh.next = h.next.WithAttrs(attrs)
+	}
+	return &nh
+}
+
+func (h *ringHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	nh.prefix = h.prefix + name + "."
+	if h.next != nil {
+		nh.next = h.next.WithGroup(name)
+	}
+	return &nh
+}
+
+func appendRingAttr(dst []RingAttr, prefix string, a slog.Attr) []RingAttr {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			dst = appendRingAttr(dst, p, ga)
+		}
+		return dst
+	}
+	if a.Key == "" {
+		return dst
+	}
+	return append(dst, RingAttr{Key: prefix + a.Key, Value: v.String()})
+}
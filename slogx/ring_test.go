@@ -0,0 +1,114 @@
+package slogx
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evan-idocoding/zkit/httpx"
+)
+
+func TestRing_CaptureAndTee(t *testing.T) {
+	ring := NewRing(WithRingLevel(slog.LevelDebug))
+	var out strings.Builder
+	next := slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(ring.Handler(next))
+
+	logger.Debug("debug only in ring")
+	logger.With("component", "db").WithGroup("q").Info("query", "rows", 3, slog.Group("t", "ms", 12))
+	logger.Info("with id", RequestIDKey, "r-1")
+
+	if strings.Contains(out.String(), "debug only in ring") || !strings.Contains(out.String(), "query") {
+		t.Fatalf("next output:\n%s", out.String())
+	}
+	recs := ring.Records()
+	if len(recs) != 3 {
+		t.Fatalf("records=%d, want 3", len(recs))
+	}
+	if recs[0].Level != slog.LevelDebug || recs[0].Seq != 1 {
+		t.Fatalf("rec0=%+v", recs[0])
+	}
+	var attrs []string
+	for _, a := range recs[1].Attrs {
+		attrs = append(attrs, a.Key+"="+a.Value)
+	}
+	if got := strings.Join(attrs, " "); got != "component=db q.rows=3 q.t.ms=12" {
+		t.Fatalf("attrs=%q", got)
+	}
+	if recs[2].RequestID != "r-1" {
+		t.Fatalf("request id=%q", recs[2].RequestID)
+	}
+
+	// Request IDs set by httpx.RequestID come from the context.
+	h := httpx.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "in request")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(httpx.DefaultRequestIDHeader, "abc")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if recs := ring.Records(); recs[len(recs)-1].RequestID != "abc" {
+		t.Fatalf("request id=%q", recs[len(recs)-1].RequestID)
+	}
+}
+
+func TestRing_Bounds(t *testing.T) {
+	ring := NewRing(WithRingMaxRecords(3))
+	logger := slog.New(ring.Handler(nil))
+	for _, m := range []string{"a", "b", "c", "d", "e"} {
+		logger.Info(m)
+	}
+	recs := ring.Records()
+	if len(recs) != 3 || recs[0].Message != "c" || recs[2].Message != "e" || recs[2].Seq != 5 {
+		t.Fatalf("records=%+v", recs)
+	}
+
+	ring = NewRing(WithRingMaxBytes(400))
+	logger = slog.New(ring.Handler(nil))
+	for i := 0; i < 10; i++ {
+		logger.Info(strings.Repeat("x", 50))
+	}
+	if n, b := ring.Len(); n == 0 || n >= 10 || b > 400 {
+		t.Fatalf("len=%d bytes=%d", n, b)
+	}
+	// An oversized record does not wipe the buffer.
+	before, _ := ring.Len()
+	logger.Info(strings.Repeat("y", 1000))
+	if n, _ := ring.Len(); n != before {
+		t.Fatalf("len=%d, want %d", n, before)
+	}
+}
+
+func TestRing_Subscribe(t *testing.T) {
+	ring := NewRing()
+	logger := slog.New(ring.Handler(nil))
+	logger.Info("old")
+
+	backlog, ch, cancel := ring.Subscribe(1)
+	if len(backlog) != 1 || backlog[0].Message != "old" {
+		t.Fatalf("backlog=%+v", backlog)
+	}
+	logger.Info("new")
+	if rec := <-ch; rec.Message != "new" {
+		t.Fatalf("rec=%+v", rec)
+	}
+
+	// Falling behind closes the channel.
+	logger.Info("one")
+	logger.Info("two")
+	<-ch
+	if _, ok := <-ch; ok {
+		t.Fatalf("expected closed channel")
+	}
+	cancel()
+	cancel()
+
+	if !slog.New(ring.Handler(nil)).Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatalf("expected enabled")
+	}
+	if slog.New(ring.Handler(nil)).Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatalf("debug should be disabled by default")
+	}
+}